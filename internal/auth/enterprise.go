@@ -0,0 +1,61 @@
+package auth
+
+import (
+	"strings"
+	"time"
+
+	"cursor-sync/internal/urlutil"
+)
+
+// DefaultAPIBaseURL is the REST API endpoint of github.com
+const DefaultAPIBaseURL = urlutil.GitHubAPIBaseURL
+
+// Endpoint describes how GitHub is reached: the REST API serving the repository and the
+// network timeout. The zero value is github.com with the default timeout
+type Endpoint struct {
+	APIBaseURL string        // REST API endpoint ("" = api.github.com)
+	Timeout    time.Duration // network.timeout (0 = DefaultNetworkTimeout)
+}
+
+// NewEndpoint returns the endpoint for a repository: the configured API URL if set,
+// otherwise the one serving repoURL (see ResolveAPIBaseURL)
+func NewEndpoint(repoURL, configuredAPI string, timeout time.Duration) Endpoint {
+	return Endpoint{APIBaseURL: ResolveAPIBaseURL(repoURL, configuredAPI), Timeout: timeout}
+}
+
+// BaseURL returns the REST API endpoint without a trailing slash
+func (e Endpoint) BaseURL() string {
+	if baseURL := strings.TrimSuffix(strings.TrimSpace(e.APIBaseURL), "/"); baseURL != "" {
+		return baseURL
+	}
+	return DefaultAPIBaseURL
+}
+
+// IsEnterprise reports whether API calls go to a GitHub Enterprise Server
+func (e Endpoint) IsEnterprise() bool {
+	return e.BaseURL() != DefaultAPIBaseURL
+}
+
+// NetworkTimeout returns how long a single API request may take, and how long a git
+// network operation may go without connecting or transferring data
+func (e Endpoint) NetworkTimeout() time.Duration {
+	if e.Timeout <= 0 {
+		return DefaultNetworkTimeout
+	}
+	return e.Timeout
+}
+
+// ResolveAPIBaseURL returns the API endpoint for a repository: the configured one if set,
+// api.github.com for github.com repositories, and https://<host>[:port][/prefix]/api/v3
+// for GitHub Enterprise Server hosts
+func ResolveAPIBaseURL(repoURL, configured string) string {
+	if configured = strings.TrimSpace(configured); configured != "" {
+		return strings.TrimSuffix(configured, "/")
+	}
+
+	parsed, err := urlutil.ParseGitHubURL(repoURL)
+	if err != nil {
+		return DefaultAPIBaseURL
+	}
+	return parsed.APIBaseURL()
+}
@@ -0,0 +1,42 @@
+package auth
+
+import (
+	"testing"
+	"time"
+)
+
+func TestResolveAPIBaseURL(t *testing.T) {
+	tests := []struct {
+		repoURL    string
+		configured string
+		want       string
+	}{
+		{"https://github.com/owner/repo.git", "", "https://api.github.com"},
+		{"git@github.com:owner/repo.git", "", "https://api.github.com"},
+		{"https://github.mycorp.com/owner/repo.git", "", "https://github.mycorp.com/api/v3"},
+		{"git@github.mycorp.com:owner/repo.git", "", "https://github.mycorp.com/api/v3"},
+		{"https://github.mycorp.com:8443/owner/repo", "", "https://github.mycorp.com:8443/api/v3"},
+		{"ssh://git@github.mycorp.com:2222/owner/repo.git", "", "https://github.mycorp.com/api/v3"},
+		{"https://www.github.com/owner/repo", "", "https://api.github.com"},
+		{"https://github.mycorp.com/owner/repo.git", "https://api.mycorp.com/", "https://api.mycorp.com"},
+	}
+
+	for _, tt := range tests {
+		if got := ResolveAPIBaseURL(tt.repoURL, tt.configured); got != tt.want {
+			t.Errorf("ResolveAPIBaseURL(%q, %q) = %q, want %q", tt.repoURL, tt.configured, got, tt.want)
+		}
+	}
+}
+
+func TestEndpointDefaults(t *testing.T) {
+	var zero Endpoint
+	if zero.BaseURL() != DefaultAPIBaseURL || zero.IsEnterprise() || zero.NetworkTimeout() != DefaultNetworkTimeout {
+		t.Errorf("zero Endpoint = %q, enterprise %v, timeout %v, want github.com with the default timeout",
+			zero.BaseURL(), zero.IsEnterprise(), zero.NetworkTimeout())
+	}
+
+	endpoint := NewEndpoint("https://github.mycorp.com/owner/repo.git", "", 5*time.Second)
+	if endpoint.BaseURL() != "https://github.mycorp.com/api/v3" || !endpoint.IsEnterprise() || endpoint.NetworkTimeout() != 5*time.Second {
+		t.Errorf("enterprise Endpoint = %q, enterprise %v, timeout %v", endpoint.BaseURL(), endpoint.IsEnterprise(), endpoint.NetworkTimeout())
+	}
+}
@@ -3,6 +3,7 @@ package auth
 import (
 	"context"
 	"fmt"
+	"net/url"
 	"os"
 	"path/filepath"
 	"strings"
@@ -11,6 +12,8 @@ import (
 	"golang.org/x/oauth2"
 
 	"cursor-sync/internal/logger"
+	"cursor-sync/internal/paths"
+	"cursor-sync/internal/ratelimit"
 )
 
 const (
@@ -19,12 +22,14 @@ const (
 
 // GitHubAuth handles GitHub authentication
 type GitHubAuth struct {
-	token  string
-	client *github.Client
+	token    string
+	login    string // Account the token belongs to, set by verifyToken
+	client   *github.Client
+	endpoint Endpoint
 }
 
-// NewGitHubAuth creates a new GitHub authentication handler
-func NewGitHubAuth() (*GitHubAuth, error) {
+// NewGitHubAuth creates a new GitHub authentication handler for the API at endpoint
+func NewGitHubAuth(endpoint Endpoint) (*GitHubAuth, error) {
 	token, err := loadGitHubToken()
 	if err != nil {
 		return nil, err
@@ -35,11 +40,22 @@ func NewGitHubAuth() (*GitHubAuth, error) {
 		&oauth2.Token{AccessToken: token},
 	)
 	tc := oauth2.NewClient(context.Background(), ts)
+	tc.Timeout = endpoint.NetworkTimeout()
 	client := github.NewClient(tc)
 
+	// Point the client at a GitHub Enterprise Server API when one is configured
+	if endpoint.IsEnterprise() {
+		baseURL, err := url.Parse(endpoint.BaseURL() + "/")
+		if err != nil {
+			return nil, fmt.Errorf("invalid GitHub API URL %s: %w", endpoint.BaseURL(), err)
+		}
+		client.BaseURL = baseURL
+	}
+
 	auth := &GitHubAuth{
-		token:  token,
-		client: client,
+		token:    token,
+		client:   client,
+		endpoint: endpoint,
 	}
 
 	// Verify token works
@@ -60,6 +76,16 @@ func (ga *GitHubAuth) GetToken() string {
 	return ga.token
 }
 
+// Endpoint returns the GitHub API endpoint and network timeout the handler uses
+func (ga *GitHubAuth) Endpoint() Endpoint {
+	return ga.endpoint
+}
+
+// Login returns the login of the account the token belongs to
+func (ga *GitHubAuth) Login() string {
+	return ga.login
+}
+
 // verifyToken verifies the GitHub token is valid
 func (ga *GitHubAuth) verifyToken() error {
 	ctx := context.Background()
@@ -68,24 +94,24 @@ func (ga *GitHubAuth) verifyToken() error {
 	user, resp, err := ga.client.Users.Get(ctx, "")
 	if err != nil {
 		if resp != nil && resp.StatusCode == 401 {
-			return fmt.Errorf("invalid GitHub token - please check your token in ~/.cursor-sync/.github")
+			tokenPath, _ := TokenFilePath()
+			return fmt.Errorf("invalid GitHub token - please check your token in %s", tokenPath)
 		}
-		return fmt.Errorf("failed to verify GitHub token: %w", err)
+		return fmt.Errorf("failed to verify GitHub token: %w", ratelimit.FromGitHubError(err))
 	}
 
-	logger.Info("✅ GitHub token verified for user: %s", user.GetLogin())
+	ga.login = user.GetLogin()
+	logger.Info("✅ GitHub token verified for user: %s", ga.login)
 	return nil
 }
 
 // loadGitHubToken loads the GitHub token from file
 func loadGitHubToken() (string, error) {
-	home, err := os.UserHomeDir()
+	tokenPath, err := TokenFilePath()
 	if err != nil {
-		return "", fmt.Errorf("failed to get home directory: %w", err)
+		return "", err
 	}
 
-	tokenPath := filepath.Join(home, ".cursor-sync", GitHubTokenFile)
-
 	// Check if token file exists
 	if _, err := os.Stat(tokenPath); os.IsNotExist(err) {
 		return "", fmt.Errorf("GitHub token not found. Please create %s with your GitHub Personal Access Token", tokenPath)
@@ -117,19 +143,16 @@ func SaveGitHubToken(token string) error {
 		return fmt.Errorf("invalid GitHub token format")
 	}
 
-	home, err := os.UserHomeDir()
+	tokenPath, err := TokenFilePath()
 	if err != nil {
-		return fmt.Errorf("failed to get home directory: %w", err)
+		return err
 	}
 
-	// Create .cursor-sync directory if it doesn't exist
-	cursorSyncDir := filepath.Join(home, ".cursor-sync")
-	if err := os.MkdirAll(cursorSyncDir, 0700); err != nil {
-		return fmt.Errorf("failed to create .cursor-sync directory: %w", err)
+	// Create the config directory if it doesn't exist
+	if err := os.MkdirAll(filepath.Dir(tokenPath), 0700); err != nil {
+		return fmt.Errorf("failed to create config directory: %w", err)
 	}
 
-	tokenPath := filepath.Join(cursorSyncDir, GitHubTokenFile)
-
 	// Write token to file with restricted permissions
 	if err := os.WriteFile(tokenPath, []byte(token), 0600); err != nil {
 		return fmt.Errorf("failed to write GitHub token: %w", err)
@@ -139,6 +162,15 @@ func SaveGitHubToken(token string) error {
 	return nil
 }
 
+// TokenFilePath returns the file the GitHub token is stored in
+func TokenFilePath() (string, error) {
+	configDir, err := paths.ConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(configDir, GitHubTokenFile), nil
+}
+
 // isValidGitHubTokenFormat checks if the token format looks like a GitHub token
 func isValidGitHubTokenFormat(token string) bool {
 	// GitHub personal access tokens start with ghp_ or github_pat_
@@ -167,8 +199,7 @@ func ShowTokenRequiredMessage() {
 	fmt.Println("3. Select scopes: 'repo' (Full control of private repositories)")
 	fmt.Println("4. Copy the generated token")
 	fmt.Println("\nTo configure the token:")
-	home, _ := os.UserHomeDir()
-	tokenPath := filepath.Join(home, ".cursor-sync", GitHubTokenFile)
+	tokenPath, _ := TokenFilePath()
 	fmt.Printf("5. Save your token to: %s\n", tokenPath)
 	fmt.Printf("   echo 'your_token_here' > %s\n", tokenPath)
 	fmt.Printf("   chmod 600 %s\n", tokenPath)
@@ -0,0 +1,7 @@
+package auth
+
+import "time"
+
+// DefaultNetworkTimeout bounds a single GitHub API request, and how long a git network
+// operation may go without connecting or transferring data
+const DefaultNetworkTimeout = 60 * time.Second
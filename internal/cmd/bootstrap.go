@@ -3,11 +3,17 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"strings"
 
 	"github.com/spf13/cobra"
 
+	"cursor-sync/internal/config"
+	"cursor-sync/internal/console"
+	"cursor-sync/internal/daemon"
+	"cursor-sync/internal/installer"
 	"cursor-sync/internal/interactive"
 	"cursor-sync/internal/logger"
+	"cursor-sync/internal/privacy"
 )
 
 // bootstrapCmd represents the comprehensive setup command
@@ -22,152 +28,294 @@ This command will:
 3. 🔑 Help you set up your GitHub Personal Access Token
 4. 📦 Configure your private repository (cursor-sync-bucket)
 5. ⚙️  Create all necessary configuration files
-6. 🔧 Install the background daemon
-7. 🚀 Start the sync service
-8. ✅ Verify everything is working
+6. 🔒 Verify the repository is private before anything is installed
+7. 🔧 Install the background daemon
+8. 🚀 Start the sync service
+9. ✅ Verify everything is working
 
-No need to run multiple commands - bootstrap handles everything!`,
+No need to run multiple commands - bootstrap handles everything!
+
+Re-running it after fixing one thing doesn't have to redo everything: --only runs just
+the named steps (cursor, setup, privacy, validate, install, start, verify) and
+--skip-install / --skip-start leave the daemon alone. An installed or running daemon
+is never reinstalled or restarted.
+
+Examples:
+  cursor-sync bootstrap --only privacy,install,start
+  cursor-sync bootstrap --skip-install --skip-start`,
 	Run: func(cmd *cobra.Command, args []string) {
-		fmt.Println("🚀 CURSOR-SYNC BOOTSTRAP")
-		fmt.Println("=" + fmt.Sprintf("%*s", 79, "="))
-		fmt.Println()
-		fmt.Println("Welcome! This wizard will set up cursor-sync completely in one go.")
-		fmt.Println("Sit back and follow the prompts - we'll handle everything!")
-		fmt.Println()
-
-		// Step 1: Cursor Validation
-		if err := validateCursorInstallation(); err != nil {
-			fmt.Printf("❌ Bootstrap failed at Cursor validation: %v\n", err)
+		steps, err := selectBootstrapSteps(bootstrapOnly, bootstrapSkipInstall, bootstrapSkipStart)
+		if err != nil {
+			errorf("❌ %v\n", err)
 			os.Exit(1)
 		}
 
-		// Step 2: Interactive Setup (Token + Repository)
-		if err := runInteractiveSetup(); err != nil {
-			fmt.Printf("❌ Bootstrap failed at interactive setup: %v\n", err)
-			os.Exit(1)
+		sayln("🚀 CURSOR-SYNC BOOTSTRAP")
+		sayln("=" + fmt.Sprintf("%*s", 79, "="))
+		sayln()
+		if len(steps) == len(bootstrapSteps) {
+			sayln("Welcome! This wizard will set up cursor-sync completely in one go.")
+			sayln("Sit back and follow the prompts - we'll handle everything!")
+		} else {
+			sayf("Running bootstrap steps: %s\n", bootstrapStepNames(steps))
 		}
+		sayln()
 
-		// Step 3: Final Configuration Validation
-		if err := validateConfiguration(); err != nil {
-			fmt.Printf("❌ Bootstrap failed at configuration validation: %v\n", err)
-			os.Exit(1)
+		for _, step := range steps {
+			if err := step.run(); err != nil {
+				errorf("❌ Bootstrap failed at %s: %v\n", step.stage, err)
+				if step.hint != "" {
+					errorln(step.hint)
+				}
+				os.Exit(1)
+			}
 		}
 
-		// Step 4: Installation
-		if err := performInstallation(); err != nil {
-			fmt.Printf("❌ Bootstrap failed at installation: %v\n", err)
-			os.Exit(1)
+		if len(steps) == len(bootstrapSteps) {
+			showSuccessMessage()
+			return
 		}
+		sayf("✅ Bootstrap steps completed: %s\n", bootstrapStepNames(steps))
+	},
+}
 
-		// Step 5: Start Service
-		if err := startSyncService(); err != nil {
-			fmt.Printf("❌ Bootstrap failed at service startup: %v\n", err)
-			os.Exit(1)
+var (
+	bootstrapOnly        []string
+	bootstrapSkipInstall bool
+	bootstrapSkipStart   bool
+)
+
+// bootstrapStep is one stage of the bootstrap wizard
+type bootstrapStep struct {
+	name  string // Name accepted by --only
+	stage string // Describes the step in failure messages
+	hint  string // Printed after a failure (optional)
+	run   func() error
+}
+
+// bootstrapSteps are the wizard's steps in the order they run. Each can be re-run on its
+// own: steps whose work is already done (daemon installed or running) skip it
+var bootstrapSteps = []bootstrapStep{
+	{name: "cursor", stage: "Cursor validation", run: validateCursorInstallation},
+	{name: "setup", stage: "interactive setup", run: runInteractiveSetup},
+	// A public or unverifiable repository stops here, before a daemon is installed that
+	// would refuse to sync
+	{name: "privacy", stage: "privacy pre-flight", run: preflightRepositoryPrivacy,
+		hint: "Nothing was installed or started. Fix the repository and run 'cursor-sync bootstrap --only privacy,install,start' again."},
+	{name: "validate", stage: "configuration validation", run: validateConfiguration},
+	{name: "install", stage: "installation", run: performInstallation},
+	{name: "start", stage: "service startup", run: startSyncService},
+	{name: "verify", stage: "final verification", run: verifyInstallation},
+}
+
+// selectBootstrapSteps returns the steps to run: those named by --only, or all of them
+// minus the skipped ones
+func selectBootstrapSteps(only []string, skipInstall, skipStart bool) ([]bootstrapStep, error) {
+	selected := make(map[string]bool)
+	for _, name := range only {
+		name = strings.ToLower(strings.TrimSpace(name))
+		found := false
+		for _, step := range bootstrapSteps {
+			if step.name == name {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("unknown bootstrap step %q (steps: %s)", name, bootstrapStepNames(bootstrapSteps))
 		}
+		selected[name] = true
+	}
 
-		// Step 6: Final Verification
-		if err := verifyInstallation(); err != nil {
-			fmt.Printf("❌ Bootstrap failed at final verification: %v\n", err)
-			os.Exit(1)
+	var steps []bootstrapStep
+	for _, step := range bootstrapSteps {
+		switch {
+		case len(selected) > 0 && !selected[step.name]:
+		case skipInstall && step.name == "install":
+		case skipStart && step.name == "start":
+		default:
+			steps = append(steps, step)
 		}
+	}
+	if len(steps) == 0 {
+		return nil, fmt.Errorf("no bootstrap steps left to run")
+	}
+	return steps, nil
+}
 
-		// Success!
-		showSuccessMessage()
-	},
+// bootstrapStepNames lists the names of steps, comma-separated
+func bootstrapStepNames(steps []bootstrapStep) string {
+	names := make([]string, len(steps))
+	for i, step := range steps {
+		names[i] = step.name
+	}
+	return strings.Join(names, ", ")
 }
 
 func validateCursorInstallation() error {
-	fmt.Println("🔍 STEP 1: Validating Cursor IDE Installation")
-	fmt.Println(fmt.Sprintf("%*s", 50, "-"))
+	sayln("🔍 STEP 1: Validating Cursor IDE Installation")
+	sayln(fmt.Sprintf("%*s", 50, "-"))
 
-	// Use the existing check command logic
-	checkCmd.Run(checkCmd, []string{})
-	fmt.Println()
+	if err := runCursorCheck(); err != nil {
+		return err
+	}
+	sayln()
 	return nil
 }
 
 func runInteractiveSetup() error {
-	fmt.Println("⚙️ STEP 2: Interactive Configuration")
-	fmt.Println(fmt.Sprintf("%*s", 50, "-"))
+	sayln("⚙️ STEP 2: Interactive Configuration")
+	sayln(fmt.Sprintf("%*s", 50, "-"))
 
-	wizard := interactive.NewSetupWizard()
+	wizard := interactive.NewSetupWizard(networkTimeout)
 	if err := wizard.RunInteractiveSetup(); err != nil {
 		return fmt.Errorf("interactive setup failed: %w", err)
 	}
 
-	fmt.Println()
+	sayln()
 	return nil
 }
 
-func validateConfiguration() error {
-	fmt.Println("✅ STEP 3: Validating Complete Configuration")
-	fmt.Println(fmt.Sprintf("%*s", 50, "-"))
+// preflightRepositoryPrivacy checks that the configured repository is private, only
+// reading from GitHub
+func preflightRepositoryPrivacy() error {
+	sayln("🔒 STEP 3: Repository Privacy Pre-flight")
+	sayln(fmt.Sprintf("%*s", 50, "-"))
 
-	// Use validate command logic but capture output
-	validateCmd.Run(validateCmd, []string{})
-	fmt.Println()
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+	repoURL := cfg.Repository.URL
+	if repoURL == "" {
+		return fmt.Errorf("repository URL not found in configuration")
+	}
+
+	sayf("Verifying %s is private...\n", repoURL)
+	isPrivate, err := privacy.NewRepositoryChecker(cfg.GitHubEndpoint()).CheckRepositoryPrivacy(repoURL)
+	if err != nil {
+		privacy.ShowPrivacyCheckError(repoURL, err)
+		return fmt.Errorf("cannot verify repository privacy")
+	}
+	if !isPrivate {
+		privacy.ShowPrivacyWarning(repoURL)
+		return fmt.Errorf("public repository detected")
+	}
+
+	sayln("✅ Repository is private")
+	sayln()
 	return nil
 }
 
+func validateConfiguration() error {
+	sayln("✅ STEP 4: Validating Complete Configuration")
+	sayln(fmt.Sprintf("%*s", 50, "-"))
+
+	return runValidate()
+}
+
 func performInstallation() error {
-	fmt.Println("🔧 STEP 4: Installing Background Daemon")
-	fmt.Println(fmt.Sprintf("%*s", 50, "-"))
+	sayln("🔧 STEP 5: Installing Background Daemon")
+	sayln(fmt.Sprintf("%*s", 50, "-"))
 
-	// Use install command logic
-	installCmd.Run(installCmd, []string{})
-	fmt.Println()
+	installed, err := installer.IsInstalled()
+	if err != nil {
+		return fmt.Errorf("failed to check for an existing installation: %w", err)
+	}
+	if installed {
+		sayln("✅ Daemon already installed - skipping (reinstall with 'cursor-sync install --force')")
+		sayln()
+		return nil
+	}
+
+	// The setup step has already written the configuration, which a plain install
+	// would take for an existing installation
+	if err := installer.New("", true).Install(); err != nil {
+		return err
+	}
+	sayln("✅ Daemon installed - it starts automatically on login")
+	sayln()
 	return nil
 }
 
 func startSyncService() error {
-	fmt.Println("🚀 STEP 5: Starting Sync Service")
-	fmt.Println(fmt.Sprintf("%*s", 50, "-"))
+	sayln("🚀 STEP 6: Starting Sync Service")
+	sayln(fmt.Sprintf("%*s", 50, "-"))
+
+	if daemonRunning() {
+		sayln("✅ Daemon already running - skipping")
+		sayln()
+		return nil
+	}
 
-	// Use start command logic
-	startCmd.Run(startCmd, []string{})
-	fmt.Println()
+	if err := controlDaemon("start"); err != nil {
+		return fmt.Errorf("failed to start daemon: %w", err)
+	}
+	sayln(console.Stdout.Success("Cursor Sync started"))
+	sayln(console.Stdout.Info("🔄", "Initial sync will be performed automatically"))
+	sayln()
 	return nil
 }
 
+// daemonRunning reports whether a daemon answers on the control socket or is loaded by launchd
+func daemonRunning() bool {
+	if _, err := daemon.SendControl(daemon.CommandStatus, controlTimeout); err == nil {
+		return true
+	}
+	status, err := getDaemonStatus()
+	return err == nil && status == "running"
+}
+
 func verifyInstallation() error {
-	fmt.Println("🔎 STEP 6: Final Verification")
-	fmt.Println(fmt.Sprintf("%*s", 50, "-"))
+	sayln("🔎 STEP 7: Final Verification")
+	sayln(fmt.Sprintf("%*s", 50, "-"))
 
-	// Use status command to verify everything is working
-	statusCmd.Run(statusCmd, []string{})
-	fmt.Println()
+	running, err := showStatus()
+	if err != nil {
+		return err
+	}
+	sayln()
+	if !running {
+		return fmt.Errorf("daemon is not running - check 'cursor-sync logs' for the reason")
+	}
 	return nil
 }
 
 func showSuccessMessage() {
-	fmt.Println("🎉 BOOTSTRAP COMPLETE!")
-	fmt.Println("=" + fmt.Sprintf("%*s", 79, "="))
-	fmt.Println()
-	fmt.Println("✅ Cursor-sync is now fully installed and running!")
-	fmt.Println()
-	fmt.Println("📊 What's been set up:")
-	fmt.Println("  • Cursor IDE validation passed")
-	fmt.Println("  • GitHub token configured and validated")
-	fmt.Println("  • Private repository configured")
-	fmt.Println("  • Background daemon installed")
-	fmt.Println("  • Sync service started and running")
-	fmt.Println("  • Initial sync completed")
-	fmt.Println()
-	fmt.Println("🎯 Your settings are now syncing automatically!")
-	fmt.Println()
-	fmt.Println("📋 Useful commands:")
-	fmt.Println("  cursor-sync status    # Check sync status")
-	fmt.Println("  cursor-sync pause     # Temporarily pause syncing")
-	fmt.Println("  cursor-sync resume    # Resume syncing")
-	fmt.Println("  cursor-sync logs      # View sync logs")
-	fmt.Println()
-	fmt.Println("🔄 Make changes in Cursor IDE - they'll automatically sync within 10 seconds!")
-	fmt.Println("🌟 cursor-sync is now protecting your settings across all your machines.")
-	fmt.Println()
+	sayln("🎉 BOOTSTRAP COMPLETE!")
+	sayln("=" + fmt.Sprintf("%*s", 79, "="))
+	sayln()
+	sayln("✅ Cursor-sync is now fully installed and running!")
+	sayln()
+	sayln("📊 What's been set up:")
+	sayln("  • Cursor IDE validation passed")
+	sayln("  • GitHub token configured and validated")
+	sayln("  • Private repository configured and verified")
+	sayln("  • Background daemon installed")
+	sayln("  • Sync service started and running")
+	sayln("  • Initial sync completed")
+	sayln()
+	sayln("🎯 Your settings are now syncing automatically!")
+	sayln()
+	sayln("📋 Useful commands:")
+	sayln("  cursor-sync status    # Check sync status")
+	sayln("  cursor-sync pause     # Temporarily pause syncing")
+	sayln("  cursor-sync resume    # Resume syncing")
+	sayln("  cursor-sync logs      # View sync logs")
+	sayln()
+	sayln("🔄 Make changes in Cursor IDE - they'll automatically sync within 10 seconds!")
+	sayln("🌟 cursor-sync is now protecting your settings across all your machines.")
+	sayln()
 
 	logger.Info("Bootstrap completed successfully")
 }
 
 func init() {
 	rootCmd.AddCommand(bootstrapCmd)
+
+	bootstrapCmd.Flags().StringSliceVar(&bootstrapOnly, "only", nil, "Run only these steps (cursor, setup, privacy, validate, install, start, verify)")
+	bootstrapCmd.Flags().BoolVar(&bootstrapSkipInstall, "skip-install", false, "Don't install the background daemon")
+	bootstrapCmd.Flags().BoolVar(&bootstrapSkipStart, "skip-start", false, "Don't start the sync service")
+	bootstrapCmd.MarkFlagsMutuallyExclusive("only", "skip-install")
+	bootstrapCmd.MarkFlagsMutuallyExclusive("only", "skip-start")
 }
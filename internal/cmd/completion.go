@@ -0,0 +1,55 @@
+package cmd
+
+import (
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"cursor-sync/internal/logger"
+)
+
+// completionCmd represents the completion command
+var completionCmd = &cobra.Command{
+	Use:   "completion [bash|zsh|fish|powershell]",
+	Short: "Generate a shell completion script",
+	Long: `Generate a tab-completion script for cursor-sync's commands and flags
+(including the dates that have logs for 'cursor-sync logs --date').
+
+Bash (requires the bash-completion package):
+  cursor-sync completion bash > /etc/bash_completion.d/cursor-sync         # Linux
+  cursor-sync completion bash > $(brew --prefix)/etc/bash_completion.d/cursor-sync  # macOS
+
+Zsh (enable completion once with: echo "autoload -U compinit; compinit" >> ~/.zshrc):
+  cursor-sync completion zsh > "${fpath[1]}/_cursor-sync"
+
+Fish:
+  cursor-sync completion fish > ~/.config/fish/completions/cursor-sync.fish
+
+PowerShell:
+  cursor-sync completion powershell | Out-String | Invoke-Expression
+
+Start a new shell for the completions to take effect.`,
+	DisableFlagsInUseLine: true,
+	ValidArgs:             []string{"bash", "zsh", "fish", "powershell"},
+	Args:                  cobra.MatchAll(cobra.ExactArgs(1), cobra.OnlyValidArgs),
+	Run: func(cmd *cobra.Command, args []string) {
+		var err error
+		switch args[0] {
+		case "bash":
+			err = rootCmd.GenBashCompletionV2(os.Stdout, true)
+		case "zsh":
+			err = rootCmd.GenZshCompletion(os.Stdout)
+		case "fish":
+			err = rootCmd.GenFishCompletion(os.Stdout, true)
+		case "powershell":
+			err = rootCmd.GenPowerShellCompletionWithDesc(os.Stdout)
+		}
+		if err != nil {
+			logger.Fatal("Failed to generate %s completion: %v", args[0], err)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(completionCmd)
+}
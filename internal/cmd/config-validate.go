@@ -8,6 +8,7 @@ import (
 	"github.com/spf13/viper"
 
 	"cursor-sync/internal/config"
+	"cursor-sync/internal/console"
 )
 
 // configValidateCmd validates only the configuration file without Cursor installation checks
@@ -16,8 +17,9 @@ var configValidateCmd = &cobra.Command{
 	Short: "Validate configuration file only (skip Cursor installation checks)",
 	Long:  `Validate the configuration file syntax and values without checking Cursor installation or GitHub connectivity.`,
 	Run: func(cmd *cobra.Command, args []string) {
-		fmt.Println("🔍 Validating configuration file...")
-		fmt.Println()
+		out := console.Stdout
+		sayln(out.Heading("🔍", "Validating configuration file"))
+		sayln()
 
 		// Load configuration with Viper directly (bypass cursor validation)
 		var cfg config.Config
@@ -27,32 +29,32 @@ var configValidateCmd = &cobra.Command{
 
 		// Unmarshal the configuration
 		if err := viper.Unmarshal(&cfg); err != nil {
-			fmt.Printf("❌ Configuration syntax error: %v\n", err)
+			errorln(console.Stderr.Failure("Configuration syntax error: %v", err))
 			return
 		}
 
 		// Expand paths
 		if err := expandConfigPaths(&cfg); err != nil {
-			fmt.Printf("❌ Path expansion failed: %v\n", err)
+			errorln(console.Stderr.Failure("Path expansion failed: %v", err))
 			return
 		}
 
 		// Validate configuration values (without external dependencies)
 		if err := validateConfigValues(&cfg); err != nil {
-			fmt.Printf("❌ Configuration validation failed: %v\n", err)
+			errorln(console.Stderr.Failure("Configuration validation failed: %v", err))
 			return
 		}
 
 		// Configuration is valid
-		fmt.Println("✅ Configuration file is valid")
-		fmt.Printf("   Repository URL: %s\n", cfg.Repository.URL)
-		fmt.Printf("   Pull Interval: %v\n", cfg.Sync.PullInterval)
-		fmt.Printf("   Push Interval: %v\n", cfg.Sync.PushInterval)
-		fmt.Printf("   Debounce Time: %v\n", cfg.Sync.DebounceTime)
-		fmt.Printf("   Watch Enabled: %v\n", cfg.Sync.WatchEnabled)
-		fmt.Printf("   Conflict Resolution: %s\n", cfg.Sync.ConflictResolve)
-		fmt.Println()
-		fmt.Println("🎉 Configuration validation passed!")
+		sayln(out.Success("Configuration file is valid"))
+		sayln(out.Field(3, "Repository URL", cfg.Repository.URL))
+		sayln(out.Field(3, "Pull Interval", cfg.Sync.PullInterval))
+		sayln(out.Field(3, "Push Interval", cfg.Sync.PushInterval))
+		sayln(out.Field(3, "Debounce Time", cfg.Sync.DebounceTime))
+		sayln(out.Field(3, "Watch Enabled", cfg.Sync.WatchEnabled))
+		sayln(out.Field(3, "Conflict Resolution", cfg.Sync.ConflictResolve))
+		sayln()
+		sayln(out.Info("🎉", "Configuration validation passed!"))
 	},
 }
 
@@ -60,15 +62,19 @@ var configValidateCmd = &cobra.Command{
 func setConfigDefaults() {
 	// Set the same defaults as the main config
 	viper.SetDefault("repository.branch", "main")
+	viper.SetDefault("repository.auto_init", true)
 	viper.SetDefault("sync.pull_interval", "5m")
 	viper.SetDefault("sync.push_interval", "5m")
 	viper.SetDefault("sync.debounce_time", "10s")
 	viper.SetDefault("sync.watch_enabled", true)
+	viper.SetDefault("sync.auto_push", true)
 	viper.SetDefault("sync.conflict_resolve", "newer")
 	viper.SetDefault("logging.level", "info")
+	viper.SetDefault("logging.format", "text")
 	viper.SetDefault("logging.max_size", 10)
 	viper.SetDefault("logging.max_days", 30)
 	viper.SetDefault("logging.compress", true)
+	viper.SetDefault("update.check_enabled", true)
 }
 
 func expandConfigPaths(cfg *config.Config) error {
@@ -83,8 +89,12 @@ func validateConfigValues(cfg *config.Config) error {
 		return fmt.Errorf("repository URL is required")
 	}
 
-	if cfg.Repository.LocalPath == "" {
-		return fmt.Errorf("repository local path is required")
+	if err := config.ValidateSubdir(cfg.Repository.Subdir); err != nil {
+		return err
+	}
+
+	if err := config.ValidateTargets(cfg.Cursor.Targets); err != nil {
+		return err
 	}
 
 	// Sync validation
@@ -98,7 +108,7 @@ func validateConfigValues(cfg *config.Config) error {
 
 	// CRITICAL: Debounce time validation (minimum 10 seconds)
 	if cfg.Sync.DebounceTime < 10*time.Second {
-		fmt.Printf("⚠️  Debounce time too low (%v), setting to minimum 10s\n", cfg.Sync.DebounceTime)
+		sayln(console.Stdout.Warning("Debounce time too low (%v), setting to minimum 10s", cfg.Sync.DebounceTime))
 		cfg.Sync.DebounceTime = 10 * time.Second
 	}
 
@@ -107,6 +117,16 @@ func validateConfigValues(cfg *config.Config) error {
 		return fmt.Errorf("conflict_resolve must be 'newer', 'local', or 'remote'")
 	}
 
+	// Logging format validation
+	if cfg.Logging.Format != "" && cfg.Logging.Format != "text" && cfg.Logging.Format != "json" {
+		return fmt.Errorf("logging format must be 'text' or 'json'")
+	}
+
+	// Notification webhook validation
+	if err := config.ValidateNotify(cfg.Sync); err != nil {
+		return err
+	}
+
 	return nil
 }
 
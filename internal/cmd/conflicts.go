@@ -0,0 +1,81 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"cursor-sync/internal/conflicts"
+)
+
+// conflictsCmd represents the conflicts command
+var conflictsCmd = &cobra.Command{
+	Use:   "conflicts",
+	Short: "Show recently resolved sync conflicts",
+	Long: `Show conflicts between local and remote settings and how they were resolved.
+
+Every conflict resolution is recorded in conflicts.log in the state directory
+(~/.cursor-sync, or $XDG_STATE_HOME/cursor-sync on Linux), one JSON object per
+line, with the strategy used, which side won, the affected files and
+the commit timestamps that were compared. Files show the machine that last
+changed them on the remote, taken from the repository's file manifest. Use this to audit what the 'newer'
+strategy decided on your behalf.
+
+Examples:
+  cursor-sync conflicts          # Show the last 10 conflicts
+  cursor-sync conflicts -n 50    # Show the last 50 conflicts`,
+	Run: func(cmd *cobra.Command, args []string) {
+		limit, _ := cmd.Flags().GetInt("number")
+
+		records, err := conflicts.Recent(limit)
+		if err != nil {
+			errorf("❌ Failed to read conflict log: %v\n", err)
+			return
+		}
+
+		if len(records) == 0 {
+			resultln("✅ No conflicts recorded")
+			return
+		}
+
+		path, _ := conflicts.LogPath()
+		resultf("⚔️  Last %d conflict(s) (%s):\n\n", len(records), path)
+
+		for _, record := range records {
+			resultf("🕒 %s  strategy: %s  winner: %s", record.Time.Local().Format("2006-01-02 15:04:05"), record.Strategy, record.Winner)
+			if record.Host != "" {
+				resultf("  host: %s", record.Host)
+			}
+			resultln()
+
+			if record.LocalCommitTime != nil && record.RemoteCommitTime != nil {
+				resultf("   Local commit:  %s\n", record.LocalCommitTime.Local().Format(time.RFC3339))
+				resultf("   Remote commit: %s\n", record.RemoteCommitTime.Local().Format(time.RFC3339))
+			}
+
+			if len(record.Files) > 0 {
+				files := make([]string, len(record.Files))
+				for i, file := range record.Files {
+					files[i] = file
+					if host, ok := record.LastChangedBy[file]; ok {
+						files[i] = fmt.Sprintf("%s (last changed by %s)", file, host)
+					}
+				}
+				resultf("   Files: %s\n", strings.Join(files, ", "))
+			}
+
+			if record.Backup != "" {
+				resultf("   Backup of local versions: %s\n", record.Backup)
+			}
+			resultln()
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(conflictsCmd)
+
+	conflictsCmd.Flags().IntP("number", "n", 10, "Number of recent conflicts to show")
+}
@@ -4,11 +4,16 @@ import (
 	"fmt"
 	"os"
 	"os/exec"
+	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 
 	"cursor-sync/internal/config"
+	"cursor-sync/internal/console"
+	"cursor-sync/internal/daemon"
 	"cursor-sync/internal/logger"
+	"cursor-sync/internal/sync"
 )
 
 // statusCmd represents the status command
@@ -17,40 +22,90 @@ var statusCmd = &cobra.Command{
 	Short: "Show daemon status",
 	Long:  "Show the current status of the cursor-sync daemon",
 	Run: func(cmd *cobra.Command, args []string) {
-		status, err := getDaemonStatus()
-		if err != nil {
+		if _, err := showStatus(); err != nil {
 			logger.Error("Failed to get daemon status: %v", err)
-			return
 		}
+	},
+}
 
-		fmt.Printf("Cursor Sync Status: %s\n", status)
+// showStatus prints the daemon's status and reports whether it is running
+func showStatus() (bool, error) {
+	// A daemon answering on the control socket knows its own state best
+	if response, err := daemon.SendControl(daemon.CommandStatus, controlTimeout); err == nil && response.Status != nil {
+		printControlStatus(response.Status)
+		return true, nil
+	}
 
-		// Show additional info if running
-		if status == "running" {
-			cfg, err := config.Load()
-			if err == nil {
-				fmt.Printf("Repository: %s\n", cfg.Repository.URL)
-				fmt.Printf("Pull interval: %v\n", cfg.Sync.PullInterval)
-				fmt.Printf("Push interval: %v\n", cfg.Sync.PushInterval)
+	status, err := getDaemonStatus()
+	if err != nil {
+		return false, err
+	}
+
+	out := console.Stdout
+	if status == "running" {
+		resultln(out.Field(0, "Cursor Sync Status", out.Good(status)))
+	} else {
+		resultln(out.Field(0, "Cursor Sync Status", out.Bad(status)))
+	}
+
+	// Show additional info if running
+	if status == "running" {
+		cfg, err := config.Load()
+		if err == nil {
+			resultln(out.Field(0, "Repository", cfg.Repository.URL))
+			resultln(out.Field(0, "Pull interval", humanDuration(cfg.Sync.PullInterval)))
+			resultln(out.Field(0, "Push interval", humanDuration(cfg.Sync.PushInterval)))
+			if cfg.Sync.Pushes() {
+				printPushState(-1, cfg.Sync.StaleWarning)
 			}
+			printHeldDeletes()
 		}
-	},
+	}
+	return status == "running", nil
 }
 
 // pauseCmd represents the pause command
 var pauseCmd = &cobra.Command{
 	Use:   "pause",
 	Short: "Pause sync daemon",
-	Long:  "Temporarily pause the cursor-sync daemon without stopping it completely",
+	Long: `Temporarily pause the cursor-sync daemon without stopping it completely.
+
+With --for the daemon resumes automatically once the duration has passed:
+  cursor-sync pause --for 30m`,
 	Run: func(cmd *cobra.Command, args []string) {
 		if err := controlDaemon("pause"); err != nil {
 			logger.Error("Failed to pause daemon: %v", err)
 			return
 		}
-		fmt.Println("✅ Cursor Sync paused")
+		if pauseFor > 0 {
+			sayln(console.Stdout.Success("Cursor Sync paused until %s", time.Now().Add(pauseFor).Format("15:04:05")))
+			return
+		}
+		sayln(console.Stdout.Success("Cursor Sync paused"))
+	},
+}
+
+// reloadCmd represents the reload command
+var reloadCmd = &cobra.Command{
+	Use:   "reload",
+	Short: "Reload daemon configuration",
+	Long:  "Make the running daemon re-read its configuration without restarting it",
+	Run: func(cmd *cobra.Command, args []string) {
+		response, err := daemon.SendControl(daemon.CommandReloadConfig, controlTimeout)
+		if err != nil {
+			logger.Error("Failed to reload configuration: %v", err)
+			return
+		}
+		sayln(console.Stdout.Success("%s", response.Message))
 	},
 }
 
+// controlTimeout bounds quick control socket commands
+const controlTimeout = 10 * time.Second
+
+// pauseFor is how long pause lasts before the daemon resumes on its own (0 = until resume)
+var pauseFor time.Duration
+
 // resumeCmd represents the resume command
 var resumeCmd = &cobra.Command{
 	Use:   "resume",
@@ -61,7 +116,7 @@ var resumeCmd = &cobra.Command{
 			logger.Error("Failed to resume daemon: %v", err)
 			return
 		}
-		fmt.Println("✅ Cursor Sync resumed")
+		sayln(console.Stdout.Success("Cursor Sync resumed"))
 	},
 }
 
@@ -75,7 +130,7 @@ var stopCmd = &cobra.Command{
 			logger.Error("Failed to stop daemon: %v", err)
 			return
 		}
-		fmt.Println("✅ Cursor Sync stopped")
+		sayln(console.Stdout.Success("Cursor Sync stopped"))
 	},
 }
 
@@ -89,18 +144,20 @@ var startCmd = &cobra.Command{
 			logger.Error("Failed to start daemon: %v", err)
 			return
 		}
-		fmt.Println("✅ Cursor Sync started")
-		fmt.Println("🔄 Initial sync will be performed automatically")
-                fmt.Println("📋 Check logs with: cursor-sync logs")
+		sayln(console.Stdout.Success("Cursor Sync started"))
+		sayln(console.Stdout.Info("🔄", "Initial sync will be performed automatically"))
+                sayln(console.Stdout.Info("📋", "Check logs with: cursor-sync logs"))
 	},
 }
 
 func init() {
 	rootCmd.AddCommand(statusCmd)
 	rootCmd.AddCommand(pauseCmd)
+	pauseCmd.Flags().DurationVar(&pauseFor, "for", 0, "Resume automatically after this duration (e.g. 30m)")
 	rootCmd.AddCommand(resumeCmd)
 	rootCmd.AddCommand(stopCmd)
 	rootCmd.AddCommand(startCmd)
+	rootCmd.AddCommand(reloadCmd)
 }
 
 func getDaemonStatus() (string, error) {
@@ -132,20 +189,130 @@ func controlDaemon(action string) error {
 	case "stop":
 		return exec.Command("launchctl", "unload", plistPath).Run()
 	case "pause":
-		// Create pause file
-		pauseFile := fmt.Sprintf("%s/.cursor-sync/paused", home)
-		file, err := os.Create(pauseFile)
+		if pauseFor < 0 {
+			return fmt.Errorf("--for must be a positive duration")
+		}
+
+		// Pause the running daemon directly when it answers on the control socket
+		command := daemon.CommandPause
+		if pauseFor > 0 {
+			command += " " + pauseFor.String()
+		}
+		if _, err := daemon.SendControl(command, controlTimeout); err != daemon.ErrDaemonNotRunning {
+			return err
+		}
+
+		// Create pause file, holding the expiry time when pausing --for a duration
+		pauseFile, err := daemon.PauseFilePath()
 		if err != nil {
 			return err
 		}
-		file.Close()
+		if err := daemon.WritePauseFile(pauseFile, pauseFor); err != nil {
+			return err
+		}
 		logger.Info("Created pause file at " + pauseFile)
 		return nil
 	case "resume":
+		if _, err := daemon.SendControl(daemon.CommandResume, controlTimeout); err != daemon.ErrDaemonNotRunning {
+			return err
+		}
+
 		// Remove pause file
-		pauseFile := fmt.Sprintf("%s/.cursor-sync/paused", home)
+		pauseFile, err := daemon.PauseFilePath()
+		if err != nil {
+			return err
+		}
 		return os.Remove(pauseFile)
 	default:
 		return fmt.Errorf("unknown action: %s", action)
 	}
 }
+
+// printControlStatus prints the state reported by a running daemon
+func printControlStatus(status *daemon.ControlStatus) {
+	out := console.Stdout
+	resultln(out.Field(0, "Cursor Sync Status", out.Good("running")))
+	resultln(out.Field(0, "PID", status.PID))
+	resultln(out.Field(0, "Repository", fmt.Sprintf("%s (branch %s)", status.Repository, status.Branch)))
+	if status.Mode != "" {
+		resultln(out.Field(0, "Mode", fmt.Sprintf("%s (%s)", status.Mode, config.DescribeMode(status.Mode))))
+	}
+	resultln(out.Field(0, "Pull interval", formatInterval(status.PullInterval, status.ConfigPullInterval)))
+	resultln(out.Field(0, "Push interval", formatInterval(status.PushInterval, status.ConfigPushInterval)))
+
+	switch {
+	case status.PausedUntil != nil:
+		resultln(out.Field(0, "Paused until", out.Bad(status.PausedUntil.Format("2006-01-02 15:04:05"))))
+	case status.Paused:
+		resultln(out.Field(0, "Paused", out.Bad("yes")))
+	}
+	if status.SyncInProgress {
+		resultln(out.Info("🔄", "Sync in progress"))
+	}
+	if status.LastSync != nil {
+		resultln(out.Field(0, "Last sync", status.LastSync.Format("2006-01-02 15:04:05")))
+	}
+	printPushState(status.PendingPush, status.StaleWarning)
+	printHeldDeletes()
+	for _, path := range status.MissingPaths {
+		resultln(out.Warning("Missing: %s (syncing suspended)", path))
+	}
+}
+
+// printPushState shows the local commits the remote is missing (pending < 0 uses the count
+// the last push attempt recorded) and when it last had all of them, with a warning once
+// that is longer ago than sync.stale_warning (threshold 0 = no warning)
+func printPushState(pending int, threshold time.Duration) {
+	status, err := sync.ReadStatus()
+	if err != nil {
+		return
+	}
+	if pending < 0 {
+		pending = status.PendingPush
+	}
+	out := console.Stdout
+	if pending > 0 {
+		resultln(out.Field(0, "Commits pending push", fmt.Sprintf("%d (run 'cursor-sync push')", pending)))
+	}
+	if !status.LastPushed.IsZero() {
+		resultln(out.Field(0, "Last push", status.LastPushed.Local().Format("2006-01-02 15:04:05")))
+	}
+	if age, stale := status.PushStale(threshold, time.Now()); stale {
+		resultln(out.Warning("No successful push for %s (sync.stale_warning: %s) - check 'cursor-sync logs'",
+			humanDuration(age.Round(time.Minute)), humanDuration(threshold)))
+	}
+}
+
+// printHeldDeletes warns about deletions sync.max_deletes / sync.max_delete_percent held back
+func printHeldDeletes() {
+	status, err := sync.ReadStatus()
+	if err != nil {
+		return
+	}
+	for _, operation := range []string{"pull", "push"} {
+		if held, ok := status.HeldDeletes[operation]; ok {
+			resultln(console.Stdout.Warning("Deletions held since %s: %s - run 'cursor-sync sync --confirm-deletes' if intended",
+				held.At.Local().Format("2006-01-02 15:04:05"), held.Detail))
+		}
+	}
+}
+
+// formatInterval shows an effective interval, noting the configured one when overridden
+func formatInterval(effective, configured time.Duration) string {
+	if configured == 0 || effective == configured {
+		return humanDuration(effective)
+	}
+	return fmt.Sprintf("%s (override; config: %s)", humanDuration(effective), humanDuration(configured))
+}
+
+// humanDuration formats a duration without trailing zero units ("5m" instead of "5m0s")
+func humanDuration(d time.Duration) string {
+	s := d.String()
+	if strings.HasSuffix(s, "m0s") {
+		s = strings.TrimSuffix(s, "0s")
+	}
+	if strings.HasSuffix(s, "h0m") {
+		s = strings.TrimSuffix(s, "0m")
+	}
+	return s
+}
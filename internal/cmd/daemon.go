@@ -5,6 +5,7 @@ import (
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
 
 	"github.com/spf13/cobra"
 
@@ -13,6 +14,13 @@ import (
 	"cursor-sync/internal/logger"
 )
 
+var (
+	daemonBranch            string
+	daemonForcePrivacyCheck bool
+	daemonPullInterval      time.Duration
+	daemonPushInterval      time.Duration
+)
+
 // daemonCmd represents the daemon command
 var daemonCmd = &cobra.Command{
 	Use:   "daemon",
@@ -24,7 +32,12 @@ The daemon will:
 - Watch for file changes in real-time
 - Sync changes at configured intervals
 - Handle conflicts by preferring newer commits
-- Log all activities with detailed information`,
+- Log all activities with detailed information
+
+Use --verbose to also mirror the log file to stdout (e.g. for launchd or journald).
+Use --branch to sync against another branch without editing the configuration.
+Use --pull-interval/--push-interval to run a tighter cycle (e.g. 30s) for debugging;
+they take precedence over the configuration, also across config reloads.`,
 	Run: func(cmd *cobra.Command, args []string) {
 		logger.Info("Starting Cursor Sync daemon...")
 
@@ -32,13 +45,31 @@ The daemon will:
 		if err != nil {
 			logger.Fatal("Failed to load configuration: %v", err)
 		}
+		applyBranchOverride(cfg, daemonBranch)
+
+		// Overrides are validated like the configured intervals they replace
+		pullInterval, pushInterval := cfg.Sync.PullInterval, cfg.Sync.PushInterval
+		if cmd.Flags().Changed("pull-interval") {
+			pullInterval = daemonPullInterval
+		}
+		if cmd.Flags().Changed("push-interval") {
+			pushInterval = daemonPushInterval
+		}
+		if err := config.ValidateIntervals(pullInterval, pushInterval); err != nil {
+			logger.Fatal("Invalid interval override: %v", err)
+		}
 
 		// Create daemon instance
-		d, err := daemon.New(cfg)
+		d, err := daemon.New(cfg, verbose)
 		if err != nil {
 			logger.Fatal("Failed to create daemon: %v", err)
 		}
 
+		if daemonForcePrivacyCheck {
+			d.ForcePrivacyCheck()
+		}
+		d.SetIntervalOverrides(daemonPullInterval, daemonPushInterval)
+
 		// Setup signal handling for graceful shutdown
 		ctx, cancel := context.WithCancel(context.Background())
 		sigChan := make(chan os.Signal, 1)
@@ -61,4 +92,9 @@ The daemon will:
 
 func init() {
 	rootCmd.AddCommand(daemonCmd)
+
+	daemonCmd.Flags().StringVar(&daemonBranch, "branch", "", "Sync against this branch instead of the configured one")
+	daemonCmd.Flags().DurationVar(&daemonPullInterval, "pull-interval", 0, "Pull interval overriding sync.pull_interval (e.g. 30s)")
+	daemonCmd.Flags().DurationVar(&daemonPushInterval, "push-interval", 0, "Push interval overriding sync.push_interval (e.g. 30s)")
+	daemonCmd.Flags().BoolVar(&daemonForcePrivacyCheck, "force-privacy-check", false, "Re-check repository privacy with GitHub on startup instead of using the cached result")
 }
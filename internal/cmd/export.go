@@ -0,0 +1,58 @@
+package cmd
+
+import (
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	"cursor-sync/internal/config"
+	"cursor-sync/internal/logger"
+	"cursor-sync/internal/sync"
+)
+
+// exportCmd represents the export command
+var exportCmd = &cobra.Command{
+	Use:   "export <dir>",
+	Short: "Copy the synced settings from the repository into a folder",
+	Long: `Export the settings stored in the sync repository into a folder without
+running the daemon or touching the local Cursor installation.
+
+The repository is cloned (or pulled if it already exists locally) and its
+User/ directory is copied to <dir>/User. When cursor.targets is configured,
+each target is exported to <dir>/<subdir>/User.
+
+Useful when migrating to a new machine before installing Cursor:
+
+  cursor-sync export ~/cursor-settings-backup`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		destDir, err := filepath.Abs(args[0])
+		if err != nil {
+			logger.Fatal("Invalid destination directory: %v", err)
+		}
+
+		cfg, err := config.Load()
+		if err != nil {
+			logger.Fatal("Failed to load configuration: %v", err)
+		}
+
+		syncer, err := sync.New(cfg)
+		if err != nil {
+			logger.Fatal("Failed to create syncer: %v", err)
+		}
+		defer syncer.Close()
+
+		sayf("📦 Exporting settings to %s...\n", destDir)
+		if err := syncer.Export(destDir); err != nil {
+			logger.Error("Export failed: %v", err)
+			errorln("❌ Export failed")
+			return
+		}
+
+		sayln("✅ Settings exported successfully")
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(exportCmd)
+}
@@ -0,0 +1,71 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	"cursor-sync/internal/config"
+	"cursor-sync/internal/logger"
+	"cursor-sync/internal/sync"
+)
+
+var importTarget string
+
+// importCmd represents the import command
+var importCmd = &cobra.Command{
+	Use:   "import <dir>",
+	Short: "Push an existing folder of settings into the repository",
+	Long: `Import a folder of Cursor settings into the sync repository, e.g. a dotfiles
+backup or a folder created with 'cursor-sync export'.
+
+<dir> must contain a User/ directory. It is copied into the repository in place
+of the local Cursor installation (exclude_paths and <dir>/User/.cursorsyncignore
+apply), committed and pushed. Files already in the repository are kept unless
+the import overwrites them; the local Cursor installation is not touched.
+
+Examples:
+  cursor-sync import ~/dotfiles/cursor
+  cursor-sync import ~/backup/Code --target vscode   # With cursor.targets configured`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		srcDir, err := filepath.Abs(args[0])
+		if err != nil {
+			logger.Fatal("Invalid source directory: %v", err)
+		}
+		// Accept the User directory itself as well
+		if filepath.Base(srcDir) == "User" {
+			srcDir = filepath.Dir(srcDir)
+		}
+
+		cfg, err := config.Load()
+		if err != nil {
+			logger.Fatal("Failed to load configuration: %v", err)
+		}
+
+		syncer, err := sync.New(cfg)
+		if err != nil {
+			logger.Fatal("Failed to create syncer: %v", err)
+		}
+		defer syncer.Close()
+		if !quiet {
+			syncer.SetProgressOutput(os.Stderr)
+		}
+
+		sayf("📥 Importing settings from %s...\n", srcDir)
+		if err := syncer.Import(srcDir, importTarget); err != nil {
+			logger.Error("Import failed: %v", err)
+			errorln("❌ Import failed")
+			return
+		}
+
+		sayln("✅ Settings imported successfully")
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(importCmd)
+
+	importCmd.Flags().StringVar(&importTarget, "target", "", "Import into this cursor target (default: the first target)")
+}
@@ -1,7 +1,6 @@
 package cmd
 
 import (
-	"fmt"
 	"os"
 	"path/filepath"
 
@@ -9,6 +8,7 @@ import (
 
 	"cursor-sync/internal/installer"
 	"cursor-sync/internal/logger"
+	"cursor-sync/internal/paths"
 )
 
 var (
@@ -25,7 +25,7 @@ var installCmd = &cobra.Command{
 This command works with both setup and manual configurations:
 
 SETUP FLOW (Recommended):
-- If you ran 'cursor-sync setup', this command will use your existing ~/.cursor-sync/config.yaml
+- If you ran 'cursor-sync setup', this command will use your existing config.yaml
 - No additional configuration needed
 
 MANUAL FLOW:
@@ -49,12 +49,12 @@ Examples:
 	Args: cobra.NoArgs,
 	Run: func(cmd *cobra.Command, args []string) {
 		// Check if user already has a configuration from setup
-		home, err := os.UserHomeDir()
+		configDir, err := paths.ConfigDir()
 		if err != nil {
-			logger.Fatal("Failed to get home directory: %v", err)
+			logger.Fatal("Failed to resolve config directory: %v", err)
 		}
 
-		userConfigPath := filepath.Join(home, ".cursor-sync", "config.yaml")
+		userConfigPath := filepath.Join(configDir, "config.yaml")
 		if _, err := os.Stat(userConfigPath); err == nil {
 			// User has configuration from setup, use that
 			logger.Info("Found existing configuration from setup: %s", userConfigPath)
@@ -66,11 +66,11 @@ Examples:
 				logger.Fatal("Installation failed: %v", err)
 			}
 
-			fmt.Println("✅ Cursor Sync installed successfully!")
-			fmt.Println("📂 Configuration loaded from: ~/.cursor-sync/config.yaml")
-			fmt.Println("🚀 Daemon will start automatically on login")
-			fmt.Println("📋 Use 'cursor-sync status' to check daemon status")
-			fmt.Println("⏸️  Use 'cursor-sync pause' to temporarily stop syncing")
+			sayln("✅ Cursor Sync installed successfully!")
+			sayf("📂 Configuration loaded from: %s\n", userConfigPath)
+			sayln("🚀 Daemon will start automatically on login")
+			sayln("📋 Use 'cursor-sync status' to check daemon status")
+			sayln("⏸️  Use 'cursor-sync pause' to temporarily stop syncing")
 			return
 		}
 
@@ -93,11 +93,11 @@ Examples:
 			logger.Fatal("Installation failed: %v", err)
 		}
 
-		fmt.Println("✅ Cursor Sync installed successfully!")
-		fmt.Println("📂 Configuration loaded from: config/sync.yaml")
-		fmt.Println("🚀 Daemon will start automatically on login")
-		fmt.Println("📋 Use 'cursor-sync status' to check daemon status")
-		fmt.Println("⏸️  Use 'cursor-sync pause' to temporarily stop syncing")
+		sayln("✅ Cursor Sync installed successfully!")
+		sayln("📂 Configuration loaded from: config/sync.yaml")
+		sayln("🚀 Daemon will start automatically on login")
+		sayln("📋 Use 'cursor-sync status' to check daemon status")
+		sayln("⏸️  Use 'cursor-sync pause' to temporarily stop syncing")
 	},
 }
 
@@ -1,12 +1,16 @@
 package cmd
 
 import (
+	"bufio"
 	"fmt"
 	"os"
-	"path/filepath"
+	"os/exec"
 	"time"
 
 	"github.com/spf13/cobra"
+
+	"cursor-sync/internal/config"
+	"cursor-sync/internal/logger"
 )
 
 // logsCmd represents the logs command
@@ -16,7 +20,7 @@ var logsCmd = &cobra.Command{
 	Long: `View cursor-sync logs from the current or previous days.
 
 Examples:
-  cursor-sync logs           # Show today's logs  
+  cursor-sync logs           # Show today's logs
   cursor-sync logs --tail    # Follow logs in real-time
   cursor-sync logs --date 2024-01-15  # Show logs from specific date`,
 	Run: func(cmd *cobra.Command, args []string) {
@@ -25,47 +29,75 @@ Examples:
 		lines, _ := cmd.Flags().GetInt("lines")
 
 		if err := viewLogs(tail, date, lines); err != nil {
-			fmt.Printf("❌ Failed to view logs: %v\n", err)
+			errorf("❌ Failed to view logs: %v\n", err)
 		}
 	},
 }
 
 func viewLogs(tail bool, date string, lines int) error {
-	home, err := os.UserHomeDir()
+	logsDir, err := config.LogDir()
 	if err != nil {
-		return fmt.Errorf("failed to get home directory: %w", err)
+		return err
 	}
 
-	logsDir := filepath.Join(home, ".cursor-sync", "logs")
-
 	// Determine log file
-	var logFile string
+	day := time.Now()
 	if date != "" {
-		logFile = filepath.Join(logsDir, date+".log")
-	} else {
-		// Today's log
-		today := time.Now().Format("2006-01-02")
-		logFile = filepath.Join(logsDir, today+".log")
+		day, err = logger.ParseLogDate(date)
+		if err != nil {
+			return fmt.Errorf("invalid date %q (expected YYYY-MM-DD): %w", date, err)
+		}
 	}
+	logFile := logger.LogFilePath(logsDir, day)
 
 	// Check if log file exists
 	if _, err := os.Stat(logFile); os.IsNotExist(err) {
-		fmt.Printf("📄 No logs found for %s\n", date)
-		fmt.Printf("Log file: %s\n", logFile)
+		resultf("📄 No logs found for %s\n", day.Format("2006-01-02"))
+		resultf("Log file: %s\n", logFile)
 		return nil
 	}
 
-	fmt.Printf("📋 Viewing logs: %s\n", logFile)
-	fmt.Println()
+	sayf("📋 Viewing logs: %s\n", logFile)
+	sayln()
 
 	if tail {
-		// Follow logs in real-time
-		fmt.Println("Following logs (press Ctrl+C to exit)...")
-		fmt.Printf("tail -f %s\n", logFile)
-	} else {
-		// Show last N lines
-		fmt.Printf("Showing last %d lines:\n", lines)
-		fmt.Printf("tail -%d %s\n", lines, logFile)
+		// Follow logs in real-time, by name so output continues after a size rotation
+		// moves the file aside
+		sayln("Following logs (press Ctrl+C to exit)...")
+		tailCmd := exec.Command("tail", "-n", fmt.Sprintf("%d", lines), "-F", logFile)
+		tailCmd.Stdout = os.Stdout
+		tailCmd.Stderr = os.Stderr
+		return tailCmd.Run()
+	}
+
+	// Show last N lines
+	sayf("Showing last %d lines:\n", lines)
+	return printLastLines(logFile, lines)
+}
+
+// printLastLines prints the last n lines of a file
+func printLastLines(path string, n int) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open log file: %w", err)
+	}
+	defer file.Close()
+
+	var buffer []string
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		buffer = append(buffer, scanner.Text())
+		if n > 0 && len(buffer) > n {
+			buffer = buffer[1:]
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read log file: %w", err)
+	}
+
+	for _, line := range buffer {
+		resultln(line)
 	}
 
 	return nil
@@ -76,4 +108,15 @@ func init() {
 	logsCmd.Flags().BoolP("tail", "f", false, "Follow logs in real-time")
 	logsCmd.Flags().StringP("date", "d", "", "Show logs from specific date (YYYY-MM-DD)")
 	logsCmd.Flags().IntP("lines", "n", 50, "Number of lines to show")
+
+	logsCmd.RegisterFlagCompletionFunc("date", completeLogDates)
+}
+
+// completeLogDates offers the dates that have logs for --date
+func completeLogDates(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	logsDir, err := config.LogDir()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+	return logger.LogDates(logsDir), cobra.ShellCompDirectiveNoFileComp
 }
@@ -0,0 +1,126 @@
+package cmd
+
+import (
+	"bufio"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+
+	"cursor-sync/internal/config"
+	"cursor-sync/internal/console"
+	"cursor-sync/internal/daemon"
+	"cursor-sync/internal/logger"
+	"cursor-sync/internal/sync"
+)
+
+// markerResetConfirmation must be typed to confirm --reset
+const markerResetConfirmation = "overwrite"
+
+var (
+	markerStatus bool
+	markerReset  bool
+	markerYes    bool
+)
+
+// markerCmd represents the marker command
+var markerCmd = &cobra.Command{
+	Use:   "marker",
+	Short: "Show or reset the marker that protects local settings",
+	Long: `Show or reset the sync marker (.custom.sync in each Cursor config folder).
+
+The marker records that this machine's settings have been synced before. Without
+it, the next sync treats local settings as never synced and OVERWRITES them from
+the repository (a publishing mirror pushes them instead).
+
+--status (the default) shows whether each sync target has a marker and when it
+last synced. --reset deletes the markers to force that fresh overwrite; it asks
+you to type '` + markerResetConfirmation + `' first, or takes --yes in scripts. The
+overwrite happens on the next sync or daemon start and replaces every local file,
+including ones changed after the repository's last commit (they are backed up).`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg, err := config.Load()
+		if err != nil {
+			logger.Fatal("Failed to load configuration: %v", err)
+		}
+
+		if !markerReset {
+			printMarkerStatus(sync.SyncMarkers(cfg))
+			return
+		}
+
+		if !markerYes {
+			// Without a terminal there is nobody to ask
+			if !term.IsTerminal(int(os.Stdin.Fd())) {
+				errorln("❌ --reset needs confirmation - run it in a terminal or pass --yes")
+				os.Exit(1)
+			}
+			if !confirmMarkerReset() {
+				errorln("❌ Reset cancelled - markers left in place")
+				os.Exit(1)
+			}
+		}
+
+		removed, err := sync.ResetSyncMarkers(cfg)
+		if err != nil {
+			logger.Fatal("Failed to reset sync marker: %v", err)
+		}
+		if len(removed) == 0 {
+			sayln("ℹ️  No sync marker to remove")
+		}
+		for _, path := range removed {
+			resultf("Removed %s\n", path)
+		}
+		sayln("🔄 The next sync overwrites all local settings from the repository")
+
+		// A running daemon only reads the markers when it starts
+		if _, err := daemon.SendControl(daemon.CommandStatus, controlTimeout); err == nil {
+			sayln("💡 Restart the daemon to apply it now: cursor-sync stop && cursor-sync start")
+		}
+	},
+}
+
+// printMarkerStatus prints the sync marker of every sync target
+func printMarkerStatus(statuses []sync.MarkerStatus) {
+	out := console.Stdout
+	for _, status := range statuses {
+		if len(statuses) > 1 {
+			resultln(out.Heading("🎯", "Target "+status.Target))
+		}
+		resultln(out.Field(0, "Marker", status.Path))
+		if !status.Exists {
+			resultln(out.Field(0, "Status", out.Bad("missing - the next sync overwrites local settings from the repository")))
+			continue
+		}
+		resultln(out.Field(0, "Status", out.Good("present")))
+		if status.LastSync != "" {
+			resultln(out.Field(0, "Last sync", status.LastSync))
+		}
+		resultln(out.Field(0, "Updated", status.ModTime.Format("2006-01-02 15:04:05")))
+		if status.Repository != "" {
+			resultln(out.Field(0, "Repository", status.Repository))
+		}
+	}
+}
+
+// confirmMarkerReset asks the user to type the confirmation word
+func confirmMarkerReset() bool {
+	errorln("⚠️  Resetting the sync marker makes the next sync OVERWRITE this machine's")
+	errorln("   Cursor settings with the repository's. Local changes that were never pushed are lost.")
+	errorf("Type '%s' to continue: ", markerResetConfirmation)
+
+	reader := bufio.NewReader(os.Stdin)
+	answer, _ := reader.ReadString('\n')
+	return strings.TrimSpace(answer) == markerResetConfirmation
+}
+
+func init() {
+	rootCmd.AddCommand(markerCmd)
+
+	markerCmd.Flags().BoolVar(&markerStatus, "status", false, "Show whether each sync target has a marker (default)")
+	markerCmd.Flags().BoolVar(&markerReset, "reset", false, "Delete the markers so the next sync overwrites local settings from the repository")
+	markerCmd.Flags().BoolVarP(&markerYes, "yes", "y", false, "Skip the --reset confirmation")
+	markerCmd.MarkFlagsMutuallyExclusive("status", "reset")
+}
@@ -0,0 +1,116 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"cursor-sync/internal/auth"
+	"cursor-sync/internal/config"
+	"cursor-sync/internal/git"
+	"cursor-sync/internal/logger"
+	"cursor-sync/internal/privacy"
+)
+
+// migrateCmd represents the migrate command
+var migrateCmd = &cobra.Command{
+	Use:   "migrate <new-repository-url>",
+	Short: "Move syncing to a renamed or transferred repository",
+	Long: `Point cursor-sync at a new repository URL after the GitHub repository was
+renamed or transferred.
+
+Unlike 'cursor-sync repo', which only edits the configuration files, migrate
+also re-points the existing local clone's origin remote. The new repository
+must be private and reachable: its privacy is verified and a fetch is made
+before anything is changed for good. If the fetch fails the clone keeps its
+old remote and the configuration is left untouched.
+
+Restart the daemon afterwards so it picks up the new repository.
+
+Example:
+  cursor-sync migrate https://github.com/new-owner/cursor-settings.git`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		newURL := strings.TrimSpace(args[0])
+		if newURL == "" {
+			logger.Fatal("Repository URL cannot be empty")
+		}
+
+		cfg, err := config.Load()
+		if err != nil {
+			logger.Fatal("Failed to load configuration: %v", err)
+		}
+
+		sayf("🔒 Verifying %s is private...\n", newURL)
+		endpoint := auth.NewEndpoint(newURL, cfg.Repository.APIBaseURL, cfg.Network.Timeout)
+		isPrivate, err := privacy.NewRepositoryChecker(endpoint).CheckRepositoryPrivacy(newURL)
+		if err != nil {
+			privacy.ShowPrivacyCheckError(newURL, err)
+			logger.Fatal("Cannot verify repository privacy - migration aborted")
+		}
+		if !isPrivate {
+			privacy.ShowPrivacyWarning(newURL)
+			logger.Fatal("Public repository detected - migration aborted")
+		}
+
+		if err := migrateClone(cfg, newURL); err != nil {
+			logger.Fatal("Migration failed: %v", err)
+		}
+
+		if err := config.UpdateRepositoryURL(newURL); err != nil {
+			logger.Fatal("Failed to update repository URL in configuration: %v", err)
+		}
+
+		sayln("✅ Migrated to the new repository")
+		sayln("🔄 Restart the daemon to sync with it: cursor-sync stop && cursor-sync start")
+	},
+}
+
+// migrateClone re-points the local clone's origin at newURL and fetches to confirm it works
+// Without a local clone there is nothing to re-point; the next sync clones the new URL
+func migrateClone(cfg *config.Config, newURL string) error {
+	if _, err := os.Stat(filepath.Join(cfg.Repository.LocalPath, ".git")); os.IsNotExist(err) {
+		sayln("ℹ️  No local clone yet - only the configuration is updated")
+		return nil
+	}
+
+	// Opened with the configured URL so the current remote is what gets replaced
+	repo, err := git.New(cfg.Repository.LocalPath, "origin", cfg.Repository.Branch, cfg.Repository.URL, cfg.GitHubEndpoint())
+	if err != nil {
+		return err
+	}
+	if err := repo.Open(); err != nil {
+		return err
+	}
+
+	oldURL, err := repo.RemoteURL()
+	if err != nil {
+		return err
+	}
+	if oldURL == newURL {
+		sayln("ℹ️  Local clone already points at this repository")
+		return nil
+	}
+
+	sayf("🔀 Re-pointing local clone from %s\n", oldURL)
+	if err := repo.SetRemoteURL(newURL); err != nil {
+		return err
+	}
+
+	sayln("📥 Fetching from the new repository...")
+	if err := repo.Fetch(); err != nil {
+		if restoreErr := repo.SetRemoteURL(oldURL); restoreErr != nil {
+			logger.Error("Failed to restore remote %s: %v", oldURL, restoreErr)
+		}
+		return fmt.Errorf("new repository is not reachable (remote restored): %w", err)
+	}
+
+	return nil
+}
+
+func init() {
+	rootCmd.AddCommand(migrateCmd)
+}
@@ -0,0 +1,45 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+)
+
+// quiet is set by --quiet: decorative output is suppressed, results and errors remain
+var quiet bool
+
+// sayf prints decorative output - banners, progress, hints and confirmations - unless
+// --quiet is set
+func sayf(format string, args ...interface{}) {
+	if !quiet {
+		fmt.Printf(format, args...)
+	}
+}
+
+// sayln is sayf with fmt.Println formatting
+func sayln(args ...interface{}) {
+	if !quiet {
+		fmt.Println(args...)
+	}
+}
+
+// resultf prints what the command was run for (status, statistics, file lists), which
+// scripts rely on, so --quiet doesn't suppress it
+func resultf(format string, args ...interface{}) {
+	fmt.Printf(format, args...)
+}
+
+// resultln is resultf with fmt.Println formatting
+func resultln(args ...interface{}) {
+	fmt.Println(args...)
+}
+
+// errorf prints a failure to stderr, with or without --quiet
+func errorf(format string, args ...interface{}) {
+	fmt.Fprintf(os.Stderr, format, args...)
+}
+
+// errorln is errorf with fmt.Println formatting
+func errorln(args ...interface{}) {
+	fmt.Fprintln(os.Stderr, args...)
+}
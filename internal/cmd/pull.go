@@ -0,0 +1,96 @@
+package cmd
+
+import (
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"cursor-sync/internal/config"
+	"cursor-sync/internal/daemon"
+	"cursor-sync/internal/logger"
+)
+
+var (
+	pullConfirmDeletes bool
+	pullForce          bool
+	pullInclude        []string
+	pullExclude        []string
+)
+
+// pullCmd represents the pull command
+var pullCmd = &cobra.Command{
+	Use:   "pull",
+	Short: "Apply remote settings without pushing local changes",
+	Long: `Pull remote changes and apply them to the local settings - the first half of
+'cursor-sync sync'. Local changes are neither committed nor pushed, so half-edited
+settings stay on this machine.
+
+The pull runs inside the daemon when one is running, so it never overlaps with
+one of its syncs. Otherwise it runs standalone; on a machine that has never
+synced, local settings are overwritten from the repository.
+
+Use --force to re-hash every file instead of trusting cached hashes, restoring local
+files that drifted from the repository without the change detection noticing.
+
+Use --include/--exclude (repeatable) to apply only some remote files this time,
+e.g. --include User/keybindings.json. Patterns work like cursor.exclude_paths;
+local files outside the scope are neither overwritten nor deleted.
+
+Exits with status 1 when the pull fails.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg, err := config.Load()
+		if err != nil {
+			logger.Fatal("Failed to load configuration: %v", err)
+		}
+		if !cfg.Sync.Pulls() {
+			logger.Fatal("sync.mode %s never applies remote changes", cfg.Sync.Mode)
+		}
+
+		command := daemon.CommandPull
+		if pullConfirmDeletes {
+			command += " " + daemon.ArgConfirmDeletes
+		}
+		if pullForce {
+			command += " " + daemon.ArgForce
+		}
+		command += scopeArguments(pullInclude, pullExclude)
+		response, err := daemon.SendControl(command, syncNowTimeout)
+		if err == nil {
+			sayf("✅ Daemon %s\n", response.Message)
+			return
+		}
+		if err != daemon.ErrDaemonNotRunning {
+			logger.Error("Daemon pull failed: %v", err)
+			os.Exit(1)
+		}
+
+		// A pull-only process must never push, not even during a first-time initialization
+		cfg.Sync.Mode = config.ModeMirrorPull
+		syncer := newStandaloneSyncer(cfg, false, pullConfirmDeletes)
+		if pullForce {
+			syncer.ForcePull()
+		}
+		if len(pullInclude) > 0 || len(pullExclude) > 0 {
+			syncer.SetScope(pullInclude, pullExclude)
+		}
+
+		sayln("📥 Pulling remote changes...")
+		err = syncer.SyncFromRemote()
+		syncer.Close()
+		if err != nil {
+			logger.Error("Failed to pull remote changes: %v", err)
+			errorln("❌ Pull failed")
+			os.Exit(1)
+		}
+		sayln("✅ Remote changes pulled successfully")
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(pullCmd)
+
+	pullCmd.Flags().BoolVar(&pullConfirmDeletes, "confirm-deletes", false, "Propagate deletions even when they exceed sync.max_deletes or sync.max_delete_percent")
+	pullCmd.Flags().BoolVar(&pullForce, "force", false, "Re-hash every file instead of trusting cached hashes")
+	pullCmd.Flags().StringArrayVar(&pullInclude, "include", nil, "Only apply remote files matching this pattern (repeatable)")
+	pullCmd.Flags().StringArrayVar(&pullExclude, "exclude", nil, "Leave files matching this pattern out of this pull (repeatable)")
+}
@@ -0,0 +1,122 @@
+package cmd
+
+import (
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"cursor-sync/internal/config"
+	"cursor-sync/internal/daemon"
+	"cursor-sync/internal/logger"
+)
+
+// pushTimeout bounds how long push waits for the daemon to finish pushing
+const pushTimeout = 5 * time.Minute
+
+var (
+	pushCommitted      bool
+	pushConfirmDeletes bool
+	pushForce          bool
+	pushInclude        []string
+	pushExclude        []string
+)
+
+// pushCmd represents the push command
+var pushCmd = &cobra.Command{
+	Use:   "push",
+	Short: "Push local changes without pulling remote ones",
+	Long: `Commit the local settings and push them - the second half of 'cursor-sync sync'.
+Remote changes are not applied locally (a push rejected because the remote has
+moved on is still resolved with sync.conflict_resolve).
+
+Commits held back by sync.auto_push: false are pushed too. With --committed only
+those existing commits are pushed and the current local settings aren't
+committed, e.g. after reviewing what was captured; 'cursor-sync status' shows
+how many commits are waiting.
+
+Use --force when local changes seem to be missing from the repository: every file
+is re-hashed instead of trusting cached hashes, and unpushed commits are pushed
+even when nothing else changed.
+
+Use --include/--exclude (repeatable) to push only some files this time, e.g.
+--include User/settings.json. Patterns work like cursor.exclude_paths; files
+outside the scope are neither committed nor deleted in the repository.
+
+The push runs inside the daemon when one is running, so it never overlaps with
+one of its syncs. Exits with status 1 when commits could not be pushed.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg, err := config.Load()
+		if err != nil {
+			logger.Fatal("Failed to load configuration: %v", err)
+		}
+		if !cfg.Sync.Pushes() {
+			logger.Fatal("sync.mode %s never pushes", cfg.Sync.Mode)
+		}
+
+		command := daemon.CommandPush
+		if pushCommitted {
+			command += " " + daemon.ArgCommitted
+		}
+		if pushConfirmDeletes {
+			command += " " + daemon.ArgConfirmDeletes
+		}
+		if pushForce {
+			command += " " + daemon.ArgForce
+		}
+		command += scopeArguments(pushInclude, pushExclude)
+		response, err := daemon.SendControl(command, pushTimeout)
+		if err == nil {
+			sayf("✅ Daemon %s\n", response.Message)
+			return
+		}
+		if err != daemon.ErrDaemonNotRunning {
+			logger.Error("Daemon push failed: %v", err)
+			os.Exit(1)
+		}
+
+		syncer := newStandaloneSyncer(cfg, false, pushConfirmDeletes)
+
+		if pushCommitted {
+			pushed, err := syncer.PushPending()
+			syncer.Close()
+			if err != nil {
+				logger.Error("Failed to push commits: %v", err)
+				errorln("❌ Push failed")
+				os.Exit(1)
+			}
+			sayf("✅ Pushed %d commit(s)\n", pushed)
+			return
+		}
+
+		if pushForce {
+			syncer.ForcePush()
+		}
+		if len(pushInclude) > 0 || len(pushExclude) > 0 {
+			syncer.SetScope(pushInclude, pushExclude)
+		}
+
+		sayln("📤 Pushing local changes...")
+		err = syncer.PushLocalChanges()
+		syncer.Close()
+		if err != nil {
+			logger.Error("Failed to push local changes: %v", err)
+			errorln("❌ Push failed")
+			os.Exit(1)
+		}
+		sayln("✅ Local changes pushed successfully")
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(pushCmd)
+
+	pushCmd.Flags().BoolVar(&pushCommitted, "committed", false, "Only push existing commits, without committing the current local settings")
+	pushCmd.Flags().BoolVar(&pushConfirmDeletes, "confirm-deletes", false, "Propagate deletions even when they exceed sync.max_deletes or sync.max_delete_percent")
+	pushCmd.Flags().BoolVar(&pushForce, "force", false, "Re-hash every file and push even when no changes are detected")
+	pushCmd.Flags().StringArrayVar(&pushInclude, "include", nil, "Only push files matching this pattern (repeatable)")
+	pushCmd.Flags().StringArrayVar(&pushExclude, "exclude", nil, "Leave files matching this pattern out of this push (repeatable)")
+	pushCmd.MarkFlagsMutuallyExclusive("committed", "force")
+	pushCmd.MarkFlagsMutuallyExclusive("committed", "include")
+	pushCmd.MarkFlagsMutuallyExclusive("committed", "exclude")
+}
@@ -1,7 +1,6 @@
 package cmd
 
 import (
-	"fmt"
 	"strings"
 
 	"github.com/spf13/cobra"
@@ -16,7 +15,7 @@ var repoCmd = &cobra.Command{
 	Short: "Update repository URL in all configuration files",
 	Long: `Update the repository URL in all configuration files.
 
-This command will automatically update both the user's config file (~/.cursor-sync/config.yaml)
+This command will automatically update both the user's config file (config.yaml in the config directory)
 and the project's config file (config/sync.yaml) with the provided repository URL.
 
 Examples:
@@ -30,14 +29,14 @@ Examples:
 			logger.Fatal("Repository URL cannot be empty")
 		}
 
-		fmt.Printf("🔄 Updating repository URL to: %s\n", repoURL)
+		sayf("🔄 Updating repository URL to: %s\n", repoURL)
 
 		if err := config.UpdateRepositoryURL(repoURL); err != nil {
 			logger.Fatal("Failed to update repository URL: %v", err)
 		}
 
-		fmt.Println("✅ Repository URL updated successfully in all configuration files!")
-		fmt.Println("🚀 You can now run 'cursor-sync sync' to start syncing")
+		sayln("✅ Repository URL updated successfully in all configuration files!")
+		sayln("🚀 You can now run 'cursor-sync sync' to start syncing")
 	},
 }
 
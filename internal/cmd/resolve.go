@@ -0,0 +1,188 @@
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+
+	"cursor-sync/internal/config"
+	"cursor-sync/internal/git"
+	"cursor-sync/internal/logger"
+)
+
+// resolveList only lists the conflicted files
+var resolveList bool
+
+// resolveCmd represents the resolve command
+var resolveCmd = &cobra.Command{
+	Use:   "resolve",
+	Short: "Resolve merge conflicts left in the local repository",
+	Long: `Settle files a merge left conflicted in the local clone, e.g. after merging
+by hand with git. Syncing stops while any file is unmerged.
+
+For every conflicted file choose the local version, the remote version, or
+edit the file (with its conflict markers) in $VISUAL or $EDITOR. Files can be
+skipped and resolved in a later run. Once none is left the merge is committed;
+run 'cursor-sync sync' afterwards to apply the result to Cursor and push it.
+
+Examples:
+  cursor-sync resolve          # Resolve conflicted files one by one
+  cursor-sync resolve --list   # Only list conflicted files`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg, err := config.Load()
+		if err != nil {
+			logger.Fatal("Failed to load configuration: %v", err)
+		}
+
+		repo, err := git.New(cfg.Repository.LocalPath, "origin", cfg.Repository.Branch, cfg.Repository.URL, cfg.GitHubEndpoint())
+		if err != nil {
+			logger.Fatal("Failed to open repository: %v", err)
+		}
+		if err := repo.Open(); err != nil {
+			logger.Fatal("Failed to open repository: %v", err)
+		}
+
+		files, err := repo.UnmergedFiles()
+		if err != nil {
+			logger.Fatal("Failed to read merge state: %v", err)
+		}
+		if len(files) == 0 {
+			resultln("✅ No unresolved conflicts")
+			return
+		}
+
+		resultf("⚔️  %d conflicted file(s):\n", len(files))
+		for _, file := range files {
+			resultf("   - %s\n", file.Path)
+		}
+		if resolveList {
+			return
+		}
+		if !term.IsTerminal(int(os.Stdin.Fd())) {
+			errorln("❌ Resolving conflicts needs an interactive terminal")
+			os.Exit(1)
+		}
+
+		reader := bufio.NewReader(os.Stdin)
+		skipped := 0
+		for _, file := range files {
+			content, resolved, err := chooseResolution(reader, cfg.Repository.LocalPath, file)
+			if err != nil {
+				logger.Fatal("Failed to resolve %s: %v", file.Path, err)
+			}
+			if !resolved {
+				skipped++
+				continue
+			}
+			if err := repo.ResolveFile(file.Path, content); err != nil {
+				logger.Fatal("Failed to resolve %s: %v", file.Path, err)
+			}
+			sayf("✅ Resolved %s\n", file.Path)
+		}
+
+		if skipped > 0 {
+			resultf("⏸️  %d file(s) still unresolved - run 'cursor-sync resolve' again to finish\n", skipped)
+			return
+		}
+		if err := repo.CompleteMerge(cfg.Sync.CommitAuthor()); err != nil {
+			logger.Fatal("Failed to complete merge: %v", err)
+		}
+		resultln("🎉 Merge completed")
+		sayln("🔄 Run 'cursor-sync sync' to apply it to Cursor and push it")
+	},
+}
+
+// conflictMarker starts the local side of a conflict in a file git merged with markers
+const conflictMarker = "<<<<<<<"
+
+// chooseResolution asks which version of file to keep and returns its content (nil when
+// the chosen side deleted the file), or resolved false when the user skips it
+func chooseResolution(reader *bufio.Reader, clonePath string, file git.UnmergedFile) ([]byte, bool, error) {
+	fmt.Println()
+	fmt.Printf("📄 %s\n", file.Path)
+	fmt.Printf("   Local:  %s\n", describeVersion(file.Local))
+	fmt.Printf("   Remote: %s\n", describeVersion(file.Remote))
+
+	for {
+		fmt.Print("Keep (l)ocal, (r)emote, (e)dit or (s)kip? ")
+		answer, err := reader.ReadString('\n')
+		if err != nil {
+			return nil, false, fmt.Errorf("no answer: %w", err)
+		}
+
+		switch strings.ToLower(strings.TrimSpace(answer)) {
+		case "l", "local":
+			return file.Local, true, nil
+		case "r", "remote":
+			return file.Remote, true, nil
+		case "s", "skip":
+			return nil, false, nil
+		case "e", "edit":
+			content, err := editConflict(filepath.Join(clonePath, filepath.FromSlash(file.Path)), file)
+			if err != nil {
+				return nil, false, err
+			}
+			if bytes.Contains(content, []byte(conflictMarker)) {
+				fmt.Println("⚠️  The file still contains conflict markers")
+				continue
+			}
+			return content, true, nil
+		}
+	}
+}
+
+// describeVersion summarizes one side of a conflicted file
+func describeVersion(content []byte) string {
+	if content == nil {
+		return "deleted"
+	}
+	return fmt.Sprintf("%d bytes", len(content))
+}
+
+// editConflict opens the conflicted file in the user's editor and returns what was saved.
+// A file git didn't leave in the clone (one side deleted it) starts from the other side
+func editConflict(path string, file git.UnmergedFile) ([]byte, error) {
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		content := file.Local
+		if content == nil {
+			content = file.Remote
+		}
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return nil, err
+		}
+		if err := os.WriteFile(path, content, 0644); err != nil {
+			return nil, err
+		}
+	}
+
+	editor := os.Getenv("VISUAL")
+	if editor == "" {
+		editor = os.Getenv("EDITOR")
+	}
+	if editor == "" {
+		editor = "vi"
+	}
+	// The editor may carry arguments, e.g. "code --wait"
+	fields := strings.Fields(editor)
+	editCmd := exec.Command(fields[0], append(fields[1:], path)...)
+	editCmd.Stdin = os.Stdin
+	editCmd.Stdout = os.Stdout
+	editCmd.Stderr = os.Stderr
+	if err := editCmd.Run(); err != nil {
+		return nil, fmt.Errorf("editor %s failed: %w", editor, err)
+	}
+	return os.ReadFile(path)
+}
+
+func init() {
+	rootCmd.AddCommand(resolveCmd)
+
+	resolveCmd.Flags().BoolVar(&resolveList, "list", false, "Only list conflicted files")
+}
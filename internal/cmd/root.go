@@ -1,19 +1,21 @@
 package cmd
 
 import (
-	"fmt"
 	"os"
+	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 
 	"cursor-sync/internal/config"
 	"cursor-sync/internal/logger"
+	"cursor-sync/internal/paths"
 )
 
 var (
-	cfgFile string
-	verbose bool
+	cfgFile        string
+	verbose        bool
+	networkTimeout time.Duration
 )
 
 // rootCmd represents the base command when called without any subcommands
@@ -34,6 +36,9 @@ Features:
 	PersistentPreRun: func(cmd *cobra.Command, args []string) {
 		// Initialize logger
 		logger.Init(verbose)
+		if quiet {
+			logger.Quiet()
+		}
 	},
 }
 
@@ -49,25 +54,27 @@ func init() {
 	cobra.OnInitialize(initConfig)
 
 	// Global flags
-	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default is $HOME/.cursor-sync/config.yaml)")
+	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default is config.yaml in ~/.cursor-sync, or $XDG_CONFIG_HOME/cursor-sync on Linux)")
 	rootCmd.PersistentFlags().BoolVarP(&verbose, "verbose", "v", false, "verbose output")
+	rootCmd.PersistentFlags().BoolVarP(&quiet, "quiet", "q", false, "only print errors and results (no banners, progress or hints)")
+	rootCmd.MarkFlagsMutuallyExclusive("verbose", "quiet")
+	rootCmd.PersistentFlags().DurationVar(&networkTimeout, "timeout", 0, "limit for each GitHub API call, and for git clone/fetch/pull/push without network activity (default network.timeout from the config)")
 
 	// Bind flags to viper
 	viper.BindPFlag("verbose", rootCmd.PersistentFlags().Lookup("verbose"))
+	viper.BindPFlag("network.timeout", rootCmd.PersistentFlags().Lookup("timeout"))
 }
 
 // initConfig reads in config file and ENV variables
 func initConfig() {
 	if cfgFile != "" {
-		// Use config file from the flag
+		// Use config file from the flag; config.Load (daemon, sync, ...) reads it too
+		config.SetConfigFile(cfgFile)
 		viper.SetConfigFile(cfgFile)
 	} else {
-		// Find home directory
-		home, err := os.UserHomeDir()
+		// Search config.yaml in the config directory
+		configDir, err := paths.ConfigDir()
 		cobra.CheckErr(err)
-
-		// Search config in home directory with name ".cursor-sync" (without extension)
-		configDir := fmt.Sprintf("%s/.cursor-sync", home)
 		viper.AddConfigPath(configDir)
 		viper.SetConfigType("yaml")
 		viper.SetConfigName("config")
@@ -78,7 +85,7 @@ func initConfig() {
 	// If a config file is found, read it in
 	if err := viper.ReadInConfig(); err == nil {
 		logger.Debug("Using config file: %s", viper.ConfigFileUsed())
-	} else {
+	} else if cfgFile == "" {
 		// Create default config if none exists
 		if err := config.CreateDefaultConfig(); err != nil {
 			logger.Error("Failed to create default config: %v", err)
@@ -1,7 +1,7 @@
 package cmd
 
 import (
-	"fmt"
+	"os"
 
 	"github.com/spf13/cobra"
 
@@ -9,6 +9,14 @@ import (
 	"cursor-sync/internal/logger"
 )
 
+var (
+	setupNonInteractive bool
+	setupRepoURL        string
+	setupBranch         string
+	setupTokenEnv       string
+	setupCursorPath     string
+)
+
 // setupCmd represents the setup command
 var setupCmd = &cobra.Command{
 	Use:   "setup",
@@ -19,25 +27,76 @@ This wizard will guide you through:
 - Configuring your IDE installation path (Cursor, VS Code, or custom)
 - Setting up your GitHub Personal Access Token
 - Configuring your Git repository for settings storage (cursor-sync-bucket recommended)
-- Validating repository privacy and accessibility  
+- Validating repository privacy and accessibility
 - Creating necessary configuration files
 
-The setup wizard is also automatically triggered when required settings are missing.`,
+The setup wizard is also automatically triggered when required settings are missing.
+
+For automated provisioning (Ansible, Docker, ...) use --non-interactive. Values can
+also be provided through CURSOR_SYNC_REPO, CURSOR_SYNC_BRANCH and CURSOR_SYNC_CURSOR_PATH.
+
+Examples:
+  cursor-sync setup
+  GITHUB_TOKEN=ghp_... cursor-sync setup --non-interactive \
+    --repo https://github.com/username/cursor-sync-bucket.git \
+    --token-env GITHUB_TOKEN --cursor-path ~/.config/Cursor`,
 	Run: func(cmd *cobra.Command, args []string) {
+		wizard := interactive.NewSetupWizard(networkTimeout)
+
+		if setupNonInteractive {
+			logger.Info("Starting non-interactive setup...")
+
+			opts := interactive.NonInteractiveOptions{
+				RepoURL:    flagOrEnv(setupRepoURL, "CURSOR_SYNC_REPO"),
+				Branch:     flagOrEnv(setupBranch, "CURSOR_SYNC_BRANCH"),
+				CursorPath: flagOrEnv(setupCursorPath, "CURSOR_SYNC_CURSOR_PATH"),
+			}
+			if setupTokenEnv != "" {
+				opts.Token = os.Getenv(setupTokenEnv)
+				if opts.Token == "" {
+					errorf("❌ Setup failed: environment variable %s is empty or not set\n", setupTokenEnv)
+					os.Exit(1)
+				}
+			}
+
+			if err := wizard.RunNonInteractiveSetup(opts); err != nil {
+				errorf("❌ Setup failed: %v\n", err)
+				logger.Error("Non-interactive setup failed: %v", err)
+				os.Exit(1)
+			}
+
+			sayln("🎉 Setup completed successfully!")
+			logger.Info("Non-interactive setup completed successfully")
+			return
+		}
+
 		logger.Info("Starting interactive setup wizard...")
 
-		wizard := interactive.NewSetupWizard()
 		if err := wizard.RunInteractiveSetup(); err != nil {
-			fmt.Printf("❌ Setup failed: %v\n", err)
+			errorf("❌ Setup failed: %v\n", err)
 			logger.Error("Interactive setup failed: %v", err)
 			return
 		}
 
-		fmt.Println("🎉 Interactive setup completed successfully!")
+		sayln("🎉 Interactive setup completed successfully!")
 		logger.Info("Interactive setup completed successfully")
 	},
 }
 
+// flagOrEnv returns the flag value, falling back to the given environment variable
+func flagOrEnv(value, envVar string) string {
+	if value != "" {
+		return value
+	}
+	return os.Getenv(envVar)
+}
+
 func init() {
 	rootCmd.AddCommand(setupCmd)
+
+	setupCmd.Flags().BoolVar(&setupNonInteractive, "non-interactive", false, "Run setup without prompts (for automated provisioning)")
+	setupCmd.Flags().StringVar(&setupRepoURL, "repo", "", "Repository URL (non-interactive mode, env: CURSOR_SYNC_REPO)")
+	setupCmd.Flags().StringVar(&setupBranch, "branch", "", "Repository branch (non-interactive mode, env: CURSOR_SYNC_BRANCH)")
+	setupCmd.Flags().StringVar(&setupTokenEnv, "token-env", "", "Name of the environment variable holding the GitHub token (non-interactive mode)")
+	setupCmd.Flags().StringVar(&setupCursorPath, "cursor-path", "", "Cursor config path (non-interactive mode, env: CURSOR_SYNC_CURSOR_PATH)")
 }
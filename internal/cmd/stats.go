@@ -0,0 +1,74 @@
+package cmd
+
+import (
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"cursor-sync/internal/config"
+	"cursor-sync/internal/logger"
+	"cursor-sync/internal/sync"
+)
+
+// statsCmd represents the stats command
+var statsCmd = &cobra.Command{
+	Use:   "stats",
+	Short: "Summarize repository and sync health",
+	Long: `Show a summary of the settings repository and recent syncs:
+- files tracked in the local clone, synced files and their size
+- size of the clone on disk and its number of commits
+- local files skipped by the exclusion rules
+- when the last sync finished and how long pulls and pushes take on average
+
+Sync times come from status.json in the state directory, which every pull and push
+(by the daemon or by "cursor-sync sync") updates. Nothing is synced or changed.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg, err := config.Load()
+		if err != nil {
+			logger.Fatal("Failed to load configuration: %v", err)
+		}
+
+		syncer, err := sync.New(cfg)
+		if err != nil {
+			logger.Fatal("Failed to create syncer: %v", err)
+		}
+
+		stats, err := syncer.Stats()
+		syncer.Close()
+		if err != nil {
+			logger.Fatal("Failed to collect statistics: %v", err)
+		}
+
+		resultf("📦 Repository: %s (branch %s)\n", cfg.Repository.URL, cfg.Repository.Branch)
+		resultf("   📄 Tracked files: %d\n", stats.TrackedFiles)
+		resultf("   🔄 Synced files: %d (%s)\n", stats.SyncedFiles, sync.FormatSize(stats.SyncedSize))
+		resultf("   🚫 Excluded local files: %d\n", stats.ExcludedFiles)
+		resultf("   💾 Size on disk: %s\n", sync.FormatSize(stats.DiskSize))
+		if stats.Shallow {
+			resultf("   📜 Commits: %d (shallow clone, older history not fetched)\n", stats.Commits)
+		} else {
+			resultf("   📜 Commits: %d\n", stats.Commits)
+		}
+
+		resultln()
+		last, ok := stats.Status.LastSync()
+		if !ok {
+			resultln("🕐 No syncs recorded yet")
+			return
+		}
+		resultf("🕐 Last sync: %s %s ago (%s)\n", last.Operation,
+			humanDuration(time.Since(last.FinishedAt).Round(time.Second)), last.FinishedAt.Local().Format("2006-01-02 15:04:05"))
+		for _, operation := range []string{"pull", "push"} {
+			if average, count := stats.Status.AverageDuration(operation); count > 0 {
+				resultf("   ⏱️  Average %s: %s (last %d)\n", operation, average.Round(time.Millisecond), count)
+			}
+		}
+		if stats.Status.PendingPush > 0 {
+			resultf("   📤 Commits pending push: %d (run 'cursor-sync push')\n", stats.Status.PendingPush)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(statsCmd)
+}
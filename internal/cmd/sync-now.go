@@ -0,0 +1,45 @@
+package cmd
+
+import (
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"cursor-sync/internal/daemon"
+	"cursor-sync/internal/logger"
+)
+
+// syncNowTimeout bounds how long sync-now waits for the daemon to finish its sync
+const syncNowTimeout = 10 * time.Minute
+
+// syncNowCmd represents the sync-now command
+var syncNowCmd = &cobra.Command{
+	Use:   "sync-now",
+	Short: "Ask the running daemon to sync immediately",
+	Long: `Ask the running daemon to pull and push right away and wait for the result.
+
+The sync runs inside the daemon, so it never overlaps with one the daemon is
+already performing and only one process ever touches the local clone. When no
+daemon is running, a standalone sync is performed instead (like 'cursor-sync sync').`,
+	Run: func(cmd *cobra.Command, args []string) {
+		sayln("🔄 Asking the daemon to sync...")
+
+		response, err := daemon.SendControl(daemon.CommandSyncNow, syncNowTimeout)
+		if err == daemon.ErrDaemonNotRunning {
+			sayln("ℹ️  No daemon running - performing a standalone sync")
+			runStandaloneSync("", false, false, false, nil, nil)
+			return
+		}
+		if err != nil {
+			logger.Error("Daemon sync failed: %v", err)
+			os.Exit(1)
+		}
+
+		sayf("✅ Daemon %s\n", response.Message)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(syncNowCmd)
+}
@@ -2,14 +2,28 @@ package cmd
 
 import (
 	"fmt"
+	"os"
+	"strings"
 
 	"github.com/spf13/cobra"
+	"golang.org/x/term"
 
 	"cursor-sync/internal/config"
+	"cursor-sync/internal/daemon"
+	"cursor-sync/internal/git"
 	"cursor-sync/internal/logger"
 	"cursor-sync/internal/sync"
 )
 
+var (
+	syncBranch            string
+	syncForcePrivacyCheck bool
+	syncConfirmDeletes    bool
+	syncForce             bool
+	syncInclude           []string
+	syncExclude           []string
+)
+
 // syncCmd represents the sync command
 var syncCmd = &cobra.Command{
 	Use:   "sync",
@@ -23,50 +37,192 @@ This command performs a full sync sequence:
 This is useful for:
 - Testing sync functionality
 - Forcing a sync outside of normal intervals
-- Troubleshooting sync issues`,
+- Troubleshooting sync issues
+
+Use --branch to sync against another branch (e.g. a scratch branch) for this run
+without editing the configuration.
+
+A sync that would delete more files than sync.max_deletes (or sync.max_delete_percent
+of the tracked files) is aborted; use --confirm-deletes when the deletion is intended.
+
+Use --force when local and remote settings seem to have drifted apart without the
+change detection noticing: every file is re-hashed instead of trusting cached
+hashes, and unpushed commits are pushed even when nothing else changed.
+
+Use --include/--exclude (repeatable) to narrow this sync without editing the
+configuration. Patterns work like cursor.exclude_paths, relative to the Cursor
+config directory; files outside the scope are left alone on both sides:
+  cursor-sync sync --include User/settings.json
+  cursor-sync sync --exclude 'User/snippets/**'`,
 	Run: func(cmd *cobra.Command, args []string) {
-		logger.Info("Starting manual sync operation...")
-
-		cfg, err := config.Load()
-		if err != nil {
-			logger.Fatal("Failed to load configuration: %v", err)
-		}
-
-		// Create syncer instance
-		syncer, err := sync.New(cfg)
-		if err != nil {
-			logger.Fatal("Failed to create syncer: %v", err)
-		}
-
-		// Initialize syncer
-		if err := syncer.Initialize(); err != nil {
-			logger.Fatal("Failed to initialize syncer: %v", err)
-		}
-
-		fmt.Println("🔄 Performing manual sync...")
-
-		// Perform pull sync
-		fmt.Println("📥 Pulling remote changes...")
-		if err := syncer.SyncFromRemote(); err != nil {
-			logger.Error("Failed to pull remote changes: %v", err)
-			fmt.Println("❌ Pull sync failed")
-		} else {
-			fmt.Println("✅ Remote changes pulled successfully")
-		}
-
-		// Perform push sync
-		fmt.Println("📤 Pushing local changes...")
-		if err := syncer.SyncToRemote(); err != nil {
-			logger.Error("Failed to push local changes: %v", err)
-			fmt.Println("❌ Push sync failed")
-		} else {
-			fmt.Println("✅ Local changes pushed successfully")
-		}
-
-		fmt.Println("🎉 Manual sync completed")
+		runStandaloneSync(syncBranch, syncForcePrivacyCheck, syncConfirmDeletes, syncForce, syncInclude, syncExclude)
 	},
 }
 
+// runStandaloneSync pulls and pushes with a syncer of its own, outside any running daemon.
+// include and exclude narrow it to some files (nil = the configured scope)
+func runStandaloneSync(branch string, forcePrivacyCheck, confirmDeletes, force bool, include, exclude []string) {
+	logger.Info("Starting manual sync operation...")
+
+	cfg, err := config.Load()
+	if err != nil {
+		logger.Fatal("Failed to load configuration: %v", err)
+	}
+	applyBranchOverride(cfg, branch)
+
+	syncer := newStandaloneSyncer(cfg, forcePrivacyCheck, confirmDeletes)
+	defer syncer.Close()
+
+	if force {
+		syncer.ForcePull()
+		syncer.ForcePush()
+	}
+	// The first sync on a machine, done by Initialize, always covers every file
+	if len(include) > 0 || len(exclude) > 0 {
+		syncer.SetScope(include, exclude)
+	}
+
+	sayln("🔄 Performing manual sync...")
+
+	// Perform pull sync
+	sayln("📥 Pulling remote changes...")
+	if err := syncer.SyncFromRemote(); err != nil {
+		logger.Error("Failed to pull remote changes: %v", err)
+		errorln("❌ Pull sync failed")
+	} else {
+		sayln("✅ Remote changes pulled successfully")
+	}
+
+	// Perform push sync
+	sayln("📤 Pushing local changes...")
+	if err := syncer.SyncToRemote(); err != nil {
+		logger.Error("Failed to push local changes: %v", err)
+		errorln("❌ Push sync failed")
+	} else {
+		sayln("✅ Local changes pushed successfully")
+	}
+
+	sayln("🎉 Manual sync completed")
+}
+
+// newStandaloneSyncer creates and initializes a syncer for an interactive command, with
+// the same privacy check and sync marker handling as the daemon
+func newStandaloneSyncer(cfg *config.Config, forcePrivacyCheck, confirmDeletes bool) *sync.Syncer {
+	syncer, err := sync.New(cfg)
+	if err != nil {
+		logger.Fatal("Failed to create syncer: %v", err)
+	}
+
+	if forcePrivacyCheck {
+		syncer.ForcePrivacyCheck()
+	}
+	syncer.ConfirmDeletes(confirmDeletes)
+	if !quiet {
+		syncer.SetProgressOutput(os.Stderr)
+	}
+
+	// Ask before overwriting local settings while Cursor is running
+	if term.IsTerminal(int(os.Stdin.Fd())) {
+		syncer.SetOverwriteConfirmation(confirmOverwriteWhileRunning)
+		syncer.SetConflictPrompt(promptConflictWinner)
+		syncer.SetInitialConflictPrompt(promptInitialConflict)
+	}
+
+	if err := syncer.Initialize(); err != nil {
+		syncer.Close()
+		logger.Fatal("Failed to initialize syncer: %v", err)
+	}
+	return syncer
+}
+
+// confirmOverwriteWhileRunning asks the user whether to overwrite settings while Cursor is running
+func confirmOverwriteWhileRunning() bool {
+	fmt.Println()
+	fmt.Println("⚠️  Cursor is running!")
+	fmt.Println("This is the first sync on this machine, so local settings will be OVERWRITTEN from remote.")
+	fmt.Println("Cursor may revert these files from memory on its next save.")
+	fmt.Println("Quit Cursor first, or answer 'n' to defer the overwrite until Cursor is closed.")
+	fmt.Print("Overwrite local settings now anyway? (y/N): ")
+
+	var answer string
+	fmt.Scanln(&answer)
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	return answer == "y" || answer == "yes"
+}
+
+// promptInitialConflict asks whether the first sync on this machine keeps local files that
+// are newer than the repository or overwrites them
+func promptInitialConflict(files []string) string {
+	fmt.Println()
+	fmt.Println("⚠️  This is the first sync on this machine, but these local files differ from the")
+	fmt.Println("repository and were changed after its last commit:")
+	for _, file := range files {
+		fmt.Printf("   - %s\n", file)
+	}
+	fmt.Println("Keeping them publishes them with the next push; overwriting backs them up to")
+	fmt.Println("backups/ in the state directory first.")
+	fmt.Print("Keep (l)ocal versions or overwrite with (r)emote? (L/r): ")
+
+	var answer string
+	fmt.Scanln(&answer)
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	if answer == "r" || answer == "remote" {
+		return "remote"
+	}
+	return "local"
+}
+
+// promptConflictWinner asks which side keeps files both sides changed when the commit
+// times are too close (or skewed) for the "newer" strategy to decide
+func promptConflictWinner(conflict git.ConflictPrompt) string {
+	fmt.Println()
+	fmt.Println("⚔️  Local and remote settings both changed, and their commit times can't be trusted:")
+	fmt.Printf("   Local commit:  %s\n", conflict.LocalCommitTime.Local().Format("2006-01-02 15:04:05"))
+	fmt.Printf("   Remote commit: %s\n", conflict.RemoteCommitTime.Local().Format("2006-01-02 15:04:05"))
+	for _, file := range conflict.Files {
+		fmt.Printf("   - %s\n", file)
+	}
+	fmt.Println("The other side's versions of these files are discarded (remote ones stay in its history,")
+	fmt.Println("local ones are backed up to backups/ in the state directory).")
+	fmt.Print("Keep (l)ocal or (r)emote versions? (l/R): ")
+
+	var answer string
+	fmt.Scanln(&answer)
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	if answer == "l" || answer == "local" {
+		return "local"
+	}
+	return "remote"
+}
+
+// scopeArguments encodes --include/--exclude patterns as control command arguments
+func scopeArguments(include, exclude []string) string {
+	var arguments string
+	for _, pattern := range include {
+		arguments += " " + daemon.ValueArgument(daemon.ArgInclude, pattern)
+	}
+	for _, pattern := range exclude {
+		arguments += " " + daemon.ValueArgument(daemon.ArgExclude, pattern)
+	}
+	return arguments
+}
+
+// applyBranchOverride replaces the configured repository branch for this invocation
+func applyBranchOverride(cfg *config.Config, branch string) {
+	if branch == "" || branch == cfg.Repository.Branch {
+		return
+	}
+	logger.Info("🔀 Using branch override: %s (configured: %s)", branch, cfg.Repository.Branch)
+	cfg.Repository.Branch = branch
+}
+
 func init() {
 	rootCmd.AddCommand(syncCmd)
+
+	syncCmd.Flags().StringVar(&syncBranch, "branch", "", "Sync against this branch instead of the configured one")
+	syncCmd.Flags().BoolVar(&syncForcePrivacyCheck, "force-privacy-check", false, "Re-check repository privacy with GitHub instead of using the cached result")
+	syncCmd.Flags().BoolVar(&syncConfirmDeletes, "confirm-deletes", false, "Propagate deletions even when they exceed sync.max_deletes or sync.max_delete_percent")
+	syncCmd.Flags().BoolVar(&syncForce, "force", false, "Re-hash every file and push even when no changes are detected")
+	syncCmd.Flags().StringArrayVar(&syncInclude, "include", nil, "Only sync files matching this pattern (repeatable)")
+	syncCmd.Flags().StringArrayVar(&syncExclude, "exclude", nil, "Leave files matching this pattern out of this sync (repeatable)")
 }
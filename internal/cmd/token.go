@@ -1,7 +1,6 @@
 package cmd
 
 import (
-	"fmt"
 	"strings"
 
 	"github.com/spf13/cobra"
@@ -16,7 +15,8 @@ var tokenCmd = &cobra.Command{
 	Short: "Set GitHub Personal Access Token for repository authentication",
 	Long: `Set the GitHub Personal Access Token (PAT) required for secure repository access.
 
-The token is stored securely in ~/.cursor-sync/.github and used for all Git operations.
+The token is stored securely in .github in the config directory (~/.cursor-sync, or
+$XDG_CONFIG_HOME/cursor-sync on Linux) and used for all Git operations.
 
 To create a GitHub token:
 1. Go to GitHub → Settings → Developer settings → Personal access tokens
@@ -36,17 +36,18 @@ Example:
 			logger.Fatal("Failed to save GitHub token: %v", err)
 		}
 
-		fmt.Println("✅ GitHub token saved successfully!")
-		fmt.Println("🔒 Token stored securely in ~/.cursor-sync/.github")
-		fmt.Println("🚀 You can now use cursor-sync with your private repositories")
+		sayln("✅ GitHub token saved successfully!")
+		tokenPath, _ := auth.TokenFilePath()
+		sayf("🔒 Token stored securely in %s\n", tokenPath)
+		sayln("🚀 You can now use cursor-sync with your private repositories")
 
 		// Verify the token works
-		fmt.Println("\n🔍 Verifying token...")
-		if _, err := auth.NewGitHubAuth(); err != nil {
+		sayln("\n🔍 Verifying token...")
+		if _, err := auth.NewGitHubAuth(auth.Endpoint{Timeout: networkTimeout}); err != nil {
 			logger.Error("Token verification failed: %v", err)
-			fmt.Println("❌ Token verification failed - please check your token")
+			errorln("❌ Token verification failed - please check your token")
 		} else {
-			fmt.Println("✅ Token verified successfully!")
+			sayln("✅ Token verified successfully!")
 		}
 	},
 }
@@ -58,14 +59,14 @@ var tokenShowCmd = &cobra.Command{
 	Long:  "Display the current GitHub token status and user information",
 	Run: func(cmd *cobra.Command, args []string) {
 		if !auth.HasValidToken() {
-			fmt.Println("❌ No GitHub token found")
+			errorln("❌ No GitHub token found")
 			auth.ShowTokenRequiredMessage()
 			return
 		}
 
-		githubAuth, err := auth.NewGitHubAuth()
+		githubAuth, err := auth.NewGitHubAuth(auth.Endpoint{Timeout: networkTimeout})
 		if err != nil {
-			fmt.Printf("❌ Token verification failed: %v\n", err)
+			errorf("❌ Token verification failed: %v\n", err)
 			return
 		}
 
@@ -73,11 +74,12 @@ var tokenShowCmd = &cobra.Command{
 		token := githubAuth.GetToken()
 		if len(token) > 8 {
 			maskedToken := token[:8] + strings.Repeat("*", len(token)-8)
-			fmt.Printf("✅ GitHub token: %s\n", maskedToken)
+			resultf("✅ GitHub token: %s\n", maskedToken)
 		}
 
-		fmt.Println("🔒 Token file: ~/.cursor-sync/.github")
-		fmt.Println("✅ Authentication verified")
+		tokenPath, _ := auth.TokenFilePath()
+		resultf("🔒 Token file: %s\n", tokenPath)
+		sayln("✅ Authentication verified")
 	},
 }
 
@@ -0,0 +1,80 @@
+package cmd
+
+import (
+	"context"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"cursor-sync/internal/daemon"
+	"cursor-sync/internal/logger"
+	"cursor-sync/internal/update"
+	"cursor-sync/internal/version"
+)
+
+var (
+	updateCheckOnly  bool
+	updateSkipVerify bool
+)
+
+// updateCmd represents the update command
+var updateCmd = &cobra.Command{
+	Use:   "update",
+	Short: "Install the latest cursor-sync release",
+	Long: `Check GitHub releases for a newer cursor-sync and replace this binary with it.
+The download is verified against the release's checksums.txt. A release that doesn't
+publish one is refused unless --skip-verify is passed.
+
+With --check, only report whether an update is available (exit status 1 if so).
+The daemon runs the same check on startup when update.check_enabled is set, but
+never installs anything - updating always takes this command.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Minute)
+		defer cancel()
+
+		release, newer, err := update.Check(ctx, true)
+		if err != nil {
+			logger.Error("Failed to check for updates: %v", err)
+			errorln("❌ Update check failed")
+			os.Exit(1)
+		}
+		if !newer {
+			if version.Version == "dev" {
+				resultf("Development build - latest release is %s\n", release.Version())
+			} else {
+				resultf("cursor-sync %s is up to date\n", version.Version)
+			}
+			return
+		}
+
+		resultf("cursor-sync %s is available (running %s)\n", release.Version(), version.Version)
+		if updateCheckOnly {
+			sayf("   Release notes: %s\n", release.HTMLURL)
+			sayln("💡 Run 'cursor-sync update' to install it")
+			os.Exit(1)
+		}
+
+		sayf("⬇️  Downloading cursor-sync %s...\n", release.Version())
+		path, err := update.Install(ctx, release, updateSkipVerify)
+		if err != nil {
+			logger.Error("Failed to install update: %v", err)
+			errorln("❌ Update failed - the current binary was left unchanged")
+			os.Exit(1)
+		}
+		sayf("✅ Updated %s to %s\n", path, release.Version())
+
+		// A running daemon keeps executing the old binary until it restarts
+		if _, err := daemon.SendControl(daemon.CommandStatus, 2*time.Second); err == nil {
+			sayln("💡 Restart the daemon to use the new version: cursor-sync stop && cursor-sync start")
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(updateCmd)
+
+	updateCmd.Flags().BoolVar(&updateCheckOnly, "check", false, "Only report whether a newer release is available")
+	updateCmd.Flags().BoolVar(&updateSkipVerify, "skip-verify", false, "Install a release that publishes no checksums.txt (the download can't be verified)")
+}
@@ -1,11 +1,12 @@
 package cmd
 
 import (
-	"fmt"
+	"os"
 
 	"github.com/spf13/cobra"
 
 	"cursor-sync/internal/config"
+	"cursor-sync/internal/console"
 	"cursor-sync/internal/cursor"
 )
 
@@ -21,64 +22,85 @@ This command checks:
 - Required settings files and directories
 - Repository configuration (if provided)`,
 	Run: func(cmd *cobra.Command, args []string) {
-		fmt.Println("🔍 Validating cursor-sync configuration and Cursor installation...")
-		fmt.Println()
-
-		// Load and validate configuration
-		cfg, err := config.Load()
-		if err != nil {
-			fmt.Printf("❌ Configuration validation failed: %v\n", err)
-			return
+		if err := runValidate(); err != nil {
+			errorln(console.Stderr.Failure("Configuration validation failed: %v", err))
+			os.Exit(1)
 		}
 
-		// Configuration loaded successfully
-		fmt.Println("✅ Configuration loaded successfully")
-		fmt.Printf("   Repository: %s\n", cfg.Repository.URL)
-		fmt.Printf("   Branch: %s\n", cfg.Repository.Branch)
-		fmt.Printf("   Local Path: %s\n", cfg.Repository.LocalPath)
-		fmt.Printf("   Cursor Path: %s\n", cfg.Cursor.ConfigPath)
-		fmt.Println()
-
-		// Cursor validation already happened during config.Load(),
-		// so if we get here, everything is valid
-		fmt.Println("✅ Cursor IDE installation validated")
-		fmt.Printf("   Settings Directory: %s\n", cfg.Cursor.ConfigPath)
-		fmt.Printf("   Pull Interval: %v\n", cfg.Sync.PullInterval)
-		fmt.Printf("   Push Interval: %v\n", cfg.Sync.PushInterval)
-		fmt.Printf("   Debounce Time: %v\n", cfg.Sync.DebounceTime)
-		fmt.Printf("   Watch Enabled: %v\n", cfg.Sync.WatchEnabled)
-		fmt.Printf("   Conflict Resolution: %s\n", cfg.Sync.ConflictResolve)
-		fmt.Println()
-
-		fmt.Println("🎉 All validations passed! cursor-sync is ready to use.")
-		fmt.Println()
-		fmt.Println("Next steps:")
-		fmt.Println("1. Set your GitHub token: cursor-sync token <your-token>")
-		fmt.Println("2. Install the daemon: cursor-sync install")
-		fmt.Println("3. Start syncing: cursor-sync start")
+		sayln("Next steps:")
+		sayln("1. Set your GitHub token: cursor-sync token <your-token>")
+		sayln("2. Install the daemon: cursor-sync install")
+		sayln("3. Start syncing: cursor-sync start")
 	},
 }
 
+// runValidate loads the configuration, which validates it and the Cursor installation,
+// and prints the result. The returned error hasn't been printed yet
+func runValidate() error {
+	out := console.Stdout
+	sayln(out.Heading("🔍", "Validating cursor-sync configuration and Cursor installation"))
+	sayln()
+
+	// Load and validate configuration
+	cfg, err := config.Load()
+	if err != nil {
+		return err
+	}
+
+	// Configuration loaded successfully
+	sayln(out.Success("Configuration loaded successfully"))
+	sayln(out.Field(3, "Repository", cfg.Repository.URL))
+	sayln(out.Field(3, "Branch", cfg.Repository.Branch))
+	sayln(out.Field(3, "Local Path", cfg.Repository.LocalPath))
+	sayln(out.Field(3, "Cursor Path", cfg.Cursor.ConfigPath))
+	sayln()
+
+	// Cursor validation already happened during config.Load(),
+	// so if we get here, everything is valid
+	sayln(out.Success("Cursor IDE installation validated"))
+	sayln(out.Field(3, "Settings Directory", cfg.Cursor.ConfigPath))
+	sayln(out.Field(3, "Pull Interval", cfg.Sync.PullInterval))
+	sayln(out.Field(3, "Push Interval", cfg.Sync.PushInterval))
+	sayln(out.Field(3, "Debounce Time", cfg.Sync.DebounceTime))
+	sayln(out.Field(3, "Watch Enabled", cfg.Sync.WatchEnabled))
+	sayln(out.Field(3, "Conflict Resolution", cfg.Sync.ConflictResolve))
+	sayln()
+
+	sayln(out.Info("🎉", "All validations passed! cursor-sync is ready to use."))
+	sayln()
+	return nil
+}
+
 // checkCmd represents the check command (alias for validate with different output format)
 var checkCmd = &cobra.Command{
 	Use:   "check",
 	Short: "Quick health check of Cursor installation",
 	Long:  `Perform a quick health check to verify Cursor IDE is installed and accessible.`,
 	Run: func(cmd *cobra.Command, args []string) {
-		detector := cursor.NewDetector(cursor.GetDefaultCursorPath())
+		if err := runCursorCheck(); err != nil {
+			errorln(console.Stderr.Failure("Cursor check failed: %v", err))
+			os.Exit(1)
+		}
+		sayln("Ready for synchronization!")
+	},
+}
 
-		fmt.Print("🔍 Checking Cursor installation... ")
+// runCursorCheck checks that Cursor is installed at its default location and prints the
+// result. The returned error hasn't been printed yet
+func runCursorCheck() error {
+	detector := cursor.NewDetector(cursor.GetDefaultCursorPath())
 
-		if err := detector.DetectAndValidate(); err != nil {
-			fmt.Println("❌")
-			fmt.Printf("\nCursor check failed: %v\n", err)
-			return
-		}
+	out := console.Stdout
+	sayf("%s ", out.Info("🔍", "Checking Cursor installation..."))
 
-		fmt.Println("✅")
-		fmt.Printf("Cursor IDE found at: %s\n", cursor.GetDefaultCursorPath())
-		fmt.Println("Ready for synchronization!")
-	},
+	if err := detector.DetectAndValidate(); err != nil {
+		sayln(out.Bad("failed"))
+		return err
+	}
+
+	sayln(out.Good("ok"))
+	resultln(out.Field(0, "Cursor IDE found at", cursor.GetDefaultCursorPath()))
+	return nil
 }
 
 func init() {
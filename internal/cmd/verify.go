@@ -0,0 +1,81 @@
+package cmd
+
+import (
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"cursor-sync/internal/config"
+	"cursor-sync/internal/logger"
+	"cursor-sync/internal/sync"
+)
+
+// verifyCmd represents the verify command
+var verifyCmd = &cobra.Command{
+	Use:   "verify",
+	Short: "Check that local settings match the repository",
+	Long: `Compare the local Cursor User directory with the User directory of the local
+repository clone and report any divergence.
+
+Every file that takes part in syncing (exclusions and .cursorsyncignore apply)
+is hashed on both sides. Files with different content, files only present
+locally and files only present in the repository are listed, along with the
+machine that last changed the repository copy (from .cursor-sync/manifest.json).
+
+The command exits with status 1 when a divergence is found, so it can be used
+as a post-sync sanity check in scripts:
+
+  cursor-sync sync && cursor-sync verify`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg, err := config.Load()
+		if err != nil {
+			logger.Fatal("Failed to load configuration: %v", err)
+		}
+
+		syncer, err := sync.New(cfg)
+		if err != nil {
+			logger.Fatal("Failed to create syncer: %v", err)
+		}
+
+		sayln("🔍 Verifying local settings against the repository...")
+		result, err := syncer.Verify()
+		syncer.Close()
+		if err != nil {
+			logger.Fatal("Verification failed: %v", err)
+		}
+
+		if !result.Diverged() {
+			resultf("✅ Local settings match the repository (%d files checked)\n", result.Checked)
+			return
+		}
+
+		printVerifyList("❗ Content differs", result.Mismatched, result.LastChanged)
+		printVerifyList("📄 Only local", result.OnlyLocal, nil)
+		printVerifyList("☁️  Only in repository", result.OnlyRemote, result.LastChanged)
+
+		errorf("❌ Local settings diverge from the repository (%d differing files)\n",
+			len(result.Mismatched)+len(result.OnlyLocal)+len(result.OnlyRemote))
+		os.Exit(1)
+	},
+}
+
+// printVerifyList prints a titled list of files, or nothing if the list is empty
+// Files with a manifest entry show which machine last changed the repository copy
+func printVerifyList(title string, files []string, lastChanged map[string]sync.ManifestEntry) {
+	if len(files) == 0 {
+		return
+	}
+	resultf("%s (%d):\n", title, len(files))
+	for _, file := range files {
+		if entry, ok := lastChanged[file]; ok {
+			resultf("   %s (%s)\n", file, entry)
+			continue
+		}
+		resultf("   %s\n", file)
+	}
+	resultln()
+}
+
+func init() {
+	rootCmd.AddCommand(verifyCmd)
+}
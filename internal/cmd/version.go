@@ -0,0 +1,35 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+
+	"cursor-sync/internal/version"
+)
+
+// versionCmd represents the version command
+var versionCmd = &cobra.Command{
+	Use:   "version",
+	Short: "Show build version and commit",
+	Long:  `Show the version, git commit, build date and Go version of this build. Include it in bug reports.`,
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		info := version.Get()
+		commit := info.Commit
+		if info.Modified {
+			commit += " (uncommitted changes)"
+		}
+
+		resultf("cursor-sync %s\n", info.Version)
+		resultf("   Commit: %s\n", commit)
+		resultf("   Built: %s\n", info.Date)
+		resultf("   Go: %s %s\n", info.GoVersion, info.Platform)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(versionCmd)
+
+	// --version prints the one-line form
+	rootCmd.Version = version.Get().String()
+	rootCmd.SetVersionTemplate("cursor-sync {{.Version}}\n")
+}
@@ -0,0 +1,115 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+
+	"cursor-sync/internal/config"
+	"cursor-sync/internal/logger"
+	"cursor-sync/internal/sync"
+)
+
+// whichCmd represents the which command
+var whichCmd = &cobra.Command{
+	Use:   "which <path>",
+	Short: "Explain how a single file is synced",
+	Long: `Explain why a file is or isn't syncing.
+
+The path is relative to the Cursor User directory ("settings.json" and
+"User/settings.json" name the same file). For every sync target the command
+reports:
+- the exclusion rule (cursor.exclude_paths, .cursorsyncignore,
+  cursor.global_storage_allow or cursor.workspace_allow) that decides whether
+  the file is synced
+- the workspace folder a file below workspaceStorage belongs to
+- whether the file watcher picks up its changes (cursor.include_paths)
+- size, modification time and hash of the local and repository copies
+- which machine last pushed it (from the repository's file manifest)
+- what the next pull and the next push would do with it
+
+Nothing is synced or changed. A sync pulls before it pushes.
+
+Examples:
+  cursor-sync which settings.json
+  cursor-sync which User/snippets/go.json`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg, err := config.Load()
+		if err != nil {
+			logger.Fatal("Failed to load configuration: %v", err)
+		}
+
+		syncer, err := sync.New(cfg)
+		if err != nil {
+			logger.Fatal("Failed to create syncer: %v", err)
+		}
+
+		explanations, err := syncer.Explain(args[0])
+		syncer.Close()
+		if err != nil {
+			logger.Fatal("Failed to explain %s: %v", args[0], err)
+		}
+
+		for _, e := range explanations {
+			printExplanation(e, len(explanations) > 1)
+		}
+	},
+}
+
+// printExplanation prints how one target syncs the file
+func printExplanation(e *sync.FileExplanation, showTarget bool) {
+	if showTarget {
+		resultf("🎯 %s (target %s)\n", e.Path, e.Target)
+	} else {
+		resultf("🎯 %s\n", e.Path)
+	}
+
+	switch {
+	case e.Excluded:
+		resultf("   🚫 Excluded: %s\n", e.Rule)
+	case e.Rule != "":
+		resultf("   ✅ Synced: %s\n", e.Rule)
+	default:
+		resultln("   ✅ Synced: no exclusion rule matches")
+	}
+	if e.Workspace != "" {
+		resultf("   🗂️  Workspace: %s\n", e.Workspace)
+	}
+	if !e.Excluded {
+		if e.Watched {
+			resultf("   👀 Watched: %s\n", e.WatchRule)
+		} else {
+			resultf("   💤 Not watched: %s\n", e.WatchRule)
+		}
+	}
+
+	printFileState("📄 Local", e.Local)
+	printFileState("☁️  Repository", e.Repo)
+	if e.LastChanged != nil {
+		resultf("   📝 Repository copy %s\n", e.LastChanged)
+	}
+
+	resultf("   📥 Next pull: %s\n", e.Pull)
+	resultf("   📤 Next push: %s\n", e.Push)
+	resultln()
+}
+
+// printFileState prints one side of the file
+func printFileState(title string, state sync.FileState) {
+	switch {
+	case !state.Exists:
+		resultf("   %s: missing (%s)\n", title, state.Path)
+	case state.Symlink:
+		resultf("   %s: symlink (%s)\n", title, state.Path)
+	default:
+		hash := "unreadable"
+		if state.Hash != "" {
+			hash = state.Hash[:12]
+		}
+		resultf("   %s: %d bytes, modified %s, sha256 %s (%s)\n",
+			title, state.Size, state.ModTime.Format("2006-01-02 15:04:05"), hash, state.Path)
+	}
+}
+
+func init() {
+	rootCmd.AddCommand(whichCmd)
+}
@@ -1,8 +1,10 @@
 package config
 
 import (
+	"crypto/sha256"
 	"fmt"
 	"os"
+	"path"
 	"path/filepath"
 	"strings"
 	"time"
@@ -10,8 +12,11 @@ import (
 	"github.com/spf13/viper"
 	"gopkg.in/yaml.v3"
 
+	"cursor-sync/internal/auth"
 	"cursor-sync/internal/cursor"
 	"cursor-sync/internal/logger"
+	"cursor-sync/internal/notify"
+	"cursor-sync/internal/paths"
 )
 
 // Config represents the application configuration
@@ -20,6 +25,8 @@ type Config struct {
 	Sync       Sync       `yaml:"sync" mapstructure:"sync"`
 	Cursor     Cursor     `yaml:"cursor" mapstructure:"cursor"`
 	Logging    Logging    `yaml:"logging" mapstructure:"logging"`
+	Update     Update     `yaml:"update" mapstructure:"update"`
+	Network    Network    `yaml:"network" mapstructure:"network"`
 }
 
 // Repository configuration
@@ -27,35 +34,210 @@ type Repository struct {
 	URL       string `yaml:"url" mapstructure:"url"`
 	LocalPath string `yaml:"local_path" mapstructure:"local_path"`
 	Branch    string `yaml:"branch" mapstructure:"branch"`
+	Subdir    string `yaml:"subdir" mapstructure:"subdir"`
+	Shallow   bool   `yaml:"shallow" mapstructure:"shallow"`
+	// Let GitHub seed repositories cursor-sync creates with a README (false = created empty and seeded by cursor-sync's first push)
+	AutoInit bool `yaml:"auto_init" mapstructure:"auto_init"`
+	// GitHub REST API endpoint (empty = derived from the URL: api.github.com or https://<host>[:port][/prefix]/api/v3)
+	APIBaseURL string `yaml:"api_base_url,omitempty" mapstructure:"api_base_url"`
+}
+
+// ContentPath returns the directory inside the local clone that holds the synced files
+// (the clone root unless a subdir is configured)
+func (r Repository) ContentPath() string {
+	return filepath.Join(r.LocalPath, r.Subdir)
+}
+
+// GitHubEndpoint returns how GitHub is reached for the repository: the API serving it
+// (repository.api_base_url or derived from the URL) and network.timeout
+func (c *Config) GitHubEndpoint() auth.Endpoint {
+	return auth.NewEndpoint(c.Repository.URL, c.Repository.APIBaseURL, c.Network.Timeout)
 }
 
 // Sync configuration
 type Sync struct {
 	PullInterval       time.Duration `yaml:"pull_interval" mapstructure:"pull_interval"`
 	PushInterval       time.Duration `yaml:"push_interval" mapstructure:"push_interval"`
+	IntervalJitter     time.Duration `yaml:"interval_jitter" mapstructure:"interval_jitter"` // Random extra delay per periodic sync, 0 = off
 	DebounceTime       time.Duration `yaml:"debounce_time" mapstructure:"debounce_time"`
+	DebounceMode       string        `yaml:"debounce_mode" mapstructure:"debounce_mode"`
 	WatchEnabled       bool          `yaml:"watch_enabled" mapstructure:"watch_enabled"`
 	ConflictResolve    string        `yaml:"conflict_resolve" mapstructure:"conflict_resolve"`
+	ClockSkewTolerance time.Duration `yaml:"clock_skew_tolerance" mapstructure:"clock_skew_tolerance"` // "newer" merges commits closer than this
 	HashThrottleDelay  time.Duration `yaml:"hash_throttle_delay" mapstructure:"hash_throttle_delay"`
 	HashPollingTimeout time.Duration `yaml:"hash_polling_timeout" mapstructure:"hash_polling_timeout"`
+	DebugReport        bool          `yaml:"debug_report" mapstructure:"debug_report"`
+	NotifyURL          string        `yaml:"notify_url" mapstructure:"notify_url"`
+	NotifyOn           []string      `yaml:"notify_on" mapstructure:"notify_on"`
+	NotifyFormat       string        `yaml:"notify_format" mapstructure:"notify_format"`
+	DesktopNotify      bool          `yaml:"desktop_notifications" mapstructure:"desktop_notifications"`
+	PrivacyCheckTTL    time.Duration `yaml:"privacy_check_interval" mapstructure:"privacy_check_interval"`
+	SnapshotDatabases  bool          `yaml:"snapshot_databases" mapstructure:"snapshot_databases"`
+	MaxRepoSize        int           `yaml:"max_repo_size" mapstructure:"max_repo_size"` // MB, 0 = no limit
+	ContentCheck       bool          `yaml:"content_check" mapstructure:"content_check"` // Hash the clone against HEAD when git status reports no changes
+	ValidateJSON       bool          `yaml:"validate_json" mapstructure:"validate_json"` // Don't commit JSON settings files that fail to parse
+	IncrementalPull    bool          `yaml:"incremental_pull" mapstructure:"incremental_pull"`
+	IncrementalMax     int           `yaml:"incremental_max_files" mapstructure:"incremental_max_files"`
+	SquashWindow       time.Duration `yaml:"squash_window" mapstructure:"squash_window"`             // 0 = one commit per sync
+	PushLease          time.Duration `yaml:"push_lease" mapstructure:"push_lease"`                   // Advisory lock other machines wait for before pushing, 0 = off
+	StaleWarning       time.Duration `yaml:"stale_warning" mapstructure:"stale_warning"`             // Warn when no push succeeded for this long, 0 = off
+	AnonymizeHostname  bool          `yaml:"anonymize_hostname" mapstructure:"anonymize_hostname"`   // Name this machine by a hash of its hostname in the repository
+	MachineAlias       string        `yaml:"machine_alias" mapstructure:"machine_alias"`             // Name this machine by this alias in the repository
+	CommitAuthorName   string        `yaml:"commit_author_name" mapstructure:"commit_author_name"`   // "" = cursor-sync
+	CommitAuthorEmail  string        `yaml:"commit_author_email" mapstructure:"commit_author_email"` // "" = cursor-sync@local
+	AutoPush           bool          `yaml:"auto_push" mapstructure:"auto_push"`                     // false = commit only, publish with 'cursor-sync push'
+	MaxDeletes         int           `yaml:"max_deletes" mapstructure:"max_deletes"`                 // Files per sync, 0 = no limit
+	MaxDeletePercent   int           `yaml:"max_delete_percent" mapstructure:"max_delete_percent"`   // Percent of tracked files, 0 = no limit
+	UseLFS             bool          `yaml:"use_lfs" mapstructure:"use_lfs"`
+	LFSPatterns        []string      `yaml:"lfs_patterns" mapstructure:"lfs_patterns"` // Gitattributes-style globs stored in Git LFS
+	Mode               string        `yaml:"mode" mapstructure:"mode"`
+}
+
+// Author of sync commits unless sync.commit_author_name / sync.commit_author_email say otherwise
+const (
+	DefaultCommitAuthorName  = "cursor-sync"
+	DefaultCommitAuthorEmail = "cursor-sync@local"
+)
+
+// Sync directions (sync.mode)
+const (
+	ModeBidirectional = "bidirectional" // Pull remote changes and push local ones
+	ModeMirrorPull    = "mirror-pull"   // Only receive settings; local changes never flow back
+	ModeMirrorPush    = "mirror-push"   // Only publish settings; remote changes are never applied
+)
+
+// DescribeMode explains a sync.mode value for humans
+func DescribeMode(mode string) string {
+	switch mode {
+	case ModeMirrorPull:
+		return "receives settings, never pushes local changes"
+	case ModeMirrorPush:
+		return "publishes settings, never applies remote changes"
+	default:
+		return "pulls and pushes"
+	}
+}
+
+// Pulls reports whether sync.mode lets remote changes be applied locally
+func (s Sync) Pulls() bool {
+	return s.Mode != ModeMirrorPush
 }
 
+// Pushes reports whether sync.mode lets local changes be pushed
+func (s Sync) Pushes() bool {
+	return s.Mode != ModeMirrorPull
+}
+
+// CommitAuthor returns the author of sync commits, falling back to cursor-sync's own identity
+func (s Sync) CommitAuthor() (name, email string) {
+	name, email = s.CommitAuthorName, s.CommitAuthorEmail
+	if name == "" {
+		name = DefaultCommitAuthorName
+	}
+	if email == "" {
+		email = DefaultCommitAuthorEmail
+	}
+	return name, email
+}
+
+// MachineName is how this machine is named in the repository (commit messages, the file
+// manifest, push leases): sync.machine_alias, else with sync.anonymize_hostname a stable
+// pseudonym derived from the hostname, else the hostname itself
+func (s Sync) MachineName() string {
+	if s.MachineAlias != "" {
+		return s.MachineAlias
+	}
+	hostname, _ := os.Hostname()
+	if s.AnonymizeHostname {
+		return fmt.Sprintf("machine-%x", sha256.Sum256([]byte(hostname)))[:len("machine-")+8]
+	}
+	return hostname
+}
+
+// Watcher debounce modes (sync.debounce_mode)
+const (
+	DebounceFile = "file" // Debounce each file separately
+	DebounceDir  = "dir"  // Collapse rapid changes under a directory into one event
+)
+
 // Cursor configuration
 type Cursor struct {
 	ConfigPath   string   `yaml:"config_path" mapstructure:"config_path"`
 	ExcludePaths []string `yaml:"exclude_paths" mapstructure:"exclude_paths"`
 	IncludePaths []string `yaml:"include_paths" mapstructure:"include_paths"`
+	Targets      []Target `yaml:"targets,omitempty" mapstructure:"targets"`
+	// Files under User/globalStorage that are synced even though the folder is excluded
+	GlobalStorageAllow []string `yaml:"global_storage_allow,omitempty" mapstructure:"global_storage_allow"`
+	// Workspaces under User/workspaceStorage (folder hash, workspace path or folder name)
+	// that are synced even though the folder is excluded
+	WorkspaceAllow []string `yaml:"workspace_allow,omitempty" mapstructure:"workspace_allow"`
+}
+
+// Target is an IDE installation (Cursor, VS Code, ...) whose User directory is synced
+// into <repository subdir>/<target subdir>/User
+type Target struct {
+	Name       string `yaml:"name" mapstructure:"name"`
+	ConfigPath string `yaml:"config_path" mapstructure:"config_path"`
+	Subdir     string `yaml:"subdir" mapstructure:"subdir"`
+}
+
+// SyncTargets returns the installations to sync
+// Without explicit targets, config_path is synced directly into the repository
+func (c Cursor) SyncTargets() []Target {
+	if len(c.Targets) > 0 {
+		return c.Targets
+	}
+	return []Target{{Name: "cursor", ConfigPath: c.ConfigPath}}
 }
 
 // Logging configuration
 type Logging struct {
 	Level    string `yaml:"level" mapstructure:"level"`
+	Format   string `yaml:"format" mapstructure:"format"`
 	LogDir   string `yaml:"log_dir" mapstructure:"log_dir"`
 	MaxSize  int    `yaml:"max_size" mapstructure:"max_size"`
 	MaxDays  int    `yaml:"max_days" mapstructure:"max_days"`
 	Compress bool   `yaml:"compress" mapstructure:"compress"`
 }
 
+// Update configuration
+type Update struct {
+	CheckEnabled bool `yaml:"check_enabled" mapstructure:"check_enabled"` // Check GitHub releases for a newer version when the daemon starts
+}
+
+// Network configuration
+type Network struct {
+	// Limit for a single GitHub API request, and for a git clone/fetch/pull/push without
+	// network activity (0 = default)
+	Timeout time.Duration `yaml:"timeout" mapstructure:"timeout"`
+}
+
+// configFile overrides the default config file location (set by the --config flag)
+var configFile string
+
+// SetConfigFile makes Load read path instead of config.yaml in the config directory ("" = default)
+func SetConfigFile(path string) {
+	configFile = path
+}
+
+// FilePath returns the config file Load reads: the --config override or config.yaml in
+// paths.ConfigDir
+func FilePath() (string, error) {
+	if configFile != "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("failed to get home directory: %w", err)
+		}
+		return filepath.Abs(expandHome(configFile, home))
+	}
+
+	configDir, err := paths.ConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(configDir, "config.yaml"), nil
+}
+
 // Load loads the configuration from file and environment variables
 func Load() (*Config, error) {
 	var cfg Config
@@ -64,12 +246,10 @@ func Load() (*Config, error) {
 	setDefaults()
 
 	// Set up viper to read from user config file
-	home, err := os.UserHomeDir()
+	userConfigPath, err := FilePath()
 	if err != nil {
-		return nil, fmt.Errorf("failed to get home directory: %w", err)
+		return nil, err
 	}
-
-	userConfigPath := filepath.Join(home, ".cursor-sync", "config.yaml")
 	viper.SetConfigFile(userConfigPath)
 
 	// Read the user config file (this will override defaults)
@@ -108,12 +288,10 @@ func Load() (*Config, error) {
 
 // CreateDefaultConfig creates a default configuration file
 func CreateDefaultConfig() error {
-	home, err := os.UserHomeDir()
+	configDir, err := paths.ConfigDir()
 	if err != nil {
-		return fmt.Errorf("failed to get home directory: %w", err)
+		return err
 	}
-
-	configDir := filepath.Join(home, ".cursor-sync")
 	if err := os.MkdirAll(configDir, 0755); err != nil {
 		return fmt.Errorf("failed to create config directory: %w", err)
 	}
@@ -142,7 +320,7 @@ func CreateDefaultConfig() error {
 		return nil
 	}
 
-	config := getDefaultConfig(home)
+	config := getDefaultConfig()
 
 	data, err := yaml.Marshal(config)
 	if err != nil {
@@ -166,9 +344,21 @@ func setDefaults() {
 			viper.ReadInConfig()
 		}
 	}
+
+	// Configs written before these options existed keep the previous behaviour
+	viper.SetDefault("repository.auto_init", true)
+	viper.SetDefault("update.check_enabled", true)
+	viper.SetDefault("network.timeout", auth.DefaultNetworkTimeout.String())
+	viper.SetDefault("sync.auto_push", true)
+	viper.SetDefault("sync.privacy_check_interval", "1h")
+
+	// Older configs get the mass-delete guard and JSON validation too
+	viper.SetDefault("sync.max_deletes", 50)
+	viper.SetDefault("sync.max_delete_percent", 50)
+	viper.SetDefault("sync.validate_json", true)
 }
 
-func getDefaultConfig(home string) *Config {
+func getDefaultConfig() *Config {
 	// Load the example config as the default
 	wd, err := os.Getwd()
 	if err != nil {
@@ -192,33 +382,55 @@ func getDefaultConfig(home string) *Config {
 	}
 
 	// Fallback to minimal config if example config fails
+	stateDir, _ := paths.StateDir()
 	return &Config{
 		Repository: Repository{
 			URL:       "",
-			LocalPath: filepath.Join(home, ".cursor-sync", "settings"),
+			LocalPath: filepath.Join(stateDir, "settings"),
 			Branch:    "main",
+			AutoInit:  true,
 		},
 		Sync: Sync{
 			PullInterval:       5 * time.Minute,
 			PushInterval:       5 * time.Minute,
 			DebounceTime:       10 * time.Second,
+			DebounceMode:       DebounceFile,
+			Mode:               ModeBidirectional,
 			WatchEnabled:       true,
+			AutoPush:           true,
 			ConflictResolve:    "newer",
+			ClockSkewTolerance: 2 * time.Minute,
 			HashThrottleDelay:  100 * time.Millisecond,
 			HashPollingTimeout: 10 * time.Second,
+			PrivacyCheckTTL:    time.Hour,
+			StaleWarning:       time.Hour,
+			ValidateJSON:       true,
+			SnapshotDatabases:  true,
+			MaxRepoSize:        200,
+			IncrementalMax:     50,
+			MaxDeletes:         50,
+			MaxDeletePercent:   50,
+			LFSPatterns:        []string{"*.vscdb", "*.png", "*.jpg", "*.gif", "*.ico", "*.vsix"},
 		},
 		Cursor: Cursor{
-			ConfigPath:   filepath.Join(home, "Library", "Application Support", "Cursor"),
+			ConfigPath:   cursor.GetDefaultCursorPath(), // OS-specific location
 			ExcludePaths: []string{},
 			IncludePaths: []string{},
 		},
 		Logging: Logging{
 			Level:    "info",
-			LogDir:   filepath.Join(home, ".cursor-sync", "logs"),
+			Format:   "text",
+			LogDir:   filepath.Join(stateDir, "logs"),
 			MaxSize:  10,
 			MaxDays:  30,
 			Compress: true,
 		},
+		Update: Update{
+			CheckEnabled: true,
+		},
+		Network: Network{
+			Timeout: auth.DefaultNetworkTimeout,
+		},
 	}
 }
 
@@ -228,14 +440,77 @@ func expandPaths(cfg *Config) error {
 		return fmt.Errorf("failed to get home directory: %w", err)
 	}
 
-	// Expand home directory in paths
-	cfg.Repository.LocalPath = expandHome(cfg.Repository.LocalPath, home)
-	cfg.Cursor.ConfigPath = expandHome(cfg.Cursor.ConfigPath, home)
-	cfg.Logging.LogDir = expandHome(cfg.Logging.LogDir, home)
+	// The clone and the logs live in the state directory unless configured otherwise
+	if cfg.Repository.LocalPath == "" || cfg.Logging.LogDir == "" {
+		stateDir, err := paths.StateDir()
+		if err != nil {
+			return err
+		}
+		if cfg.Repository.LocalPath == "" {
+			cfg.Repository.LocalPath = filepath.Join(stateDir, "settings")
+		}
+		if cfg.Logging.LogDir == "" {
+			cfg.Logging.LogDir = filepath.Join(stateDir, "logs")
+		}
+	}
+
+	// Expand environment variables and home directory in paths
+	if cfg.Repository.LocalPath, err = expandPath("repository.local_path", cfg.Repository.LocalPath, home); err != nil {
+		return err
+	}
+	if cfg.Cursor.ConfigPath, err = expandPath("cursor.config_path", cfg.Cursor.ConfigPath, home); err != nil {
+		return err
+	}
+	for i := range cfg.Cursor.Targets {
+		field := fmt.Sprintf("cursor.targets[%s].config_path", cfg.Cursor.Targets[i].Name)
+		if cfg.Cursor.Targets[i].ConfigPath, err = expandPath(field, cfg.Cursor.Targets[i].ConfigPath, home); err != nil {
+			return err
+		}
+	}
+	if cfg.Logging.LogDir, err = expandPath("logging.log_dir", cfg.Logging.LogDir, home); err != nil {
+		return err
+	}
 
 	return nil
 }
 
+// LogDir returns the configured log directory resolved as Load resolves it, so readers of
+// the logs find them where the daemon writes them even when the rest of the config is invalid
+func LogDir() (string, error) {
+	logDir := viper.GetString("logging.log_dir")
+	if logDir == "" {
+		stateDir, err := paths.StateDir()
+		if err != nil {
+			return "", err
+		}
+		return filepath.Join(stateDir, "logs"), nil
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get home directory: %w", err)
+	}
+	return expandPath("logging.log_dir", logDir, home)
+}
+
+// expandPath expands $VAR/${VAR} and a leading ~ in a configured path
+// An unset variable is an error: expanding it to "" would silently point somewhere else
+func expandPath(field, path, home string) (string, error) {
+	var unset []string
+	expanded := os.Expand(path, func(name string) string {
+		value, ok := os.LookupEnv(name)
+		if !ok {
+			unset = append(unset, name)
+		}
+		return value
+	})
+
+	if len(unset) > 0 {
+		return "", fmt.Errorf("%s: environment variable %s is not set", field, strings.Join(unset, ", "))
+	}
+	return expandHome(expanded, home), nil
+}
+
 func expandHome(path, home string) string {
 	if len(path) > 0 && path[0] == '~' {
 		return filepath.Join(home, path[1:])
@@ -252,16 +527,37 @@ func validate(cfg *Config) error {
 		return fmt.Errorf("repository local path is required")
 	}
 
+	if err := ValidateSubdir(cfg.Repository.Subdir); err != nil {
+		return err
+	}
+
+	// The first target is the primary installation when targets are used
+	if cfg.Cursor.ConfigPath == "" && len(cfg.Cursor.Targets) > 0 {
+		cfg.Cursor.ConfigPath = cfg.Cursor.Targets[0].ConfigPath
+	}
+
 	if cfg.Cursor.ConfigPath == "" {
 		return fmt.Errorf("cursor config path is required")
 	}
 
-	if cfg.Sync.PullInterval <= 0 {
-		return fmt.Errorf("pull interval must be positive")
+	if err := ValidateTargets(cfg.Cursor.Targets); err != nil {
+		return err
 	}
 
-	if cfg.Sync.PushInterval <= 0 {
-		return fmt.Errorf("push interval must be positive")
+	for _, pattern := range cfg.Cursor.GlobalStorageAllow {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid global_storage_allow pattern %q: %w", pattern, err)
+		}
+	}
+
+	for _, entry := range cfg.Cursor.WorkspaceAllow {
+		if strings.TrimSpace(entry) == "" {
+			return fmt.Errorf("workspace_allow entries must not be empty")
+		}
+	}
+
+	if err := ValidateIntervals(cfg.Sync.PullInterval, cfg.Sync.PushInterval); err != nil {
+		return err
 	}
 
 	if cfg.Sync.DebounceTime < 10*time.Second {
@@ -269,17 +565,176 @@ func validate(cfg *Config) error {
 		cfg.Sync.DebounceTime = 10 * time.Second
 	}
 
+	if cfg.Sync.MaxRepoSize < 0 {
+		return fmt.Errorf("max_repo_size must not be negative (0 = no limit)")
+	}
+
+	if cfg.Sync.IncrementalPull && cfg.Sync.IncrementalMax <= 0 {
+		return fmt.Errorf("incremental_max_files must be positive when incremental_pull is enabled")
+	}
+
+	if cfg.Sync.ClockSkewTolerance < 0 {
+		return fmt.Errorf("clock_skew_tolerance must not be negative")
+	}
+
+	if cfg.Sync.IntervalJitter < 0 {
+		return fmt.Errorf("interval_jitter must not be negative (0 = disabled)")
+	}
+
+	if cfg.Network.Timeout < 0 {
+		return fmt.Errorf("network.timeout must not be negative (0 = default)")
+	}
+
+	if cfg.Sync.StaleWarning < 0 {
+		return fmt.Errorf("stale_warning must not be negative (0 = disabled)")
+	}
+
+	if strings.ContainsAny(cfg.Sync.CommitAuthorName+cfg.Sync.CommitAuthorEmail, "<>\r\n") {
+		return fmt.Errorf("commit_author_name and commit_author_email must not contain '<', '>' or line breaks")
+	}
+
+	if strings.ContainsAny(cfg.Sync.MachineAlias, "\r\n") {
+		return fmt.Errorf("machine_alias must be a single line")
+	}
+
+	if cfg.Sync.PushLease < 0 {
+		return fmt.Errorf("push_lease must not be negative (0 = disabled)")
+	}
+
+	if cfg.Sync.SquashWindow < 0 {
+		return fmt.Errorf("squash_window must not be negative (0 = disabled)")
+	}
+
+	if cfg.Sync.MaxDeletes < 0 {
+		return fmt.Errorf("max_deletes must not be negative (0 = no limit)")
+	}
+
+	if cfg.Sync.MaxDeletePercent < 0 || cfg.Sync.MaxDeletePercent > 100 {
+		return fmt.Errorf("max_delete_percent must be between 0 and 100 (0 = no limit)")
+	}
+
+	if cfg.Sync.UseLFS && len(cfg.Sync.LFSPatterns) == 0 {
+		return fmt.Errorf("lfs_patterns must not be empty when use_lfs is enabled")
+	}
+
+	switch cfg.Sync.Mode {
+	case "", ModeBidirectional, ModeMirrorPull, ModeMirrorPush:
+	default:
+		return fmt.Errorf("mode must be '%s', '%s' or '%s'", ModeBidirectional, ModeMirrorPull, ModeMirrorPush)
+	}
+
+	if cfg.Sync.DebounceMode != "" && cfg.Sync.DebounceMode != DebounceFile && cfg.Sync.DebounceMode != DebounceDir {
+		return fmt.Errorf("debounce_mode must be 'file' or 'dir'")
+	}
+
 	if cfg.Sync.ConflictResolve != "newer" && cfg.Sync.ConflictResolve != "local" && cfg.Sync.ConflictResolve != "remote" {
 		return fmt.Errorf("conflict_resolve must be 'newer', 'local', or 'remote'")
 	}
 
+	if cfg.Logging.Format != "" && cfg.Logging.Format != "text" && cfg.Logging.Format != "json" {
+		return fmt.Errorf("logging format must be 'text' or 'json'")
+	}
+
+	if err := ValidateNotify(cfg.Sync); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// ValidateNotify checks the webhook notification settings
+func ValidateNotify(sync Sync) error {
+	for _, event := range sync.NotifyOn {
+		if !notify.ValidEvent(event) {
+			return fmt.Errorf("notify_on contains unknown event '%s' (use 'error', 'conflict', 'success' or 'stale')", event)
+		}
+	}
+
+	if sync.NotifyFormat != "" && sync.NotifyFormat != notify.FormatJSON && sync.NotifyFormat != notify.FormatSlack {
+		return fmt.Errorf("notify_format must be 'json' or 'slack'")
+	}
+
+	return nil
+}
+
+// ValidateSubdir ensures repository.subdir stays inside the repository
+func ValidateSubdir(subdir string) error {
+	if subdir == "" {
+		return nil
+	}
+
+	clean := filepath.Clean(subdir)
+	if filepath.IsAbs(subdir) || clean == ".." || strings.HasPrefix(clean, ".."+string(filepath.Separator)) {
+		return fmt.Errorf("repository subdir must be a relative path inside the repository: %s", subdir)
+	}
+
+	if clean == ".git" || strings.HasPrefix(clean, ".git"+string(filepath.Separator)) {
+		return fmt.Errorf("repository subdir cannot be inside .git: %s", subdir)
+	}
+
+	return nil
+}
+
+// ValidateTargets checks that sync targets have unique names and distinct repository subdirs
+func ValidateTargets(targets []Target) error {
+	names := make(map[string]bool)
+	subdirs := make(map[string]string)
+
+	for _, target := range targets {
+		if target.Name == "" {
+			return fmt.Errorf("every cursor target needs a name")
+		}
+		if names[target.Name] {
+			return fmt.Errorf("duplicate cursor target name: %s", target.Name)
+		}
+		names[target.Name] = true
+
+		if target.ConfigPath == "" {
+			return fmt.Errorf("cursor target %s: config_path is required", target.Name)
+		}
+
+		if err := ValidateSubdir(target.Subdir); err != nil {
+			return fmt.Errorf("cursor target %s: %w", target.Name, err)
+		}
+
+		// Targets would overwrite each other's User directory in the repository
+		if len(targets) > 1 && target.Subdir == "" {
+			return fmt.Errorf("cursor target %s: subdir is required when syncing multiple targets", target.Name)
+		}
+
+		subdir := filepath.Clean(target.Subdir)
+		if other, exists := subdirs[subdir]; exists {
+			return fmt.Errorf("cursor targets %s and %s use the same subdir: %s", other, target.Name, target.Subdir)
+		}
+		subdirs[subdir] = target.Name
+	}
+
 	return nil
 }
 
 // validateCursorInstallation performs comprehensive Cursor installation validation
 func validateCursorInstallation(cfg *Config) error {
-	detector := cursor.NewDetector(cfg.Cursor.ConfigPath)
-	return detector.DetectAndValidate()
+	for _, target := range cfg.Cursor.SyncTargets() {
+		detector := cursor.NewDetector(target.ConfigPath)
+		if err := detector.DetectAndValidate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ValidateIntervals checks the sync pull and push intervals
+// Shared by config validation and the daemon's --pull-interval/--push-interval overrides
+func ValidateIntervals(pull, push time.Duration) error {
+	if pull <= 0 {
+		return fmt.Errorf("pull interval must be positive")
+	}
+
+	if push <= 0 {
+		return fmt.Errorf("push interval must be positive")
+	}
+
+	return nil
 }
 
 // parseTimeDurations manually parses time duration strings from viper
@@ -298,6 +753,13 @@ func parseTimeDurations(cfg *Config) error {
 		}
 	}
 
+	// Parse interval jitter
+	if jitterStr := viper.GetString("sync.interval_jitter"); jitterStr != "" {
+		if duration, err := time.ParseDuration(jitterStr); err == nil {
+			cfg.Sync.IntervalJitter = duration
+		}
+	}
+
 	// Parse debounce time
 	if debounceStr := viper.GetString("sync.debounce_time"); debounceStr != "" {
 		if duration, err := time.ParseDuration(debounceStr); err == nil {
@@ -319,18 +781,58 @@ func parseTimeDurations(cfg *Config) error {
 		}
 	}
 
+	// Parse privacy check interval
+	if privacyStr := viper.GetString("sync.privacy_check_interval"); privacyStr != "" {
+		if duration, err := time.ParseDuration(privacyStr); err == nil {
+			cfg.Sync.PrivacyCheckTTL = duration
+		}
+	}
+
+	// Parse clock skew tolerance
+	if skewStr := viper.GetString("sync.clock_skew_tolerance"); skewStr != "" {
+		if duration, err := time.ParseDuration(skewStr); err == nil {
+			cfg.Sync.ClockSkewTolerance = duration
+		}
+	}
+
+	// Parse squash window
+	if squashStr := viper.GetString("sync.squash_window"); squashStr != "" {
+		if duration, err := time.ParseDuration(squashStr); err == nil {
+			cfg.Sync.SquashWindow = duration
+		}
+	}
+
+	// Parse push lease
+	if leaseStr := viper.GetString("sync.push_lease"); leaseStr != "" {
+		if duration, err := time.ParseDuration(leaseStr); err == nil {
+			cfg.Sync.PushLease = duration
+		}
+	}
+
+	// Parse stale warning
+	if staleStr := viper.GetString("sync.stale_warning"); staleStr != "" {
+		if duration, err := time.ParseDuration(staleStr); err == nil {
+			cfg.Sync.StaleWarning = duration
+		}
+	}
+
+	// Parse network timeout; the --timeout flag is bound to it and wins over the config file
+	if timeoutStr := viper.GetString("network.timeout"); timeoutStr != "" {
+		if duration, err := time.ParseDuration(timeoutStr); err == nil {
+			cfg.Network.Timeout = duration
+		}
+	}
+
 	return nil
 }
 
 // UpdateRepositoryURL updates the repository URL in all configuration files
 func UpdateRepositoryURL(repoURL string) error {
 	// Update user's config file
-	home, err := os.UserHomeDir()
+	userConfigPath, err := FilePath()
 	if err != nil {
-		return fmt.Errorf("failed to get home directory: %w", err)
+		return err
 	}
-
-	userConfigPath := filepath.Join(home, ".cursor-sync", "config.yaml")
 	if err := updateConfigFileURL(userConfigPath, repoURL); err != nil {
 		return fmt.Errorf("failed to update user config: %w", err)
 	}
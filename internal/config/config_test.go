@@ -0,0 +1,144 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+func TestExpandPath(t *testing.T) {
+	home := filepath.Join("/", "home", "user")
+	t.Setenv("CURSOR_SYNC_TEST_XDG", "/data/config")
+
+	tests := []struct {
+		name    string
+		path    string
+		want    string
+		wantErr string
+	}{
+		{"home", "~/.config/Cursor", filepath.Join(home, ".config", "Cursor"), ""},
+		{"braced variable", "${CURSOR_SYNC_TEST_XDG}/Cursor", "/data/config/Cursor", ""},
+		{"plain variable", "$CURSOR_SYNC_TEST_XDG/Cursor", "/data/config/Cursor", ""},
+		{"unchanged", "/opt/cursor", "/opt/cursor", ""},
+		{"unset variable", "${CURSOR_SYNC_TEST_UNSET}/Cursor", "", "CURSOR_SYNC_TEST_UNSET is not set"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := expandPath("cursor.config_path", tt.path, home)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("expandPath(%q) error = %v, want containing %q", tt.path, err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("expandPath(%q) error = %v", tt.path, err)
+			}
+			if got != tt.want {
+				t.Errorf("expandPath(%q) = %q, want %q", tt.path, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLogDirExpandsLikeLoad(t *testing.T) {
+	t.Setenv("CURSOR_SYNC_TEST_STATE", "/data/state")
+	viper.Reset()
+	defer viper.Reset()
+
+	viper.Set("logging.log_dir", "$CURSOR_SYNC_TEST_STATE/cursor-sync/logs")
+	if got, err := LogDir(); err != nil || got != "/data/state/cursor-sync/logs" {
+		t.Errorf("LogDir() = %q, %v; want the variable expanded", got, err)
+	}
+
+	viper.Set("logging.log_dir", "$CURSOR_SYNC_TEST_UNSET/logs")
+	if _, err := LogDir(); err == nil || !strings.Contains(err.Error(), "CURSOR_SYNC_TEST_UNSET") {
+		t.Errorf("LogDir() with an unset variable error = %v, want it named", err)
+	}
+}
+
+func TestMachineName(t *testing.T) {
+	hostname, err := os.Hostname()
+	if err != nil {
+		t.Skip("no hostname:", err)
+	}
+
+	if got := (Sync{}).MachineName(); got != hostname {
+		t.Errorf("MachineName() = %q, want the hostname %q", got, hostname)
+	}
+
+	anonymized := Sync{AnonymizeHostname: true}.MachineName()
+	if anonymized == hostname || !strings.HasPrefix(anonymized, "machine-") || len(anonymized) != len("machine-")+8 {
+		t.Errorf("anonymized MachineName() = %q, want machine-<8 hex digits>", anonymized)
+	}
+	if again := (Sync{AnonymizeHostname: true}).MachineName(); again != anonymized {
+		t.Errorf("pseudonym changed between calls: %q, %q", anonymized, again)
+	}
+
+	if got := (Sync{AnonymizeHostname: true, MachineAlias: "work-laptop"}).MachineName(); got != "work-laptop" {
+		t.Errorf("MachineName() with alias = %q, want work-laptop", got)
+	}
+}
+
+// loadOlderConfig reads a config file written before the newer options existed the way Load
+// does, without the validation that needs a Cursor installation
+func loadOlderConfig(t *testing.T) *Config {
+	t.Helper()
+	viper.Reset()
+	t.Cleanup(viper.Reset)
+
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	older := "repository:\n  url: https://github.com/user/cursor-settings.git\nsync:\n  pull_interval: 5m\n  push_interval: 5m\n"
+	if err := os.WriteFile(path, []byte(older), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	setDefaults()
+	viper.SetConfigFile(path)
+	if err := viper.ReadInConfig(); err != nil {
+		t.Fatalf("ReadInConfig() error = %v", err)
+	}
+	var cfg Config
+	if err := viper.Unmarshal(&cfg); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	return &cfg
+}
+
+func TestOlderConfigsKeepPreviousBehaviour(t *testing.T) {
+	cfg := loadOlderConfig(t)
+
+	if !cfg.Sync.AutoPush {
+		t.Error("sync.auto_push = false for a config without the key, want true")
+	}
+}
+
+func TestOlderConfigsCachePrivacyChecks(t *testing.T) {
+	cfg := loadOlderConfig(t)
+
+	if cfg.Sync.PrivacyCheckTTL != time.Hour {
+		t.Errorf("privacy_check_interval = %v for a config without the key, want 1h", cfg.Sync.PrivacyCheckTTL)
+	}
+}
+
+func TestOlderConfigsGetDeleteLimits(t *testing.T) {
+	cfg := loadOlderConfig(t)
+
+	if cfg.Sync.MaxDeletes != 50 || cfg.Sync.MaxDeletePercent != 50 {
+		t.Errorf("max_deletes = %d, max_delete_percent = %d for a config without the keys, want 50 and 50",
+			cfg.Sync.MaxDeletes, cfg.Sync.MaxDeletePercent)
+	}
+}
+
+func TestOlderConfigsValidateJSON(t *testing.T) {
+	cfg := loadOlderConfig(t)
+
+	if !cfg.Sync.ValidateJSON {
+		t.Error("validate_json = false for a config without the key, want true")
+	}
+}
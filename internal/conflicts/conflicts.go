@@ -0,0 +1,104 @@
+package conflicts
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"cursor-sync/internal/git"
+	"cursor-sync/internal/paths"
+)
+
+// LogFileName is the append-only conflict log in the state directory
+const LogFileName = "conflicts.log"
+
+// Record is a single conflict log entry
+type Record struct {
+	git.ConflictEvent
+	Host string `json:"host,omitempty"`
+}
+
+var appendMutex sync.Mutex
+
+// LogPath returns the path of the conflict log
+func LogPath() (string, error) {
+	stateDir, err := paths.StateDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(stateDir, LogFileName), nil
+}
+
+// Append writes a conflict event to the conflict log as one JSON line
+func Append(event git.ConflictEvent) error {
+	path, err := LogPath()
+	if err != nil {
+		return err
+	}
+
+	record := Record{ConflictEvent: event}
+	record.Host, _ = os.Hostname()
+
+	data, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("failed to marshal conflict record: %w", err)
+	}
+
+	appendMutex.Lock()
+	defer appendMutex.Unlock()
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create conflict log directory: %w", err)
+	}
+
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open conflict log: %w", err)
+	}
+	defer file.Close()
+
+	if _, err := file.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("failed to write conflict log: %w", err)
+	}
+	return nil
+}
+
+// Recent returns up to limit of the most recent conflict records, oldest first
+// A missing log yields no records; lines that can't be parsed are skipped
+func Recent(limit int) ([]Record, error) {
+	path, err := LogPath()
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open conflict log: %w", err)
+	}
+	defer file.Close()
+
+	var records []Record
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var record Record
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			continue
+		}
+		records = append(records, record)
+		if limit > 0 && len(records) > limit {
+			records = records[1:]
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read conflict log: %w", err)
+	}
+
+	return records, nil
+}
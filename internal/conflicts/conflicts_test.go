@@ -0,0 +1,86 @@
+package conflicts
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"cursor-sync/internal/git"
+)
+
+func TestAppendAndRecentRoundTrip(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("XDG_STATE_HOME", "")
+
+	if records, err := Recent(10); err != nil || len(records) != 0 {
+		t.Fatalf("Recent() without a log = %v, %v; want nothing", records, err)
+	}
+
+	localTime := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	for _, winner := range []string{"local", "remote", "merged"} {
+		event := git.ConflictEvent{
+			Time:            localTime,
+			Strategy:        "newer",
+			Winner:          winner,
+			Files:           []string{"User/settings.json"},
+			LocalCommitTime: &localTime,
+		}
+		if err := Append(event); err != nil {
+			t.Fatalf("Append(%s) error = %v", winner, err)
+		}
+	}
+
+	records, err := Recent(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 3 {
+		t.Fatalf("Recent(0) returned %d records, want 3", len(records))
+	}
+	host, _ := os.Hostname()
+	first := records[0]
+	if first.Winner != "local" || first.Strategy != "newer" || first.Host != host ||
+		len(first.Files) != 1 || first.Files[0] != "User/settings.json" ||
+		first.LocalCommitTime == nil || !first.LocalCommitTime.Equal(localTime) {
+		t.Errorf("first record = %+v, want the appended local event from %s", first, host)
+	}
+
+	// The limit keeps the most recent records, oldest first
+	records, err = Recent(2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 2 || records[0].Winner != "remote" || records[1].Winner != "merged" {
+		t.Errorf("Recent(2) = %+v, want the remote and merged events", records)
+	}
+}
+
+func TestRecentSkipsUnparseableLines(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("XDG_STATE_HOME", "")
+
+	if err := Append(git.ConflictEvent{Winner: "local"}); err != nil {
+		t.Fatal(err)
+	}
+	path, err := LogPath()
+	if err != nil {
+		t.Fatal(err)
+	}
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	file.WriteString("{truncated\n")
+	file.Close()
+	if err := Append(git.ConflictEvent{Winner: "remote"}); err != nil {
+		t.Fatal(err)
+	}
+
+	records, err := Recent(10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 2 || records[0].Winner != "local" || records[1].Winner != "remote" {
+		t.Errorf("Recent() = %+v, want the two valid records", records)
+	}
+}
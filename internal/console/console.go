@@ -0,0 +1,111 @@
+// Package console formats CLI output for the stream it goes to: colors and emoji on a
+// terminal, plain text when piped, redirected or when NO_COLOR is set
+package console
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"golang.org/x/term"
+)
+
+// ANSI escape sequences
+const (
+	reset  = "\033[0m"
+	bold   = "\033[1m"
+	red    = "\033[31m"
+	green  = "\033[32m"
+	yellow = "\033[33m"
+	cyan   = "\033[36m"
+)
+
+// Style decides how output for one stream is decorated
+type Style struct {
+	Color bool // ANSI colors
+	Emoji bool // Emoji markers and banner rules; plain words otherwise
+}
+
+var (
+	// Stdout is the style of results and decorative output
+	Stdout = Detect(os.Stdout)
+	// Stderr is the style of errors
+	Stderr = Detect(os.Stderr)
+)
+
+// Detect returns the style for f: emoji on a terminal, colors too unless NO_COLOR is set
+// (https://no-color.org) or TERM is "dumb"
+func Detect(f *os.File) Style {
+	if !term.IsTerminal(int(f.Fd())) {
+		return Style{}
+	}
+	return Style{
+		Color: os.Getenv("NO_COLOR") == "" && os.Getenv("TERM") != "dumb",
+		Emoji: true,
+	}
+}
+
+// Heading formats a section title, underlined with a rule on a terminal
+func (s Style) Heading(emoji, title string) string {
+	if !s.Emoji {
+		return title
+	}
+	line := emoji + " " + s.paint(bold, title)
+	return line + "\n" + strings.Repeat("=", 80)
+}
+
+// Success formats a completed step ("✅ ..." or "OK: ...")
+func (s Style) Success(format string, args ...interface{}) string {
+	return s.marked("✅", "OK:", green, fmt.Sprintf(format, args...))
+}
+
+// Failure formats an error ("❌ ..." or "ERROR: ...")
+func (s Style) Failure(format string, args ...interface{}) string {
+	return s.marked("❌", "ERROR:", red, fmt.Sprintf(format, args...))
+}
+
+// Warning formats a problem that doesn't stop the command ("⚠️  ..." or "WARNING: ...")
+func (s Style) Warning(format string, args ...interface{}) string {
+	return s.marked("⚠️ ", "WARNING:", yellow, fmt.Sprintf(format, args...))
+}
+
+// Info formats a neutral step, with its emoji on a terminal only
+func (s Style) Info(emoji, format string, args ...interface{}) string {
+	message := fmt.Sprintf(format, args...)
+	if !s.Emoji {
+		return message
+	}
+	return emoji + " " + message
+}
+
+// Field formats a "label: value" line, indented below a heading. Labels are the same in
+// every style so scripts can match on them
+func (s Style) Field(indent int, label string, value interface{}) string {
+	return strings.Repeat(" ", indent) + s.paint(cyan, label+":") + " " + fmt.Sprint(value)
+}
+
+// Good highlights a value that is as it should be (e.g. "running")
+func (s Style) Good(value string) string {
+	return s.paint(green, value)
+}
+
+// Bad highlights a value that needs attention (e.g. "stopped")
+func (s Style) Bad(value string) string {
+	return s.paint(red, value)
+}
+
+// marked prefixes a message with an emoji on a terminal or a plain word otherwise
+func (s Style) marked(emoji, word, color, message string) string {
+	if !s.Emoji {
+		return word + " " + message
+	}
+	return s.paint(color, emoji+" "+message)
+}
+
+// paint wraps text in a color when colors are enabled
+func (s Style) paint(color, text string) string {
+	if !s.Color {
+		return text
+	}
+	return color + text + reset
+}
@@ -0,0 +1,48 @@
+package console
+
+import (
+	"os"
+	"testing"
+)
+
+func TestStyles(t *testing.T) {
+	plain := Style{}
+	if got := plain.Success("synced %d files", 3); got != "OK: synced 3 files" {
+		t.Errorf("plain Success() = %q", got)
+	}
+	if got := plain.Failure("push failed"); got != "ERROR: push failed" {
+		t.Errorf("plain Failure() = %q", got)
+	}
+	if got := plain.Heading("🔍", "Validation"); got != "Validation" {
+		t.Errorf("plain Heading() = %q", got)
+	}
+	if got := plain.Field(3, "Branch", "main"); got != "   Branch: main" {
+		t.Errorf("plain Field() = %q", got)
+	}
+
+	noColor := Style{Emoji: true}
+	if got := noColor.Success("done"); got != "✅ done" {
+		t.Errorf("emoji Success() = %q", got)
+	}
+	if got := noColor.Good("running"); got != "running" {
+		t.Errorf("Good() without colors = %q", got)
+	}
+
+	colored := Style{Color: true, Emoji: true}
+	if got := colored.Bad("stopped"); got != red+"stopped"+reset {
+		t.Errorf("colored Bad() = %q", got)
+	}
+}
+
+func TestDetectPipe(t *testing.T) {
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	defer w.Close()
+
+	if style := Detect(w); style.Color || style.Emoji {
+		t.Errorf("Detect(pipe) = %+v, want plain", style)
+	}
+}
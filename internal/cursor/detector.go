@@ -205,12 +205,21 @@ func GetDefaultCursorPath() string {
 		return ""
 	}
 
-	switch runtime.GOOS {
+	return defaultCursorPath(runtime.GOOS, home, os.Getenv("APPDATA"))
+}
+
+// defaultCursorPath returns Cursor's configuration directory for an OS
+// On Windows it honours %APPDATA% (which may be redirected) like the setup wizard's detection
+func defaultCursorPath(goos, home, appData string) string {
+	switch goos {
 	case "darwin": // macOS
 		return filepath.Join(home, "Library", "Application Support", "Cursor")
 	case "linux":
 		return filepath.Join(home, ".config", "Cursor")
 	case "windows":
+		if appData != "" {
+			return filepath.Join(appData, "Cursor")
+		}
 		return filepath.Join(home, "AppData", "Roaming", "Cursor")
 	default:
 		return ""
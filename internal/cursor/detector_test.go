@@ -0,0 +1,33 @@
+package cursor
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestDefaultCursorPath(t *testing.T) {
+	home := filepath.Join("home", "user")
+	redirected := filepath.Join("D:", "Profiles", "user", "Roaming")
+
+	tests := []struct {
+		name    string
+		goos    string
+		appData string
+		want    string
+	}{
+		{"macOS", "darwin", "", filepath.Join(home, "Library", "Application Support", "Cursor")},
+		{"linux", "linux", "", filepath.Join(home, ".config", "Cursor")},
+		{"windows uses APPDATA", "windows", filepath.Join(home, "AppData", "Roaming"), filepath.Join(home, "AppData", "Roaming", "Cursor")},
+		{"windows redirected APPDATA", "windows", redirected, filepath.Join(redirected, "Cursor")},
+		{"windows without APPDATA", "windows", "", filepath.Join(home, "AppData", "Roaming", "Cursor")},
+		{"unsupported", "plan9", "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := defaultCursorPath(tt.goos, home, tt.appData); got != tt.want {
+				t.Errorf("defaultCursorPath(%q) = %q, want %q", tt.goos, got, tt.want)
+			}
+		})
+	}
+}
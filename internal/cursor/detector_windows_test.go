@@ -0,0 +1,15 @@
+package cursor
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestGetDefaultCursorPathHonoursAPPDATA(t *testing.T) {
+	appData := t.TempDir()
+	t.Setenv("APPDATA", appData)
+
+	if got, want := GetDefaultCursorPath(), filepath.Join(appData, "Cursor"); got != want {
+		t.Errorf("GetDefaultCursorPath() = %q, want %q", got, want)
+	}
+}
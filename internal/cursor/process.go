@@ -0,0 +1,34 @@
+package cursor
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// IsRunning reports whether a Cursor IDE process is currently running
+func IsRunning() (bool, error) {
+	switch runtime.GOOS {
+	case "darwin", "linux":
+		// pgrep exits with 1 when no process matched
+		err := exec.Command("pgrep", "-i", "-x", "cursor").Run()
+		if err == nil {
+			return true, nil
+		}
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && exitErr.ExitCode() == 1 {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to check for running Cursor process: %w", err)
+	case "windows":
+		output, err := exec.Command("tasklist", "/FI", "IMAGENAME eq Cursor.exe", "/NH").Output()
+		if err != nil {
+			return false, fmt.Errorf("failed to check for running Cursor process: %w", err)
+		}
+		return strings.Contains(strings.ToLower(string(output)), "cursor.exe"), nil
+	default:
+		return false, fmt.Errorf("process detection not supported on %s", runtime.GOOS)
+	}
+}
@@ -0,0 +1,402 @@
+package daemon
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"cursor-sync/internal/logger"
+	"cursor-sync/internal/paths"
+)
+
+// Control commands accepted on the daemon socket
+const (
+	CommandPause        = "pause"         // Optional argument: duration, e.g. "pause 30m". Also writes the pause file
+	CommandResume       = "resume"        // Also removes the pause file
+	CommandSyncNow      = "sync-now"      // Pull and push immediately, waiting for the result
+	CommandStatus       = "status"        // Reply carries a ControlStatus
+	CommandReloadConfig = "reload-config" // Re-read the configuration files
+	CommandPull         = "pull"          // Pull only; optional arguments: confirm-deletes, force, include=, exclude=
+	CommandPush         = "push"          // Commit and push, even with sync.auto_push off; optional arguments: committed (push existing commits only), confirm-deletes, force, include=, exclude=
+)
+
+// Arguments of the pull and push control commands
+const (
+	ArgCommitted      = "committed"       // Push existing commits only, without committing local changes
+	ArgConfirmDeletes = "confirm-deletes" // Allow deletions over sync.max_deletes / sync.max_delete_percent
+	ArgForce          = "force"           // Re-hash every file instead of trusting cached hashes
+	ArgInclude        = "include"         // include=<pattern>: only sync matching files (repeatable)
+	ArgExclude        = "exclude"         // exclude=<pattern>: leave matching files alone (repeatable)
+)
+
+// controlReadTimeout bounds how long a client may take to send its command
+const controlReadTimeout = 5 * time.Second
+
+// ErrDaemonNotRunning is returned by SendControl when nothing listens on the control socket
+var ErrDaemonNotRunning = errors.New("daemon is not running")
+
+// ControlResponse is the single JSON line the daemon answers a command with
+type ControlResponse struct {
+	OK      bool           `json:"ok"`
+	Message string         `json:"message,omitempty"`
+	Status  *ControlStatus `json:"status,omitempty"`
+}
+
+// ControlStatus describes the running daemon
+type ControlStatus struct {
+	PID            int        `json:"pid"`
+	Paused         bool       `json:"paused"`
+	PausedUntil    *time.Time `json:"paused_until,omitempty"`
+	SyncInProgress bool       `json:"sync_in_progress"`
+	LastSync       *time.Time `json:"last_sync,omitempty"`
+	MissingPaths   []string   `json:"missing_paths,omitempty"`
+	Repository     string     `json:"repository"`
+	Branch         string     `json:"branch"`
+	Mode           string     `json:"mode"`
+	PendingPush    int        `json:"pending_push"` // Local commits not pushed yet
+	// sync.stale_warning, or 0 when this machine never pushes
+	StaleWarning time.Duration `json:"stale_warning"`
+	// Effective intervals and the configured ones (they differ when overridden on the command line)
+	PullInterval       time.Duration `json:"pull_interval"`
+	PushInterval       time.Duration `json:"push_interval"`
+	ConfigPullInterval time.Duration `json:"config_pull_interval"`
+	ConfigPushInterval time.Duration `json:"config_push_interval"`
+}
+
+// ControlSocketPath returns the Unix socket the daemon accepts control commands on
+func ControlSocketPath() (string, error) {
+	stateDir, err := paths.StateDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(stateDir, "daemon.sock"), nil
+}
+
+// SendControl sends a command to the running daemon and waits up to timeout for its reply
+// Returns ErrDaemonNotRunning when no daemon is listening
+func SendControl(command string, timeout time.Duration) (*ControlResponse, error) {
+	socketPath, err := ControlSocketPath()
+	if err != nil {
+		return nil, err
+	}
+
+	conn, err := net.DialTimeout("unix", socketPath, time.Second)
+	if err != nil {
+		return nil, ErrDaemonNotRunning
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(timeout))
+
+	if _, err := fmt.Fprintln(conn, command); err != nil {
+		return nil, fmt.Errorf("failed to send command: %w", err)
+	}
+
+	var response ControlResponse
+	if err := json.NewDecoder(conn).Decode(&response); err != nil {
+		return nil, fmt.Errorf("failed to read daemon response: %w", err)
+	}
+	if !response.OK {
+		return &response, errors.New(response.Message)
+	}
+	return &response, nil
+}
+
+// serveControl accepts control commands on the control socket until ctx is done
+func (d *Daemon) serveControl(ctx context.Context) {
+	socketPath, err := ControlSocketPath()
+	if err != nil {
+		logger.Warn("Control socket disabled: %v", err)
+		return
+	}
+
+	// A socket left behind by a crashed daemon blocks Listen; a live one means another daemon
+	if conn, err := net.DialTimeout("unix", socketPath, time.Second); err == nil {
+		conn.Close()
+		logger.Warn("Another daemon is listening on %s - control socket disabled", socketPath)
+		return
+	}
+	os.Remove(socketPath)
+	if err := os.MkdirAll(filepath.Dir(socketPath), 0755); err != nil {
+		logger.Warn("Control socket disabled: %v", err)
+		return
+	}
+
+	listener, err := net.Listen("unix", socketPath)
+	if err != nil {
+		logger.Warn("Control socket disabled: %v", err)
+		return
+	}
+	if err := os.Chmod(socketPath, 0600); err != nil {
+		logger.Warn("Failed to restrict control socket permissions: %v", err)
+	}
+	logger.Debug("Control socket listening on %s", socketPath)
+
+	go func() {
+		<-ctx.Done()
+		listener.Close() // Also removes the socket file
+	}()
+
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			if ctx.Err() == nil {
+				logger.Warn("Control socket stopped: %v", err)
+			}
+			return
+		}
+		go d.handleControl(conn)
+	}
+}
+
+// handleControl answers a single command
+func (d *Daemon) handleControl(conn net.Conn) {
+	defer conn.Close()
+
+	conn.SetReadDeadline(time.Now().Add(controlReadTimeout))
+	line, err := bufio.NewReader(conn).ReadString('\n')
+	if err != nil {
+		return
+	}
+	conn.SetReadDeadline(time.Time{})
+
+	response := d.runControl(strings.Fields(line))
+	data, _ := json.Marshal(response)
+	conn.Write(append(data, '\n'))
+}
+
+// runControl executes a control command
+func (d *Daemon) runControl(fields []string) ControlResponse {
+	if len(fields) == 0 {
+		return ControlResponse{Message: "empty command"}
+	}
+	logger.Debug("Control command: %s", strings.Join(fields, " "))
+
+	switch fields[0] {
+	case CommandPause:
+		if len(fields) > 1 {
+			duration, err := time.ParseDuration(fields[1])
+			if err != nil || duration <= 0 {
+				return ControlResponse{Message: fmt.Sprintf("invalid pause duration %q", fields[1])}
+			}
+			d.PauseFor(duration)
+			persistPause(duration)
+			return ControlResponse{OK: true, Message: fmt.Sprintf("paused until %s", time.Now().Add(duration).Format("15:04:05"))}
+		}
+		d.SetPaused(true)
+		persistPause(0)
+		return ControlResponse{OK: true, Message: "paused"}
+
+	case CommandResume:
+		d.SetPaused(false)
+		if pauseFile, err := PauseFilePath(); err == nil {
+			os.Remove(pauseFile)
+		}
+		return ControlResponse{OK: true, Message: "resumed"}
+
+	case CommandSyncNow:
+		if err := d.syncNow(); err != nil {
+			return ControlResponse{Message: err.Error()}
+		}
+		return ControlResponse{OK: true, Message: "sync completed"}
+
+	case CommandPull:
+		err := d.runManualSync("Pull", func() error {
+			d.syncer.ConfirmDeletes(hasArgument(fields, ArgConfirmDeletes))
+			defer d.syncer.ConfirmDeletes(false)
+			d.syncer.SetScope(argumentValues(fields, ArgInclude), argumentValues(fields, ArgExclude))
+			defer d.syncer.SetScope(nil, nil)
+			if hasArgument(fields, ArgForce) {
+				d.syncer.ForcePull()
+			}
+			return d.syncer.SyncFromRemote()
+		})
+		if err != nil {
+			return ControlResponse{Message: err.Error()}
+		}
+		return ControlResponse{OK: true, Message: "pulled remote changes"}
+
+	case CommandPush:
+		if hasArgument(fields, ArgCommitted) {
+			var pushed int
+			err := d.runManualSync("Push", func() (err error) {
+				pushed, err = d.syncer.PushPending()
+				return err
+			})
+			if err != nil {
+				return ControlResponse{Message: err.Error()}
+			}
+			return ControlResponse{OK: true, Message: fmt.Sprintf("pushed %d commit(s)", pushed)}
+		}
+
+		err := d.runManualSync("Push", func() error {
+			d.syncer.ConfirmDeletes(hasArgument(fields, ArgConfirmDeletes))
+			defer d.syncer.ConfirmDeletes(false)
+			d.syncer.SetScope(argumentValues(fields, ArgInclude), argumentValues(fields, ArgExclude))
+			defer d.syncer.SetScope(nil, nil)
+			if hasArgument(fields, ArgForce) {
+				d.syncer.ForcePush()
+			}
+			return d.syncer.PushLocalChanges()
+		})
+		if err != nil {
+			return ControlResponse{Message: err.Error()}
+		}
+		return ControlResponse{OK: true, Message: "pushed local changes"}
+
+	case CommandStatus:
+		status := d.controlStatus()
+		return ControlResponse{OK: true, Status: &status}
+
+	case CommandReloadConfig:
+		message, err := d.reloadConfig()
+		if err != nil {
+			return ControlResponse{Message: err.Error()}
+		}
+		return ControlResponse{OK: true, Message: message}
+
+	default:
+		return ControlResponse{Message: fmt.Sprintf("unknown command: %s", fields[0])}
+	}
+}
+
+// syncNow runs a full pull and push immediately, bypassing the minimum sync interval
+// but never running alongside another sync
+func (d *Daemon) syncNow() error {
+	if d.isPaused() {
+		return fmt.Errorf("daemon is paused - resume it first")
+	}
+	if !d.checkConfigPaths() {
+		return fmt.Errorf("cursor config directory is missing")
+	}
+
+	d.syncMutex.Lock()
+	if d.syncInProgress {
+		d.syncMutex.Unlock()
+		return fmt.Errorf("a sync is already in progress")
+	}
+	d.syncInProgress = true
+	d.lastSyncTime = time.Now()
+	d.syncMutex.Unlock()
+
+	logger.Info("🔄 Sync requested via control socket")
+	return d.performPeriodicSync()
+}
+
+// runManualSync runs a pull or push requested through the control socket, never
+// alongside another sync
+func (d *Daemon) runManualSync(name string, run func() error) error {
+	if d.isPaused() {
+		return fmt.Errorf("daemon is paused - resume it first")
+	}
+	if !d.checkConfigPaths() {
+		return fmt.Errorf("cursor config directory is missing")
+	}
+
+	d.syncMutex.Lock()
+	if d.syncInProgress {
+		d.syncMutex.Unlock()
+		return fmt.Errorf("a sync is already in progress")
+	}
+	d.syncInProgress = true
+	d.lastSyncTime = time.Now()
+	d.syncMutex.Unlock()
+	defer d.endSync()
+
+	// Disable file watcher during sync to prevent infinite loops
+	if d.watcher != nil {
+		d.watcher.Disable()
+		defer d.watcher.Enable()
+	}
+
+	logger.Info("🔄 %s requested via control socket", name)
+	return run()
+}
+
+// hasArgument reports whether a control command carries the given argument
+func hasArgument(fields []string, argument string) bool {
+	for _, field := range fields[1:] {
+		if field == argument {
+			return true
+		}
+	}
+	return false
+}
+
+// ValueArgument encodes a name=value control command argument; the value is escaped so
+// it may contain spaces
+func ValueArgument(name, value string) string {
+	return name + "=" + url.QueryEscape(value)
+}
+
+// argumentValues returns the values of every name=value argument of a control command
+func argumentValues(fields []string, name string) []string {
+	var values []string
+	for _, field := range fields[1:] {
+		if encoded, ok := strings.CutPrefix(field, name+"="); ok {
+			if value, err := url.QueryUnescape(encoded); err == nil && value != "" {
+				values = append(values, value)
+			}
+		}
+	}
+	return values
+}
+
+// controlStatus snapshots the daemon state
+func (d *Daemon) controlStatus() ControlStatus {
+	cfg := d.currentConfig()
+	status := ControlStatus{
+		PID:        os.Getpid(),
+		Paused:     d.isPaused(),
+		Repository: cfg.Repository.URL,
+		Branch:     cfg.Repository.Branch,
+	}
+
+	d.pauseMutex.Lock()
+	if d.paused && !d.pausedUntil.IsZero() {
+		until := d.pausedUntil
+		status.PausedUntil = &until
+	}
+	d.pauseMutex.Unlock()
+
+	if pauseFile, err := PauseFilePath(); err == nil && status.PausedUntil == nil {
+		if _, until := ReadPauseFile(pauseFile, time.Now()); !until.IsZero() {
+			status.PausedUntil = &until
+		}
+	}
+
+	d.syncMutex.Lock()
+	status.PullInterval = cfg.Sync.PullInterval
+	status.PushInterval = cfg.Sync.PushInterval
+	status.Mode = cfg.Sync.Mode
+	status.ConfigPullInterval = d.configPullInterval
+	status.ConfigPushInterval = d.configPushInterval
+	status.SyncInProgress = d.syncInProgress
+	if cfg.Sync.Pushes() {
+		status.StaleWarning = cfg.Sync.StaleWarning
+	}
+	if !d.lastSyncTime.IsZero() {
+		last := d.lastSyncTime
+		status.LastSync = &last
+	}
+	d.syncMutex.Unlock()
+
+	d.pathMutex.Lock()
+	status.MissingPaths = append([]string(nil), d.missingPaths...)
+	d.pathMutex.Unlock()
+
+	if d.syncer != nil && !status.SyncInProgress {
+		if pending, err := d.syncer.PendingPushCount(); err == nil {
+			status.PendingPush = pending
+		}
+	}
+
+	return status
+}
@@ -0,0 +1,86 @@
+package daemon
+
+import (
+	"context"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"cursor-sync/internal/config"
+)
+
+func TestControlSocketPauseResumeStatus(t *testing.T) {
+	// Short path: Unix socket paths are limited to ~100 bytes
+	home, err := os.MkdirTemp("", "cs")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(home)
+	t.Setenv("HOME", home)
+
+	if _, err := SendControl(CommandStatus, time.Second); err != ErrDaemonNotRunning {
+		t.Fatalf("SendControl() without daemon error = %v, want ErrDaemonNotRunning", err)
+	}
+
+	d := &Daemon{config: &config.Config{Repository: config.Repository{URL: "https://github.com/u/r", Branch: "main"}}}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go d.serveControl(ctx)
+
+	// Wait for the listener
+	var response *ControlResponse
+	for i := 0; i < 50; i++ {
+		if response, err = SendControl(CommandStatus, time.Second); err != ErrDaemonNotRunning {
+			break
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	if err != nil || response.Status == nil || response.Status.Paused || response.Status.Branch != "main" {
+		t.Fatalf("status = %+v, %v; want running, unpaused on main", response, err)
+	}
+
+	if _, err := SendControl(CommandPause+" 30m", time.Second); err != nil {
+		t.Fatalf("pause error = %v", err)
+	}
+	if !d.isPaused() {
+		t.Error("daemon not paused after pause command")
+	}
+	// The pause outlives the process: a restarted daemon reads it from the pause file
+	if !(&Daemon{}).isPaused() {
+		t.Error("restarted daemon not paused after pause command")
+	}
+	response, err = SendControl(CommandStatus, time.Second)
+	if err != nil || response.Status.PausedUntil == nil {
+		t.Errorf("status after timed pause = %+v, %v; want paused_until set", response, err)
+	}
+	for _, command := range []string{CommandPull, CommandPush + " " + ArgCommitted} {
+		if _, err := SendControl(command, time.Second); err == nil {
+			t.Errorf("%q succeeded while paused", command)
+		}
+	}
+
+	if _, err := SendControl(CommandResume, time.Second); err != nil {
+		t.Fatalf("resume error = %v", err)
+	}
+	if d.isPaused() || (&Daemon{}).isPaused() {
+		t.Error("daemon still paused after resume command")
+	}
+
+	if _, err := SendControl("bogus", time.Second); err == nil {
+		t.Error("unknown command succeeded")
+	}
+}
+
+func TestValueArgumentsSurviveTheControlProtocol(t *testing.T) {
+	command := CommandPush + " " + ValueArgument(ArgInclude, "User/My Snippets/**") + " " +
+		ValueArgument(ArgInclude, "User/settings.json") + " " + ValueArgument(ArgExclude, "User/a=b")
+	fields := strings.Fields(command)
+
+	if got := argumentValues(fields, ArgInclude); len(got) != 2 || got[0] != "User/My Snippets/**" || got[1] != "User/settings.json" {
+		t.Errorf("include values = %q", got)
+	}
+	if got := argumentValues(fields, ArgExclude); len(got) != 1 || got[0] != "User/a=b" {
+		t.Errorf("exclude values = %q", got)
+	}
+}
@@ -2,6 +2,7 @@ package daemon
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -10,24 +11,55 @@ import (
 
 	"cursor-sync/internal/auth"
 	"cursor-sync/internal/config"
+	"cursor-sync/internal/git"
 	"cursor-sync/internal/logger"
+	"cursor-sync/internal/notify"
+	"cursor-sync/internal/ratelimit"
 	syncpkg "cursor-sync/internal/sync"
+	"cursor-sync/internal/update"
+	"cursor-sync/internal/version"
 	"cursor-sync/internal/watcher"
 )
 
 // Daemon represents the main sync daemon
 type Daemon struct {
+	// Replaced as a whole by a live reload and never changed in place once published:
+	// read it with currentConfig and keep that snapshot for the rest of the operation
 	config         *config.Config
+	configMutex    sync.RWMutex
 	syncer         *syncpkg.Syncer
 	watcher        *watcher.Watcher
+	notifier       *notify.Notifier // nil unless sync.notify_url is set
 	paused         bool
 	syncMutex      sync.Mutex // Prevents concurrent syncs
 	lastSyncTime   time.Time  // Track when last sync occurred
 	syncInProgress bool       // Track if sync is currently in progress
+	// User directories that have disappeared (syncing is suspended while any is missing)
+	missingPaths []string
+	pathMutex    sync.Mutex
+	// Guards paused and pausedUntil, which signals and the control socket toggle in-process
+	pauseMutex  sync.Mutex
+	pausedUntil time.Time // Zero for an indefinite in-process pause
+	// Signalled after a live config reload so the periodic loop picks up new intervals
+	configReloaded chan struct{}
+	// Intervals from the daemon command line (0 = use the configured one) and the
+	// configured values they replace; guarded by syncMutex like config reloads
+	pullOverride       time.Duration
+	pushOverride       time.Duration
+	configPullInterval time.Duration
+	configPushInterval time.Duration
+	// Whether the current stretch without a successful push was already reported
+	staleReported bool
+	// Whether deletions held back by the mass-delete limits were already reported
+	deletesReported bool
 }
 
+// configPathCheckInterval is how often the Cursor User directories are checked for existence
+const configPathCheckInterval = 10 * time.Second
+
 // New creates a new daemon instance
-func New(cfg *config.Config) (*Daemon, error) {
+// verbose enables debug logging and mirrors the log file to stdout
+func New(cfg *config.Config, verbose bool) (*Daemon, error) {
 	// Check GitHub token availability first
 	if !auth.HasValidToken() {
 		auth.ShowTokenRequiredMessage()
@@ -35,7 +67,11 @@ func New(cfg *config.Config) (*Daemon, error) {
 	}
 
 	// Initialize logger with config
-	if err := logger.InitWithConfig(cfg.Logging.Level, cfg.Logging.LogDir, false); err != nil {
+	if err := logger.InitWithConfig(cfg.Logging.Level, cfg.Logging.Format, cfg.Logging.LogDir, verbose, logger.Rotation{
+		MaxSize:  cfg.Logging.MaxSize,
+		MaxDays:  cfg.Logging.MaxDays,
+		Compress: cfg.Logging.Compress,
+	}); err != nil {
 		return nil, fmt.Errorf("failed to initialize logger: %w", err)
 	}
 
@@ -54,25 +90,154 @@ func New(cfg *config.Config) (*Daemon, error) {
 		}
 	}
 
-	return &Daemon{
+	d := &Daemon{
 		config:         cfg,
 		syncer:         syncer,
 		watcher:        fileWatcher,
+		notifier:       notify.New(cfg.Sync.NotifyURL, cfg.Sync.NotifyOn, cfg.Sync.NotifyFormat),
 		paused:         false,
 		lastSyncTime:   time.Time{}, // Initialize to zero time
 		syncInProgress: false,
-	}, nil
+		configReloaded: make(chan struct{}, 1),
+	}
+	d.applyIntervalOverrides(cfg)
+
+	syncer.SetConflictHandler(func(event git.ConflictEvent) {
+		detail := fmt.Sprintf("Conflict resolved with strategy '%s': %s changes won", event.Strategy, event.Winner)
+		d.notifier.Notify(notify.EventConflict, cfg.Repository.URL, nil, detail)
+	})
+
+	return d, nil
+}
+
+// ForcePrivacyCheck makes the next privacy check bypass the cached result
+func (d *Daemon) ForcePrivacyCheck() {
+	d.syncer.ForcePrivacyCheck()
+}
+
+// notifyResult sends an error or success notification for a finished sync
+func (d *Daemon) notifyResult(kind string, err error) {
+	// A sync aborted by shutdown didn't fail
+	if errors.Is(err, context.Canceled) {
+		return
+	}
+	d.checkStalePush()
+	repository := d.currentConfig().Repository.URL
+	if errors.Is(err, syncpkg.ErrTooManyDeletes) {
+		d.reportHeldDeletes(kind, err)
+		return
+	}
+	if err != nil {
+		d.notifier.Notify(notify.EventError, repository, err, fmt.Sprintf("%s sync failed", kind))
+		return
+	}
+	d.deletesReported = false
+	d.notifier.Notify(notify.EventSuccess, repository, nil, fmt.Sprintf("%s sync completed", kind))
+}
+
+// reportHeldDeletes tells the user once per stretch that syncs keep refusing a mass deletion,
+// which only 'cursor-sync sync --confirm-deletes' lets through
+func (d *Daemon) reportHeldDeletes(kind string, err error) {
+	if d.deletesReported {
+		return
+	}
+	d.deletesReported = true
+
+	cfg := d.currentConfig()
+	detail := fmt.Sprintf("%s sync held back deletions - run 'cursor-sync sync --confirm-deletes' if they are intended", kind)
+	d.notifier.Notify(notify.EventError, cfg.Repository.URL, err, detail)
+	if cfg.Sync.DesktopNotify {
+		if err := notify.Desktop("cursor-sync deletions held", detail); err != nil {
+			logger.Debug("Desktop notification failed: %v", err)
+		}
+	}
+}
+
+// currentConfig returns the configuration in effect
+func (d *Daemon) currentConfig() *config.Config {
+	d.configMutex.RLock()
+	defer d.configMutex.RUnlock()
+	return d.config
+}
+
+// publishConfig makes cfg the configuration of the daemon, syncer and watcher. cfg must
+// not be changed afterwards, and no sync may be running while it's swapped in
+func (d *Daemon) publishConfig(cfg *config.Config) {
+	d.configMutex.Lock()
+	d.config = cfg
+	d.configMutex.Unlock()
+
+	if d.syncer != nil {
+		d.syncer.SetConfig(cfg)
+	}
+	if d.watcher != nil {
+		d.watcher.SetConfig(cfg)
+	}
+}
+
+// checkStalePush warns once per stretch in which the remote has been missing local
+// commits for longer than sync.stale_warning, e.g. because every push fails
+func (d *Daemon) checkStalePush() {
+	cfg := d.currentConfig()
+	if !cfg.Sync.Pushes() {
+		return
+	}
+	status, err := syncpkg.ReadStatus()
+	if err != nil {
+		return
+	}
+	age, stale := status.PushStale(cfg.Sync.StaleWarning, time.Now())
+	if !stale {
+		d.staleReported = false
+		return
+	}
+	if d.staleReported {
+		return
+	}
+	d.staleReported = true
+
+	detail := fmt.Sprintf("No successful push for %v (sync.stale_warning: %v)", age.Round(time.Minute), cfg.Sync.StaleWarning)
+	logger.Warn("⏰ %s - local changes aren't reaching the repository, check the log for push errors", detail)
+	d.notifier.Notify(notify.EventStale, cfg.Repository.URL, nil, detail)
 }
 
 // Start starts the daemon
 func (d *Daemon) Start(ctx context.Context) error {
-	logger.Info("Starting Cursor Sync daemon...")
+	logger.Info("Starting Cursor Sync daemon %s...", version.Get())
+
+	// Stop hash workers when the daemon exits
+	defer d.syncer.Close()
+
+	// A shutdown signal aborts clones, pulls and pushes still in flight
+	d.syncer.SetContext(ctx)
 
 	// Initialize syncer
 	if err := d.syncer.Initialize(); err != nil {
 		return fmt.Errorf("failed to initialize syncer: %w", err)
 	}
 
+	// Suspend syncing while a Cursor User directory is missing and resume once it's back
+	go d.monitorConfigPaths(ctx)
+
+	// Pause/resume on SIGUSR1/SIGUSR2 in addition to the pause file
+	go d.handlePauseSignals(ctx)
+
+	// Accept commands from the CLI on daemon.sock in the state directory
+	go d.serveControl(ctx)
+
+	// Apply edits to the config file without a restart
+	go d.watchConfigFile(ctx)
+
+	// Tell the user about newer releases; installing is left to 'cursor-sync update'
+	cfg := d.currentConfig()
+	if cfg.Update.CheckEnabled {
+		go d.checkForUpdate(ctx)
+	}
+
+	if cfg.Sync.Mode == config.ModeMirrorPull || cfg.Sync.Mode == config.ModeMirrorPush {
+		logger.Info("🪞 Mirror mode %s: %s", cfg.Sync.Mode, config.DescribeMode(cfg.Sync.Mode))
+	}
+
 	// Start DUAL SYNC SYSTEM: Real-time (primary) + Periodic (fallback)
 
 	// PRIMARY: Start real-time file watcher (fsnotify) FIRST
@@ -99,16 +264,11 @@ func (d *Daemon) Start(ctx context.Context) error {
 		logger.Info("Initial sync completed successfully")
 	}
 
-	// FALLBACK: Start periodic sync timers
+	// FALLBACK: Start periodic sync timer
 	logger.Info("🚀 Starting FALLBACK sync method: Periodic intervals")
-	pullTicker := time.NewTicker(d.config.Sync.PullInterval)
-	pushTicker := time.NewTicker(d.config.Sync.PushInterval)
-
-	defer pullTicker.Stop()
-	defer pushTicker.Stop()
 
-	// Start periodic sync loops (running in parallel with real-time)
-	go d.syncLoop(ctx, pullTicker, pushTicker)
+	// Start periodic sync loop (running in parallel with real-time)
+	go d.syncLoop(ctx)
 
 	logger.Info("Daemon started successfully")
 
@@ -120,40 +280,105 @@ func (d *Daemon) Start(ctx context.Context) error {
 }
 
 // syncLoop handles periodic sync operations (fallback method)
-func (d *Daemon) syncLoop(ctx context.Context, pullTicker, pushTicker *time.Ticker) {
+func (d *Daemon) syncLoop(ctx context.Context) {
+	cfg := d.currentConfig()
 	logger.Info("🕒 Periodic sync active (fallback method) - Pull: %v, Push: %v",
-		d.config.Sync.PullInterval, d.config.Sync.PushInterval)
+		cfg.Sync.PullInterval, cfg.Sync.PushInterval)
+	if jitter := cfg.Sync.IntervalJitter; jitter > 0 {
+		logger.Info("🎲 Each periodic sync is delayed by up to %v (sync.interval_jitter)", jitter)
+	}
 
 	// Use a single combined timer to prevent concurrent pull/push operations
-	minInterval := d.config.Sync.PullInterval
-	if d.config.Sync.PushInterval < minInterval {
-		minInterval = d.config.Sync.PushInterval
-	}
+	minInterval := d.periodicInterval()
 
-	// Create a single timer for periodic comprehensive sync
-	periodicTicker := time.NewTicker(minInterval)
-	defer periodicTicker.Stop()
+	// Create a single timer for periodic comprehensive sync, re-armed with a fresh
+	// random offset after every cycle
+	periodicTimer := time.NewTimer(d.periodicDelay(minInterval))
+	defer periodicTimer.Stop()
 
 	for {
 		select {
 		case <-ctx.Done():
 			logger.Info("Periodic sync loop shutting down")
 			return
-		case <-periodicTicker.C:
-			if !d.isPaused() && d.canStartSync() {
+		case <-d.configReloaded:
+			if interval := d.periodicInterval(); interval != minInterval {
+				minInterval = interval
+				if !periodicTimer.Stop() {
+					<-periodicTimer.C
+				}
+				periodicTimer.Reset(d.periodicDelay(minInterval))
+				logger.Info("🕒 Periodic sync interval changed to %v", minInterval)
+			}
+		case <-periodicTimer.C:
+			if !d.isPaused() && d.tryStartSync() {
 				logger.Debug("🔄 Periodic comprehensive sync triggered")
 				d.performPeriodicSync()
 			}
+			periodicTimer.Reset(d.periodicDelay(minInterval))
 		}
 	}
 }
 
+// SetIntervalOverrides makes pull/push intervals take precedence over the configuration
+// (0 keeps the configured interval), also across live config reloads
+// Call it before Start: the syncer's configuration is only swapped while no sync runs
+func (d *Daemon) SetIntervalOverrides(pull, push time.Duration) {
+	d.syncMutex.Lock()
+	defer d.syncMutex.Unlock()
+
+	cfg := *d.currentConfig()
+	cfg.Sync.PullInterval = d.configPullInterval
+	cfg.Sync.PushInterval = d.configPushInterval
+	d.pullOverride = pull
+	d.pushOverride = push
+	d.applyIntervalOverrides(&cfg)
+	d.publishConfig(&cfg)
+}
+
+// applyIntervalOverrides remembers the configured intervals of a configuration that isn't
+// published yet and replaces them with the command line overrides
+// The caller must hold syncMutex once the daemon is running
+func (d *Daemon) applyIntervalOverrides(cfg *config.Config) {
+	d.configPullInterval = cfg.Sync.PullInterval
+	d.configPushInterval = cfg.Sync.PushInterval
+
+	if d.pullOverride > 0 {
+		cfg.Sync.PullInterval = d.pullOverride
+	}
+	if d.pushOverride > 0 {
+		cfg.Sync.PushInterval = d.pushOverride
+	}
+}
+
+// periodicInterval returns the shorter of the pull and push intervals
+// In a mirror mode only the interval of the direction that syncs counts
+func (d *Daemon) periodicInterval() time.Duration {
+	cfg := d.currentConfig()
+	if !cfg.Sync.Pushes() {
+		return cfg.Sync.PullInterval
+	}
+	if !cfg.Sync.Pulls() {
+		return cfg.Sync.PushInterval
+	}
+	if cfg.Sync.PushInterval < cfg.Sync.PullInterval {
+		return cfg.Sync.PushInterval
+	}
+	return cfg.Sync.PullInterval
+}
+
+// periodicDelay returns how long to wait for the next periodic sync: interval plus a
+// random part of sync.interval_jitter, so machines started together drift apart
+func (d *Daemon) periodicDelay(interval time.Duration) time.Duration {
+	return interval + ratelimit.Jitter(d.currentConfig().Sync.IntervalJitter)
+}
+
 // handleFileChanges handles real-time file changes via fsnotify (primary sync method)
 func (d *Daemon) handleFileChanges(ctx context.Context) {
 	changes := d.watcher.Changes()
 
 	// Configurable debounce to avoid excessive syncs (minimum 10 seconds)
-	debounceTime := d.config.Sync.DebounceTime
+	debounceTime := d.currentConfig().Sync.DebounceTime
 	var pendingChanges bool
 	debounceTimer := time.NewTimer(debounceTime)
 	debounceTimer.Stop()
@@ -167,6 +392,8 @@ func (d *Daemon) handleFileChanges(ctx context.Context) {
 			logger.Info("Real-time file watcher shutting down")
 			return
 		case fileChange := <-changes:
+			// Picks up debounce changes from a live config reload
+			debounceTime = d.currentConfig().Sync.DebounceTime
 			if !d.isPaused() {
 				logger.Debug("📁 File change detected: %s (%s)", fileChange.Path, fileChange.Action)
 				logger.Debug("⏳ Starting/resetting %v debounce timer", debounceTime)
@@ -174,7 +401,7 @@ func (d *Daemon) handleFileChanges(ctx context.Context) {
 				debounceTimer.Reset(debounceTime)
 			}
 		case <-debounceTimer.C:
-			if pendingChanges && !d.isPaused() && d.canStartSync() {
+			if pendingChanges && !d.isPaused() && d.tryStartSync() {
 				logger.Info("⚡ Real-time sync triggered after %v debounce period", debounceTime)
 
 				// Perform comprehensive sync (pull then push)
@@ -185,8 +412,15 @@ func (d *Daemon) handleFileChanges(ctx context.Context) {
 	}
 }
 
-// canStartSync checks if a sync operation can be started
-func (d *Daemon) canStartSync() bool {
+// tryStartSync atomically checks whether a sync can start and, if so, marks it as in progress
+// Checking and marking under one lock prevents the real-time and periodic loops from both
+// starting a sync between the check and the mark
+func (d *Daemon) tryStartSync() bool {
+	// Don't sync while a Cursor User directory is missing
+	if !d.checkConfigPaths() {
+		return false
+	}
+
 	d.syncMutex.Lock()
 	defer d.syncMutex.Unlock()
 
@@ -203,6 +437,9 @@ func (d *Daemon) canStartSync() bool {
 		return false
 	}
 
+	d.syncInProgress = true
+	d.lastSyncTime = time.Now()
+	logger.Debug("🔒 Sync started - locked")
 	return true
 }
 
@@ -223,11 +460,14 @@ func (d *Daemon) endSync() {
 	logger.Debug("🔓 Sync completed - unlocked")
 }
 
+// errPausedMidSync means a pause set during a sync cancelled its remaining steps
+var errPausedMidSync = errors.New("daemon was paused during the sync - push skipped")
+
 // performPeriodicSync performs a comprehensive periodic sync
-func (d *Daemon) performPeriodicSync() {
+// The caller must have marked the sync as started with tryStartSync
+func (d *Daemon) performPeriodicSync() error {
 	logger.Debug("📅 Performing periodic comprehensive sync...")
 
-	d.startSync()
 	defer d.endSync()
 
 	// Disable file watcher during sync to prevent infinite loops
@@ -237,20 +477,34 @@ func (d *Daemon) performPeriodicSync() {
 	}
 
 	// Step 1: Pull from remote first
-	if err := d.syncer.SyncFromRemote(); err != nil {
-		logger.Error("Periodic pull sync failed: %v", err)
+	pullErr := d.syncer.SyncFromRemote()
+	if pullErr != nil {
+		logger.Error("Periodic pull sync failed: %v", pullErr)
+		d.notifyResult("Periodic pull", pullErr)
 	} else {
 		logger.Debug("✅ Periodic pull sync completed")
 	}
 
+	// A pause set during the pull takes effect before anything is pushed
+	if d.pausedBeforeStep("push") {
+		return errors.Join(pullErr, errPausedMidSync)
+	}
+
 	// Step 2: Push local changes
-	if err := d.syncer.SyncToRemote(); err != nil {
-		logger.Error("Periodic push sync failed: %v", err)
+	pushErr := d.syncer.SyncToRemote()
+	if pushErr != nil {
+		logger.Error("Periodic push sync failed: %v", pushErr)
+		d.notifyResult("Periodic push", pushErr)
 	} else {
 		logger.Debug("✅ Periodic push sync completed")
 	}
 
+	if pullErr == nil && pushErr == nil {
+		d.notifyResult("Periodic", nil)
+	}
+
 	logger.Debug("📅 Periodic comprehensive sync finished")
+	return errors.Join(pullErr, pushErr)
 }
 
 func (d *Daemon) performPull() {
@@ -287,12 +541,18 @@ func (d *Daemon) performPush() {
 
 // performRealtimeSync performs a real-time sync (triggered by file changes)
 // When user makes local changes, we ONLY push them to remote (they're the freshest)
+// The caller must have marked the sync as started with tryStartSync
 func (d *Daemon) performRealtimeSync() {
-	logger.Info("⚡ Performing real-time sync sequence...")
-
-	d.startSync()
 	defer d.endSync()
 
+	// A receiving mirror never lets local changes flow back
+	if cfg := d.currentConfig(); !cfg.Sync.Pushes() {
+		logger.Debug("Ignoring local changes (sync.mode: %s)", cfg.Sync.Mode)
+		return
+	}
+
+	logger.Info("⚡ Performing real-time sync sequence...")
+
 	// Disable file watcher during sync to prevent infinite loops
 	if d.watcher != nil {
 		d.watcher.Disable()
@@ -302,13 +562,15 @@ func (d *Daemon) performRealtimeSync() {
 	// When user makes local changes, ONLY push them to remote
 	// DO NOT pull from remote as it would overwrite the user's changes
 	logger.Debug("📤 Real-time sync: pushing local changes to remote...")
-	if err := d.syncer.SyncToRemote(); err != nil {
+	err := d.syncer.SyncToRemote()
+	if err != nil {
 		logger.Error("Real-time push failed: %v", err)
 		// Don't fail the entire sync operation, just log the error
 		// The periodic sync will handle any remaining conflicts
 	} else {
 		logger.Info("✅ Real-time sync completed successfully")
 	}
+	d.notifyResult("Real-time", err)
 }
 
 // ForceInitialSync triggers an initial sync (used for restart scenarios)
@@ -324,6 +586,10 @@ func (d *Daemon) performInitialSync() error {
 		return nil
 	}
 
+	if !d.checkConfigPaths() {
+		return fmt.Errorf("cursor config directory is missing")
+	}
+
 	logger.Info("🔄 Starting initial sync sequence...")
 
 	d.startSync()
@@ -345,6 +611,10 @@ func (d *Daemon) performInitialSync() error {
 		logger.Info("✅ Remote changes pulled successfully")
 	}
 
+	if d.pausedBeforeStep("push") {
+		return nil
+	}
+
 	// Step 2: Push any local changes that might have accumulated
 	logger.Info("📤 Step 2: Pushing local changes...")
 	if err := d.syncer.SyncToRemote(); err != nil {
@@ -358,15 +628,127 @@ func (d *Daemon) performInitialSync() error {
 	return nil
 }
 
+// checkForUpdate logs a notice when a newer cursor-sync release is available
+// Failures only matter to someone debugging, so they are logged at debug level
+func (d *Daemon) checkForUpdate(ctx context.Context) {
+	release, newer, err := update.Check(ctx, false)
+	if err != nil {
+		logger.Debug("Update check failed: %v", err)
+		return
+	}
+	if newer {
+		logger.Warn("🆕 cursor-sync %s is available (running %s) - run 'cursor-sync update' to install it",
+			release.Version(), version.Version)
+	}
+}
+
+// monitorConfigPaths periodically checks that the Cursor User directories still exist
+func (d *Daemon) monitorConfigPaths(ctx context.Context) {
+	ticker := time.NewTicker(configPathCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			d.checkConfigPaths()
+		}
+	}
+}
+
+// checkConfigPaths reports whether every target's User directory exists
+// Logs when a directory disappears and, once all are back, restarts the file watcher
+// so the recreated directories are watched again
+func (d *Daemon) checkConfigPaths() bool {
+	var missing []string
+	for _, target := range d.currentConfig().Cursor.SyncTargets() {
+		userPath := filepath.Join(target.ConfigPath, "User")
+		if _, err := os.Stat(userPath); os.IsNotExist(err) {
+			missing = append(missing, userPath)
+		}
+	}
+
+	d.pathMutex.Lock()
+	defer d.pathMutex.Unlock()
+
+	wasMissing := len(d.missingPaths) > 0
+	d.missingPaths = missing
+
+	switch {
+	case len(missing) > 0 && !wasMissing:
+		for _, path := range missing {
+			logger.Warn("📁 Cursor config directory disappeared: %s", path)
+		}
+		logger.Warn("⏸️  Syncing suspended until the directory is recreated (checking every %v)", configPathCheckInterval)
+	case len(missing) == 0 && wasMissing:
+		logger.Info("📁 Cursor config directory is back - resuming sync")
+		if d.watcher != nil {
+			if err := d.watcher.RestartWatching(); err != nil {
+				logger.Error("Failed to restart file watching: %v", err)
+			}
+		}
+	}
+
+	return len(missing) == 0
+}
+
+// pausedBeforeStep reports whether the daemon was paused while a sync was running,
+// in which case the sync's next step is skipped instead of completing the cycle
+func (d *Daemon) pausedBeforeStep(step string) bool {
+	if !d.isPaused() {
+		return false
+	}
+	logger.Info("⏸️  Paused during sync - skipping %s", step)
+	return true
+}
+
 func (d *Daemon) isPaused() bool {
-	// Check if pause file exists
-	home, err := os.UserHomeDir()
+	d.pauseMutex.Lock()
+	if d.paused && !d.pausedUntil.IsZero() && !time.Now().Before(d.pausedUntil) {
+		d.paused = false
+		d.pausedUntil = time.Time{}
+		logger.Info("▶️  Pause expired - resuming sync")
+	}
+	paused := d.paused
+	d.pauseMutex.Unlock()
+	if paused {
+		return true
+	}
+
+	// Check if pause file exists (an expired timed pause counts as resumed)
+	pauseFile, err := PauseFilePath()
 	if err != nil {
-		return d.paused
+		return false
 	}
 
-	pauseFile := filepath.Join(home, ".cursor-sync", "paused")
-	_, err = os.Stat(pauseFile)
+	paused, _ = ReadPauseFile(pauseFile, time.Now())
+	return paused
+}
+
+// SetPaused pauses or resumes syncing in-process, independently of the pause file
+func (d *Daemon) SetPaused(paused bool) {
+	d.pauseMutex.Lock()
+	defer d.pauseMutex.Unlock()
+
+	d.pausedUntil = time.Time{}
+	if d.paused == paused {
+		return
+	}
+	d.paused = paused
+	if paused {
+		logger.Info("⏸️  Sync paused")
+	} else {
+		logger.Info("▶️  Sync resumed")
+	}
+}
+
+// PauseFor pauses syncing in-process until duration has passed
+func (d *Daemon) PauseFor(duration time.Duration) {
+	d.pauseMutex.Lock()
+	defer d.pauseMutex.Unlock()
 
-	return err == nil
+	d.paused = true
+	d.pausedUntil = time.Now().Add(duration)
+	logger.Info("⏸️  Sync paused until %s", d.pausedUntil.Format("15:04:05"))
 }
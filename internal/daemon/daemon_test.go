@@ -0,0 +1,205 @@
+package daemon
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"cursor-sync/internal/config"
+)
+
+func TestCheckConfigPathsSuspendsAndResumes(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "Cursor")
+	userPath := filepath.Join(configPath, "User")
+	if err := os.MkdirAll(userPath, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	d := &Daemon{config: &config.Config{Cursor: config.Cursor{ConfigPath: configPath}}}
+	if !d.checkConfigPaths() {
+		t.Fatal("checkConfigPaths() = false with existing User directory")
+	}
+
+	if err := os.RemoveAll(configPath); err != nil {
+		t.Fatal(err)
+	}
+	if d.checkConfigPaths() {
+		t.Fatal("checkConfigPaths() = true after the config directory was removed")
+	}
+	if d.tryStartSync() {
+		t.Fatal("tryStartSync() started a sync while the config directory is missing")
+	}
+
+	if err := os.MkdirAll(userPath, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if !d.checkConfigPaths() {
+		t.Fatal("checkConfigPaths() = false after the User directory was recreated")
+	}
+	if len(d.missingPaths) != 0 {
+		t.Errorf("missingPaths = %v, want none", d.missingPaths)
+	}
+}
+
+func TestReadPauseFileExpires(t *testing.T) {
+	pauseFile := filepath.Join(t.TempDir(), "paused")
+	now := time.Now()
+
+	if err := WritePauseFile(pauseFile, 0); err != nil {
+		t.Fatal(err)
+	}
+	if paused, _ := ReadPauseFile(pauseFile, now.Add(24*time.Hour)); !paused {
+		t.Error("indefinite pause expired")
+	}
+
+	if err := WritePauseFile(pauseFile, 30*time.Minute); err != nil {
+		t.Fatal(err)
+	}
+	if paused, until := ReadPauseFile(pauseFile, now); !paused || until.IsZero() {
+		t.Errorf("ReadPauseFile() = %v, %v; want paused with expiry", paused, until)
+	}
+	if paused, _ := ReadPauseFile(pauseFile, now.Add(31*time.Minute)); paused {
+		t.Error("pause still active after expiry")
+	}
+	if _, err := os.Stat(pauseFile); !os.IsNotExist(err) {
+		t.Error("expired pause file not removed")
+	}
+}
+
+func TestIsPausedHonoursInProcessPause(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	d := &Daemon{}
+	if d.isPaused() {
+		t.Fatal("isPaused() = true without pause file or signal")
+	}
+
+	d.SetPaused(true)
+	if !d.isPaused() {
+		t.Error("isPaused() = false after SetPaused(true)")
+	}
+
+	d.SetPaused(false)
+	if d.isPaused() {
+		t.Error("isPaused() = true after SetPaused(false)")
+	}
+}
+
+func TestIntervalOverridesTakePrecedence(t *testing.T) {
+	d := &Daemon{config: &config.Config{Sync: config.Sync{PullInterval: 5 * time.Minute, PushInterval: 10 * time.Minute}}}
+	d.applyIntervalOverrides(d.config)
+	configured := d.currentConfig()
+
+	d.SetIntervalOverrides(30*time.Second, 0)
+	if cfg := d.currentConfig(); cfg.Sync.PullInterval != 30*time.Second || cfg.Sync.PushInterval != 10*time.Minute {
+		t.Errorf("intervals = %v/%v, want 30s override and configured 10m", cfg.Sync.PullInterval, cfg.Sync.PushInterval)
+	}
+	if configured.Sync.PullInterval != 5*time.Minute {
+		t.Errorf("published configuration was changed in place: pull interval = %v", configured.Sync.PullInterval)
+	}
+	if d.configPullInterval != 5*time.Minute {
+		t.Errorf("configured pull interval = %v, want 5m", d.configPullInterval)
+	}
+	if got := d.periodicInterval(); got != 30*time.Second {
+		t.Errorf("periodicInterval() = %v, want 30s", got)
+	}
+
+	d.SetIntervalOverrides(0, 0)
+	if got := d.currentConfig().Sync.PullInterval; got != 5*time.Minute {
+		t.Errorf("pull interval after clearing override = %v, want 5m", got)
+	}
+}
+
+func TestReloadRestartsWatchingOnPathFilterChanges(t *testing.T) {
+	base := config.Cursor{ConfigPath: "/cursor", ExcludePaths: []string{"User/History/"}}
+	for name, change := range map[string]func(*config.Cursor){
+		"exclude_paths":        func(c *config.Cursor) { c.ExcludePaths = nil },
+		"include_paths":        func(c *config.Cursor) { c.IncludePaths = []string{"User/History/keep.json"} },
+		"global_storage_allow": func(c *config.Cursor) { c.GlobalStorageAllow = []string{"publisher.ext"} },
+		"workspace_allow":      func(c *config.Cursor) { c.WorkspaceAllow = []string{"project"} },
+		"config_path":          func(c *config.Cursor) { c.ConfigPath = "/other" },
+	} {
+		cursor := base
+		change(&cursor)
+		if !watchedPathsChanged(&config.Config{Cursor: cursor}, &config.Config{Cursor: base}) {
+			t.Errorf("changing %s doesn't restart watching", name)
+		}
+	}
+	if watchedPathsChanged(&config.Config{Cursor: base}, &config.Config{Cursor: base}) {
+		t.Error("an unchanged configuration restarts watching")
+	}
+}
+
+func TestPublishedConfigIsSafeForConcurrentReaders(t *testing.T) {
+	d := &Daemon{config: &config.Config{Sync: config.Sync{PullInterval: 5 * time.Minute, PushInterval: 10 * time.Minute}}}
+	d.applyIntervalOverrides(d.config)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 100; i++ {
+			if got := d.periodicInterval(); got <= 0 {
+				t.Errorf("periodicInterval() = %v", got)
+			}
+			d.periodicDelay(time.Minute)
+		}
+	}()
+	for i := 0; i < 100; i++ {
+		d.SetIntervalOverrides(time.Duration(i+1)*time.Second, 0)
+	}
+	<-done
+}
+
+func TestMirrorModeOnlyCountsItsInterval(t *testing.T) {
+	d := &Daemon{config: &config.Config{Sync: config.Sync{
+		PullInterval: 10 * time.Minute,
+		PushInterval: time.Minute,
+		Mode:         config.ModeMirrorPull,
+	}}}
+	if got := d.periodicInterval(); got != 10*time.Minute {
+		t.Errorf("periodicInterval() in mirror-pull = %v, want the pull interval", got)
+	}
+
+	d.config.Sync.Mode = config.ModeMirrorPush
+	d.config.Sync.PushInterval = 20 * time.Minute
+	if got := d.periodicInterval(); got != 20*time.Minute {
+		t.Errorf("periodicInterval() in mirror-push = %v, want the push interval", got)
+	}
+
+	// A receiving mirror ignores local changes without touching the syncer
+	d.config.Sync.Mode = config.ModeMirrorPull
+	d.syncInProgress = true
+	d.performRealtimeSync()
+	if d.syncInProgress {
+		t.Error("performRealtimeSync() didn't release the sync lock")
+	}
+}
+
+func TestPauseMidSyncSkipsNextStep(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	d := &Daemon{}
+	if d.pausedBeforeStep("push") {
+		t.Fatal("pausedBeforeStep() = true while not paused")
+	}
+
+	d.SetPaused(true)
+	if !d.pausedBeforeStep("push") {
+		t.Error("pausedBeforeStep() = false after a pause was set mid-sync")
+	}
+}
+
+func TestPeriodicDelayAddsJitter(t *testing.T) {
+	d := &Daemon{config: &config.Config{Sync: config.Sync{IntervalJitter: time.Minute}}}
+	for i := 0; i < 100; i++ {
+		if got := d.periodicDelay(5 * time.Minute); got < 5*time.Minute || got >= 6*time.Minute {
+			t.Fatalf("periodicDelay() = %v, want within [5m, 6m)", got)
+		}
+	}
+
+	d.config.Sync.IntervalJitter = 0
+	if got := d.periodicDelay(5 * time.Minute); got != 5*time.Minute {
+		t.Errorf("periodicDelay() without jitter = %v, want 5m", got)
+	}
+}
@@ -0,0 +1,74 @@
+package daemon
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"cursor-sync/internal/logger"
+	"cursor-sync/internal/paths"
+)
+
+// PauseFilePath returns the file whose presence pauses the daemon. It is empty for an
+// indefinite pause or holds the RFC 3339 time the pause expires
+func PauseFilePath() (string, error) {
+	stateDir, err := paths.StateDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(stateDir, "paused"), nil
+}
+
+// WritePauseFile pauses the daemon, until resumed (duration 0) or for the given duration
+func WritePauseFile(path string, duration time.Duration) error {
+	content := ""
+	if duration > 0 {
+		content = time.Now().Add(duration).Format(time.RFC3339)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, []byte(content), 0644)
+}
+
+// persistPause writes the pause file for a pause sent over the control socket, so it
+// survives a daemon restart like one made with the daemon stopped
+func persistPause(duration time.Duration) {
+	pauseFile, err := PauseFilePath()
+	if err == nil {
+		err = WritePauseFile(pauseFile, duration)
+	}
+	if err != nil {
+		logger.Warn("Failed to write the pause file - the pause won't survive a restart: %v", err)
+	}
+}
+
+// ReadPauseFile reports whether the pause file pauses the daemon at now, and when the
+// pause expires (zero for an indefinite pause). An expired pause file is removed
+func ReadPauseFile(path string, now time.Time) (bool, time.Time) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return false, time.Time{}
+	}
+
+	content := strings.TrimSpace(string(data))
+	if content == "" {
+		return true, time.Time{}
+	}
+
+	until, err := time.Parse(time.RFC3339, content)
+	if err != nil {
+		// Unreadable expiry: stay paused rather than sync unexpectedly
+		logger.Warn("Invalid pause expiry in %s: %q", path, content)
+		return true, time.Time{}
+	}
+
+	if !now.Before(until) {
+		if err := os.Remove(path); err == nil {
+			logger.Info("▶️  Pause expired at %s - resuming sync", until.Format("15:04:05"))
+		}
+		return false, time.Time{}
+	}
+	return true, until
+}
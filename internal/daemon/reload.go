@@ -0,0 +1,154 @@
+package daemon
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+
+	"cursor-sync/internal/config"
+	"cursor-sync/internal/logger"
+)
+
+// configReloadDelay collapses the burst of events an editor's save produces
+const configReloadDelay = time.Second
+
+// configReloadRetry is how long a reload waits for a running sync to finish
+const configReloadRetry = 5 * time.Second
+
+// errReloadBusy means a sync was running, so the configuration couldn't be swapped
+var errReloadBusy = errors.New("a sync is in progress - try again shortly")
+
+// watchConfigFile reloads the configuration whenever the config file changes
+func (d *Daemon) watchConfigFile(ctx context.Context) {
+	configPath, err := config.FilePath()
+	if err != nil {
+		logger.Warn("Config hot-reload disabled: %v", err)
+		return
+	}
+
+	fsWatcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		logger.Warn("Config hot-reload disabled: %v", err)
+		return
+	}
+	defer fsWatcher.Close()
+
+	// Watch the directory: editors often replace the file instead of writing it in place
+	if err := fsWatcher.Add(filepath.Dir(configPath)); err != nil {
+		logger.Warn("Config hot-reload disabled: %v", err)
+		return
+	}
+
+	reloadTimer := time.NewTimer(configReloadDelay)
+	reloadTimer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case event, ok := <-fsWatcher.Events:
+			if !ok {
+				return
+			}
+			if filepath.Clean(event.Name) == configPath && event.Op&(fsnotify.Write|fsnotify.Create|fsnotify.Rename) != 0 {
+				reloadTimer.Reset(configReloadDelay)
+			}
+		case err, ok := <-fsWatcher.Errors:
+			if !ok {
+				return
+			}
+			logger.Warn("Config file watcher error: %v", err)
+		case <-reloadTimer.C:
+			message, err := d.reloadConfig()
+			switch {
+			case err == errReloadBusy:
+				reloadTimer.Reset(configReloadRetry)
+			case err != nil:
+				logger.Error("Configuration change rejected, keeping the current configuration: %v", err)
+			default:
+				logger.Info("🔁 %s", message)
+			}
+		}
+	}
+}
+
+// reloadConfig re-reads and validates the configuration and applies it to the running
+// daemon, syncer and watcher. The repository section, watch_enabled and logging are set
+// up at startup and keep their current values until the daemon restarts, as do the network
+// settings the GitHub clients were built with
+func (d *Daemon) reloadConfig() (string, error) {
+	cfg, err := config.Load()
+	if err != nil {
+		return "", fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	d.syncMutex.Lock()
+	defer d.syncMutex.Unlock()
+
+	if d.syncInProgress {
+		return "", errReloadBusy
+	}
+
+	old := *d.currentConfig()
+
+	var restart []string
+	if cfg.Repository.URL != old.Repository.URL || cfg.Repository.LocalPath != old.Repository.LocalPath ||
+		cfg.Repository.Subdir != old.Repository.Subdir || cfg.Repository.APIBaseURL != old.Repository.APIBaseURL {
+		restart = append(restart, "repository")
+	}
+	if cfg.Sync.WatchEnabled != old.Sync.WatchEnabled {
+		restart = append(restart, "sync.watch_enabled")
+	}
+	if cfg.Logging != old.Logging {
+		restart = append(restart, "logging")
+	}
+	if cfg.Network != old.Network {
+		restart = append(restart, "network")
+	}
+
+	// The branch stays as is too: it may come from --branch
+	cfg.Repository = old.Repository
+	cfg.Sync.WatchEnabled = old.Sync.WatchEnabled
+	cfg.Logging = old.Logging
+	// The GitHub clients were built with the network settings at startup
+	cfg.Network = old.Network
+
+	// Readers keep the snapshot they took; no sync is running, so the syncer can switch too
+	d.applyIntervalOverrides(cfg)
+	d.publishConfig(cfg)
+
+	if d.watcher != nil {
+		if watchedPathsChanged(cfg, &old) {
+			if err := d.watcher.RestartWatching(); err != nil {
+				logger.Error("Failed to restart file watching: %v", err)
+			}
+		}
+	}
+
+	// Let the periodic loop pick up new intervals
+	select {
+	case d.configReloaded <- struct{}{}:
+	default:
+	}
+
+	if len(restart) > 0 {
+		return fmt.Sprintf("Configuration reloaded; restart the daemon to apply %s changes", strings.Join(restart, ", ")), nil
+	}
+	return "Configuration reloaded", nil
+}
+
+// watchedPathsChanged reports whether a reload changes which folders the watcher has to
+// watch: the targets, or the path filters folders are skipped by when watches are added
+func watchedPathsChanged(cfg, old *config.Config) bool {
+	return !reflect.DeepEqual(cfg.Cursor.SyncTargets(), old.Cursor.SyncTargets()) ||
+		!reflect.DeepEqual(cfg.Cursor.ExcludePaths, old.Cursor.ExcludePaths) ||
+		!reflect.DeepEqual(cfg.Cursor.IncludePaths, old.Cursor.IncludePaths) ||
+		!reflect.DeepEqual(cfg.Cursor.GlobalStorageAllow, old.Cursor.GlobalStorageAllow) ||
+		!reflect.DeepEqual(cfg.Cursor.WorkspaceAllow, old.Cursor.WorkspaceAllow)
+}
@@ -0,0 +1,31 @@
+//go:build !windows
+
+package daemon
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"cursor-sync/internal/logger"
+)
+
+// handlePauseSignals pauses syncing on SIGUSR1 and resumes it on SIGUSR2, so a running
+// daemon can be controlled with kill(1) without touching the pause file
+func (d *Daemon) handlePauseSignals(ctx context.Context) {
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, syscall.SIGUSR1, syscall.SIGUSR2)
+	defer signal.Stop(sigChan)
+
+	logger.Info("Pause with 'kill -USR1 %d', resume with 'kill -USR2 %d'", os.Getpid(), os.Getpid())
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case sig := <-sigChan:
+			d.SetPaused(sig == syscall.SIGUSR1)
+		}
+	}
+}
@@ -0,0 +1,6 @@
+package daemon
+
+import "context"
+
+// handlePauseSignals is a no-op: Windows has no SIGUSR1/SIGUSR2, only the pause file works there
+func (d *Daemon) handlePauseSignals(ctx context.Context) {}
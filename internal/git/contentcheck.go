@@ -0,0 +1,190 @@
+package git
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/filemode"
+	"github.com/go-git/go-git/v5/plumbing/format/gitignore"
+	"github.com/go-git/go-git/v5/plumbing/format/index"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// StageContentChanges compares every worktree file with HEAD by blob hash and stages the
+// ones whose content differs, independently of go-git's status - which can report a clean
+// tree on case-insensitive filesystems or after line-ending conversions. Ignored files that
+// aren't tracked and cursor-sync's internal files are skipped. Returns the staged paths
+// (slash-separated); a repository without commits has nothing to compare against
+func (r *Repository) StageContentChanges() ([]string, error) {
+	if r.repo == nil {
+		return nil, fmt.Errorf("repository not initialized")
+	}
+
+	head, err := r.repo.Head()
+	if errors.Is(err, plumbing.ErrReferenceNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get HEAD: %w", err)
+	}
+	commit, err := r.repo.CommitObject(head.Hash())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get commit: %w", err)
+	}
+	tree, err := commit.Tree()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tree: %w", err)
+	}
+
+	committed := make(map[string]plumbing.Hash)
+	err = tree.Files().ForEach(func(file *object.File) error {
+		committed[file.Name] = file.Hash
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read tree: %w", err)
+	}
+
+	worktree, err := r.repo.Worktree()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get worktree: %w", err)
+	}
+	patterns, err := gitignore.ReadPatterns(worktree.Filesystem, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read .gitignore: %w", err)
+	}
+	ignored := gitignore.NewMatcher(patterns)
+
+	idx, err := r.repo.Storer.Index()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read index: %w", err)
+	}
+
+	var staged []string
+	seen := make(map[string]bool)
+	err = filepath.WalkDir(r.localPath, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(r.localPath, path)
+		if err != nil || rel == "." {
+			return err
+		}
+		name := filepath.ToSlash(rel)
+		if d.IsDir() {
+			if name == ".git" {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		_, tracked := committed[name]
+		seen[name] = true
+		if IsInternalFile(name) || (!tracked && ignored.Match(strings.Split(name, "/"), false)) {
+			return nil
+		}
+
+		info, err := os.Lstat(path)
+		if err != nil {
+			return err
+		}
+		data, err := readBlobContent(path, info)
+		if err != nil {
+			return err
+		}
+		hash := plumbing.ComputeHash(plumbing.BlobObject, data)
+		if hash == committed[name] {
+			return nil
+		}
+
+		if err := r.stageBlob(idx, name, data, info); err != nil {
+			return fmt.Errorf("failed to stage %s: %w", name, err)
+		}
+		staged = append(staged, name)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to compare worktree with HEAD: %w", err)
+	}
+
+	// Files committed in HEAD that are gone from the worktree are deletions
+	for name := range committed {
+		if seen[name] || IsInternalFile(name) {
+			continue
+		}
+		if _, err := idx.Remove(name); err != nil && !errors.Is(err, index.ErrEntryNotFound) {
+			return nil, fmt.Errorf("failed to stage deletion of %s: %w", name, err)
+		}
+		staged = append(staged, name)
+	}
+
+	if len(staged) == 0 {
+		return nil, nil
+	}
+	if err := r.repo.Storer.SetIndex(idx); err != nil {
+		return nil, fmt.Errorf("failed to write index: %w", err)
+	}
+	sort.Strings(staged)
+	return staged, nil
+}
+
+// readBlobContent returns what git stores for a worktree file: its content, or the target
+// of a symlink
+func readBlobContent(path string, info os.FileInfo) ([]byte, error) {
+	if info.Mode()&os.ModeSymlink != 0 {
+		target, err := os.Readlink(path)
+		if err != nil {
+			return nil, err
+		}
+		return []byte(filepath.ToSlash(target)), nil
+	}
+	return os.ReadFile(path)
+}
+
+// storeBlob writes data to the object store as a blob
+func (r *Repository) storeBlob(data []byte) (plumbing.Hash, error) {
+	obj := r.repo.Storer.NewEncodedObject()
+	obj.SetType(plumbing.BlobObject)
+	obj.SetSize(int64(len(data)))
+	writer, err := obj.Writer()
+	if err != nil {
+		return plumbing.ZeroHash, err
+	}
+	if _, err := writer.Write(data); err != nil {
+		writer.Close()
+		return plumbing.ZeroHash, err
+	}
+	if err := writer.Close(); err != nil {
+		return plumbing.ZeroHash, err
+	}
+	return r.repo.Storer.SetEncodedObject(obj)
+}
+
+// stageBlob stores data as a blob object and points the index entry for name at it
+func (r *Repository) stageBlob(idx *index.Index, name string, data []byte, info os.FileInfo) error {
+	hash, err := r.storeBlob(data)
+	if err != nil {
+		return err
+	}
+
+	mode, err := filemode.NewFromOSFileMode(info.Mode())
+	if err != nil {
+		return err
+	}
+
+	entry, err := idx.Entry(name)
+	if err != nil {
+		entry = idx.Add(name)
+	}
+	entry.Hash = hash
+	entry.Mode = mode
+	entry.Size = uint32(info.Size())
+	entry.ModifiedAt = info.ModTime()
+	return nil
+}
@@ -0,0 +1,76 @@
+package git
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+func TestStageContentChanges(t *testing.T) {
+	dir := t.TempDir()
+	repo, err := git.PlainInit(dir, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	writeFile := func(name, content string) {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	writeFile(".gitignore", "*.log\n")
+	writeFile("User/settings.json", "{}")
+	writeFile("User/keybindings.json", "[]")
+	writeFile("User/snippets/go.json", "{}")
+	worktree, err := repo.Worktree()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := worktree.Add("."); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := worktree.Commit("initial", &git.CommitOptions{
+		Author: &object.Signature{Name: "test", Email: "test@local", When: time.Now()},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	r := &Repository{repo: repo, localPath: dir}
+	if staged, err := r.StageContentChanges(); err != nil || staged != nil {
+		t.Fatalf("StageContentChanges() on a clean tree = %v, %v; want nothing", staged, err)
+	}
+
+	writeFile("User/settings.json", `{"a":1}`)
+	writeFile("User/new.json", "{}")
+	writeFile("User/debug.log", "ignored")
+	writeFile("User/.custom.sync", "marker")
+	if err := os.Remove(filepath.Join(dir, "User", "snippets", "go.json")); err != nil {
+		t.Fatal(err)
+	}
+
+	staged, err := r.StageContentChanges()
+	if err != nil {
+		t.Fatalf("StageContentChanges() error = %v", err)
+	}
+	want := []string{"User/new.json", "User/settings.json", "User/snippets/go.json"}
+	if !reflect.DeepEqual(staged, want) {
+		t.Errorf("StageContentChanges() = %v, want %v", staged, want)
+	}
+
+	// The staged index commits as is, without go-git's status being involved
+	if err := r.Commit("sync", "test", "test@local"); err != nil {
+		t.Fatalf("Commit() error = %v", err)
+	}
+	if staged, err := r.StageContentChanges(); err != nil || staged != nil {
+		t.Errorf("StageContentChanges() after commit = %v, %v; want nothing", staged, err)
+	}
+}
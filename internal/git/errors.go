@@ -0,0 +1,89 @@
+package git
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/transport"
+)
+
+// Errors returned by operations that talk to the remote, wrapped around the underlying
+// go-git error so callers can classify failures with errors.Is instead of matching messages
+var (
+	// ErrPushConflict means the remote rejected a push because it has commits the local
+	// branch doesn't; pulling and pushing again resolves it
+	ErrPushConflict = errors.New("push conflict")
+	// ErrPullConflict means the remote branch can't be fast-forwarded onto the local one
+	ErrPullConflict = errors.New("pull conflict")
+	// ErrAuth means the remote refused the token
+	ErrAuth = errors.New("authentication failed")
+	// ErrNetwork means the remote couldn't be reached or didn't answer in time
+	ErrNetwork = errors.New("network error")
+	// ErrEmptyRepo means the remote repository has no commits yet
+	ErrEmptyRepo = errors.New("remote repository is empty")
+	// ErrRepoNotFound means the remote repository doesn't exist, or the token can't see it
+	ErrRepoNotFound = errors.New("repository not found")
+)
+
+// classifyRemoteError wraps err in the matching sentinel error, or returns it unchanged
+// when none applies. conflict is used for rejected updates and may be nil for clones
+func classifyRemoteError(err, conflict error) error {
+	if err == nil {
+		return nil
+	}
+	if kind := remoteErrorKind(err, conflict); kind != nil && !errors.Is(err, kind) {
+		return fmt.Errorf("%w: %w", kind, err)
+	}
+	return err
+}
+
+// remoteErrorKind returns the sentinel error describing err, or nil
+func remoteErrorKind(err, conflict error) error {
+	switch {
+	case errors.Is(err, transport.ErrAuthenticationRequired),
+		errors.Is(err, transport.ErrAuthorizationFailed),
+		errors.Is(err, transport.ErrInvalidAuthMethod):
+		return ErrAuth
+	case errors.Is(err, transport.ErrRepositoryNotFound):
+		return ErrRepoNotFound
+	case errors.Is(err, transport.ErrEmptyRemoteRepository):
+		return ErrEmptyRepo
+	case conflict != nil && isRejectedUpdate(err):
+		return conflict
+	case isNetworkError(err):
+		return ErrNetwork
+	}
+	return nil
+}
+
+// isRejectedUpdate reports whether err means the branch moved on the other side. The
+// server reports rejected pushes only as text in its status report, hence the fallback
+func isRejectedUpdate(err error) bool {
+	if errors.Is(err, git.ErrNonFastForwardUpdate) ||
+		errors.Is(err, git.ErrForceNeeded) ||
+		errors.Is(err, plumbing.ErrObjectNotFound) {
+		return true
+	}
+	msg := err.Error()
+	return strings.Contains(msg, "non-fast-forward") ||
+		strings.Contains(msg, "rejected") ||
+		strings.Contains(msg, "cannot lock ref")
+}
+
+// isNetworkError reports whether err comes from the connection rather than from git.
+// Timeouts of the HTTP transport surface as *url.Error, which is a net.Error
+func isNetworkError(err error) bool {
+	var netErr net.Error
+	return errors.As(err, &netErr) || errors.Is(err, context.DeadlineExceeded)
+}
+
+// isMissingRemoteBranch reports whether err means the branch doesn't exist on the remote
+func isMissingRemoteBranch(err error) bool {
+	return errors.Is(err, plumbing.ErrReferenceNotFound) ||
+		errors.Is(err, git.NoMatchingRefSpecError{})
+}
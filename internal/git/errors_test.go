@@ -0,0 +1,186 @@
+package git
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/plumbing/transport"
+)
+
+func TestClassifyRemoteError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want error
+	}{
+		{"non-fast-forward", git.ErrNonFastForwardUpdate, ErrPushConflict},
+		{"server rejection", errors.New("command error on refs/heads/main: failed to update ref"), nil},
+		{"cannot lock ref", errors.New("command error on refs/heads/main: cannot lock ref 'refs/heads/main'"), ErrPushConflict},
+		{"unauthorized", transport.ErrAuthenticationRequired, ErrAuth},
+		{"forbidden", fmt.Errorf("fetch: %w", transport.ErrAuthorizationFailed), ErrAuth},
+		{"not found", transport.ErrRepositoryNotFound, ErrRepoNotFound},
+		{"empty", transport.ErrEmptyRemoteRepository, ErrEmptyRepo},
+		{"dial", &net.OpError{Op: "dial", Err: errors.New("connection refused")}, ErrNetwork},
+		{"unrelated", errors.New("object is corrupt"), nil},
+	}
+	sentinels := []error{ErrPushConflict, ErrPullConflict, ErrAuth, ErrNetwork, ErrEmptyRepo, ErrRepoNotFound}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := classifyRemoteError(tt.err, ErrPushConflict)
+			if !errors.Is(got, tt.err) {
+				t.Errorf("classifyRemoteError() = %v, lost the original error", got)
+			}
+			for _, sentinel := range sentinels {
+				if want := sentinel == tt.want; errors.Is(got, sentinel) != want {
+					t.Errorf("errors.Is(%v, %v) = %v, want %v", got, sentinel, !want, want)
+				}
+			}
+		})
+	}
+}
+
+func TestClassifyRemoteErrorWithoutConflict(t *testing.T) {
+	if err := classifyRemoteError(git.ErrNonFastForwardUpdate, nil); errors.Is(err, ErrPushConflict) {
+		t.Errorf("clone error classified as a push conflict: %v", err)
+	}
+	if !isMissingRemoteBranch(git.NoMatchingRefSpecError{}) {
+		t.Error("missing remote ref not detected")
+	}
+}
+
+func TestClassifyRemoteErrorFromClone(t *testing.T) {
+	empty := t.TempDir()
+	if _, err := git.PlainInit(empty, true); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		url  string
+		want error
+	}{
+		{"empty repository", empty, ErrEmptyRepo},
+		{"missing repository", filepath.Join(t.TempDir(), "missing.git"), ErrRepoNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := git.PlainClone(t.TempDir(), false, &git.CloneOptions{URL: tt.url})
+			if err = classifyRemoteError(err, nil); !errors.Is(err, tt.want) {
+				t.Errorf("clone error %v isn't %v", err, tt.want)
+			}
+		})
+	}
+}
+
+// newClonedRepository clones a repository with one commit on master from a directory on disk
+func newClonedRepository(t *testing.T) *Repository {
+	t.Helper()
+	return cloneOrigin(t, newOrigin(t))
+}
+
+// newOrigin creates a repository with one commit on master to clone from
+func newOrigin(t *testing.T) string {
+	t.Helper()
+	src := t.TempDir()
+	origin, err := git.PlainInit(src, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(src, "settings.json"), []byte("{}"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	worktree, err := origin.Worktree()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := worktree.Add("settings.json"); err != nil {
+		t.Fatal(err)
+	}
+	signature := &object.Signature{Name: "test", Email: "test@example.com", When: time.Now()}
+	if _, err := worktree.Commit("settings", &git.CommitOptions{Author: signature}); err != nil {
+		t.Fatal(err)
+	}
+
+	return src
+}
+
+// cloneOrigin clones src as another machine would
+func cloneOrigin(t *testing.T, src string) *Repository {
+	t.Helper()
+	r := NewLocal(filepath.Join(t.TempDir(), "clone"), "origin", "master", src)
+	if err := r.Clone(src); err != nil {
+		t.Fatal(err)
+	}
+	return r
+}
+
+func TestNetworkTimeoutFailsStalledFetch(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		select {
+		case <-req.Context().Done():
+		case <-time.After(5 * time.Second):
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	r := newClonedRepository(t)
+	if err := r.repo.DeleteRemote("origin"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.repo.CreateRemote(&config.RemoteConfig{Name: "origin", URLs: []string{server.URL}}); err != nil {
+		t.Fatal(err)
+	}
+
+	r.endpoint.Timeout = 100 * time.Millisecond
+	if err := r.Fetch(); !errors.Is(err, ErrNetwork) {
+		t.Errorf("Fetch() from a stalled server = %v, want %v", err, ErrNetwork)
+	}
+}
+
+func TestInactivityTimeoutAllowsSlowTransfers(t *testing.T) {
+	local, remote := net.Pipe()
+	t.Cleanup(func() { local.Close(); remote.Close() })
+	conn := &inactivityConn{Conn: local, timeout: 50 * time.Millisecond}
+
+	// Data keeps arriving for longer than the timeout, but never pauses that long
+	go func() {
+		for i := 0; i < 5; i++ {
+			time.Sleep(20 * time.Millisecond)
+			remote.Write([]byte("x"))
+		}
+	}()
+	buf := make([]byte, 1)
+	for i := 0; i < 5; i++ {
+		if _, err := conn.Read(buf); err != nil {
+			t.Fatalf("Read() %d of a trickling transfer error = %v", i+1, err)
+		}
+	}
+
+	var netErr net.Error
+	if _, err := conn.Read(buf); !errors.As(err, &netErr) || !netErr.Timeout() {
+		t.Errorf("Read() after the transfer stalled = %v, want a timeout", err)
+	}
+}
+
+func TestCanceledContextAbortsFetch(t *testing.T) {
+	r := newClonedRepository(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	r.SetContext(ctx)
+	if err := r.Fetch(); !errors.Is(err, context.Canceled) || errors.Is(err, ErrNetwork) {
+		t.Errorf("Fetch() after shutdown = %v, want %v", err, context.Canceled)
+	}
+}
@@ -2,21 +2,28 @@ package git
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
-	"strings"
+	"sort"
 	"time"
 
 	"github.com/go-git/go-git/v5"
 	"github.com/go-git/go-git/v5/config"
 	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/format/index"
 	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/plumbing/transport"
 	"github.com/go-git/go-git/v5/plumbing/transport/http"
 
 	"cursor-sync/internal/auth"
 	"cursor-sync/internal/github"
 	"cursor-sync/internal/logger"
+	"cursor-sync/internal/paths"
+	"cursor-sync/internal/ratelimit"
+	"cursor-sync/internal/urlutil"
 )
 
 // Repository represents a Git repository
@@ -25,21 +32,305 @@ type Repository struct {
 	remoteName string
 	branch     string
 	localPath  string
+	url        string // Configured repository URL the remote must point at
 	auth       *auth.GitHubAuth
 	owner      string
 	repoName   string
+	// GitHub API missing repositories are created through, and network.timeout
+	endpoint auth.Endpoint
+	// Called after a conflict between local and remote history has been resolved
+	conflictHandler func(ConflictEvent)
+	// Strategy of the conflict resolution in progress
+	resolveStrategy string
+	// Clone/pull depth (0 = full history, 1 = shallow)
+	depth int
+	// Whether repositories created on GitHub are seeded with a README by GitHub
+	// (false = created empty and seeded by initializeEmptyRepository)
+	autoInit bool
+	// Most files an incremental pull through the GitHub API may change (0 = always git pull)
+	incrementalMaxFiles int
+	// Receives git's progress messages while cloning (nil = silent)
+	cloneProgress io.Writer
+	// Gitattributes-style patterns of files stored in Git LFS (empty = LFS disabled)
+	lfsPatterns []string
+	// Commit times closer than this aren't trusted by the "newer" strategy
+	clockSkewTolerance time.Duration
+	// Asks which side keeps files both sides changed when commit times aren't trusted (nil = remote)
+	conflictPrompt func(ConflictPrompt) string
+	// Returns how a file changed on both sides is combined (nil = files are never combined)
+	fileMerger func(name string) MergeFunc
+	// Commit times compared by the "newer" strategy (nil when not compared)
+	resolveLocalTime  *time.Time
+	resolveRemoteTime *time.Time
+	// Creates the client that creates missing repositories (nil = the GitHub API)
+	newRepoCreator func() (repoCreator, error)
+	// Cancels in-flight network operations, e.g. when the daemon shuts down (nil = never)
+	ctx context.Context
+	// How long a push lease is respected by other machines (0 = pushes take no lease) and
+	// this machine's name in the lease ref
+	pushLease time.Duration
+	leaseHost string
+	// Author of the commits the repository makes on its own, e.g. merges ("" = cursor-sync)
+	authorName  string
+	authorEmail string
 }
 
-// New creates a new Git repository instance
-func New(localPath, remoteName, branch, repoURL string) (*Repository, error) {
+// repoCreator is the part of the GitHub API a missing repository is created through
+type repoCreator interface {
+	RepositoryExists(owner, repoName string) (bool, error)
+	CreateRepository(owner, repoName, description string, autoInit bool) (*github.RepositoryResponse, error)
+	WaitForRepositoryReady(owner, repoName string, maxWait time.Duration) error
+}
+
+// repositoryCreator returns the client missing repositories are created through
+func (r *Repository) repositoryCreator() (repoCreator, error) {
+	if r.newRepoCreator != nil {
+		return r.newRepoCreator()
+	}
+	return github.New(r.endpoint)
+}
+
+// ConflictEvent describes how a conflict between local and remote history was resolved
+type ConflictEvent struct {
+	Time             time.Time         `json:"time"`
+	Strategy         string            `json:"strategy"`
+	Winner           string            `json:"winner"`                       // "local", "remote" or "merged"
+	Files            []string          `json:"files,omitempty"`              // Files that differed between local and remote (merged: the local changes kept)
+	Backup           string            `json:"backup,omitempty"`             // Directory holding the discarded local versions (remote wins only)
+	LocalCommitTime  *time.Time        `json:"local_commit_time,omitempty"`  // Last local commit when the decision was made
+	RemoteCommitTime *time.Time        `json:"remote_commit_time,omitempty"` // Last remote commit when the decision was made
+	LastChangedBy    map[string]string `json:"last_changed_by,omitempty"`    // Machine that last changed each file on the remote (filled in by the syncer)
+}
+
+// SetShallow switches between shallow (latest commit only) and full-history clones and pulls
+// Full history is needed for rollback, diffs against ancestors and proper merges
+func (r *Repository) SetShallow(shallow bool) {
+	if shallow {
+		r.depth = 1
+	} else {
+		r.depth = 0
+	}
+}
+
+// SetAutoInit chooses who seeds a repository created because the remote didn't exist:
+// GitHub (a README commit the clone waits for) or cursor-sync's first push
+// Only one of them ever does, so the two can't create unrelated histories
+func (r *Repository) SetAutoInit(autoInit bool) {
+	r.autoInit = autoInit
+}
+
+// SetCloneProgress sends git's progress messages ("Receiving objects: 45% ...") to w while cloning
+func (r *Repository) SetCloneProgress(w io.Writer) {
+	r.cloneProgress = w
+}
+
+// SetContext makes clone, fetch, pull, push and LFS transfers abort once ctx is done
+func (r *Repository) SetContext(ctx context.Context) {
+	r.ctx = ctx
+}
+
+// SetConflictHandler registers a function that is called whenever a conflict is resolved
+func (r *Repository) SetConflictHandler(handler func(ConflictEvent)) {
+	r.conflictHandler = handler
+}
+
+// beginConflictResolution records the strategy of a conflict resolution that is starting
+func (r *Repository) beginConflictResolution(strategy string) {
+	r.resolveStrategy = strategy
+	r.resolveLocalTime = nil
+	r.resolveRemoteTime = nil
+}
+
+// recordCommitTimes records the commit times compared by the "newer" strategy
+func (r *Repository) recordCommitTimes(localTime, remoteTime time.Time) {
+	r.resolveLocalTime = &localTime
+	r.resolveRemoteTime = &remoteTime
+}
+
+// reportConflict notifies the conflict handler which side won
+// files and backup describe the local changes that were discarded (remote wins only);
+// when local wins, the files still differing from the remote branch are reported
+func (r *Repository) reportConflict(winner string, files []string, backup string) {
+	if r.conflictHandler == nil {
+		return
+	}
+
+	if files == nil && winner == "local" {
+		if changed, err := r.locallyChangedFiles(); err == nil {
+			files = changed
+		} else {
+			logger.Debug("Failed to list conflicting files: %v", err)
+		}
+	}
+
+	r.conflictHandler(ConflictEvent{
+		Time:             time.Now(),
+		Strategy:         r.resolveStrategy,
+		Winner:           winner,
+		Files:            files,
+		Backup:           backup,
+		LocalCommitTime:  r.resolveLocalTime,
+		RemoteCommitTime: r.resolveRemoteTime,
+	})
+}
+
+// backupLocalChanges copies local files that differ from the remote branch to
+// backups/<timestamp> in the state directory before remote changes overwrite them
+// Returns the backup directory and the backed up files (repository-relative)
+func (r *Repository) backupLocalChanges() (string, []string, error) {
+	files, err := r.locallyChangedFiles()
+	if err != nil {
+		return "", nil, err
+	}
+	if len(files) == 0 {
+		return "", nil, nil
+	}
+
+	stateDir, err := paths.StateDir()
+	if err != nil {
+		return "", nil, err
+	}
+	backupDir := filepath.Join(stateDir, "backups", time.Now().Format("20060102-150405"))
+
+	var backedUp []string
+	for _, file := range files {
+		data, err := os.ReadFile(filepath.Join(r.localPath, file))
+		if err != nil {
+			continue // Deleted locally - nothing to back up
+		}
+
+		dest := filepath.Join(backupDir, file)
+		if err := os.MkdirAll(filepath.Dir(dest), 0755); err != nil {
+			return "", nil, fmt.Errorf("failed to create backup directory: %w", err)
+		}
+		if err := os.WriteFile(dest, data, 0644); err != nil {
+			return "", nil, fmt.Errorf("failed to back up %s: %w", file, err)
+		}
+		backedUp = append(backedUp, file)
+	}
+
+	if len(backedUp) == 0 {
+		return "", nil, nil
+	}
+
+	logger.Info("💾 Backed up %d locally changed file(s) to %s", len(backedUp), backupDir)
+	return backupDir, backedUp, nil
+}
+
+// locallyChangedFiles returns files with uncommitted changes or changes committed locally
+// since the last fetched state of the remote branch (changes made only on the remote aren't
+// included)
+func (r *Repository) locallyChangedFiles() ([]string, error) {
+	changed := make(map[string]bool)
+
+	worktree, err := r.repo.Worktree()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get worktree: %w", err)
+	}
+
+	status, err := worktree.Status()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get status: %w", err)
+	}
+	for file, stat := range status {
+		if stat.Worktree == git.Untracked {
+			continue // Untracked files are never overwritten by a pull
+		}
+		if stat.Staging != git.Unmodified || stat.Worktree != git.Unmodified {
+			changed[file] = true
+		}
+	}
+
+	head, err := r.repo.Head()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get HEAD: %w", err)
+	}
+
+	remoteRef, err := r.repo.Reference(plumbing.NewRemoteReferenceName(r.remoteName, r.branch), true)
+	if err == nil && remoteRef.Hash() != head.Hash() {
+		if diffFiles, err := r.diffFiles(r.mergeBase(head.Hash(), remoteRef.Hash()), head.Hash()); err == nil {
+			for _, file := range diffFiles {
+				changed[file] = true
+			}
+		} else {
+			logger.Debug("Failed to diff local and remote branch: %v", err)
+		}
+	}
+
+	files := make([]string, 0, len(changed))
+	for file := range changed {
+		files = append(files, file)
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// mergeBase returns the common ancestor of the local and the remote commit, so diffing it
+// against local shows only local changes. Without one (unrelated or shallow histories) it
+// returns the remote commit itself
+func (r *Repository) mergeBase(local, remote plumbing.Hash) plumbing.Hash {
+	localCommit, err := r.repo.CommitObject(local)
+	if err != nil {
+		return remote
+	}
+	remoteCommit, err := r.repo.CommitObject(remote)
+	if err != nil {
+		return remote
+	}
+	bases, err := localCommit.MergeBase(remoteCommit)
+	if err != nil || len(bases) == 0 {
+		logger.Debug("No common ancestor with the remote branch, comparing against it directly")
+		return remote
+	}
+	return bases[0].Hash
+}
+
+// diffFiles returns the paths that differ between two commits
+func (r *Repository) diffFiles(from, to plumbing.Hash) ([]string, error) {
+	fromCommit, err := r.repo.CommitObject(from)
+	if err != nil {
+		return nil, err
+	}
+	toCommit, err := r.repo.CommitObject(to)
+	if err != nil {
+		return nil, err
+	}
+
+	fromTree, err := fromCommit.Tree()
+	if err != nil {
+		return nil, err
+	}
+	toTree, err := toCommit.Tree()
+	if err != nil {
+		return nil, err
+	}
+
+	changes, err := object.DiffTree(fromTree, toTree)
+	if err != nil {
+		return nil, err
+	}
+
+	files := make([]string, 0, len(changes))
+	for _, change := range changes {
+		name := change.From.Name
+		if name == "" {
+			name = change.To.Name
+		}
+		files = append(files, name)
+	}
+	return files, nil
+}
+
+// New creates a new Git repository instance that reaches GitHub through endpoint
+func New(localPath, remoteName, branch, repoURL string, endpoint auth.Endpoint) (*Repository, error) {
 	// Initialize GitHub authentication
-	githubAuth, err := auth.NewGitHubAuth()
+	githubAuth, err := auth.NewGitHubAuth(endpoint)
 	if err != nil {
 		return nil, fmt.Errorf("GitHub authentication failed: %w", err)
 	}
 
 	// Parse repository owner and name from URL
-	owner, repoName, err := parseGitHubURL(repoURL)
+	parsed, err := urlutil.ParseGitHubURL(repoURL)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse repository URL: %w", err)
 	}
@@ -48,12 +339,25 @@ func New(localPath, remoteName, branch, repoURL string) (*Repository, error) {
 		localPath:  localPath,
 		remoteName: remoteName,
 		branch:     branch,
+		url:        repoURL,
 		auth:       githubAuth,
-		owner:      owner,
-		repoName:   repoName,
+		endpoint:   endpoint,
+		owner:      parsed.Owner,
+		repoName:   parsed.Repo,
 	}, nil
 }
 
+// NewLocal creates a repository whose remote needs no credentials, such as a bare
+// repository on disk. Without a GitHub remote, a missing repository isn't created
+func NewLocal(localPath, remoteName, branch, remoteURL string) *Repository {
+	return &Repository{
+		localPath:  localPath,
+		remoteName: remoteName,
+		branch:     branch,
+		url:        remoteURL,
+	}
+}
+
 // Clone clones a remote repository using GitHub token authentication
 func (r *Repository) Clone(remoteURL string) error {
 	logger.Info("Cloning repository from %s to %s", remoteURL, r.localPath)
@@ -71,35 +375,31 @@ func (r *Repository) Clone(remoteURL string) error {
 	}
 
 	// Use token authentication
-	auth := &http.BasicAuth{
-		Username: "token", // GitHub uses 'token' as username for PAT auth
-		Password: r.auth.GetToken(),
-	}
+	auth := r.basicAuth()
 
 	// Try to clone repository with authentication
-	repo, err := git.PlainClone(r.localPath, false, &git.CloneOptions{
+	ctx, cancel := r.networkContext()
+	repo, err := git.PlainCloneContext(ctx, r.localPath, false, &git.CloneOptions{
 		URL:           remoteURL,
 		Auth:          auth,
 		ReferenceName: plumbing.NewBranchReferenceName(r.branch),
 		SingleBranch:  true,
-		// Depth:         1, // Remove shallow clone for better Git operations
+		Depth:         r.depth, // 0 = full history unless repository.shallow is set
+		Progress:      r.cloneProgress,
 	})
+	cancel()
 
 	if err != nil {
-		// Check if error is due to empty repository (common with new GitHub repos)
-		errStr := strings.ToLower(err.Error())
-		if strings.Contains(errStr, "remote repository is empty") ||
-			strings.Contains(errStr, "reference not found") ||
-			strings.Contains(errStr, "couldn't find remote ref") {
+		err = classifyRemoteError(err, nil)
 
+		// Check if error is due to empty repository (common with new GitHub repos)
+		if errors.Is(err, ErrEmptyRepo) || isMissingRemoteBranch(err) {
 			logger.Info("🔄 Remote repository is empty - initializing with first commit...")
 			return r.initializeEmptyRepository(remoteURL, auth)
 		}
 
 		// Check if repository doesn't exist - try to create it automatically
-		if strings.Contains(strings.ToLower(err.Error()), "repository not found") ||
-			strings.Contains(strings.ToLower(err.Error()), "404") {
-
+		if errors.Is(err, ErrRepoNotFound) && r.auth != nil {
 			logger.Info("🚀 Repository not found - attempting to create it automatically...")
 			return r.createAndCloneRepository(remoteURL, auth)
 		}
@@ -114,11 +414,13 @@ func (r *Repository) Clone(remoteURL string) error {
 }
 
 // initializeEmptyRepository initializes a new local repository and pushes initial content to empty remote
-func (r *Repository) initializeEmptyRepository(remoteURL string, auth *http.BasicAuth) error {
+func (r *Repository) initializeEmptyRepository(remoteURL string, auth transport.AuthMethod) error {
 	logger.Info("🚀 Initializing empty repository with initial commit...")
 
 	// Initialize local git repository
-	repo, err := git.PlainInit(r.localPath, false)
+	repo, err := git.PlainInitWithOptions(r.localPath, &git.PlainInitOptions{
+		InitOptions: git.InitOptions{DefaultBranch: plumbing.NewBranchReferenceName(r.branch)},
+	})
 	if err != nil {
 		return fmt.Errorf("failed to initialize local repository: %w", err)
 	}
@@ -190,51 +492,62 @@ This repository contains synchronized Cursor IDE settings.
 
 	// Push to remote repository (creates main branch on GitHub)
 	logger.Info("📤 Pushing initial commit to remote repository...")
-	err = repo.Push(&git.PushOptions{
+	ctx, cancel := r.networkContext()
+	err = repo.PushContext(ctx, &git.PushOptions{
 		RemoteName: r.remoteName,
 		Auth:       auth,
-		RefSpecs:   []config.RefSpec{config.RefSpec(fmt.Sprintf("refs/heads/%s:refs/heads/%s", r.branch, r.branch))},
+		RefSpecs:   []config.RefSpec{r.branchRefSpec()},
 	})
+	cancel()
 	if err != nil && err != git.NoErrAlreadyUpToDate {
 		return fmt.Errorf("failed to push initial commit: %w", err)
 	}
 
-	logger.Info("🎉 Empty repository initialized successfully with main branch!")
+	logger.Info("🎉 Empty repository initialized successfully with %s branch!", r.branch)
 	logger.Info("📍 Repository is now ready for cursor-sync operations")
 
 	return nil
 }
 
 // createAndCloneRepository creates a new repository on GitHub and then clones it
-func (r *Repository) createAndCloneRepository(remoteURL string, auth *http.BasicAuth) error {
+func (r *Repository) createAndCloneRepository(remoteURL string, auth transport.AuthMethod) error {
 	logger.Info("🔧 Creating new repository on GitHub...")
 
 	// Create GitHub API client
-	githubAPI, err := github.New()
+	githubAPI, err := r.repositoryCreator()
 	if err != nil {
 		return fmt.Errorf("failed to create GitHub API client: %w", err)
 	}
 
 	// Parse owner and repo name from URL
-	owner, repoName, err := parseGitHubURL(remoteURL)
+	parsed, err := urlutil.ParseGitHubURL(remoteURL)
 	if err != nil {
 		return fmt.Errorf("failed to parse repository URL: %w", err)
 	}
+	owner, repoName := parsed.Owner, parsed.Repo
 
 	// Check if repository already exists (in case it was created by another process)
-	exists, err := githubAPI.RepositoryExists(owner, repoName)
+	var exists bool
+	err = retryRateLimited("repository check", func() (err error) {
+		exists, err = githubAPI.RepositoryExists(owner, repoName)
+		return err
+	})
 	if err != nil {
 		logger.Warn("Failed to check repository existence: %v", err)
 	} else if exists {
 		logger.Info("✅ Repository already exists, proceeding with clone...")
-		return r.retryCloneWithBackoff(remoteURL, auth)
+		return r.retryCloneWithBackoff(remoteURL, auth, false)
 	}
 
 	// Create repository description
 	description := fmt.Sprintf("Cursor IDE settings sync repository - managed by cursor-sync")
 
-	// Create the repository
-	repo, err := githubAPI.CreateRepository(owner, repoName, description)
+	// Create the repository (a request rejected by rate limiting created nothing, so it can be retried)
+	var repo *github.RepositoryResponse
+	err = retryRateLimited("repository creation", func() (err error) {
+		repo, err = githubAPI.CreateRepository(owner, repoName, description, r.autoInit)
+		return err
+	})
 	if err != nil {
 		return fmt.Errorf("failed to create repository: %w", err)
 	}
@@ -243,17 +556,44 @@ func (r *Repository) createAndCloneRepository(remoteURL string, auth *http.Basic
 	logger.Info("🔒 Repository is PRIVATE for security")
 
 	// Wait for repository to be ready (GitHub sometimes takes a few seconds)
-	if err := githubAPI.WaitForRepositoryReady(owner, repoName, 10*time.Second); err != nil {
+	if err := githubAPI.WaitForRepositoryReady(owner, repoName, repositoryReadyTimeout); err != nil {
 		logger.Warn("Repository not ready after waiting: %v", err)
 		logger.Info("🔄 Proceeding anyway - will retry clone with backoff...")
 	}
 
+	// An empty repository is seeded by us; cloning it would only report it as empty
+	if !r.autoInit {
+		return r.initializeEmptyRepository(remoteURL, auth)
+	}
+
 	// Retry cloning with exponential backoff
-	return r.retryCloneWithBackoff(remoteURL, auth)
+	return r.retryCloneWithBackoff(remoteURL, auth, true)
+}
+
+// repositoryReadyTimeout bounds how long a newly created repository is polled for
+const repositoryReadyTimeout = 30 * time.Second
+
+// maxRateLimitWait is the longest repository creation waits for a GitHub rate limit to reset
+const maxRateLimitWait = time.Minute
+
+// retryRateLimited runs call and, if GitHub rate limited it, waits for the limit to reset
+// (plus jitter) and tries once more. Longer waits are reported as the rate limit error
+func retryRateLimited(what string, call func() error) error {
+	err := call()
+	if rateErr, ok := ratelimit.As(err); ok {
+		if wait := rateErr.RetryWait(); wait <= maxRateLimitWait {
+			logger.Warn("⏳ GitHub API rate limit hit - retrying %s in %v", what, wait.Round(time.Second))
+			time.Sleep(wait)
+			err = call()
+		}
+	}
+	return err
 }
 
 // retryCloneWithBackoff retries cloning with exponential backoff
-func (r *Repository) retryCloneWithBackoff(remoteURL string, auth *http.BasicAuth) error {
+// seeded means GitHub is adding an initial commit: an empty remote is then still being
+// set up and is retried rather than initialized, which would race GitHub's commit
+func (r *Repository) retryCloneWithBackoff(remoteURL string, auth transport.AuthMethod, seeded bool) error {
 	maxRetries := 5
 	baseDelay := 2 * time.Second
 	maxDelay := 10 * time.Second
@@ -262,13 +602,16 @@ func (r *Repository) retryCloneWithBackoff(remoteURL string, auth *http.BasicAut
 		logger.Info("🔄 Attempt %d/%d: Trying to clone repository...", attempt, maxRetries)
 
 		// Try to clone
-		repo, err := git.PlainClone(r.localPath, false, &git.CloneOptions{
+		ctx, cancel := r.networkContext()
+		repo, err := git.PlainCloneContext(ctx, r.localPath, false, &git.CloneOptions{
 			URL:           remoteURL,
 			Auth:          auth,
 			ReferenceName: plumbing.NewBranchReferenceName(r.branch),
 			SingleBranch:  true,
-			// Depth:         1, // Remove shallow clone for better Git operations
+			Depth:         r.depth, // 0 = full history unless repository.shallow is set
+			Progress:      r.cloneProgress,
 		})
+		cancel()
 
 		if err == nil {
 			r.repo = repo
@@ -277,10 +620,8 @@ func (r *Repository) retryCloneWithBackoff(remoteURL string, auth *http.BasicAut
 		}
 
 		// Check if it's an empty repository error
-		errStr := strings.ToLower(err.Error())
-		if strings.Contains(errStr, "remote repository is empty") ||
-			strings.Contains(errStr, "reference not found") ||
-			strings.Contains(errStr, "couldn't find remote ref") {
+		err = classifyRemoteError(err, nil)
+		if (errors.Is(err, ErrEmptyRepo) && !seeded) || isMissingRemoteBranch(err) {
 
 			logger.Info("🔄 Repository is empty - initializing with first commit...")
 			return r.initializeEmptyRepository(remoteURL, auth)
@@ -298,53 +639,136 @@ func (r *Repository) retryCloneWithBackoff(remoteURL string, auth *http.BasicAut
 		}
 
 		logger.Info("⏳ Repository not ready yet, waiting %v before retry...", delay)
-		time.Sleep(delay)
+		select {
+		case <-time.After(delay):
+		case <-r.baseContext().Done():
+			return fmt.Errorf("clone canceled: %w", r.baseContext().Err())
+		}
 	}
 
 	return fmt.Errorf("failed to clone repository after %d attempts", maxRetries)
 }
 
-// parseGitHubURL parses a GitHub repository URL and extracts owner and repo name
-func parseGitHubURL(repoURL string) (owner, repo string, err error) {
-	// This function should be same as in privacy package
-	// Handle various GitHub URL formats:
-	// https://github.com/owner/repo.git
-	// https://github.com/owner/repo
-	// git@github.com:owner/repo.git
+// ErrBrokenClone is returned by Open when the local clone can't be used and should be re-cloned
+var ErrBrokenClone = errors.New("local clone is unusable")
 
-	url := strings.TrimSpace(repoURL)
-	url = strings.TrimPrefix(url, "https://")
-	url = strings.TrimPrefix(url, "http://")
-	url = strings.TrimPrefix(url, "git@")
-	url = strings.TrimSuffix(url, ".git")
+// Open opens an existing repository
+func (r *Repository) Open() error {
+	repo, err := git.PlainOpen(r.localPath)
+	if err != nil {
+		return fmt.Errorf("failed to open repository: %w: %v", ErrBrokenClone, err)
+	}
 
-	// Replace : with / for SSH format
-	url = strings.Replace(url, ":", "/", 1)
+	r.repo = repo
 
-	// Remove github.com prefix
-	if strings.HasPrefix(url, "github.com/") {
-		url = strings.TrimPrefix(url, "github.com/")
+	// The configured URL may have changed since the clone was made (e.g. cursor-sync repo),
+	// or the remote may have been removed
+	if err := r.reconcileRemoteURL(); err != nil {
+		return fmt.Errorf("%w: %v", ErrBrokenClone, err)
 	}
 
-	parts := strings.Split(url, "/")
-	if len(parts) < 2 {
-		return "", "", fmt.Errorf("invalid GitHub URL format: %s", repoURL)
+	return r.checkoutBranch()
+}
+
+// checkoutBranch switches the worktree to the configured branch if another branch is
+// checked out (e.g. when the branch was overridden with --branch). The branch is created
+// from the remote branch when it exists there, or from the current HEAD otherwise.
+func (r *Repository) checkoutBranch() error {
+	head, err := r.repo.Head()
+	if err != nil {
+		return fmt.Errorf("failed to get HEAD: %w", err)
 	}
 
-	return parts[0], parts[1], nil
-}
+	branchRef := plumbing.NewBranchReferenceName(r.branch)
+	if head.Name() == branchRef {
+		return nil
+	}
 
-// Open opens an existing repository
-func (r *Repository) Open() error {
-	repo, err := git.PlainOpen(r.localPath)
+	logger.Info("🔀 Switching repository from %s to branch %s", head.Name().Short(), r.branch)
+
+	worktree, err := r.repo.Worktree()
 	if err != nil {
-		return fmt.Errorf("failed to open repository: %w", err)
+		return fmt.Errorf("failed to get worktree: %w", err)
+	}
+
+	// Local branch already exists
+	if _, err := r.repo.Reference(branchRef, true); err == nil {
+		if err := worktree.Checkout(&git.CheckoutOptions{Branch: branchRef}); err != nil {
+			return fmt.Errorf("failed to checkout branch %s: %w", r.branch, err)
+		}
+		return nil
+	}
+
+	// Fetch the branch explicitly - single-branch clones only track the original branch
+	remoteRef := plumbing.NewRemoteReferenceName(r.remoteName, r.branch)
+	ctx, cancel := r.networkContext()
+	err = r.repo.FetchContext(ctx, &git.FetchOptions{
+		RemoteName: r.remoteName,
+		RefSpecs:   []config.RefSpec{config.RefSpec(fmt.Sprintf("+%s:%s", branchRef, remoteRef))},
+		Auth:       r.basicAuth(),
+	})
+	cancel()
+
+	hash := head.Hash()
+	switch {
+	case err == nil || err == git.NoErrAlreadyUpToDate:
+		ref, refErr := r.repo.Reference(remoteRef, true)
+		if refErr != nil {
+			return fmt.Errorf("failed to resolve remote branch %s: %w", r.branch, refErr)
+		}
+		hash = ref.Hash()
+	case isMissingRemoteBranch(err):
+		logger.Info("Branch %s doesn't exist on remote yet - creating it from %s", r.branch, head.Name().Short())
+	default:
+		// Creating the branch from the current one here would later push over the remote branch
+		return fmt.Errorf("failed to fetch branch %s: %w", r.branch, classifyRemoteError(err, nil))
+	}
+
+	if err := worktree.Checkout(&git.CheckoutOptions{
+		Branch: branchRef,
+		Hash:   hash,
+		Create: true,
+	}); err != nil {
+		return fmt.Errorf("failed to create branch %s: %w", r.branch, err)
 	}
 
-	r.repo = repo
 	return nil
 }
 
+// basicAuth returns token authentication for remote operations, or none for a repository
+// created with NewLocal
+func (r *Repository) basicAuth() transport.AuthMethod {
+	if r.auth == nil {
+		return nil
+	}
+	return &http.BasicAuth{
+		Username: "token", // GitHub uses 'token' as username for PAT auth
+		Password: r.auth.GetToken(),
+	}
+}
+
+// baseContext returns the context set with SetContext, or one that is never canceled
+func (r *Repository) baseContext() context.Context {
+	if r.ctx == nil {
+		return context.Background()
+	}
+	return r.ctx
+}
+
+// networkContext is the context of a single clone, fetch, pull or push. A connection that
+// can't be made, or stays silent, for network.timeout fails the sync with ErrNetwork
+// instead of stalling it; a slow transfer that keeps moving may take longer. Canceling
+// the base context (daemon shutdown) aborts the operation too
+func (r *Repository) networkContext() (context.Context, context.CancelFunc) {
+	return context.WithCancel(withInactivityTimeout(r.baseContext(), r.endpoint.NetworkTimeout()))
+}
+
+// branchRefSpec returns the refspec that pushes the configured branch to the remote
+func (r *Repository) branchRefSpec() config.RefSpec {
+	branchRef := plumbing.NewBranchReferenceName(r.branch)
+	return config.RefSpec(fmt.Sprintf("%s:%s", branchRef, branchRef))
+}
+
 // Pull pulls changes from the remote repository using GitHub token
 func (r *Repository) Pull() error {
 	if r.repo == nil {
@@ -353,54 +777,59 @@ func (r *Repository) Pull() error {
 
 	logger.Debug("Pulling changes from remote")
 
+	if r.incrementalMaxFiles > 0 {
+		if err := r.pullIncremental(); err == nil {
+			return nil
+		} else {
+			logger.Debug("Incremental pull not possible, using git pull: %v", err)
+		}
+	}
+
 	worktree, err := r.repo.Worktree()
 	if err != nil {
 		return fmt.Errorf("failed to get worktree: %w", err)
 	}
 
 	// Use token authentication for pull
-	auth := &http.BasicAuth{
-		Username: "token",
-		Password: r.auth.GetToken(),
-	}
+	auth := r.basicAuth()
 
-	err = worktree.Pull(&git.PullOptions{
+	ctx, cancel := r.networkContext()
+	err = worktree.PullContext(ctx, &git.PullOptions{
 		RemoteName:    r.remoteName,
 		ReferenceName: plumbing.NewBranchReferenceName(r.branch),
 		Auth:          auth,
-		// Remove shallow clone to fix merge conflicts and history issues
-		// Depth:         1, // Shallow pull - only fetch latest commit
+		Depth:         r.depth, // 0 = full history unless repository.shallow is set
 	})
+	cancel()
 
 	if err == git.NoErrAlreadyUpToDate {
 		logger.Debug("Repository already up to date")
+		r.recordSynced()
+		return nil
+	}
+
+	// Branch doesn't exist on the remote yet; the next push creates it
+	if err != nil && isMissingRemoteBranch(err) {
+		logger.Debug("Branch %s not found on remote - nothing to pull", r.branch)
 		return nil
 	}
 
 	// Handle specific Git errors more gracefully
 	if err != nil {
-		errStr := err.Error()
-
-		// Check for common conflict scenarios
-		if strings.Contains(errStr, "non-fast-forward") ||
-			strings.Contains(errStr, "rejected") ||
-			strings.Contains(errStr, "cannot lock ref") {
+		err = classifyRemoteError(err, ErrPullConflict)
+		switch {
+		case errors.Is(err, ErrPullConflict):
 			logger.Debug("Pull conflict detected: %v", err)
-			return fmt.Errorf("pull conflict: %w", err)
-		}
-
-		// Check for network or authentication issues
-		if strings.Contains(errStr, "authentication") ||
-			strings.Contains(errStr, "network") ||
-			strings.Contains(errStr, "timeout") {
+			return err
+		case errors.Is(err, ErrAuth), errors.Is(err, ErrNetwork):
 			logger.Debug("Network/authentication issue during pull: %v", err)
-			return fmt.Errorf("network/authentication error: %w", err)
+			return err
 		}
-
 		return fmt.Errorf("failed to pull changes: %w", err)
 	}
 
 	logger.Info("Pulled changes from remote")
+	r.recordSynced()
 	return nil
 }
 
@@ -419,6 +848,7 @@ func (r *Repository) PullWithConflictResolution(strategy string) error {
 
 	// If normal pull failed, try conflict resolution based on strategy
 	logger.Info("Normal pull failed, attempting conflict resolution with strategy: %s", strategy)
+	r.beginConflictResolution(strategy)
 
 	switch strategy {
 	case "newer":
@@ -445,16 +875,28 @@ func (r *Repository) pullWithNewerStrategy() error {
 		logger.Warn("Failed to get remote commit time, using local strategy: %v", err)
 		return r.pullWithLocalStrategy()
 	}
+	r.recordCommitTimes(localTime, remoteTime)
 
-	// Calculate time difference
-	timeDiff := localTime.Sub(remoteTime)
+	// Changes that don't overlap (or that overlap only in files that can be combined, such as
+	// keybindings) are merged whatever the commit times say, so neither side loses edits
+	overlap, mergeErr := r.mergeChanges()
+	if mergeErr == nil {
+		return nil
+	}
 
-	// If timestamps are very close (within 5 seconds), use deterministic fallback
-	if timeDiff.Abs() <= 5*time.Second {
-		logger.Info("Timestamps are very close (%v), using deterministic remote strategy", timeDiff)
-		return r.pullWithRemoteStrategy() // Always prefer remote for consistency
+	// A wrong clock on either machine would pick the wrong side
+	if !r.commitTimesTrusted(localTime, remoteTime, time.Now()) {
+		return r.resolveUntrustedTimes(localTime, remoteTime, overlap, mergeErr)
+	}
+	if !errors.Is(mergeErr, errOverlappingChanges) {
+		logger.Warn("Failed to merge local and remote changes: %v", mergeErr)
+	} else {
+		logger.Info("Both sides changed %d file(s) that can't be combined: %v", len(overlap), overlap)
 	}
 
+	// Calculate time difference
+	timeDiff := localTime.Sub(remoteTime)
+
 	if localTime.After(remoteTime) {
 		logger.Info("Local changes are newer by %v, keeping local version", timeDiff)
 		return r.pullWithLocalStrategy()
@@ -468,16 +910,38 @@ func (r *Repository) pullWithNewerStrategy() error {
 func (r *Repository) pullWithLocalStrategy() error {
 	logger.Info("Using local strategy - keeping local changes")
 
-	// Reset to local HEAD to discard any partial merge state
 	worktree, err := r.repo.Worktree()
 	if err != nil {
 		return fmt.Errorf("failed to get worktree: %w", err)
 	}
 
-	// Try to reset to HEAD to clean up any merge state
+	// Settings may have been copied in without being committed yet;
+	// commit them first so the reset below cannot discard them
+	if err := r.commitPendingChanges(worktree); err != nil {
+		return fmt.Errorf("failed to preserve local changes: %w", err)
+	}
+
+	// Merging the remote branch, keeping the local side of every conflict, lets the local
+	// commits be pushed; a branch that only holds its own history could never be pushed
+	//
+	// The merge reports itself to the conflict handler: "merged", or "local" for the files
+	// both sides changed
+	_, mergeErr := r.mergeBranches(true)
+	if mergeErr == nil {
+		logger.Info("Successfully merged remote changes, keeping local changes")
+		return nil
+	}
+	logger.Warn("Failed to merge remote changes, keeping local branch as is: %v", mergeErr)
+
+	head, err := r.repo.Head()
+	if err != nil {
+		return fmt.Errorf("failed to get HEAD: %w", err)
+	}
+
+	// Reset to the (now complete) local HEAD to discard any partial merge state
 	if err := worktree.Reset(&git.ResetOptions{
 		Mode:   git.HardReset,
-		Commit: plumbing.ZeroHash, // Reset to HEAD
+		Commit: head.Hash(),
 	}); err != nil {
 		// If reset fails due to missing files, try a more gentle approach
 		logger.Warn("Hard reset failed, trying soft reset: %v", err)
@@ -485,7 +949,7 @@ func (r *Repository) pullWithLocalStrategy() error {
 		// Try soft reset instead
 		if err := worktree.Reset(&git.ResetOptions{
 			Mode:   git.SoftReset,
-			Commit: plumbing.ZeroHash, // Reset to HEAD
+			Commit: head.Hash(),
 		}); err != nil {
 			logger.Warn("Soft reset also failed: %v", err)
 
@@ -497,6 +961,37 @@ func (r *Repository) pullWithLocalStrategy() error {
 	}
 
 	logger.Info("Successfully kept local changes")
+	r.reportConflict("local", nil, "")
+	return nil
+}
+
+// commitPendingChanges commits uncommitted changes to tracked and untracked files
+// Does nothing when the worktree is clean
+func (r *Repository) commitPendingChanges(worktree *git.Worktree) error {
+	status, err := worktree.Status()
+	if err != nil {
+		return fmt.Errorf("failed to get status: %w", err)
+	}
+	if status.IsClean() {
+		return nil
+	}
+
+	if err := worktree.AddWithOptions(&git.AddOptions{All: true}); err != nil {
+		return fmt.Errorf("failed to stage local changes: %w", err)
+	}
+	if err := r.unstageInternalFiles(); err != nil {
+		return err
+	}
+
+	signature := r.signature(time.Now())
+	commit, err := worktree.Commit("Preserve local changes before conflict resolution", &git.CommitOptions{
+		Author: &signature,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to commit local changes: %w", err)
+	}
+
+	logger.Info("Committed uncommitted local changes before reset: %s", commit.String()[:8])
 	return nil
 }
 
@@ -509,20 +1004,39 @@ func (r *Repository) pullWithRemoteStrategy() error {
 		return fmt.Errorf("failed to get worktree: %w", err)
 	}
 
-	// Use token authentication
-	auth := &http.BasicAuth{
-		Username: "token",
-		Password: r.auth.GetToken(),
+	// Keep a copy of the local changes that are about to be discarded
+	backupDir, backedUp, err := r.backupLocalChanges()
+	if err != nil {
+		logger.Warn("Failed to back up local changes: %v", err)
 	}
 
+	// Use token authentication
+	auth := r.basicAuth()
+
 	// Force pull to overwrite local changes
-	err = worktree.Pull(&git.PullOptions{
+	ctx, cancel := r.networkContext()
+	err = worktree.PullContext(ctx, &git.PullOptions{
 		RemoteName:    r.remoteName,
 		ReferenceName: plumbing.NewBranchReferenceName(r.branch),
 		Auth:          auth,
-		Force:         true, // Force overwrite local changes
-		// Depth:         1, // Remove shallow clone for better Git operations    // Shallow pull
+		Force:         true,    // Force overwrite local changes
+		Depth:         r.depth, // 0 = full history unless repository.shallow is set
 	})
+	cancel()
+
+	// Force only discards worktree changes; local commits the remote doesn't have make the
+	// pull fail, so reset the branch to the remote head instead (they were backed up above)
+	if errors.Is(err, git.ErrNonFastForwardUpdate) {
+		logger.Info("Local branch has diverged from remote - resetting to the remote branch")
+		remoteHash, fetchErr := r.fetchRemoteBranch()
+		if fetchErr != nil {
+			return fetchErr
+		}
+		if err := worktree.Reset(&git.ResetOptions{Mode: git.HardReset, Commit: remoteHash}); err != nil {
+			return fmt.Errorf("failed to reset to remote branch: %w", err)
+		}
+		err = nil
+	}
 
 	if err != nil && err != git.NoErrAlreadyUpToDate {
 		// If force pull fails, try to clean up and retry
@@ -534,13 +1048,15 @@ func (r *Repository) pullWithRemoteStrategy() error {
 		}
 
 		// Try force pull again
-		err = worktree.Pull(&git.PullOptions{
+		ctx, cancel := r.networkContext()
+		err = worktree.PullContext(ctx, &git.PullOptions{
 			RemoteName:    r.remoteName,
 			ReferenceName: plumbing.NewBranchReferenceName(r.branch),
 			Auth:          auth,
 			Force:         true,
-			// Depth:         1, // Remove shallow clone for better Git operations
+			Depth:         r.depth, // 0 = full history unless repository.shallow is set
 		})
+		cancel()
 
 		if err != nil && err != git.NoErrAlreadyUpToDate {
 			return fmt.Errorf("failed to force pull remote changes after cleanup: %w", err)
@@ -548,6 +1064,7 @@ func (r *Repository) pullWithRemoteStrategy() error {
 	}
 
 	logger.Info("Successfully accepted remote changes")
+	r.reportConflict("remote", backedUp, backupDir)
 	return nil
 }
 
@@ -593,47 +1110,49 @@ func (r *Repository) Push() error {
 
 	logger.Debug("Pushing changes to remote")
 
-	// Use token authentication for push
-	auth := &http.BasicAuth{
-		Username: "token",
-		Password: r.auth.GetToken(),
+	// LFS objects must be on the server before commits referencing them
+	if err := r.pushLFSObjects(); err != nil {
+		return fmt.Errorf("failed to upload LFS objects: %w", err)
+	}
+
+	// Tell other machines a push is under way and let theirs finish first
+	if r.pushLease > 0 && r.acquirePushLease() {
+		defer r.releasePushLease()
 	}
 
-	err := r.repo.Push(&git.PushOptions{
+	// Use token authentication for push
+	auth := r.basicAuth()
+
+	ctx, cancel := r.networkContext()
+	err := r.repo.PushContext(ctx, &git.PushOptions{
 		RemoteName: r.remoteName,
+		RefSpecs:   []config.RefSpec{r.branchRefSpec()},
 		Auth:       auth,
 	})
+	cancel()
 
 	if err == git.NoErrAlreadyUpToDate {
 		logger.Debug("Remote already up to date")
+		r.recordSynced()
 		return nil
 	}
 
 	// Handle specific Git errors more gracefully
 	if err != nil {
-		errStr := err.Error()
-
-		// Check for common conflict scenarios
-		if strings.Contains(errStr, "non-fast-forward") ||
-			strings.Contains(errStr, "rejected") ||
-			strings.Contains(errStr, "cannot lock ref") ||
-			strings.Contains(errStr, "object not found") {
+		err = classifyRemoteError(err, ErrPushConflict)
+		switch {
+		case errors.Is(err, ErrPushConflict):
 			logger.Debug("Push conflict detected: %v", err)
-			return fmt.Errorf("push conflict: %w", err)
-		}
-
-		// Check for network or authentication issues
-		if strings.Contains(errStr, "authentication") ||
-			strings.Contains(errStr, "network") ||
-			strings.Contains(errStr, "timeout") {
+			return err
+		case errors.Is(err, ErrAuth), errors.Is(err, ErrNetwork):
 			logger.Debug("Network/authentication issue during push: %v", err)
-			return fmt.Errorf("network/authentication error: %w", err)
+			return err
 		}
-
 		return fmt.Errorf("failed to push changes: %w", err)
 	}
 
 	logger.Info("Pushed changes to remote")
+	r.recordSynced()
 	return nil
 }
 
@@ -653,7 +1172,26 @@ func (r *Repository) Add(pattern string) error {
 		return fmt.Errorf("failed to add files: %w", err)
 	}
 
-	return nil
+	return r.unstageInternalFiles()
+}
+
+// SetCommitAuthor sets the author of the commits the repository makes on its own behalf,
+// such as merges of remote changes ("" keeps cursor-sync)
+func (r *Repository) SetCommitAuthor(name, email string) {
+	r.authorName = name
+	r.authorEmail = email
+}
+
+// signature returns the author of the repository's own commits, made at when
+func (r *Repository) signature(when time.Time) object.Signature {
+	signature := object.Signature{Name: "cursor-sync", Email: "cursor-sync@local", When: when}
+	if r.authorName != "" {
+		signature.Name = r.authorName
+	}
+	if r.authorEmail != "" {
+		signature.Email = r.authorEmail
+	}
+	return signature
 }
 
 // Commit commits staged changes
@@ -671,7 +1209,8 @@ func (r *Repository) Commit(message, authorName, authorEmail string) error {
 		Author: &object.Signature{
 			Name:  authorName,
 			Email: authorEmail,
-			When:  time.Now(),
+			// UTC so incremental pulls can rebuild the commit from the API's UTC timestamps
+			When: time.Now().UTC(),
 		},
 	})
 
@@ -699,7 +1238,47 @@ func (r *Repository) HasChanges() (bool, error) {
 		return false, fmt.Errorf("failed to get status: %w", err)
 	}
 
-	return !status.IsClean(), nil
+	if status.IsClean() {
+		return false, nil
+	}
+
+	idx, err := r.repo.Storer.Index()
+	if err != nil {
+		return false, fmt.Errorf("failed to read index: %w", err)
+	}
+
+	// Some filesystems report permission-only differences; committing those produces
+	// empty-diff auto-sync commits every cycle, so only content changes count
+	for name, fileStatus := range status {
+		if fileStatus.Staging == git.Unmodified && fileStatus.Worktree == git.Modified && r.isModeOnlyChange(idx, name) {
+			logger.Debug("Ignoring mode-only change: %s", name)
+			continue
+		}
+		return true, nil
+	}
+
+	return false, nil
+}
+
+// isModeOnlyChange reports whether a modified worktree file still has the content in the index
+func (r *Repository) isModeOnlyChange(idx *index.Index, name string) bool {
+	entry, err := idx.Entry(name)
+	if err != nil {
+		return false
+	}
+
+	fullPath := filepath.Join(r.localPath, filepath.FromSlash(name))
+	info, err := os.Lstat(fullPath)
+	if err != nil || !info.Mode().IsRegular() {
+		return false
+	}
+
+	data, err := os.ReadFile(fullPath)
+	if err != nil {
+		return false
+	}
+
+	return plumbing.ComputeHash(plumbing.BlobObject, data) == entry.Hash
 }
 
 // GetLastCommitTime returns the timestamp of the last commit
@@ -721,15 +1300,30 @@ func (r *Repository) GetLastCommitTime() (time.Time, error) {
 	return commit.Author.When, nil
 }
 
-// GetRemoteLastCommitTime returns the timestamp of the last commit on the remote branch using GitHub API
+// GetRemoteLastCommitTime returns the timestamp of the last commit on the remote branch
+// The GitHub API is used as the fast default; when no API client is available or the
+// API call fails, the remote branch is fetched and the time read from the commit object
 func (r *Repository) GetRemoteLastCommitTime() (time.Time, error) {
-	ctx := context.Background()
+	if r.auth != nil && r.auth.GetClient() != nil && r.owner != "" {
+		commitTime, err := r.remoteCommitTimeFromAPI()
+		if err == nil {
+			return commitTime, nil
+		}
+		logger.Debug("GitHub API commit lookup failed, falling back to git fetch: %v", err)
+	}
+
+	return r.remoteCommitTimeFromGit()
+}
+
+// remoteCommitTimeFromAPI reads the remote branch's last commit time from the GitHub API
+func (r *Repository) remoteCommitTimeFromAPI() (time.Time, error) {
+	ctx := r.baseContext()
 	client := r.auth.GetClient()
 
 	// Get the latest commit from the branch using GitHub API
 	branch, _, err := client.Repositories.GetBranch(ctx, r.owner, r.repoName, r.branch, 3)
 	if err != nil {
-		return time.Time{}, fmt.Errorf("failed to get branch info from GitHub API: %w", err)
+		return time.Time{}, fmt.Errorf("failed to get branch info from GitHub API: %w", ratelimit.FromGitHubError(err))
 	}
 
 	if branch.Commit == nil || branch.Commit.Commit == nil || branch.Commit.Commit.Author == nil {
@@ -739,6 +1333,26 @@ func (r *Repository) GetRemoteLastCommitTime() (time.Time, error) {
 	return branch.Commit.Commit.Author.GetDate().Time, nil
 }
 
+// remoteCommitTimeFromGit fetches the remote branch head and reads its commit time
+// Works with any git host, not just GitHub
+func (r *Repository) remoteCommitTimeFromGit() (time.Time, error) {
+	if r.repo == nil {
+		return time.Time{}, fmt.Errorf("repository not initialized")
+	}
+
+	remoteHash, err := r.fetchRemoteBranch()
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	commit, err := r.repo.CommitObject(remoteHash)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to read remote commit: %w", err)
+	}
+
+	return commit.Author.When, nil
+}
+
 // ResolveConflicts resolves merge conflicts based on strategy
 func (r *Repository) ResolveConflicts(strategy string) error {
 	if r.repo == nil {
@@ -746,6 +1360,7 @@ func (r *Repository) ResolveConflicts(strategy string) error {
 	}
 
 	logger.Info("Resolving conflicts using strategy: %s", strategy)
+	r.beginConflictResolution(strategy)
 
 	switch strategy {
 	case "newer":
@@ -769,6 +1384,15 @@ func (r *Repository) resolveByTimestamp() error {
 	if err != nil {
 		return fmt.Errorf("failed to get remote commit time: %w", err)
 	}
+	r.recordCommitTimes(localTime, remoteTime)
+
+	overlap, mergeErr := r.mergeChanges()
+	if mergeErr == nil {
+		return nil
+	}
+	if !r.commitTimesTrusted(localTime, remoteTime, time.Now()) {
+		return r.resolveUntrustedTimes(localTime, remoteTime, overlap, mergeErr)
+	}
 
 	if localTime.After(remoteTime) {
 		logger.Info("Local changes are newer, keeping local version")
@@ -780,6 +1404,13 @@ func (r *Repository) resolveByTimestamp() error {
 }
 
 func (r *Repository) resolveWithLocal() error {
+	// Keeping the local side of every conflict in a merge lets the local commits be pushed
+	_, mergeErr := r.mergeBranches(true)
+	if mergeErr == nil {
+		return nil
+	}
+	logger.Warn("Failed to merge remote changes, keeping local branch as is: %v", mergeErr)
+
 	worktree, err := r.repo.Worktree()
 	if err != nil {
 		return fmt.Errorf("failed to get worktree: %w", err)
@@ -794,12 +1425,13 @@ func (r *Repository) resolveWithLocal() error {
 		if stat.Staging == git.UpdatedButUnmerged || stat.Worktree == git.UpdatedButUnmerged {
 			// Keep local version
 			_, err = worktree.Remove(file)
-			if err != nil && !strings.Contains(err.Error(), "file does not exist") {
+			if err != nil && !errors.Is(err, os.ErrNotExist) {
 				return fmt.Errorf("failed to remove conflicted file: %w", err)
 			}
 		}
 	}
 
+	r.reportConflict("local", nil, "")
 	return nil
 }
 
@@ -809,24 +1441,49 @@ func (r *Repository) resolveWithRemote() error {
 		return fmt.Errorf("failed to get worktree: %w", err)
 	}
 
-	// Pull again to accept remote changes
-	auth := &http.BasicAuth{
-		Username: "token",
-		Password: r.auth.GetToken(),
+	// Keep a copy of the local changes that are about to be discarded
+	backupDir, backedUp, err := r.backupLocalChanges()
+	if err != nil {
+		logger.Warn("Failed to back up local changes: %v", err)
 	}
 
-	err = worktree.Pull(&git.PullOptions{
+	// Pull again to accept remote changes
+	auth := r.basicAuth()
+
+	ctx, cancel := r.networkContext()
+	err = worktree.PullContext(ctx, &git.PullOptions{
 		RemoteName:    r.remoteName,
 		ReferenceName: plumbing.NewBranchReferenceName(r.branch),
 		Auth:          auth,
 		Force:         true,
-		// Remove shallow clone to fix merge conflicts and history issues
-		// Depth:         1, // Shallow pull - only fetch latest commit
+		Depth:         r.depth, // 0 = full history unless repository.shallow is set
 	})
+	cancel()
+
+	// The local branch holds the unpushed sync commit, so the pull can't fast-forward;
+	// reset the branch to the remote head instead (the local changes were backed up above)
+	if errors.Is(err, git.ErrNonFastForwardUpdate) {
+		logger.Info("Local branch has diverged from remote - resetting to the remote branch")
+		var remoteHash plumbing.Hash
+		if remoteHash, err = r.fetchRemoteBranch(); err == nil {
+			if err = worktree.Reset(&git.ResetOptions{Mode: git.HardReset, Commit: remoteHash}); err != nil {
+				err = fmt.Errorf("failed to reset to remote branch: %w", err)
+			}
+		}
+	} else if err != nil && err != git.NoErrAlreadyUpToDate {
+		err = fmt.Errorf("failed to pull remote changes: %w", err)
+	} else {
+		err = nil
+	}
 
-	if err != nil && err != git.NoErrAlreadyUpToDate {
-		return fmt.Errorf("failed to pull remote changes: %w", err)
+	if err != nil {
+		// Nothing was discarded, so the backup would only be clutter
+		if backupDir != "" {
+			os.RemoveAll(backupDir)
+		}
+		return err
 	}
 
+	r.reportConflict("remote", backedUp, backupDir)
 	return nil
 }
@@ -0,0 +1,278 @@
+package git
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+func TestPullWithLocalStrategyKeepsUncommittedChanges(t *testing.T) {
+	dir := t.TempDir()
+	repo, err := git.PlainInit(dir, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	settings := filepath.Join(dir, "settings.json")
+	if err := os.WriteFile(settings, []byte(`{"a": 1}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	worktree, err := repo.Worktree()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := worktree.Add("settings.json"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := worktree.Commit("initial", &git.CommitOptions{
+		Author: &object.Signature{Name: "test", Email: "test@local", When: time.Now()},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Local settings copied in but not committed yet
+	if err := os.WriteFile(settings, []byte(`{"a": 2}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	keybindings := filepath.Join(dir, "keybindings.json")
+	if err := os.WriteFile(keybindings, []byte(`[]`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	r := &Repository{repo: repo, localPath: dir, remoteName: "origin", branch: "master"}
+	if err := r.pullWithLocalStrategy(); err != nil {
+		t.Fatalf("pullWithLocalStrategy() error = %v", err)
+	}
+
+	data, err := os.ReadFile(settings)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != `{"a": 2}` {
+		t.Errorf("settings.json = %s, want local edit preserved", data)
+	}
+	if _, err := os.Stat(keybindings); err != nil {
+		t.Errorf("new local file was removed: %v", err)
+	}
+
+	status, err := worktree.Status()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !status.IsClean() {
+		t.Errorf("worktree not clean after resolution:\n%s", status)
+	}
+}
+
+func TestWriteTreeRebuildsCommittedTree(t *testing.T) {
+	dir := t.TempDir()
+	repo, err := git.PlainInit(dir, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Names chosen so git's "directory sorts as name/" ordering matters
+	files := map[string]string{
+		"settings.json":                   `{}`,
+		"snippets/go.json":                `{}`,
+		"snippets.json":                   `[]`,
+		"snippets-old/a.json":             `1`,
+		"User/globalStorage/state/x.json": `2`,
+	}
+	worktree, err := repo.Worktree()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, content := range files {
+		full := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(full), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(full, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := worktree.Add(name); err != nil {
+			t.Fatal(err)
+		}
+	}
+	hash, err := worktree.Commit("initial", &git.CommitOptions{
+		Author: &object.Signature{Name: "test", Email: "test@local", When: time.Now()},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	commit, err := repo.CommitObject(hash)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	flat, err := flattenTree(commit)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := &Repository{repo: repo, localPath: dir}
+	treeHash, err := r.writeTree(flat)
+	if err != nil {
+		t.Fatalf("writeTree() error = %v", err)
+	}
+	if treeHash != commit.TreeHash {
+		t.Errorf("writeTree() = %s, want %s", treeHash, commit.TreeHash)
+	}
+}
+
+func TestHasChangesIgnoresModeOnlyChanges(t *testing.T) {
+	dir := t.TempDir()
+	repo, err := git.PlainInit(dir, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	settings := filepath.Join(dir, "settings.json")
+	if err := os.WriteFile(settings, []byte(`{"a": 1}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	worktree, err := repo.Worktree()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := worktree.Add("settings.json"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := worktree.Commit("initial", &git.CommitOptions{
+		Author: &object.Signature{Name: "test", Email: "test@local", When: time.Now()},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	r := &Repository{repo: repo, localPath: dir}
+
+	if err := os.Chmod(settings, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if changed, err := r.HasChanges(); err != nil || changed {
+		t.Errorf("HasChanges() after chmod = %v, %v; want false", changed, err)
+	}
+
+	if err := os.WriteFile(settings, []byte(`{"a": 2}`), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if changed, err := r.HasChanges(); err != nil || !changed {
+		t.Errorf("HasChanges() after edit = %v, %v; want true", changed, err)
+	}
+}
+
+func TestAddNeverStagesSyncMarker(t *testing.T) {
+	dir := t.TempDir()
+	repo, err := git.PlainInit(dir, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A marker committed by an older version, plus a fresh one and a settings file
+	for _, name := range []string{"User/.custom.sync", ".custom.sync", "User/settings.json", "User/.settings.json.tmp-123"} {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(name), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	worktree, err := repo.Worktree()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := worktree.Add("User/.custom.sync"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := worktree.Commit("leaked marker", &git.CommitOptions{
+		Author: &object.Signature{Name: "test", Email: "test@local", When: time.Now()},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	r := &Repository{repo: repo, localPath: dir}
+	if err := r.Add("."); err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+	if err := r.Commit("sync", "test", "test@local"); err != nil {
+		t.Fatalf("Commit() error = %v", err)
+	}
+
+	head, err := repo.Head()
+	if err != nil {
+		t.Fatal(err)
+	}
+	commit, err := repo.CommitObject(head.Hash())
+	if err != nil {
+		t.Fatal(err)
+	}
+	tree, err := commit.Tree()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var files []string
+	tree.Files().ForEach(func(f *object.File) error {
+		files = append(files, f.Name)
+		return nil
+	})
+	if len(files) != 1 || files[0] != "User/settings.json" {
+		t.Errorf("committed files = %v, want only User/settings.json", files)
+	}
+}
+
+func TestCheckoutBranchOnlyCreatesMissingBranches(t *testing.T) {
+	tests := []struct {
+		name          string
+		remoteMissing bool
+		wantErr       bool
+	}{
+		{name: "branch missing on remote", wantErr: false},
+		{name: "remote unreachable", remoteMissing: true, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			remoteDir := t.TempDir()
+			remote, err := git.PlainInit(remoteDir, false)
+			if err != nil {
+				t.Fatal(err)
+			}
+			commitFile(t, remote, remoteDir, "settings.json", "{}")
+
+			localDir := t.TempDir()
+			local, err := git.PlainClone(localDir, false, &git.CloneOptions{URL: remoteDir})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tt.remoteMissing {
+				if err := os.RemoveAll(remoteDir); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			r := &Repository{repo: local, localPath: localDir, remoteName: "origin", branch: "work"}
+			err = r.checkoutBranch()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("checkoutBranch() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			head, err := local.Head()
+			if err != nil {
+				t.Fatal(err)
+			}
+			want := "work"
+			if tt.wantErr {
+				want = "master"
+			}
+			if head.Name().Short() != want {
+				t.Errorf("HEAD = %s, want %s", head.Name().Short(), want)
+			}
+		})
+	}
+}
@@ -0,0 +1,69 @@
+package git
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"cursor-sync/internal/logger"
+)
+
+const (
+	gitIgnoreFile  = ".gitignore"
+	gitIgnoreBegin = "# BEGIN cursor-sync (generated from cursor.exclude_paths - edits here are overwritten)"
+	gitIgnoreEnd   = "# END cursor-sync"
+
+	// SyncMarkerFile marks a Cursor installation whose settings have been synced before
+	SyncMarkerFile = ".custom.sync"
+)
+
+// internalFilePatterns match the names of cursor-sync's local state files: the sync
+// marker and the temporary files of atomic copies. They are never committed
+var internalFilePatterns = []string{SyncMarkerFile, ".*.tmp-*"}
+
+// IsInternalFile reports whether a path is one of cursor-sync's local state files
+func IsInternalFile(name string) bool {
+	base := path.Base(strings.ReplaceAll(name, "\\", "/"))
+	for _, pattern := range internalFilePatterns {
+		if matched, _ := path.Match(pattern, base); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// WriteGitIgnore updates the generated block in the clone's .gitignore, keeping other
+// lines, so that staging the whole worktree never picks up files matching patterns
+// Files that are already committed stay tracked regardless of .gitignore
+func (r *Repository) WriteGitIgnore(patterns []string) error {
+	return r.writeManagedBlock(gitIgnoreFile, gitIgnoreBegin, gitIgnoreEnd, patterns)
+}
+
+// unstageInternalFiles drops cursor-sync's local state files from the index, whatever
+// staged them. One that was committed before is thereby deleted by the next commit
+func (r *Repository) unstageInternalFiles() error {
+	idx, err := r.repo.Storer.Index()
+	if err != nil {
+		return fmt.Errorf("failed to read index: %w", err)
+	}
+
+	var removed []string
+	entries := idx.Entries[:0]
+	for _, entry := range idx.Entries {
+		if IsInternalFile(entry.Name) {
+			removed = append(removed, entry.Name)
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	if len(removed) == 0 {
+		return nil
+	}
+
+	idx.Entries = entries
+	if err := r.repo.Storer.SetIndex(idx); err != nil {
+		return fmt.Errorf("failed to write index: %w", err)
+	}
+	logger.Warn("🛡️  Kept cursor-sync internal file(s) out of the repository: %s", strings.Join(removed, ", "))
+	return nil
+}
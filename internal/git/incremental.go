@@ -0,0 +1,405 @@
+package git
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/filemode"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/google/go-github/v56/github"
+
+	"cursor-sync/internal/logger"
+	"cursor-sync/internal/ratelimit"
+)
+
+// SetIncrementalPull enables pulling through the GitHub compare API when at most
+// maxFiles files changed since the last sync (0 = always use git pull)
+func (r *Repository) SetIncrementalPull(maxFiles int) {
+	r.incrementalMaxFiles = maxFiles
+}
+
+// treeFile is a blob entry of a flattened tree
+type treeFile struct {
+	hash plumbing.Hash
+	mode filemode.FileMode
+}
+
+// lastSyncedPath returns the file holding the last-synced remote commit per branch
+func (r *Repository) lastSyncedPath() string {
+	return filepath.Join(r.localPath, ".git", "cursor-sync", "last-synced.json")
+}
+
+// loadLastSynced returns the remote commit the branch was last synced with
+func (r *Repository) loadLastSynced() (plumbing.Hash, bool) {
+	data, err := os.ReadFile(r.lastSyncedPath())
+	if err != nil {
+		return plumbing.ZeroHash, false
+	}
+
+	var branches map[string]string
+	if err := json.Unmarshal(data, &branches); err != nil {
+		return plumbing.ZeroHash, false
+	}
+
+	sha, ok := branches[r.branch]
+	if !ok || !plumbing.IsHash(sha) {
+		return plumbing.ZeroHash, false
+	}
+	return plumbing.NewHash(sha), true
+}
+
+// saveLastSynced records the remote commit the branch is now in sync with
+func (r *Repository) saveLastSynced(hash plumbing.Hash) {
+	branches := make(map[string]string)
+	if data, err := os.ReadFile(r.lastSyncedPath()); err == nil {
+		json.Unmarshal(data, &branches)
+	}
+	branches[r.branch] = hash.String()
+
+	data, err := json.MarshalIndent(branches, "", "  ")
+	if err != nil {
+		return
+	}
+	if err := os.MkdirAll(filepath.Dir(r.lastSyncedPath()), 0755); err != nil {
+		logger.Debug("Failed to record last-synced commit: %v", err)
+		return
+	}
+	if err := os.WriteFile(r.lastSyncedPath(), data, 0644); err != nil {
+		logger.Debug("Failed to record last-synced commit: %v", err)
+	}
+}
+
+// recordSynced stores HEAD as the last-synced commit when it matches the remote branch
+func (r *Repository) recordSynced() {
+	if r.incrementalMaxFiles <= 0 || r.repo == nil {
+		return
+	}
+
+	head, err := r.repo.Head()
+	if err != nil {
+		return
+	}
+	remoteRef, err := r.repo.Reference(plumbing.NewRemoteReferenceName(r.remoteName, r.branch), true)
+	if err != nil || remoteRef.Hash() != head.Hash() {
+		return
+	}
+	r.saveLastSynced(head.Hash())
+}
+
+// pullIncremental brings the branch up to date through the GitHub API, downloading only
+// the blobs that changed since the last sync. Every tree and commit is rebuilt locally
+// and must hash to exactly what GitHub has; any error means the caller falls back to git pull
+func (r *Repository) pullIncremental() error {
+	if r.auth == nil || r.auth.GetClient() == nil || r.owner == "" {
+		return fmt.Errorf("GitHub API not available")
+	}
+
+	base, ok := r.loadLastSynced()
+	if !ok {
+		return fmt.Errorf("no last-synced commit recorded yet")
+	}
+
+	head, err := r.repo.Head()
+	if err != nil {
+		return fmt.Errorf("failed to get HEAD: %w", err)
+	}
+	if head.Hash() != base {
+		return fmt.Errorf("local branch has moved since the last sync")
+	}
+
+	ctx := r.baseContext()
+	client := r.auth.GetClient()
+
+	branch, _, err := client.Repositories.GetBranch(ctx, r.owner, r.repoName, r.branch, 3)
+	if err != nil {
+		return fmt.Errorf("failed to get remote branch: %w", ratelimit.FromGitHubError(err))
+	}
+	remoteSHA := branch.GetCommit().GetSHA()
+	if !plumbing.IsHash(remoteSHA) {
+		return fmt.Errorf("invalid remote branch head")
+	}
+	remoteHash := plumbing.NewHash(remoteSHA)
+
+	if remoteHash == base {
+		logger.Debug("Remote branch unchanged since last sync (%s)", base.String()[:8])
+		return nil
+	}
+
+	comparison, _, err := client.Repositories.CompareCommits(ctx, r.owner, r.repoName, base.String(), remoteSHA, nil)
+	if err != nil {
+		return fmt.Errorf("failed to compare commits: %w", ratelimit.FromGitHubError(err))
+	}
+	if comparison.GetStatus() != "ahead" {
+		return fmt.Errorf("remote branch is %s of the last sync, not ahead", comparison.GetStatus())
+	}
+	if len(comparison.Files) > r.incrementalMaxFiles || comparison.GetTotalCommits() > r.incrementalMaxFiles ||
+		len(comparison.Commits) != comparison.GetTotalCommits() {
+		return fmt.Errorf("%d files in %d commits changed - too large for an incremental pull", len(comparison.Files), comparison.GetTotalCommits())
+	}
+
+	worktree, err := r.repo.Worktree()
+	if err != nil {
+		return fmt.Errorf("failed to get worktree: %w", err)
+	}
+	status, err := worktree.Status()
+	if err != nil {
+		return fmt.Errorf("failed to get status: %w", err)
+	}
+	if !status.IsClean() {
+		return fmt.Errorf("worktree has uncommitted changes")
+	}
+
+	baseCommit, err := r.repo.CommitObject(base)
+	if err != nil {
+		return fmt.Errorf("failed to read last-synced commit: %w", err)
+	}
+	files, err := flattenTree(baseCommit)
+	if err != nil {
+		return err
+	}
+
+	// Rebuild every new commit in order on top of the last-synced one
+	fetched := 0
+	for _, listed := range comparison.Commits {
+		commit, _, err := client.Repositories.GetCommit(ctx, r.owner, r.repoName, listed.GetSHA(), nil)
+		if err != nil {
+			return fmt.Errorf("failed to get commit %s: %w", listed.GetSHA(), ratelimit.FromGitHubError(err))
+		}
+
+		if err := r.applyCommitFiles(ctx, client, files, commit.Files, &fetched); err != nil {
+			return err
+		}
+
+		treeHash, err := r.writeTree(files)
+		if err != nil {
+			return err
+		}
+		if treeHash.String() != commit.GetCommit().GetTree().GetSHA() {
+			return fmt.Errorf("rebuilt tree of %s doesn't match GitHub", listed.GetSHA())
+		}
+
+		if err := r.writeCommit(commit, treeHash); err != nil {
+			return err
+		}
+	}
+
+	// Fast-forward the branch and update the changed files in the worktree
+	if err := worktree.Reset(&git.ResetOptions{Mode: git.HardReset, Commit: remoteHash}); err != nil {
+		return fmt.Errorf("failed to update worktree: %w", err)
+	}
+	remoteRef := plumbing.NewHashReference(plumbing.NewRemoteReferenceName(r.remoteName, r.branch), remoteHash)
+	if err := r.repo.Storer.SetReference(remoteRef); err != nil {
+		return fmt.Errorf("failed to update remote branch: %w", err)
+	}
+	r.saveLastSynced(remoteHash)
+
+	logger.Info("Pulled %d commit(s) from remote via GitHub API (%d blob(s) downloaded)", len(comparison.Commits), fetched)
+	return nil
+}
+
+// flattenTree returns all blobs of a commit's tree by path
+func flattenTree(commit *object.Commit) (map[string]treeFile, error) {
+	tree, err := commit.Tree()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read tree: %w", err)
+	}
+
+	files := make(map[string]treeFile)
+	err = tree.Files().ForEach(func(f *object.File) error {
+		files[f.Name] = treeFile{hash: f.Hash, mode: f.Mode}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read tree: %w", err)
+	}
+	return files, nil
+}
+
+// applyCommitFiles applies a commit's file changes to a flattened tree, downloading new blobs
+func (r *Repository) applyCommitFiles(ctx context.Context, client *github.Client, files map[string]treeFile, changes []*github.CommitFile, fetched *int) error {
+	for _, change := range changes {
+		name := change.GetFilename()
+
+		switch change.GetStatus() {
+		case "removed":
+			delete(files, name)
+			continue
+		case "renamed":
+			delete(files, change.GetPreviousFilename())
+		}
+
+		hash := plumbing.NewHash(change.GetSHA())
+		if !r.hasObject(hash) {
+			data, _, err := client.Git.GetBlobRaw(ctx, r.owner, r.repoName, change.GetSHA())
+			if err != nil {
+				return fmt.Errorf("failed to download %s: %w", name, ratelimit.FromGitHubError(err))
+			}
+			if err := r.writeBlob(hash, data); err != nil {
+				return err
+			}
+			*fetched++
+		}
+
+		// The compare API doesn't report modes: keep the previous one (new files are regular)
+		mode := filemode.Regular
+		if existing, ok := files[name]; ok {
+			mode = existing.mode
+		}
+		files[name] = treeFile{hash: hash, mode: mode}
+	}
+	return nil
+}
+
+// hasObject reports whether an object is already in the local object store
+func (r *Repository) hasObject(hash plumbing.Hash) bool {
+	return r.repo.Storer.HasEncodedObject(hash) == nil
+}
+
+// writeBlob stores a downloaded blob and checks it hashes to what GitHub reported
+func (r *Repository) writeBlob(want plumbing.Hash, data []byte) error {
+	obj := r.repo.Storer.NewEncodedObject()
+	obj.SetType(plumbing.BlobObject)
+	writer, err := obj.Writer()
+	if err != nil {
+		return err
+	}
+	if _, err := writer.Write(data); err != nil {
+		writer.Close()
+		return err
+	}
+	if err := writer.Close(); err != nil {
+		return err
+	}
+
+	if obj.Hash() != want {
+		return fmt.Errorf("downloaded blob %s has unexpected content", want)
+	}
+	_, err = r.repo.Storer.SetEncodedObject(obj)
+	return err
+}
+
+// writeTree stores the trees of a flattened file list and returns the root tree hash
+func (r *Repository) writeTree(files map[string]treeFile) (plumbing.Hash, error) {
+	// Group entries by directory; directories are added to their parents bottom-up
+	dirs := map[string]map[string]treeFile{"": {}}
+	for name, file := range files {
+		dir, base := path.Split(name)
+		dir = strings.TrimSuffix(dir, "/")
+		for d := dir; ; d = strings.TrimSuffix(path.Dir(d), ".") {
+			if _, ok := dirs[d]; !ok {
+				dirs[d] = make(map[string]treeFile)
+			}
+			if d == "" {
+				break
+			}
+		}
+		dirs[dir][base] = file
+	}
+
+	// Deepest directories first so subtree hashes are known before their parents are written
+	names := make([]string, 0, len(dirs))
+	for dir := range dirs {
+		names = append(names, dir)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		return strings.Count(names[i], "/") > strings.Count(names[j], "/") ||
+			(strings.Count(names[i], "/") == strings.Count(names[j], "/") && len(names[i]) > len(names[j]))
+	})
+
+	var rootHash plumbing.Hash
+	for _, dir := range names {
+		entries := dirs[dir]
+		hash, err := r.writeTreeObject(entries)
+		if err != nil {
+			return plumbing.ZeroHash, err
+		}
+		if dir == "" {
+			rootHash = hash
+			continue
+		}
+		parent := strings.TrimSuffix(path.Dir(dir), ".")
+		dirs[parent][path.Base(dir)] = treeFile{hash: hash, mode: filemode.Dir}
+	}
+	return rootHash, nil
+}
+
+// writeTreeObject stores one tree with its entries in git order
+func (r *Repository) writeTreeObject(entries map[string]treeFile) (plumbing.Hash, error) {
+	tree := &object.Tree{}
+	for name, entry := range entries {
+		tree.Entries = append(tree.Entries, object.TreeEntry{Name: name, Mode: entry.mode, Hash: entry.hash})
+	}
+
+	// Git compares directory names as if they ended with a slash
+	sortKey := func(e object.TreeEntry) string {
+		if e.Mode == filemode.Dir {
+			return e.Name + "/"
+		}
+		return e.Name
+	}
+	sort.Slice(tree.Entries, func(i, j int) bool {
+		return sortKey(tree.Entries[i]) < sortKey(tree.Entries[j])
+	})
+
+	obj := r.repo.Storer.NewEncodedObject()
+	if err := tree.Encode(obj); err != nil {
+		return plumbing.ZeroHash, fmt.Errorf("failed to encode tree: %w", err)
+	}
+	return r.repo.Storer.SetEncodedObject(obj)
+}
+
+// writeCommit rebuilds a commit from its API description and stores it if it hashes
+// to the commit's SHA on GitHub
+func (r *Repository) writeCommit(commit *github.RepositoryCommit, treeHash plumbing.Hash) error {
+	data := commit.GetCommit()
+
+	var parents []plumbing.Hash
+	for _, parent := range commit.Parents {
+		hash := plumbing.NewHash(parent.GetSHA())
+		if !r.hasObject(hash) {
+			return fmt.Errorf("parent %s of %s is not available locally", parent.GetSHA(), commit.GetSHA())
+		}
+		parents = append(parents, hash)
+	}
+
+	rebuilt := &object.Commit{
+		Author: object.Signature{
+			Name:  data.GetAuthor().GetName(),
+			Email: data.GetAuthor().GetEmail(),
+			When:  data.GetAuthor().GetDate().Time.UTC(),
+		},
+		Committer: object.Signature{
+			Name:  data.GetCommitter().GetName(),
+			Email: data.GetCommitter().GetEmail(),
+			When:  data.GetCommitter().GetDate().Time.UTC(),
+		},
+		PGPSignature: data.GetVerification().GetSignature(),
+		TreeHash:     treeHash,
+		ParentHashes: parents,
+	}
+
+	// The API may drop the message's trailing newline
+	want := plumbing.NewHash(commit.GetSHA())
+	for _, message := range []string{data.GetMessage(), data.GetMessage() + "\n"} {
+		rebuilt.Message = message
+		obj := r.repo.Storer.NewEncodedObject()
+		if err := rebuilt.Encode(obj); err != nil {
+			return fmt.Errorf("failed to encode commit: %w", err)
+		}
+		if obj.Hash() == want {
+			_, err := r.repo.Storer.SetEncodedObject(obj)
+			return err
+		}
+	}
+
+	// Typically a commit made in a non-UTC timezone: the API only reports UTC times
+	return fmt.Errorf("rebuilt commit %s doesn't match GitHub", commit.GetSHA())
+}
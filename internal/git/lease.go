@@ -0,0 +1,273 @@
+package git
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+
+	"cursor-sync/internal/logger"
+)
+
+// leaseRefPrefix is the namespace of push leases, one ref per machine. The refs point at
+// empty commits whose message ends with the lease's expiry
+const leaseRefPrefix = "refs/cursor-sync-lock/"
+
+// leaseExpiresPrefix starts the line of a lease commit message holding its expiry
+const leaseExpiresPrefix = "Expires: "
+
+// leasePollInterval is how often a deferred push checks whether the other lease is gone
+const leasePollInterval = 2 * time.Second
+
+// pushLease is another machine's lease found on the remote
+type pushLease struct {
+	host    string
+	hash    plumbing.Hash
+	expires time.Time
+}
+
+// SetPushLease makes Push take an advisory lease named after host for the duration of
+// the push, and first wait (at most lease) while another machine holds a fresh one.
+// Leases only make simultaneous pushes less likely; conflicts are still resolved (0 = off)
+func (r *Repository) SetPushLease(host string, lease time.Duration) {
+	r.leaseHost = leaseName(host)
+	r.pushLease = lease
+}
+
+// leaseName turns a host name into a valid ref name component
+func leaseName(host string) string {
+	name := strings.Map(func(c rune) rune {
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9', c == '-', c == '_':
+			return c
+		}
+		return '-'
+	}, host)
+	if name == "" {
+		return "unknown"
+	}
+	return name
+}
+
+// leaseRef returns this machine's lease ref
+func (r *Repository) leaseRef() plumbing.ReferenceName {
+	return plumbing.ReferenceName(leaseRefPrefix + r.leaseHost)
+}
+
+// acquirePushLease waits while another machine holds a fresh lease, removes leases left
+// behind by machines that never released them, and publishes this machine's lease. It
+// reports whether the lease was published. Failures are logged: the push goes ahead anyway
+func (r *Repository) acquirePushLease() bool {
+	deadline := time.Now().Add(r.pushLease)
+	for {
+		leases, err := r.fetchLeases()
+		if err != nil {
+			logger.Warn("⚠️  Failed to check push leases, pushing without one: %v", err)
+			return false
+		}
+
+		now := time.Now()
+		var fresh, stale []pushLease
+		for _, lease := range leases {
+			if lease.expires.After(now) {
+				fresh = append(fresh, lease)
+			} else {
+				stale = append(stale, lease)
+			}
+		}
+		r.removeStaleLeases(stale)
+
+		if len(fresh) == 0 {
+			break
+		}
+		holder := fresh[len(fresh)-1]
+		wait := time.Until(deadline)
+		if wait <= 0 {
+			logger.Info("⏳ %s still holds a push lease - pushing anyway", holder.host)
+			break
+		}
+		logger.Info("⏳ %s is pushing (lease until %s) - deferring push", holder.host, holder.expires.Format("15:04:05"))
+		if wait > leasePollInterval {
+			wait = leasePollInterval
+		}
+		select {
+		case <-time.After(wait):
+		case <-r.baseContext().Done():
+			return false
+		}
+	}
+
+	if err := r.publishLease(time.Now()); err != nil {
+		logger.Warn("⚠️  Failed to take a push lease, pushing without one: %v", err)
+		return false
+	}
+	return true
+}
+
+// fetchLeases fetches the lease refs and returns the other machines' leases, sorted by
+// expiry. Local copies are dropped first so leases deleted on the remote don't linger
+func (r *Repository) fetchLeases() ([]pushLease, error) {
+	if err := r.forEachLeaseRef(func(ref *plumbing.Reference) error {
+		return r.repo.Storer.RemoveReference(ref.Name())
+	}); err != nil {
+		return nil, fmt.Errorf("failed to clear local leases: %w", err)
+	}
+
+	ctx, cancel := r.networkContext()
+	defer cancel()
+	err := r.repo.FetchContext(ctx, &git.FetchOptions{
+		RemoteName: r.remoteName,
+		RefSpecs:   []config.RefSpec{config.RefSpec("+" + leaseRefPrefix + "*:" + leaseRefPrefix + "*")},
+		Auth:       r.basicAuth(),
+	})
+	if err != nil && err != git.NoErrAlreadyUpToDate && !isMissingRemoteBranch(err) {
+		return nil, fmt.Errorf("failed to fetch leases: %w", classifyRemoteError(err, nil))
+	}
+
+	var leases []pushLease
+	err = r.forEachLeaseRef(func(ref *plumbing.Reference) error {
+		if ref.Name() == r.leaseRef() {
+			return nil
+		}
+		commit, err := r.repo.CommitObject(ref.Hash())
+		if err != nil {
+			return fmt.Errorf("failed to read lease %s: %w", ref.Name(), err)
+		}
+		leases = append(leases, pushLease{
+			host:    strings.TrimPrefix(ref.Name().String(), leaseRefPrefix),
+			hash:    ref.Hash(),
+			expires: r.leaseExpiry(commit),
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(leases, func(i, j int) bool { return leases[i].expires.Before(leases[j].expires) })
+	return leases, nil
+}
+
+// leaseExpiry returns when the lease in commit expires. Commit times have only second
+// precision, so the holder records the expiry of its lease period in the message; the
+// local lease period counts from the commit time when it's missing
+func (r *Repository) leaseExpiry(commit *object.Commit) time.Time {
+	for _, line := range strings.Split(commit.Message, "\n") {
+		if value, ok := strings.CutPrefix(line, leaseExpiresPrefix); ok {
+			if expires, err := time.Parse(time.RFC3339Nano, value); err == nil {
+				return expires
+			}
+		}
+	}
+	return commit.Committer.When.Add(r.pushLease)
+}
+
+// forEachLeaseRef calls fn for every local lease ref
+func (r *Repository) forEachLeaseRef(fn func(*plumbing.Reference) error) error {
+	refs, err := r.repo.References()
+	if err != nil {
+		return err
+	}
+	var leaseRefs []*plumbing.Reference
+	err = refs.ForEach(func(ref *plumbing.Reference) error {
+		if strings.HasPrefix(ref.Name().String(), leaseRefPrefix) {
+			leaseRefs = append(leaseRefs, ref)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	for _, ref := range leaseRefs {
+		if err := fn(ref); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// publishLease points this machine's lease ref at an empty commit made at now and pushes it
+func (r *Repository) publishLease(now time.Time) error {
+	treeHash, err := r.storeObject(&object.Tree{})
+	if err != nil {
+		return fmt.Errorf("failed to store lease tree: %w", err)
+	}
+	signature := object.Signature{Name: "cursor-sync", Email: "cursor-sync@localhost", When: now}
+	hash, err := r.storeObject(&object.Commit{
+		Author:    signature,
+		Committer: signature,
+		Message:   fmt.Sprintf("cursor-sync push lease for %s\n\n%s%s\n", r.leaseHost, leaseExpiresPrefix, now.Add(r.pushLease).Format(time.RFC3339Nano)),
+		TreeHash:  treeHash,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to store lease commit: %w", err)
+	}
+	if err := r.repo.Storer.SetReference(plumbing.NewHashReference(r.leaseRef(), hash)); err != nil {
+		return fmt.Errorf("failed to set lease ref: %w", err)
+	}
+	return r.pushLeaseRefs([]config.RefSpec{config.RefSpec("+" + r.leaseRef() + ":" + r.leaseRef())}, nil)
+}
+
+// releasePushLease deletes this machine's lease on the remote, unless another machine
+// has replaced it in the meantime
+func (r *Repository) releasePushLease() {
+	ref, err := r.repo.Reference(r.leaseRef(), false)
+	if err != nil {
+		return
+	}
+	if err := r.pushLeaseRefs(
+		[]config.RefSpec{config.RefSpec(":" + r.leaseRef())},
+		[]config.RefSpec{config.RefSpec(ref.Hash().String() + ":" + r.leaseRef().String())},
+	); err != nil {
+		logger.Debug("Failed to release push lease: %v", err)
+	}
+	if err := r.repo.Storer.RemoveReference(r.leaseRef()); err != nil {
+		logger.Debug("Failed to remove local push lease: %v", err)
+	}
+}
+
+// removeStaleLeases deletes expired leases of other machines, each only if it wasn't
+// renewed since it was fetched
+func (r *Repository) removeStaleLeases(stale []pushLease) {
+	for _, lease := range stale {
+		name := plumbing.ReferenceName(leaseRefPrefix + lease.host)
+		logger.Debug("Removing stale push lease of %s (expired %s)", lease.host, lease.expires.Format(time.RFC3339))
+		if err := r.pushLeaseRefs(
+			[]config.RefSpec{config.RefSpec(":" + name)},
+			[]config.RefSpec{config.RefSpec(lease.hash.String() + ":" + name.String())},
+		); err != nil {
+			logger.Debug("Failed to remove stale push lease of %s: %v", lease.host, err)
+		}
+	}
+}
+
+// pushLeaseRefs pushes lease ref updates, each only if the remote ref matches require
+func (r *Repository) pushLeaseRefs(refSpecs, require []config.RefSpec) error {
+	ctx, cancel := r.networkContext()
+	defer cancel()
+	err := r.repo.PushContext(ctx, &git.PushOptions{
+		RemoteName:        r.remoteName,
+		RefSpecs:          refSpecs,
+		RequireRemoteRefs: require,
+		Auth:              r.basicAuth(),
+	})
+	if err != nil && err != git.NoErrAlreadyUpToDate {
+		return classifyRemoteError(err, nil)
+	}
+	return nil
+}
+
+// storeObject writes a tree or commit to the object database and returns its hash
+func (r *Repository) storeObject(obj interface {
+	Encode(plumbing.EncodedObject) error
+}) (plumbing.Hash, error) {
+	encoded := r.repo.Storer.NewEncodedObject()
+	if err := obj.Encode(encoded); err != nil {
+		return plumbing.ZeroHash, err
+	}
+	return r.repo.Storer.SetEncodedObject(encoded)
+}
@@ -0,0 +1,75 @@
+package git
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPushLeaseDefersOtherMachines(t *testing.T) {
+	src := newOrigin(t)
+	first, second := cloneOrigin(t, src), cloneOrigin(t, src)
+	first.SetPushLease("first.local", time.Minute)
+	second.SetPushLease("second.local", 200*time.Millisecond)
+
+	if !first.acquirePushLease() {
+		t.Fatal("first machine couldn't take a lease")
+	}
+	leases, err := second.fetchLeases()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(leases) != 1 || leases[0].host != "first-local" {
+		t.Fatalf("leases = %+v, want the first machine's", leases)
+	}
+
+	// The second machine waits at most its own lease period for the first one
+	start := time.Now()
+	if !second.acquirePushLease() {
+		t.Fatal("second machine couldn't take a lease")
+	}
+	if waited := time.Since(start); waited < 200*time.Millisecond {
+		t.Errorf("second machine pushed after %v despite the first machine's lease", waited)
+	}
+	second.releasePushLease()
+
+	first.releasePushLease()
+	if leases, err := second.fetchLeases(); err != nil || len(leases) != 0 {
+		t.Errorf("leases after release = %+v, %v, want none", leases, err)
+	}
+}
+
+func TestStalePushLeaseIsRemoved(t *testing.T) {
+	src := newOrigin(t)
+	crashed, other := cloneOrigin(t, src), cloneOrigin(t, src)
+	crashed.SetPushLease("crashed", time.Minute)
+	other.SetPushLease("other", time.Minute)
+
+	if err := crashed.publishLease(time.Now().Add(-time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+
+	start := time.Now()
+	if !other.acquirePushLease() {
+		t.Fatal("couldn't take a lease")
+	}
+	if waited := time.Since(start); waited > 10*time.Second {
+		t.Errorf("waited %v for an expired lease", waited)
+	}
+	other.releasePushLease()
+
+	if leases, err := crashed.fetchLeases(); err != nil || len(leases) != 0 {
+		t.Errorf("leases = %+v, %v, want the stale one removed", leases, err)
+	}
+}
+
+func TestLeaseName(t *testing.T) {
+	for host, want := range map[string]string{
+		"MacBook-Pro.local": "MacBook-Pro-local",
+		"my host~1":         "my-host-1",
+		"":                  "unknown",
+	} {
+		if got := leaseName(host); got != want {
+			t.Errorf("leaseName(%q) = %q, want %q", host, got, want)
+		}
+	}
+}
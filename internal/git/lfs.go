@@ -0,0 +1,265 @@
+package git
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"cursor-sync/internal/logger"
+	"cursor-sync/internal/urlutil"
+)
+
+// ErrLFSNotInstalled is returned by EnableLFS when git or the git-lfs extension is missing
+var ErrLFSNotInstalled = errors.New("git-lfs is not installed")
+
+// ErrLFSObjectMissing is returned by ReadLFSObject when the object hasn't been downloaded
+var ErrLFSObjectMissing = errors.New("LFS object not downloaded")
+
+const (
+	// lfsPointerVersion is the first line of every Git LFS pointer file
+	lfsPointerVersion = "version https://git-lfs.github.com/spec/v1"
+	// lfsPointerMaxSize is the largest file that is treated as a pointer
+	lfsPointerMaxSize = 1024
+
+	gitAttributesFile  = ".gitattributes"
+	gitAttributesBegin = "# BEGIN cursor-sync LFS (generated from sync.lfs_patterns)"
+	gitAttributesEnd   = "# END cursor-sync LFS"
+)
+
+// LFSPointer is a parsed Git LFS pointer file
+type LFSPointer struct {
+	OID  string // SHA-256 of the content
+	Size int64
+}
+
+// EnableLFS stores files matching the gitattributes-style patterns as Git LFS objects
+// The clone's worktree then holds pointer files; the syncer converts between pointers and
+// content with LFSClean and ReadLFSObject, and Push/FetchLFSObjects transfer the objects
+// with the git-lfs binary. Patterns are written to .gitattributes so other git clients agree
+func (r *Repository) EnableLFS(patterns []string) error {
+	if _, err := exec.LookPath("git"); err != nil {
+		return fmt.Errorf("%w: git not found in PATH", ErrLFSNotInstalled)
+	}
+	if output, err := exec.Command("git", "lfs", "version").CombinedOutput(); err != nil {
+		return fmt.Errorf("%w: %s", ErrLFSNotInstalled, strings.TrimSpace(string(output)))
+	}
+
+	if err := r.writeGitAttributes(patterns); err != nil {
+		return err
+	}
+
+	r.lfsPatterns = patterns
+	logger.Debug("Git LFS enabled for: %s", strings.Join(patterns, ", "))
+	return nil
+}
+
+// LFSEnabled reports whether EnableLFS has been called
+func (r *Repository) LFSEnabled() bool {
+	return len(r.lfsPatterns) > 0
+}
+
+// LFSTracked reports whether a repository-relative path is stored in Git LFS
+// Like .gitattributes, patterns without a slash match the file name at any depth
+func (r *Repository) LFSTracked(name string) bool {
+	name = filepath.ToSlash(name)
+	for _, pattern := range r.lfsPatterns {
+		subject := name
+		if !strings.Contains(pattern, "/") {
+			subject = path.Base(name)
+		}
+		if matched, _ := path.Match(strings.TrimPrefix(pattern, "/"), subject); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// writeGitAttributes updates the generated LFS block in .gitattributes, keeping other lines
+func (r *Repository) writeGitAttributes(patterns []string) error {
+	var block []string
+	for _, pattern := range patterns {
+		block = append(block, pattern+" filter=lfs diff=lfs merge=lfs -text")
+	}
+	return r.writeManagedBlock(gitAttributesFile, gitAttributesBegin, gitAttributesEnd, block)
+}
+
+// writeManagedBlock replaces the lines between begin and end in a file at the clone's
+// root with block (appending it when missing), keeping everything else as it is
+func (r *Repository) writeManagedBlock(name, begin, end string, block []string) error {
+	filePath := filepath.Join(r.localPath, name)
+
+	existing, err := os.ReadFile(filePath)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to read %s: %w", name, err)
+	}
+
+	var lines []string
+	inBlock := false
+	for _, line := range strings.Split(strings.TrimRight(string(existing), "\n"), "\n") {
+		switch {
+		case line == begin:
+			inBlock = true
+		case line == end:
+			inBlock = false
+		case !inBlock && (line != "" || len(lines) > 0):
+			lines = append(lines, line)
+		}
+	}
+
+	lines = append(lines, begin)
+	lines = append(lines, block...)
+	lines = append(lines, end)
+
+	content := []byte(strings.Join(lines, "\n") + "\n")
+	if bytes.Equal(content, existing) {
+		return nil
+	}
+	if err := os.WriteFile(filePath, content, 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", name, err)
+	}
+	return nil
+}
+
+// ParseLFSPointer parses a Git LFS pointer file, reporting false for any other content
+func ParseLFSPointer(data []byte) (LFSPointer, bool) {
+	if len(data) > lfsPointerMaxSize || !bytes.HasPrefix(data, []byte(lfsPointerVersion+"\n")) {
+		return LFSPointer{}, false
+	}
+
+	var pointer LFSPointer
+	for _, line := range strings.Split(string(data), "\n") {
+		if oid, ok := strings.CutPrefix(line, "oid sha256:"); ok {
+			pointer.OID = oid
+		} else if size, ok := strings.CutPrefix(line, "size "); ok {
+			n, err := strconv.ParseInt(size, 10, 64)
+			if err != nil {
+				return LFSPointer{}, false
+			}
+			pointer.Size = n
+		}
+	}
+	if len(pointer.OID) != sha256.Size*2 {
+		return LFSPointer{}, false
+	}
+	return pointer, true
+}
+
+// Encode returns the pointer file content
+func (p LFSPointer) Encode() []byte {
+	return []byte(fmt.Sprintf("%s\noid sha256:%s\nsize %d\n", lfsPointerVersion, p.OID, p.Size))
+}
+
+// NewLFSPointer returns the pointer describing data
+func NewLFSPointer(data []byte) LFSPointer {
+	return LFSPointer{OID: fmt.Sprintf("%x", sha256.Sum256(data)), Size: int64(len(data))}
+}
+
+// lfsObjectPath returns where git-lfs keeps the object with the given OID
+func (r *Repository) lfsObjectPath(oid string) string {
+	return filepath.Join(r.localPath, ".git", "lfs", "objects", oid[0:2], oid[2:4], oid)
+}
+
+// LFSClean stores data as an LFS object (the equivalent of git-lfs' clean filter) and
+// returns the pointer to commit in its place
+func (r *Repository) LFSClean(data []byte) ([]byte, error) {
+	pointer := NewLFSPointer(data)
+	objectPath := r.lfsObjectPath(pointer.OID)
+
+	if _, err := os.Stat(objectPath); os.IsNotExist(err) {
+		if err := os.MkdirAll(filepath.Dir(objectPath), 0755); err != nil {
+			return nil, fmt.Errorf("failed to create LFS object directory: %w", err)
+		}
+		tmpPath := objectPath + ".tmp"
+		if err := os.WriteFile(tmpPath, data, 0644); err != nil {
+			return nil, fmt.Errorf("failed to write LFS object: %w", err)
+		}
+		if err := os.Rename(tmpPath, objectPath); err != nil {
+			os.Remove(tmpPath)
+			return nil, fmt.Errorf("failed to store LFS object: %w", err)
+		}
+	}
+
+	return pointer.Encode(), nil
+}
+
+// ReadLFSObject returns the content a pointer refers to (the equivalent of git-lfs' smudge
+// filter). Objects are downloaded by FetchLFSObjects; ErrLFSObjectMissing is returned otherwise
+func (r *Repository) ReadLFSObject(pointer LFSPointer) ([]byte, error) {
+	data, err := os.ReadFile(r.lfsObjectPath(pointer.OID))
+	if os.IsNotExist(err) {
+		return nil, fmt.Errorf("%w: %s", ErrLFSObjectMissing, pointer.OID)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read LFS object: %w", err)
+	}
+	if NewLFSPointer(data) != pointer {
+		return nil, fmt.Errorf("LFS object %s is corrupt", pointer.OID)
+	}
+	return data, nil
+}
+
+// FetchLFSObjects downloads the LFS objects referenced by the remote branch
+func (r *Repository) FetchLFSObjects() error {
+	if !r.LFSEnabled() {
+		return nil
+	}
+	return r.runLFS("fetch", r.remoteName, r.branch)
+}
+
+// pushLFSObjects uploads the LFS objects referenced by commits the remote doesn't have yet
+func (r *Repository) pushLFSObjects() error {
+	if !r.LFSEnabled() {
+		return nil
+	}
+	return r.runLFS("push", r.remoteName, r.branch)
+}
+
+// runLFS runs a git-lfs command in the clone, authenticating with the GitHub token
+// The token is passed through the environment (scoped to the repository host) rather than
+// the command line, so it never shows up in process listings
+func (r *Repository) runLFS(args ...string) error {
+	cmd := exec.CommandContext(r.baseContext(), "git", append([]string{"lfs"}, args...)...)
+	cmd.Dir = r.localPath
+	cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0")
+	if scope := lfsAuthScope(r.url); r.auth != nil && scope != "" {
+		credentials := base64.StdEncoding.EncodeToString([]byte("token:" + r.auth.GetToken()))
+		cmd.Env = append(cmd.Env,
+			"GIT_CONFIG_COUNT=1",
+			"GIT_CONFIG_KEY_0=http."+scope+".extraHeader",
+			"GIT_CONFIG_VALUE_0=Authorization: Basic "+credentials,
+		)
+	}
+
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("git lfs %s failed: %w: %s", args[0], err, strings.TrimSpace(string(output)))
+	}
+	logger.Debug("git lfs %s: %s", args[0], strings.TrimSpace(string(output)))
+	return nil
+}
+
+// lfsAuthScope returns the http.<url> prefix the token header is limited to: the web host
+// of the repository, which serves the LFS API below <repo>.git/info/lfs whatever form the
+// configured URL takes. Object downloads redirected elsewhere don't get the token
+func lfsAuthScope(repoURL string) string {
+	if repoURL == "" {
+		return ""
+	}
+	parsed, err := urlutil.ParseGitHubURL(repoURL)
+	if err != nil {
+		return ""
+	}
+	scheme := "https"
+	if parsed.Scheme == "http" {
+		scheme = "http"
+	}
+	return scheme + "://" + parsed.WebHost() + "/"
+}
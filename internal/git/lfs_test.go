@@ -0,0 +1,96 @@
+package git
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLFSCleanAndReadObject(t *testing.T) {
+	dir := t.TempDir()
+	r := &Repository{localPath: dir, lfsPatterns: []string{"*.vscdb", "/User/icons/*.png"}}
+
+	data := []byte("SQLite format 3\x00 binary state")
+	pointerData, err := r.LFSClean(data)
+	if err != nil {
+		t.Fatalf("LFSClean() error = %v", err)
+	}
+
+	pointer, ok := ParseLFSPointer(pointerData)
+	if !ok {
+		t.Fatalf("LFSClean() returned an unparseable pointer:\n%s", pointerData)
+	}
+	if pointer != NewLFSPointer(data) {
+		t.Errorf("pointer = %+v, want %+v", pointer, NewLFSPointer(data))
+	}
+
+	content, err := r.ReadLFSObject(pointer)
+	if err != nil {
+		t.Fatalf("ReadLFSObject() error = %v", err)
+	}
+	if string(content) != string(data) {
+		t.Errorf("ReadLFSObject() = %q, want %q", content, data)
+	}
+
+	if _, ok := ParseLFSPointer(data); ok {
+		t.Error("ParseLFSPointer() accepted file content")
+	}
+
+	for name, want := range map[string]bool{
+		"User/globalStorage/state.vscdb": true,
+		"User/icons/logo.png":            true,
+		"User/snippets/logo.png":         false,
+		"User/settings.json":             false,
+	} {
+		if got := r.LFSTracked(name); got != want {
+			t.Errorf("LFSTracked(%q) = %v, want %v", name, got, want)
+		}
+	}
+}
+
+func TestLFSAuthScope(t *testing.T) {
+	for repoURL, want := range map[string]string{
+		"https://github.com/owner/repo":           "https://github.com/",
+		"https://github.com/owner/repo.git":       "https://github.com/",
+		"git@github.com:owner/repo.git":           "https://github.com/",
+		"owner/repo":                              "https://github.com/",
+		"https://ghe.example.com:8443/owner/repo": "https://ghe.example.com:8443/",
+		"http://ghe.internal/owner/repo":          "http://ghe.internal/",
+		"":                                        "",
+	} {
+		if got := lfsAuthScope(repoURL); got != want {
+			t.Errorf("lfsAuthScope(%q) = %q, want %q", repoURL, got, want)
+		}
+	}
+}
+
+func TestWriteGitAttributesKeepsOtherLines(t *testing.T) {
+	dir := t.TempDir()
+	attributesPath := filepath.Join(dir, gitAttributesFile)
+	if err := os.WriteFile(attributesPath, []byte("*.json text eol=lf\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	r := &Repository{localPath: dir}
+	if err := r.writeGitAttributes([]string{"*.vscdb"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.writeGitAttributes([]string{"*.png"}); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(attributesPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := strings.Join([]string{
+		"*.json text eol=lf",
+		gitAttributesBegin,
+		"*.png filter=lfs diff=lfs merge=lfs -text",
+		gitAttributesEnd,
+	}, "\n") + "\n"
+	if string(data) != want {
+		t.Errorf(".gitattributes =\n%s\nwant\n%s", data, want)
+	}
+}
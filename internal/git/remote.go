@@ -0,0 +1,130 @@
+package git
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
+
+	"cursor-sync/internal/logger"
+)
+
+// RemoteURL returns the URL the clone's remote points at
+func (r *Repository) RemoteURL() (string, error) {
+	if r.repo == nil {
+		return "", fmt.Errorf("repository not initialized")
+	}
+
+	remote, err := r.repo.Remote(r.remoteName)
+	if err != nil {
+		return "", fmt.Errorf("failed to get remote %s: %w", r.remoteName, err)
+	}
+	if urls := remote.Config().URLs; len(urls) > 0 {
+		return urls[0], nil
+	}
+	return "", nil
+}
+
+// SetRemoteURL re-points the clone's remote at url, keeping its fetch refspecs
+func (r *Repository) SetRemoteURL(url string) error {
+	if r.repo == nil {
+		return fmt.Errorf("repository not initialized")
+	}
+
+	var fetch []config.RefSpec
+	if remote, err := r.repo.Remote(r.remoteName); err == nil {
+		fetch = remote.Config().Fetch
+		if err := r.repo.DeleteRemote(r.remoteName); err != nil {
+			return fmt.Errorf("failed to remove remote %s: %w", r.remoteName, err)
+		}
+	}
+
+	if _, err := r.repo.CreateRemote(&config.RemoteConfig{
+		Name:  r.remoteName,
+		URLs:  []string{url},
+		Fetch: fetch,
+	}); err != nil {
+		return fmt.Errorf("failed to create remote %s: %w", r.remoteName, err)
+	}
+	return nil
+}
+
+// reconcileRemoteURL re-points the remote at the configured URL when they differ
+func (r *Repository) reconcileRemoteURL() error {
+	if r.url == "" {
+		return nil
+	}
+
+	current, err := r.RemoteURL()
+	if errors.Is(err, git.ErrRemoteNotFound) {
+		logger.Warn("Remote %s is missing from the local clone - recreating it for %s", r.remoteName, r.url)
+		return r.SetRemoteURL(r.url)
+	}
+	if err != nil {
+		return err
+	}
+	if sameRemoteURL(current, r.url) {
+		return nil
+	}
+
+	logger.Info("🔀 Repository URL changed - updating remote %s from %s to %s", r.remoteName, current, r.url)
+	return r.SetRemoteURL(r.url)
+}
+
+// sameRemoteURL compares repository URLs ignoring a trailing slash or .git suffix
+func sameRemoteURL(a, b string) bool {
+	normalize := func(url string) string {
+		return strings.TrimSuffix(strings.TrimSuffix(url, "/"), ".git")
+	}
+	return normalize(a) == normalize(b)
+}
+
+// Fetch downloads the remote's refs and objects without touching the worktree
+func (r *Repository) Fetch() error {
+	if r.repo == nil {
+		return fmt.Errorf("repository not initialized")
+	}
+
+	ctx, cancel := r.networkContext()
+	err := r.repo.FetchContext(ctx, &git.FetchOptions{
+		RemoteName: r.remoteName,
+		Auth:       r.basicAuth(),
+		Depth:      r.depth,
+	})
+	cancel()
+	if err != nil && err != git.NoErrAlreadyUpToDate {
+		return fmt.Errorf("failed to fetch from %s: %w", r.remoteName, classifyRemoteError(err, nil))
+	}
+	return nil
+}
+
+// RemoteFile returns the content of a file (slash-separated, repository-relative) on the
+// remote-tracking branch as of the last fetch
+// A file missing from the branch is reported as object.ErrFileNotFound
+func (r *Repository) RemoteFile(path string) ([]byte, error) {
+	if r.repo == nil {
+		return nil, fmt.Errorf("repository not initialized")
+	}
+
+	ref, err := r.repo.Reference(plumbing.NewRemoteReferenceName(r.remoteName, r.branch), true)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve remote branch %s: %w", r.branch, err)
+	}
+	commit, err := r.repo.CommitObject(ref.Hash())
+	if err != nil {
+		return nil, fmt.Errorf("failed to read remote commit: %w", err)
+	}
+
+	file, err := commit.File(path)
+	if err != nil {
+		return nil, err
+	}
+	contents, err := file.Contents()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	return []byte(contents), nil
+}
@@ -0,0 +1,154 @@
+package git
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
+
+	"cursor-sync/internal/github"
+	"cursor-sync/internal/ratelimit"
+)
+
+func TestSetRemoteURLKeepsFetchRefSpecs(t *testing.T) {
+	repo, err := git.PlainInit(t.TempDir(), false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fetch := []config.RefSpec{"+refs/heads/main:refs/remotes/origin/main"}
+	if _, err := repo.CreateRemote(&config.RemoteConfig{
+		Name:  "origin",
+		URLs:  []string{"https://github.com/old/settings.git"},
+		Fetch: fetch,
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	r := &Repository{repo: repo, remoteName: "origin", branch: "main"}
+	if err := r.SetRemoteURL("https://github.com/new/settings.git"); err != nil {
+		t.Fatalf("SetRemoteURL() error = %v", err)
+	}
+
+	if url, err := r.RemoteURL(); err != nil || url != "https://github.com/new/settings.git" {
+		t.Errorf("RemoteURL() = %q, %v; want new URL", url, err)
+	}
+	remote, err := repo.Remote("origin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := remote.Config().Fetch; len(got) != 1 || got[0] != fetch[0] {
+		t.Errorf("fetch refspecs = %v, want %v", got, fetch)
+	}
+}
+
+func TestReconcileRemoteURL(t *testing.T) {
+	repo, err := git.PlainInit(t.TempDir(), false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := repo.CreateRemote(&config.RemoteConfig{
+		Name: "origin",
+		URLs: []string{"https://github.com/old/settings.git"},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Only the .git suffix differs: left alone
+	r := &Repository{repo: repo, remoteName: "origin", url: "https://github.com/old/settings"}
+	if err := r.reconcileRemoteURL(); err != nil {
+		t.Fatal(err)
+	}
+	if url, _ := r.RemoteURL(); url != "https://github.com/old/settings.git" {
+		t.Errorf("remote rewritten for equivalent URL: %s", url)
+	}
+
+	r.url = "https://github.com/new/settings"
+	if err := r.reconcileRemoteURL(); err != nil {
+		t.Fatalf("reconcileRemoteURL() error = %v", err)
+	}
+	if url, _ := r.RemoteURL(); url != "https://github.com/new/settings" {
+		t.Errorf("RemoteURL() = %s, want configured URL", url)
+	}
+}
+
+func TestReconcileRecreatesMissingRemote(t *testing.T) {
+	dir := t.TempDir()
+	repo, err := git.PlainInit(dir, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := &Repository{repo: repo, localPath: dir, remoteName: "origin", url: "https://github.com/u/settings"}
+	if err := r.reconcileRemoteURL(); err != nil {
+		t.Fatalf("reconcileRemoteURL() error = %v", err)
+	}
+	if url, err := r.RemoteURL(); err != nil || url != "https://github.com/u/settings" {
+		t.Errorf("RemoteURL() = %q, %v; want recreated remote", url, err)
+	}
+}
+
+func TestOpenReportsBrokenClone(t *testing.T) {
+	r := &Repository{localPath: t.TempDir(), remoteName: "origin", branch: "main"}
+	if err := r.Open(); !errors.Is(err, ErrBrokenClone) {
+		t.Errorf("Open() on non-repository error = %v, want ErrBrokenClone", err)
+	}
+}
+
+// fakeCreator stands in for the GitHub API when a missing repository is created
+type fakeCreator struct {
+	createErrs []error // Returned by successive CreateRepository calls
+	creates    int
+	waited     bool
+}
+
+func (f *fakeCreator) RepositoryExists(owner, repoName string) (bool, error) {
+	return false, nil
+}
+
+func (f *fakeCreator) CreateRepository(owner, repoName, description string, autoInit bool) (*github.RepositoryResponse, error) {
+	err := f.createErrs[f.creates]
+	f.creates++
+	if err != nil {
+		return nil, err
+	}
+	return &github.RepositoryResponse{FullName: owner + "/" + repoName, Private: true}, nil
+}
+
+func (f *fakeCreator) WaitForRepositoryReady(owner, repoName string, maxWait time.Duration) error {
+	f.waited = true
+	return nil
+}
+
+func TestCreateAndCloneRepositoryReportsCreationFailure(t *testing.T) {
+	forbidden := errors.New("GitHub API returned 403: Resource not accessible by personal access token")
+	tests := []struct {
+		name        string
+		createErrs  []error
+		wantCreates int
+	}{
+		{"forbidden", []error{forbidden}, 1},
+		{"rate limited, then forbidden", []error{&ratelimit.Error{Reset: time.Now()}, forbidden}, 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			creator := &fakeCreator{createErrs: tt.createErrs}
+			r := &Repository{
+				localPath:      t.TempDir(),
+				newRepoCreator: func() (repoCreator, error) { return creator, nil },
+			}
+
+			err := r.createAndCloneRepository("https://github.com/me/settings.git", nil)
+			if !errors.Is(err, forbidden) {
+				t.Fatalf("createAndCloneRepository() error = %v, want the creation error", err)
+			}
+			if creator.creates != tt.wantCreates {
+				t.Errorf("%d creation attempts, want %d", creator.creates, tt.wantCreates)
+			}
+			if creator.waited {
+				t.Error("waited for a repository that wasn't created")
+			}
+		})
+	}
+}
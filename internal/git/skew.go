@@ -0,0 +1,343 @@
+package git
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+
+	"cursor-sync/internal/logger"
+)
+
+// errOverlappingChanges is returned by mergeChanges when both sides changed the same file
+var errOverlappingChanges = errors.New("local and remote changed the same files")
+
+// ConflictPrompt describes a conflict the "newer" strategy couldn't decide by commit time
+type ConflictPrompt struct {
+	LocalCommitTime  time.Time
+	RemoteCommitTime time.Time
+	Files            []string // Changed on both sides since their common ancestor
+}
+
+// SetClockSkewTolerance sets how far apart local and remote commit times must be for the
+// "newer" strategy to trust them. Closer commit times (or times in the future) could be
+// ordered wrongly by a machine with a bad clock, so changes are merged instead
+func (r *Repository) SetClockSkewTolerance(tolerance time.Duration) {
+	r.clockSkewTolerance = tolerance
+}
+
+// MergeFunc combines the versions of a file that local and remote both changed since base
+// (nil when the file didn't exist there), or fails when the changes themselves collide
+type MergeFunc func(base, local, remote []byte) ([]byte, error)
+
+// SetFileMerger registers a function that returns how a file (by repository path) that both
+// sides changed is combined, or nil when one side's version has to win
+func (r *Repository) SetFileMerger(merger func(name string) MergeFunc) {
+	r.fileMerger = merger
+}
+
+// SetConflictPrompt registers a function that asks the user which side ("local" or "remote")
+// keeps files both sides changed when commit times can't be trusted. Without it the remote
+// side wins and the local versions are backed up
+func (r *Repository) SetConflictPrompt(prompt func(ConflictPrompt) string) {
+	r.conflictPrompt = prompt
+}
+
+// commitTimesTrusted reports whether the "newer" strategy may pick a side by commit time,
+// warning about clocks that are evidently wrong
+func (r *Repository) commitTimesTrusted(localTime, remoteTime, now time.Time) bool {
+	trusted := true
+
+	if ahead := localTime.Sub(now); ahead > r.clockSkewTolerance {
+		logger.Warn("🚨 The last local commit is %v in the future (%s) - this machine's clock is or was wrong",
+			ahead.Round(time.Second), localTime.Format(time.RFC3339))
+		logger.Warn("🚨 Fix the system clock: until then conflicts are merged instead of picking the newer side")
+		trusted = false
+	}
+	if ahead := remoteTime.Sub(now); ahead > r.clockSkewTolerance {
+		logger.Warn("🚨 The last remote commit is %v in the future (%s) - the clock of the machine that pushed it is wrong",
+			ahead.Round(time.Second), remoteTime.Format(time.RFC3339))
+		trusted = false
+	}
+
+	if diff := localTime.Sub(remoteTime).Abs(); diff <= r.clockSkewTolerance {
+		logger.Warn("⚠️  Local and remote commits are only %v apart (tolerance %v) - too close to tell which is newer",
+			diff.Round(time.Second), r.clockSkewTolerance)
+		trusted = false
+	}
+	return trusted
+}
+
+// resolveUntrustedTimes settles a conflict whose commit times can't be trusted and whose
+// changes mergeChanges couldn't merge: files changed on both sides are decided by the
+// conflict prompt, or by the remote side (backing up the local versions) when there is none
+func (r *Repository) resolveUntrustedTimes(localTime, remoteTime time.Time, overlap []string, err error) error {
+	if !errors.Is(err, errOverlappingChanges) {
+		logger.Warn("Failed to merge local and remote changes, keeping remote version: %v", err)
+		return r.pullWithRemoteStrategy()
+	}
+
+	logger.Warn("⚠️  Both sides changed %d file(s): %v", len(overlap), overlap)
+	if r.conflictPrompt != nil {
+		winner := r.conflictPrompt(ConflictPrompt{LocalCommitTime: localTime, RemoteCommitTime: remoteTime, Files: overlap})
+		if winner == "local" {
+			return r.pullWithLocalStrategy()
+		}
+	}
+	return r.pullWithRemoteStrategy()
+}
+
+// mergeChanges fetches the remote branch and combines it with the local branch when the two
+// changed different files since their common ancestor, committing a merge with both as
+// parents. Files changed on both sides are combined with the registered file merger where
+// possible; for the others errOverlappingChanges is returned together with those files,
+// and nothing is changed
+func (r *Repository) mergeChanges() ([]string, error) {
+	return r.mergeBranches(false)
+}
+
+// mergeBranches is mergeChanges; with keepLocal, files changed on both sides that can't be
+// combined take the local version instead of failing the merge
+func (r *Repository) mergeBranches(keepLocal bool) ([]string, error) {
+	worktree, err := r.repo.Worktree()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get worktree: %w", err)
+	}
+	if err := r.commitPendingChanges(worktree); err != nil {
+		return nil, fmt.Errorf("failed to preserve local changes: %w", err)
+	}
+
+	remoteHash, err := r.fetchRemoteBranch()
+	if err != nil {
+		return nil, err
+	}
+	head, err := r.repo.Head()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get HEAD: %w", err)
+	}
+
+	localCommit, err := r.repo.CommitObject(head.Hash())
+	if err != nil {
+		return nil, fmt.Errorf("failed to read local commit: %w", err)
+	}
+	remoteCommit, err := r.repo.CommitObject(remoteHash)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read remote commit: %w", err)
+	}
+
+	bases, err := localCommit.MergeBase(remoteCommit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find common ancestor: %w", err)
+	}
+	if len(bases) == 0 {
+		return nil, fmt.Errorf("local and remote branch have no common ancestor (shallow clone?)")
+	}
+	base := bases[0]
+
+	switch base.Hash {
+	case remoteHash: // Remote has nothing new
+		return nil, nil
+	case head.Hash(): // Nothing new locally
+		if err := worktree.Reset(&git.ResetOptions{Mode: git.HardReset, Commit: remoteHash}); err != nil {
+			return nil, fmt.Errorf("failed to fast-forward to remote: %w", err)
+		}
+		return nil, nil
+	}
+
+	baseFiles, err := flattenTree(base)
+	if err != nil {
+		return nil, err
+	}
+	localFiles, err := flattenTree(localCommit)
+	if err != nil {
+		return nil, err
+	}
+	merged, err := flattenTree(remoteCommit)
+	if err != nil {
+		return nil, err
+	}
+	remoteFiles := make(map[string]treeFile, len(merged))
+	for name, file := range merged {
+		remoteFiles[name] = file
+	}
+
+	var overlap, kept, localChanged, combined []string
+	for _, name := range changedFiles(baseFiles, localFiles) {
+		localFile, inLocal := localFiles[name]
+		remoteFile, inRemote := remoteFiles[name]
+		if inLocal == inRemote && localFile == remoteFile {
+			continue // Same change on both sides
+		}
+		if baseFile, inBase := baseFiles[name]; inBase != inRemote || baseFile != remoteFile {
+			file, ok := r.combineFile(name, baseFiles, localFiles, remoteFiles)
+			if ok {
+				merged[name] = file
+				combined = append(combined, name)
+				localChanged = append(localChanged, name)
+				continue
+			}
+			if !keepLocal {
+				overlap = append(overlap, name)
+				continue
+			}
+			kept = append(kept, name)
+		}
+		localChanged = append(localChanged, name)
+		if inLocal {
+			merged[name] = localFile
+		} else {
+			delete(merged, name)
+		}
+	}
+	if len(overlap) > 0 {
+		return overlap, errOverlappingChanges
+	}
+
+	treeHash, err := r.writeTree(merged)
+	if err != nil {
+		return nil, fmt.Errorf("failed to write merged tree: %w", err)
+	}
+	signature := r.signature(time.Now())
+	commit := &object.Commit{
+		Author:       signature,
+		Committer:    signature,
+		Message:      "Merge remote changes",
+		TreeHash:     treeHash,
+		ParentHashes: []plumbing.Hash{head.Hash(), remoteHash},
+	}
+	obj := r.repo.Storer.NewEncodedObject()
+	if err := commit.Encode(obj); err != nil {
+		return nil, fmt.Errorf("failed to encode merge commit: %w", err)
+	}
+	mergeHash, err := r.repo.Storer.SetEncodedObject(obj)
+	if err != nil {
+		return nil, fmt.Errorf("failed to store merge commit: %w", err)
+	}
+
+	// Moving HEAD's branch and resetting updates the files the remote changed
+	if err := worktree.Reset(&git.ResetOptions{Mode: git.HardReset, Commit: mergeHash}); err != nil {
+		return nil, fmt.Errorf("failed to check out merge: %w", err)
+	}
+
+	logger.Info("🔀 Merged remote changes with %d locally changed file(s)", len(localChanged))
+	if len(combined) > 0 {
+		logger.Info("🧩 Combined changes from both sides in: %v", combined)
+	}
+	if len(kept) > 0 {
+		logger.Info("📌 Kept the local version of files changed on both sides: %v", kept)
+		r.reportConflict("local", kept, "")
+		return nil, nil
+	}
+	r.reportConflict("merged", localChanged, "")
+	return nil, nil
+}
+
+// combineFile merges a file both sides changed with the registered file merger, storing
+// the result as a new blob. Returns false when the file can't be combined
+func (r *Repository) combineFile(name string, baseFiles, localFiles, remoteFiles map[string]treeFile) (treeFile, bool) {
+	if r.fileMerger == nil {
+		return treeFile{}, false
+	}
+	merge := r.fileMerger(name)
+	localFile, inLocal := localFiles[name]
+	remoteFile, inRemote := remoteFiles[name]
+	// A deletion on either side can't be combined with an edit
+	if merge == nil || !inLocal || !inRemote {
+		return treeFile{}, false
+	}
+
+	var base []byte
+	if baseFile, ok := baseFiles[name]; ok {
+		data, err := r.readBlob(baseFile.hash)
+		if err != nil {
+			logger.Warn("Failed to read common ancestor of %s: %v", name, err)
+			return treeFile{}, false
+		}
+		base = data
+	}
+	local, err := r.readBlob(localFile.hash)
+	if err != nil {
+		logger.Warn("Failed to read local %s: %v", name, err)
+		return treeFile{}, false
+	}
+	remote, err := r.readBlob(remoteFile.hash)
+	if err != nil {
+		logger.Warn("Failed to read remote %s: %v", name, err)
+		return treeFile{}, false
+	}
+
+	data, err := merge(base, local, remote)
+	if err != nil {
+		logger.Info("Can't combine both sides' changes to %s: %v", name, err)
+		return treeFile{}, false
+	}
+	hash, err := r.storeBlob(data)
+	if err != nil {
+		logger.Warn("Failed to store merged %s: %v", name, err)
+		return treeFile{}, false
+	}
+	return treeFile{hash: hash, mode: localFile.mode}, true
+}
+
+// readBlob returns the content of a blob object
+func (r *Repository) readBlob(hash plumbing.Hash) ([]byte, error) {
+	blob, err := r.repo.BlobObject(hash)
+	if err != nil {
+		return nil, err
+	}
+	reader, err := blob.Reader()
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+	return io.ReadAll(reader)
+}
+
+// fetchRemoteBranch fetches the remote branch and returns its head
+func (r *Repository) fetchRemoteBranch() (plumbing.Hash, error) {
+	branchRef := plumbing.NewBranchReferenceName(r.branch)
+	remoteRef := plumbing.NewRemoteReferenceName(r.remoteName, r.branch)
+
+	fetchOptions := &git.FetchOptions{
+		RemoteName: r.remoteName,
+		RefSpecs:   []config.RefSpec{config.RefSpec(fmt.Sprintf("+%s:%s", branchRef, remoteRef))},
+	}
+	if r.auth != nil {
+		fetchOptions.Auth = r.basicAuth()
+	}
+
+	ctx, cancel := r.networkContext()
+	defer cancel()
+	if err := r.repo.FetchContext(ctx, fetchOptions); err != nil && err != git.NoErrAlreadyUpToDate {
+		return plumbing.ZeroHash, fmt.Errorf("failed to fetch remote branch: %w", classifyRemoteError(err, nil))
+	}
+
+	ref, err := r.repo.Reference(remoteRef, true)
+	if err != nil {
+		return plumbing.ZeroHash, fmt.Errorf("failed to resolve remote branch %s: %w", r.branch, err)
+	}
+	return ref.Hash(), nil
+}
+
+// changedFiles returns the paths whose blob or mode differ between two flattened trees
+func changedFiles(from, to map[string]treeFile) []string {
+	var changed []string
+	for name, file := range from {
+		if other, ok := to[name]; !ok || other != file {
+			changed = append(changed, name)
+		}
+	}
+	for name := range to {
+		if _, ok := from[name]; !ok {
+			changed = append(changed, name)
+		}
+	}
+	sort.Strings(changed)
+	return changed
+}
@@ -0,0 +1,345 @@
+package git
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+
+	"cursor-sync/internal/jsonc"
+	"cursor-sync/internal/paths"
+)
+
+// commitFile writes a file in a worktree and commits it
+func commitFile(t *testing.T, repo *git.Repository, dir, name, content string) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	worktree, err := repo.Worktree()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := worktree.Add(name); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := worktree.Commit("update "+name, &git.CommitOptions{
+		Author: &object.Signature{Name: "test", Email: "test@local", When: time.Now()},
+	}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// divergedClone returns a clone of a fresh remote that holds a.json and b.json, after
+// the remote changed remoteFile and the clone changed localFile (without committing)
+func divergedClone(t *testing.T, remoteFile, localFile string) (*Repository, string) {
+	t.Helper()
+	remoteDir := t.TempDir()
+	remote, err := git.PlainInit(remoteDir, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	commitFile(t, remote, remoteDir, "a.json", "a")
+	commitFile(t, remote, remoteDir, "b.json", "b")
+
+	localDir := t.TempDir()
+	local, err := git.PlainClone(localDir, false, &git.CloneOptions{URL: remoteDir})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	commitFile(t, remote, remoteDir, remoteFile, "remote")
+	if err := os.WriteFile(filepath.Join(localDir, localFile), []byte("local"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	return &Repository{repo: local, localPath: localDir, remoteName: "origin", branch: "master"}, localDir
+}
+
+func TestMergeChangesCombinesDifferentFiles(t *testing.T) {
+	r, dir := divergedClone(t, "a.json", "b.json")
+
+	var event ConflictEvent
+	r.SetConflictHandler(func(e ConflictEvent) { event = e })
+
+	if overlap, err := r.mergeChanges(); err != nil {
+		t.Fatalf("mergeChanges() = %v, %v", overlap, err)
+	}
+
+	for name, want := range map[string]string{"a.json": "remote", "b.json": "local"} {
+		if data, _ := os.ReadFile(filepath.Join(dir, name)); string(data) != want {
+			t.Errorf("%s = %q, want %q", name, data, want)
+		}
+	}
+
+	head, err := r.repo.Head()
+	if err != nil {
+		t.Fatal(err)
+	}
+	commit, err := r.repo.CommitObject(head.Hash())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(commit.ParentHashes) != 2 {
+		t.Errorf("HEAD has %d parents, want a merge commit", len(commit.ParentHashes))
+	}
+	if event.Winner != "merged" || len(event.Files) != 1 || event.Files[0] != "b.json" {
+		t.Errorf("conflict event = %+v, want b.json merged", event)
+	}
+}
+
+func TestMergeChangesRefusesOverlappingChanges(t *testing.T) {
+	r, dir := divergedClone(t, "a.json", "a.json")
+
+	overlap, err := r.mergeChanges()
+	if !errors.Is(err, errOverlappingChanges) || len(overlap) != 1 || overlap[0] != "a.json" {
+		t.Fatalf("mergeChanges() = %v, %v, want a.json overlapping", overlap, err)
+	}
+	if data, _ := os.ReadFile(filepath.Join(dir, "a.json")); string(data) != "local" {
+		t.Errorf("a.json = %q, want the local version left alone", data)
+	}
+}
+
+func TestCommitTimesTrusted(t *testing.T) {
+	now := time.Now()
+	r := &Repository{clockSkewTolerance: 2 * time.Minute}
+
+	tests := []struct {
+		name          string
+		local, remote time.Time
+		want          bool
+	}{
+		{"far apart", now.Add(-time.Hour), now.Add(-10 * time.Minute), true},
+		{"within tolerance", now.Add(-time.Minute), now.Add(-2 * time.Minute), false},
+		{"local commit in the future", now.Add(time.Hour), now.Add(-time.Hour), false},
+		{"remote commit in the future", now.Add(-time.Hour), now.Add(time.Hour), false},
+	}
+	for _, tt := range tests {
+		if got := r.commitTimesTrusted(tt.local, tt.remote, now); got != tt.want {
+			t.Errorf("%s: commitTimesTrusted() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestMergeChangesCombinesKeybindings(t *testing.T) {
+	remoteDir := t.TempDir()
+	remote, err := git.PlainInit(remoteDir, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	commitFile(t, remote, remoteDir, "keybindings.json", `[
+    {"key": "cmd+shift+d", "command": "editor.action.copyLinesDownAction"}
+]`)
+
+	localDir := t.TempDir()
+	local, err := git.PlainClone(localDir, false, &git.CloneOptions{URL: remoteDir})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	commitFile(t, remote, remoteDir, "keybindings.json", `[
+    {"key": "cmd+shift+d", "command": "editor.action.copyLinesDownAction"},
+    {"key": "cmd+alt+v", "command": "markdown.showPreviewToSide", "when": "editorLangId == markdown"}
+]`)
+	if err := os.WriteFile(filepath.Join(localDir, "keybindings.json"), []byte(`[
+    {"key": "cmd+shift+d", "command": "editor.action.copyLinesDownAction"},
+    {"key": "ctrl+shift+t", "command": "workbench.action.terminal.new"}
+]`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	r := &Repository{repo: local, localPath: localDir, remoteName: "origin", branch: "master"}
+	if overlap, err := r.mergeChanges(); !errors.Is(err, errOverlappingChanges) || len(overlap) != 1 {
+		t.Fatalf("mergeChanges() without a file merger = %v, %v, want keybindings.json overlapping", overlap, err)
+	}
+
+	r.SetFileMerger(func(name string) MergeFunc {
+		if name == "keybindings.json" {
+			return jsonc.MergeKeybindings
+		}
+		return nil
+	})
+	if overlap, err := r.mergeChanges(); err != nil {
+		t.Fatalf("mergeChanges() = %v, %v", overlap, err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(localDir, "keybindings.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, command := range []string{"editor.action.copyLinesDownAction", "markdown.showPreviewToSide", "workbench.action.terminal.new"} {
+		if !strings.Contains(string(data), command) {
+			t.Errorf("merged keybindings.json lost %s:\n%s", command, data)
+		}
+	}
+}
+
+func TestLocallyChangedFilesIgnoresRemoteOnlyChanges(t *testing.T) {
+	r, dir := divergedClone(t, "a.json", "b.json")
+	// Undo the local edit: only the remote changed
+	if err := os.WriteFile(filepath.Join(dir, "b.json"), []byte("b"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.repo.Fetch(&git.FetchOptions{RemoteName: "origin"}); err != nil {
+		t.Fatal(err)
+	}
+
+	files, err := r.locallyChangedFiles()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 0 {
+		t.Errorf("locallyChangedFiles() = %v, want nothing for remote-only changes", files)
+	}
+
+	// A local commit is still reported, without the remote's change
+	commitFile(t, r.repo, dir, "b.json", "local")
+	if files, err = r.locallyChangedFiles(); err != nil || len(files) != 1 || files[0] != "b.json" {
+		t.Errorf("locallyChangedFiles() = %v, %v, want [b.json]", files, err)
+	}
+}
+
+func TestPullWithLocalStrategyMergesRemoteHistory(t *testing.T) {
+	// Both sides changed a.json: the local version wins, but the remote commit becomes a
+	// parent so the local branch can be pushed
+	r, dir := divergedClone(t, "a.json", "a.json")
+	var event ConflictEvent
+	r.SetConflictHandler(func(e ConflictEvent) { event = e })
+
+	if err := r.pullWithLocalStrategy(); err != nil {
+		t.Fatalf("pullWithLocalStrategy() error = %v", err)
+	}
+
+	if data, _ := os.ReadFile(filepath.Join(dir, "a.json")); string(data) != "local" {
+		t.Errorf("a.json = %q, want the local version", data)
+	}
+	head, err := r.repo.Head()
+	if err != nil {
+		t.Fatal(err)
+	}
+	commit, err := r.repo.CommitObject(head.Hash())
+	if err != nil {
+		t.Fatal(err)
+	}
+	remoteRef, err := r.repo.Reference(plumbing.NewRemoteReferenceName("origin", "master"), true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(commit.ParentHashes) != 2 || commit.ParentHashes[1] != remoteRef.Hash() {
+		t.Errorf("HEAD parents = %v, want the local commit and the remote head %s", commit.ParentHashes, remoteRef.Hash())
+	}
+	if event.Winner != "local" || len(event.Files) != 1 || event.Files[0] != "a.json" {
+		t.Errorf("conflict event = %+v, want a.json kept locally", event)
+	}
+}
+
+func TestPullWithLocalStrategyReportsMerge(t *testing.T) {
+	// The sides changed different files: both changes are kept and the merge is reported
+	r, dir := divergedClone(t, "a.json", "b.json")
+	var events []ConflictEvent
+	r.SetConflictHandler(func(e ConflictEvent) { events = append(events, e) })
+	r.beginConflictResolution("local")
+
+	if err := r.pullWithLocalStrategy(); err != nil {
+		t.Fatalf("pullWithLocalStrategy() error = %v", err)
+	}
+
+	for name, want := range map[string]string{"a.json": "remote", "b.json": "local"} {
+		if data, _ := os.ReadFile(filepath.Join(dir, name)); string(data) != want {
+			t.Errorf("%s = %q, want %q", name, data, want)
+		}
+	}
+	if len(events) != 1 || events[0].Winner != "merged" || events[0].Strategy != "local" ||
+		len(events[0].Files) != 1 || events[0].Files[0] != "b.json" {
+		t.Errorf("conflict events = %+v, want one local-strategy merge of b.json", events)
+	}
+}
+
+func TestPullWithRemoteStrategyResetsDivergedBranch(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	// The local side committed its own change to a.json: a force pull alone can't apply
+	r, dir := divergedClone(t, "a.json", "a.json")
+	commitFile(t, r.repo, dir, "a.json", "local")
+
+	if err := r.pullWithRemoteStrategy(); err != nil {
+		t.Fatalf("pullWithRemoteStrategy() error = %v", err)
+	}
+
+	if data, _ := os.ReadFile(filepath.Join(dir, "a.json")); string(data) != "remote" {
+		t.Errorf("a.json = %q, want the remote version", data)
+	}
+	head, err := r.repo.Head()
+	if err != nil {
+		t.Fatal(err)
+	}
+	remoteRef, err := r.repo.Reference(plumbing.NewRemoteReferenceName("origin", "master"), true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if head.Hash() != remoteRef.Hash() {
+		t.Errorf("HEAD = %s, want the remote head %s", head.Hash(), remoteRef.Hash())
+	}
+
+	// The discarded local commit is kept as a backup
+	stateDir, err := paths.StateDir()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if backups, _ := filepath.Glob(filepath.Join(stateDir, "backups", "*", "a.json")); len(backups) == 0 {
+		t.Error("locally committed a.json wasn't backed up")
+	}
+}
+
+func TestResolveByTimestampResetsToNewerRemote(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	// Push-conflict path: both sides committed a.json and the local commit, older than the
+	// remote one, is still unpushed
+	r, dir := divergedClone(t, "a.json", "a.json")
+	worktree, err := r.repo.Worktree()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := worktree.Add("a.json"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := worktree.Commit("sync", &git.CommitOptions{
+		Author: &object.Signature{Name: "test", Email: "test@local", When: time.Now().Add(-time.Hour)},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	var event ConflictEvent
+	r.SetConflictHandler(func(e ConflictEvent) { event = e })
+
+	if err := r.ResolveConflicts("newer"); err != nil {
+		t.Fatalf("ResolveConflicts() error = %v", err)
+	}
+
+	if data, _ := os.ReadFile(filepath.Join(dir, "a.json")); string(data) != "remote" {
+		t.Errorf("a.json = %q, want the newer remote version", data)
+	}
+	head, err := r.repo.Head()
+	if err != nil {
+		t.Fatal(err)
+	}
+	remoteRef, err := r.repo.Reference(plumbing.NewRemoteReferenceName("origin", "master"), true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if head.Hash() != remoteRef.Hash() {
+		t.Errorf("HEAD = %s, want the remote head %s", head.Hash(), remoteRef.Hash())
+	}
+	if event.Winner != "remote" || event.Backup == "" || len(event.Files) != 1 || event.Files[0] != "a.json" {
+		t.Errorf("conflict event = %+v, want the local a.json discarded and backed up", event)
+	}
+	if data, err := os.ReadFile(filepath.Join(event.Backup, "a.json")); err != nil || string(data) != "local" {
+		t.Errorf("backup of a.json = %q, %v; want the local version", data, err)
+	}
+}
@@ -0,0 +1,185 @@
+package git
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+
+	"cursor-sync/internal/logger"
+)
+
+// CanSquashHead reports whether HEAD is an unpushed commit whose message starts with
+// messagePrefix and that was first authored less than window ago, so the next commit
+// may be folded into it. Commits already on the remote are never eligible
+func (r *Repository) CanSquashHead(window time.Duration, messagePrefix string) (bool, error) {
+	if r.repo == nil {
+		return false, fmt.Errorf("repository not initialized")
+	}
+
+	head, err := r.repo.Head()
+	if err != nil {
+		return false, nil // No commits yet
+	}
+
+	commit, err := r.repo.CommitObject(head.Hash())
+	if err != nil {
+		return false, fmt.Errorf("failed to read HEAD commit: %w", err)
+	}
+
+	// A root commit has no parent to re-attach the squashed commit to
+	if commit.NumParents() == 0 || !strings.HasPrefix(commit.Message, messagePrefix) {
+		return false, nil
+	}
+
+	// The author time is kept when squashing, so the window is measured from the first commit
+	if time.Since(commit.Author.When) >= window {
+		return false, nil
+	}
+
+	pushed, err := r.isPushed(commit)
+	if err != nil {
+		return false, err
+	}
+	return !pushed, nil
+}
+
+// isPushed reports whether a commit is contained in the remote-tracking branch
+func (r *Repository) isPushed(commit *object.Commit) (bool, error) {
+	remoteRef, err := r.repo.Reference(plumbing.NewRemoteReferenceName(r.remoteName, r.branch), true)
+	if err == plumbing.ErrReferenceNotFound {
+		return false, nil // Branch never pushed
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to read remote branch: %w", err)
+	}
+
+	if remoteRef.Hash() == commit.Hash {
+		return true, nil
+	}
+
+	remoteCommit, err := r.repo.CommitObject(remoteRef.Hash())
+	if err != nil {
+		// Remote commit missing locally (e.g. shallow clone): assume pushed to stay safe
+		return true, nil
+	}
+	return commit.IsAncestor(remoteCommit)
+}
+
+// SquashCommit replaces HEAD with a commit of the staged changes and message, keeping
+// HEAD's parents and original author time. Callers must check CanSquashHead first
+func (r *Repository) SquashCommit(message, authorName, authorEmail string) error {
+	if r.repo == nil {
+		return fmt.Errorf("repository not initialized")
+	}
+
+	head, err := r.repo.Head()
+	if err != nil {
+		return fmt.Errorf("failed to get HEAD: %w", err)
+	}
+	previous, err := r.repo.CommitObject(head.Hash())
+	if err != nil {
+		return fmt.Errorf("failed to read HEAD commit: %w", err)
+	}
+	if previous.NumParents() == 0 {
+		return fmt.Errorf("cannot squash into the root commit")
+	}
+
+	worktree, err := r.repo.Worktree()
+	if err != nil {
+		return fmt.Errorf("failed to get worktree: %w", err)
+	}
+
+	// Committing on HEAD's parents drops HEAD from the branch; its changes are still in the index
+	commit, err := worktree.Commit(message, &git.CommitOptions{
+		Author: &object.Signature{
+			Name:  authorName,
+			Email: authorEmail,
+			When:  previous.Author.When,
+		},
+		Committer: &object.Signature{
+			Name:  authorName,
+			Email: authorEmail,
+			When:  time.Now().UTC(),
+		},
+		Parents: previous.ParentHashes,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to squash commit: %w", err)
+	}
+
+	logger.Debug("Squashed %s into commit: %s", previous.Hash.String()[:8], commit.String())
+	return nil
+}
+
+// HasUnpushedCommits reports whether HEAD has commits the remote branch doesn't contain
+func (r *Repository) HasUnpushedCommits() (bool, error) {
+	if r.repo == nil {
+		return false, fmt.Errorf("repository not initialized")
+	}
+
+	head, err := r.repo.Head()
+	if err != nil {
+		return false, nil // No commits yet
+	}
+	commit, err := r.repo.CommitObject(head.Hash())
+	if err != nil {
+		return false, fmt.Errorf("failed to read HEAD commit: %w", err)
+	}
+
+	pushed, err := r.isPushed(commit)
+	if err != nil {
+		return false, err
+	}
+	return !pushed, nil
+}
+
+// UnpushedCommitCount returns how many commits reachable from HEAD the remote branch
+// doesn't contain
+func (r *Repository) UnpushedCommitCount() (int, error) {
+	if r.repo == nil {
+		return 0, fmt.Errorf("repository not initialized")
+	}
+
+	head, err := r.repo.Head()
+	if err != nil {
+		return 0, nil // No commits yet
+	}
+	headCommit, err := r.repo.CommitObject(head.Hash())
+	if err != nil {
+		return 0, fmt.Errorf("failed to read HEAD commit: %w", err)
+	}
+
+	pushed := make(map[plumbing.Hash]bool)
+	remoteRef, err := r.repo.Reference(plumbing.NewRemoteReferenceName(r.remoteName, r.branch), true)
+	if err == nil {
+		remoteCommit, err := r.repo.CommitObject(remoteRef.Hash())
+		if err != nil {
+			return 0, nil // Remote commit missing locally (e.g. shallow clone): assume pushed like isPushed
+		}
+		err = object.NewCommitPreorderIter(remoteCommit, nil, nil).ForEach(func(c *object.Commit) error {
+			pushed[c.Hash] = true
+			return nil
+		})
+		if err != nil && !errors.Is(err, plumbing.ErrObjectNotFound) {
+			return 0, fmt.Errorf("failed to read remote history: %w", err)
+		}
+	} else if err != plumbing.ErrReferenceNotFound {
+		return 0, fmt.Errorf("failed to read remote branch: %w", err)
+	}
+
+	count := 0
+	err = object.NewCommitPreorderIter(headCommit, pushed, nil).ForEach(func(*object.Commit) error {
+		count++
+		return nil
+	})
+	// The walk fails at the missing parents of a shallow clone; those were pushed long ago
+	if err != nil && !errors.Is(err, plumbing.ErrObjectNotFound) {
+		return 0, fmt.Errorf("failed to read local history: %w", err)
+	}
+	return count, nil
+}
@@ -0,0 +1,97 @@
+package git
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+)
+
+func TestSquashCommitNeverRewritesPushedCommits(t *testing.T) {
+	dir := t.TempDir()
+	repo, err := git.PlainInit(dir, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := &Repository{repo: repo, localPath: dir, remoteName: "origin", branch: "master"}
+
+	write := func(content string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(dir, "settings.json"), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := r.Add("."); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	write(`{"a": 1}`)
+	if err := r.Commit("Initial commit", "test", "test@local"); err != nil {
+		t.Fatal(err)
+	}
+	root, _ := repo.Head()
+
+	write(`{"a": 2}`)
+	if err := r.Commit("Auto-sync from host at 1", "cursor-sync", "cursor-sync@local"); err != nil {
+		t.Fatal(err)
+	}
+
+	if ok, err := r.CanSquashHead(time.Hour, "Auto-sync from "); err != nil || !ok {
+		t.Fatalf("CanSquashHead() = %v, %v; want true for unpushed auto-sync commit", ok, err)
+	}
+
+	write(`{"a": 3}`)
+	if err := r.SquashCommit("Auto-sync from host at 2", "cursor-sync", "cursor-sync@local"); err != nil {
+		t.Fatalf("SquashCommit() error = %v", err)
+	}
+
+	head, _ := repo.Head()
+	commit, err := repo.CommitObject(head.Hash())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(commit.ParentHashes) != 1 || commit.ParentHashes[0] != root.Hash() {
+		t.Errorf("squashed commit parents = %v, want [%s]", commit.ParentHashes, root.Hash())
+	}
+	file, err := commit.File("settings.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if content, _ := file.Contents(); content != `{"a": 3}` {
+		t.Errorf("squashed settings.json = %s, want latest change", content)
+	}
+
+	// Once the remote branch has the commit it must not be squashed into
+	remoteRef := plumbing.NewHashReference(plumbing.NewRemoteReferenceName("origin", "master"), head.Hash())
+	if err := repo.Storer.SetReference(remoteRef); err != nil {
+		t.Fatal(err)
+	}
+	if ok, err := r.CanSquashHead(time.Hour, "Auto-sync from "); err != nil || ok {
+		t.Errorf("CanSquashHead() = %v, %v; want false for pushed commit", ok, err)
+	}
+}
+
+func TestUnpushedCommitCount(t *testing.T) {
+	r, dir := divergedClone(t, "a.json", "b.json")
+
+	if count, err := r.UnpushedCommitCount(); err != nil || count != 0 {
+		t.Fatalf("UnpushedCommitCount() = %d, %v before committing, want 0", count, err)
+	}
+
+	commitFile(t, r.repo, dir, "b.json", "local 1")
+	commitFile(t, r.repo, dir, "b.json", "local 2")
+	if count, err := r.UnpushedCommitCount(); err != nil || count != 2 {
+		t.Fatalf("UnpushedCommitCount() = %d, %v, want 2", count, err)
+	}
+
+	// Merging the remote branch adds a merge commit but doesn't count remote commits
+	if _, err := r.mergeChanges(); err != nil {
+		t.Fatal(err)
+	}
+	if count, err := r.UnpushedCommitCount(); err != nil || count != 3 {
+		t.Errorf("UnpushedCommitCount() = %d, %v after merging, want 3", count, err)
+	}
+}
@@ -0,0 +1,68 @@
+package git
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// RepositoryStats summarizes the local clone
+type RepositoryStats struct {
+	Commits      int   // Reachable from HEAD; only the fetched ones in a shallow clone
+	Shallow      bool  // Older history wasn't fetched
+	TrackedFiles int   // Files in the index
+	DiskSize     int64 // Everything below the clone, .git included
+}
+
+// Stats counts the commits and tracked files of the local clone and measures its size
+// The clone doesn't need to be opened first; it is only read
+func (r *Repository) Stats() (*RepositoryStats, error) {
+	repo := r.repo
+	if repo == nil {
+		var err error
+		if repo, err = git.PlainOpen(r.localPath); err != nil {
+			return nil, fmt.Errorf("failed to open repository: %w", err)
+		}
+	}
+
+	stats := &RepositoryStats{}
+
+	if head, err := repo.Head(); err == nil {
+		commits, err := repo.Log(&git.LogOptions{From: head.Hash()})
+		if err != nil {
+			return nil, fmt.Errorf("failed to read history: %w", err)
+		}
+		err = commits.ForEach(func(*object.Commit) error {
+			stats.Commits++
+			return nil
+		})
+		// The walk fails at the missing parents of a shallow clone; count what was fetched
+		if err != nil && !errors.Is(err, plumbing.ErrObjectNotFound) {
+			return nil, fmt.Errorf("failed to count commits: %w", err)
+		}
+	}
+
+	if shallow, err := repo.Storer.Shallow(); err == nil {
+		stats.Shallow = len(shallow) > 0
+	}
+
+	idx, err := repo.Storer.Index()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read index: %w", err)
+	}
+	stats.TrackedFiles = len(idx.Entries)
+
+	filepath.Walk(r.localPath, func(path string, info os.FileInfo, err error) error {
+		if err == nil && info.Mode().IsRegular() {
+			stats.DiskSize += info.Size()
+		}
+		return nil
+	})
+
+	return stats, nil
+}
@@ -0,0 +1,54 @@
+package git
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+func TestStatsCountsCommitsAndTrackedFiles(t *testing.T) {
+	dir := t.TempDir()
+	repo, err := git.PlainInit(dir, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	worktree, err := repo.Worktree()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i, name := range []string{"settings.json", "keybindings.json", "snippets/go.json"} {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte("{}"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := worktree.Add(name); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := worktree.Commit(name, &git.CommitOptions{
+			Author: &object.Signature{Name: "test", Email: "test@local", When: time.Now().Add(time.Duration(i) * time.Second)},
+		}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// The clone is read without being opened first
+	r := &Repository{localPath: dir}
+	stats, err := r.Stats()
+	if err != nil {
+		t.Fatalf("Stats() error = %v", err)
+	}
+	if stats.Commits != 3 || stats.TrackedFiles != 3 || stats.Shallow {
+		t.Errorf("Stats() = %+v, want 3 commits and 3 tracked files", stats)
+	}
+	if stats.DiskSize < 6 {
+		t.Errorf("DiskSize = %d, want at least the 6 bytes of the files", stats.DiskSize)
+	}
+}
@@ -0,0 +1,59 @@
+package git
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/go-git/go-git/v5/plumbing/transport/client"
+	githttp "github.com/go-git/go-git/v5/plumbing/transport/http"
+)
+
+// inactivityTimeoutKey carries network.timeout from networkContext to the HTTP transport
+type inactivityTimeoutKey struct{}
+
+// withInactivityTimeout makes connections dialed for ctx fail once they have been idle
+// for timeout
+func withInactivityTimeout(ctx context.Context, timeout time.Duration) context.Context {
+	return context.WithValue(ctx, inactivityTimeoutKey{}, timeout)
+}
+
+func init() {
+	// Bound connecting and each read or write rather than the whole operation, so cloning
+	// or pushing a large repository over a slow link isn't cut off while data still flows
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.DialContext = dialWithInactivityTimeout
+
+	httpClient := githttp.NewClient(&http.Client{Transport: transport})
+	client.InstallProtocol("https", httpClient)
+	client.InstallProtocol("http", httpClient)
+}
+
+// dialWithInactivityTimeout connects within the timeout set with withInactivityTimeout
+// and returns a connection enforcing it on every read and write
+func dialWithInactivityTimeout(ctx context.Context, network, address string) (net.Conn, error) {
+	timeout, ok := ctx.Value(inactivityTimeoutKey{}).(time.Duration)
+	dialer := &net.Dialer{Timeout: timeout, KeepAlive: 30 * time.Second}
+	conn, err := dialer.DialContext(ctx, network, address)
+	if err != nil || !ok || timeout <= 0 {
+		return conn, err
+	}
+	return &inactivityConn{Conn: conn, timeout: timeout}, nil
+}
+
+// inactivityConn fails a read or write that makes no progress for timeout
+type inactivityConn struct {
+	net.Conn
+	timeout time.Duration
+}
+
+func (c *inactivityConn) Read(b []byte) (int, error) {
+	c.Conn.SetReadDeadline(time.Now().Add(c.timeout))
+	return c.Conn.Read(b)
+}
+
+func (c *inactivityConn) Write(b []byte) (int, error) {
+	c.Conn.SetWriteDeadline(time.Now().Add(c.timeout))
+	return c.Conn.Write(b)
+}
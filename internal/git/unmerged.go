@@ -0,0 +1,185 @@
+package git
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/format/index"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// UnmergedFile is a file a merge left conflicted in the clone, with both sides' versions
+type UnmergedFile struct {
+	Path   string // Relative to the clone root, slash-separated
+	Local  []byte // nil when the local side deleted the file
+	Remote []byte // nil when the remote side deleted the file
+}
+
+// mergeStateFiles are written to .git by a merge that stopped on conflicts
+var mergeStateFiles = []string{"MERGE_HEAD", "MERGE_MSG", "MERGE_MODE"}
+
+// UnmergedFiles returns the files a merge left conflicted in the clone, e.g. after a
+// manual 'git merge'. go-git can't merge, so its Status never reports UpdatedButUnmerged;
+// the index stages are read directly instead
+func (r *Repository) UnmergedFiles() ([]UnmergedFile, error) {
+	if r.repo == nil {
+		return nil, fmt.Errorf("repository not initialized")
+	}
+
+	idx, err := r.repo.Storer.Index()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read index: %w", err)
+	}
+
+	files := make(map[string]*UnmergedFile)
+	for _, entry := range idx.Entries {
+		// Stage 0 is a merged entry (go-git's index.Merged constant says 1, but decodes 0)
+		if entry.Stage == 0 {
+			continue
+		}
+		file, ok := files[entry.Name]
+		if !ok {
+			file = &UnmergedFile{Path: entry.Name}
+			files[entry.Name] = file
+		}
+		if entry.Stage != index.OurMode && entry.Stage != index.TheirMode {
+			continue
+		}
+		content, err := r.readBlob(entry.Hash)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", entry.Name, err)
+		}
+		if entry.Stage == index.OurMode {
+			file.Local = content
+		} else {
+			file.Remote = content
+		}
+	}
+
+	unmerged := make([]UnmergedFile, 0, len(files))
+	for _, file := range files {
+		unmerged = append(unmerged, *file)
+	}
+	sort.Slice(unmerged, func(i, j int) bool { return unmerged[i].Path < unmerged[j].Path })
+	return unmerged, nil
+}
+
+// ResolveFile settles an unmerged file with content (nil deletes it) and stages the result
+func (r *Repository) ResolveFile(path string, content []byte) error {
+	if r.repo == nil {
+		return fmt.Errorf("repository not initialized")
+	}
+
+	fullPath := filepath.Join(r.localPath, filepath.FromSlash(path))
+	if content == nil {
+		if err := os.Remove(fullPath); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to delete %s: %w", path, err)
+		}
+	} else {
+		if err := os.MkdirAll(filepath.Dir(fullPath), 0755); err != nil {
+			return fmt.Errorf("failed to create directory for %s: %w", path, err)
+		}
+		if err := os.WriteFile(fullPath, content, 0644); err != nil {
+			return fmt.Errorf("failed to write %s: %w", path, err)
+		}
+	}
+
+	// Drop the conflict stages; the resolved file is staged as a regular entry below
+	idx, err := r.repo.Storer.Index()
+	if err != nil {
+		return fmt.Errorf("failed to read index: %w", err)
+	}
+	entries := idx.Entries[:0]
+	for _, entry := range idx.Entries {
+		if entry.Name != path {
+			entries = append(entries, entry)
+		}
+	}
+	idx.Entries = entries
+	if err := r.repo.Storer.SetIndex(idx); err != nil {
+		return fmt.Errorf("failed to update index: %w", err)
+	}
+
+	if content == nil {
+		return nil
+	}
+	worktree, err := r.repo.Worktree()
+	if err != nil {
+		return fmt.Errorf("failed to get worktree: %w", err)
+	}
+	if _, err := worktree.Add(path); err != nil {
+		return fmt.Errorf("failed to stage %s: %w", path, err)
+	}
+	return nil
+}
+
+// CompleteMerge commits the resolved files once no file is left unmerged. The commit the
+// interrupted merge recorded in MERGE_HEAD becomes the second parent, and its MERGE_MSG
+// the message
+func (r *Repository) CompleteMerge(authorName, authorEmail string) error {
+	unmerged, err := r.UnmergedFiles()
+	if err != nil {
+		return err
+	}
+	if len(unmerged) > 0 {
+		return fmt.Errorf("%d file(s) are still unmerged", len(unmerged))
+	}
+
+	head, err := r.repo.Head()
+	if err != nil {
+		return fmt.Errorf("failed to get HEAD: %w", err)
+	}
+	parents := []plumbing.Hash{head.Hash()}
+	message := "Resolve sync conflicts"
+
+	gitDir := filepath.Join(r.localPath, ".git")
+	if data, err := os.ReadFile(filepath.Join(gitDir, "MERGE_HEAD")); err == nil {
+		for _, line := range strings.Fields(string(data)) {
+			parents = append(parents, plumbing.NewHash(line))
+		}
+		if data, err := os.ReadFile(filepath.Join(gitDir, "MERGE_MSG")); err == nil {
+			if msg := stripCommentLines(string(data)); msg != "" {
+				message = msg
+			}
+		}
+	}
+
+	worktree, err := r.repo.Worktree()
+	if err != nil {
+		return fmt.Errorf("failed to get worktree: %w", err)
+	}
+	if _, err := worktree.Commit(message, &git.CommitOptions{
+		Author: &object.Signature{
+			Name:  authorName,
+			Email: authorEmail,
+			When:  time.Now().UTC(),
+		},
+		Parents: parents,
+	}); err != nil {
+		return fmt.Errorf("failed to commit resolution: %w", err)
+	}
+
+	for _, name := range mergeStateFiles {
+		if err := os.Remove(filepath.Join(gitDir, name)); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to clear merge state: %w", err)
+		}
+	}
+	return nil
+}
+
+// stripCommentLines drops git's "#" comment lines from a commit message
+func stripCommentLines(message string) string {
+	var lines []string
+	for _, line := range strings.Split(message, "\n") {
+		if !strings.HasPrefix(line, "#") {
+			lines = append(lines, line)
+		}
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
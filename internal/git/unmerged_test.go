@@ -0,0 +1,114 @@
+package git
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/format/index"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// conflictSettings leaves settings.json unmerged between "local" and "remote" the way
+// git merge does: conflict stages in the index and the other side in MERGE_HEAD
+func conflictSettings(t *testing.T, r *Repository) plumbing.Hash {
+	t.Helper()
+	head, err := r.repo.Head()
+	if err != nil {
+		t.Fatal(err)
+	}
+	headCommit, err := r.repo.CommitObject(head.Hash())
+	if err != nil {
+		t.Fatal(err)
+	}
+	mergeHead, err := r.storeObject(&object.Commit{
+		Author:       headCommit.Author,
+		Committer:    headCommit.Committer,
+		Message:      "remote change",
+		TreeHash:     headCommit.TreeHash,
+		ParentHashes: []plumbing.Hash{head.Hash()},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	idx, err := r.repo.Storer.Index()
+	if err != nil {
+		t.Fatal(err)
+	}
+	base := *idx.Entries[0]
+	idx.Entries = nil
+	for stage, content := range map[index.Stage]string{index.AncestorMode: "{}", index.OurMode: "local", index.TheirMode: "remote"} {
+		hash, err := r.storeBlob([]byte(content))
+		if err != nil {
+			t.Fatal(err)
+		}
+		entry := base
+		entry.Hash, entry.Stage = hash, stage
+		idx.Entries = append(idx.Entries, &entry)
+	}
+	if err := r.repo.Storer.SetIndex(idx); err != nil {
+		t.Fatal(err)
+	}
+
+	gitDir := filepath.Join(r.localPath, ".git")
+	if err := os.WriteFile(filepath.Join(gitDir, "MERGE_HEAD"), []byte(mergeHead.String()+"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(gitDir, "MERGE_MSG"), []byte("Merge remote settings\n# Conflicts:\n#\tsettings.json\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return mergeHead
+}
+
+func TestResolveUnmergedFiles(t *testing.T) {
+	r := newClonedRepository(t)
+	mergeHead := conflictSettings(t, r)
+
+	files, err := r.UnmergedFiles()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 1 || files[0].Path != "settings.json" || string(files[0].Local) != "local" || string(files[0].Remote) != "remote" {
+		t.Fatalf("UnmergedFiles() = %+v, want settings.json local/remote", files)
+	}
+	if err := r.CompleteMerge("test", "test@example.com"); err == nil {
+		t.Fatal("CompleteMerge() succeeded with an unmerged file")
+	}
+
+	if err := r.ResolveFile("settings.json", []byte("merged")); err != nil {
+		t.Fatal(err)
+	}
+	if files, err := r.UnmergedFiles(); err != nil || len(files) != 0 {
+		t.Fatalf("UnmergedFiles() after resolving = %+v, %v", files, err)
+	}
+	if err := r.CompleteMerge("test", "test@example.com"); err != nil {
+		t.Fatal(err)
+	}
+
+	head, err := r.repo.Head()
+	if err != nil {
+		t.Fatal(err)
+	}
+	commit, err := r.repo.CommitObject(head.Hash())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(commit.ParentHashes) != 2 || commit.ParentHashes[1] != mergeHead {
+		t.Errorf("merge commit parents = %v, want MERGE_HEAD %s second", commit.ParentHashes, mergeHead)
+	}
+	if commit.Message != "Merge remote settings" {
+		t.Errorf("merge commit message = %q, want MERGE_MSG without comments", commit.Message)
+	}
+	file, err := commit.File("settings.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if content, _ := file.Contents(); content != "merged" {
+		t.Errorf("committed settings.json = %q, want the resolution", content)
+	}
+	if _, err := os.Stat(filepath.Join(r.localPath, ".git", "MERGE_HEAD")); !os.IsNotExist(err) {
+		t.Errorf("MERGE_HEAD left behind: %v", err)
+	}
+}
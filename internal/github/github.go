@@ -5,16 +5,20 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"strings"
 	"time"
 
 	"cursor-sync/internal/auth"
 	"cursor-sync/internal/logger"
+	"cursor-sync/internal/ratelimit"
 )
 
 // GitHubAPI handles GitHub API operations
 type GitHubAPI struct {
-	token  string
-	client *http.Client
+	token   string
+	login   string // Account the token belongs to
+	client  *http.Client
+	baseURL string // REST API endpoint without a trailing slash
 }
 
 // RepositoryCreateRequest represents the request body for creating a repository
@@ -40,43 +44,42 @@ type RepositoryResponse struct {
 	UpdatedAt   string `json:"updated_at"`
 }
 
-// New creates a new GitHub API client
-func New() (*GitHubAPI, error) {
-	githubAuth, err := auth.NewGitHubAuth()
+// New creates a new GitHub API client for the API at endpoint
+func New(endpoint auth.Endpoint) (*GitHubAPI, error) {
+	githubAuth, err := auth.NewGitHubAuth(endpoint)
 	if err != nil {
 		return nil, fmt.Errorf("GitHub authentication failed: %w", err)
 	}
 
 	return &GitHubAPI{
 		token: githubAuth.GetToken(),
+		login: githubAuth.Login(),
 		client: &http.Client{
-			Timeout: 30 * time.Second,
+			Timeout: endpoint.NetworkTimeout(),
 		},
+		baseURL: endpoint.BaseURL(),
 	}, nil
 }
 
-// CreateRepository creates a new private repository on GitHub
-func (g *GitHubAPI) CreateRepository(owner, repoName, description string) (*RepositoryResponse, error) {
-	url := fmt.Sprintf("https://api.github.com/user/repos")
-
-	// If owner is specified and different from authenticated user, use org endpoint
-	if owner != "" {
-		// Check if it's an organization
-		if g.isOrganization(owner) {
-			url = fmt.Sprintf("https://api.github.com/orgs/%s/repos", owner)
-		} else {
-			// For user repositories, we'll use the user endpoint
-			// GitHub will create it under the authenticated user
-			url = "https://api.github.com/user/repos"
-		}
+// CreateRepository creates a new private repository on GitHub, seeded with a README
+// and .gitignore when autoInit is set and empty otherwise
+// The repository is created under owner or not at all: an owner that is neither the
+// token's account nor an organization is refused instead of falling back to the
+// token's account, where the configured URL would never find it
+func (g *GitHubAPI) CreateRepository(owner, repoName, description string, autoInit bool) (*RepositoryResponse, error) {
+	url, inOrg, err := g.createURL(owner)
+	if err != nil {
+		return nil, err
 	}
 
 	requestBody := RepositoryCreateRequest{
-		Name:              repoName,
-		Description:       description,
-		Private:           true,   // Always create as private for security
-		AutoInit:          true,   // Initialize with README
-		GitignoreTemplate: "Node", // Add .gitignore for Node.js projects
+		Name:        repoName,
+		Description: description,
+		Private:     true,     // Always create as private for security
+		AutoInit:    autoInit, // Initialize with README
+	}
+	if autoInit {
+		requestBody.GitignoreTemplate = "Node" // Add .gitignore for Node.js projects
 	}
 
 	jsonData, err := json.Marshal(requestBody)
@@ -104,14 +107,25 @@ func (g *GitHubAPI) CreateRepository(owner, repoName, description string) (*Repo
 		if err := json.NewDecoder(resp.Body).Decode(&repo); err != nil {
 			return nil, fmt.Errorf("failed to decode response: %w", err)
 		}
+		if owner != "" && !strings.EqualFold(repo.FullName, owner+"/"+repoName) {
+			return nil, fmt.Errorf("repository was created as %s instead of %s/%s - delete it and create %s/%s manually",
+				repo.FullName, owner, repoName, owner, repoName)
+		}
 		return &repo, nil
 	}
 
+	if rateErr := ratelimit.FromResponse(resp); rateErr != nil {
+		return nil, rateErr
+	}
+
 	// Handle different error cases
 	switch resp.StatusCode {
 	case http.StatusUnauthorized:
 		return nil, fmt.Errorf("GitHub token is invalid or expired")
 	case http.StatusForbidden:
+		if inOrg {
+			return nil, fmt.Errorf("insufficient permissions to create repositories in organization %s - ask an owner to create %s/%s or to allow members to create repositories", owner, owner, repoName)
+		}
 		return nil, fmt.Errorf("insufficient permissions to create repository")
 	case http.StatusUnprocessableEntity:
 		return nil, fmt.Errorf("repository name is invalid or already exists")
@@ -124,7 +138,7 @@ func (g *GitHubAPI) CreateRepository(owner, repoName, description string) (*Repo
 
 // RepositoryExists checks if a repository exists
 func (g *GitHubAPI) RepositoryExists(owner, repoName string) (bool, error) {
-	url := fmt.Sprintf("https://api.github.com/repos/%s/%s", owner, repoName)
+	url := fmt.Sprintf("%s/repos/%s/%s", g.baseURL, owner, repoName)
 
 	req, err := http.NewRequest("GET", url, nil)
 	if err != nil {
@@ -140,6 +154,10 @@ func (g *GitHubAPI) RepositoryExists(owner, repoName string) (bool, error) {
 	}
 	defer resp.Body.Close()
 
+	if rateErr := ratelimit.FromResponse(resp); rateErr != nil {
+		return false, rateErr
+	}
+
 	switch resp.StatusCode {
 	case http.StatusOK:
 		return true, nil
@@ -154,13 +172,31 @@ func (g *GitHubAPI) RepositoryExists(owner, repoName string) (bool, error) {
 	}
 }
 
+// createURL returns the endpoint that creates a repository under owner and whether
+// owner is an organization
+func (g *GitHubAPI) createURL(owner string) (string, bool, error) {
+	if owner == "" || strings.EqualFold(owner, g.login) {
+		return g.baseURL + "/user/repos", false, nil
+	}
+
+	isOrg, err := g.isOrganization(owner)
+	if err != nil {
+		return "", false, fmt.Errorf("failed to look up repository owner %s: %w", owner, err)
+	}
+	if !isOrg {
+		return "", false, fmt.Errorf("cannot create a repository under %s: the GitHub token belongs to %s and %s is not an organization - create the repository manually or use a token of the %s account",
+			owner, g.login, owner, owner)
+	}
+	return fmt.Sprintf("%s/orgs/%s/repos", g.baseURL, owner), true, nil
+}
+
 // isOrganization checks if the given name is an organization
-func (g *GitHubAPI) isOrganization(name string) bool {
-	url := fmt.Sprintf("https://api.github.com/orgs/%s", name)
+func (g *GitHubAPI) isOrganization(name string) (bool, error) {
+	url := fmt.Sprintf("%s/orgs/%s", g.baseURL, name)
 
 	req, err := http.NewRequest("GET", url, nil)
 	if err != nil {
-		return false
+		return false, fmt.Errorf("failed to create request: %w", err)
 	}
 
 	req.Header.Set("Authorization", "token "+g.token)
@@ -168,36 +204,66 @@ func (g *GitHubAPI) isOrganization(name string) bool {
 
 	resp, err := g.client.Do(req)
 	if err != nil {
-		return false
+		return false, fmt.Errorf("failed to make request: %w", err)
 	}
 	defer resp.Body.Close()
 
-	return resp.StatusCode == http.StatusOK
+	if rateErr := ratelimit.FromResponse(resp); rateErr != nil {
+		return false, rateErr
+	}
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return true, nil
+	case http.StatusNotFound:
+		return false, nil
+	case http.StatusUnauthorized:
+		return false, fmt.Errorf("GitHub token is invalid or expired")
+	default:
+		return false, fmt.Errorf("GitHub API error: %d", resp.StatusCode)
+	}
 }
 
+// Poll intervals of WaitForRepositoryReady: doubled after every check, with jitter
+const (
+	readyPollBase = time.Second
+	readyPollMax  = 8 * time.Second
+)
+
 // WaitForRepositoryReady waits for the repository to be ready after creation
-// GitHub sometimes takes a few seconds to fully initialize a new repository
+// GitHub sometimes takes a few seconds to fully initialize a new repository. Checks
+// back off exponentially, and a rate limit is waited out (honouring Retry-After) rather
+// than polled through, so slow provisioning doesn't trip the secondary rate limits
 func (g *GitHubAPI) WaitForRepositoryReady(owner, repoName string, maxWait time.Duration) error {
 	logger.Info("⏳ Waiting for repository to be ready...")
 
-	startTime := time.Now()
-	checkInterval := 2 * time.Second
+	deadline := time.Now().Add(maxWait)
 
-	for time.Since(startTime) < maxWait {
+	for attempt := 1; time.Now().Before(deadline); attempt++ {
 		exists, err := g.RepositoryExists(owner, repoName)
-		if err != nil {
-			logger.Debug("Repository check failed: %v", err)
-			time.Sleep(checkInterval)
+		if rateErr, ok := ratelimit.As(err); ok {
+			wait := rateErr.RetryWait()
+			if time.Now().Add(wait).After(deadline) {
+				return rateErr
+			}
+			logger.Info("⏳ GitHub API rate limit hit, waiting %v...", wait.Round(time.Second))
+			time.Sleep(wait)
 			continue
 		}
-
-		if exists {
+		if err != nil {
+			logger.Debug("Repository check failed: %v", err)
+		} else if exists {
 			logger.Info("✅ Repository is ready!")
 			return nil
+		} else {
+			logger.Debug("Repository not ready yet, waiting...")
 		}
 
-		logger.Debug("Repository not ready yet, waiting...")
-		time.Sleep(checkInterval)
+		delay := ratelimit.Backoff(attempt, readyPollBase, readyPollMax)
+		if remaining := time.Until(deadline); delay > remaining {
+			delay = remaining
+		}
+		time.Sleep(delay)
 	}
 
 	return fmt.Errorf("repository not ready after %v", maxWait)
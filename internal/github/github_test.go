@@ -0,0 +1,71 @@
+package github
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// newTestAPI points the API client at a fake GitHub serving the given organizations
+// and records the endpoints repositories are created through
+func newTestAPI(t *testing.T, orgs ...string) (*GitHubAPI, *[]string) {
+	var created []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodGet && strings.HasPrefix(r.URL.Path, "/orgs/"):
+			name := strings.TrimPrefix(r.URL.Path, "/orgs/")
+			for _, org := range orgs {
+				if org == name {
+					w.Write([]byte(`{}`))
+					return
+				}
+			}
+			w.WriteHeader(http.StatusNotFound)
+		case r.Method == http.MethodPost:
+			created = append(created, r.URL.Path)
+			owner := "me"
+			if strings.HasPrefix(r.URL.Path, "/orgs/") {
+				owner = strings.Split(r.URL.Path, "/")[2]
+			}
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{"full_name": "` + owner + `/settings"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	return &GitHubAPI{token: "test", login: "me", client: server.Client(), baseURL: server.URL}, &created
+}
+
+func TestCreateRepositoryUsesOwnerNamespace(t *testing.T) {
+	api, created := newTestAPI(t, "my-org")
+
+	if _, err := api.CreateRepository("me", "settings", "", true); err != nil {
+		t.Fatalf("CreateRepository(me) failed: %v", err)
+	}
+	if _, err := api.CreateRepository("my-org", "settings", "", true); err != nil {
+		t.Fatalf("CreateRepository(my-org) failed: %v", err)
+	}
+
+	want := []string{"/user/repos", "/orgs/my-org/repos"}
+	if strings.Join(*created, " ") != strings.Join(want, " ") {
+		t.Errorf("created through %v, want %v", *created, want)
+	}
+}
+
+func TestCreateRepositoryRefusesOtherAccounts(t *testing.T) {
+	api, created := newTestAPI(t)
+
+	_, err := api.CreateRepository("someone-else", "settings", "", true)
+	if err == nil {
+		t.Fatal("CreateRepository() created a repository for another user's namespace")
+	}
+	if !strings.Contains(err.Error(), "someone-else") {
+		t.Errorf("error doesn't name the owner: %v", err)
+	}
+	if len(*created) != 0 {
+		t.Errorf("repository created through %v", *created)
+	}
+}
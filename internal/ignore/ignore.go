@@ -0,0 +1,195 @@
+package ignore
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// FileName is the name of the ignore file looked up in the Cursor User directory
+const FileName = ".cursorsyncignore"
+
+// rule is a single compiled ignore pattern
+type rule struct {
+	pattern string
+	regex   *regexp.Regexp
+	negate  bool
+	dirOnly bool // Pattern ends with "/" and only matches directories
+}
+
+// Matcher matches paths against gitignore-style patterns
+type Matcher struct {
+	rules []rule
+}
+
+// Load reads gitignore-style patterns from the given file
+// A missing file is not an error and results in an empty matcher
+func Load(path string) (*Matcher, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return &Matcher{}, nil
+		}
+		return nil, fmt.Errorf("failed to open ignore file: %w", err)
+	}
+	defer file.Close()
+
+	var lines []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read ignore file: %w", err)
+	}
+
+	return Parse(lines), nil
+}
+
+// Parse compiles gitignore-style pattern lines into a matcher
+// Blank lines and lines starting with # are skipped
+func Parse(lines []string) *Matcher {
+	m := &Matcher{}
+
+	for _, line := range lines {
+		line = strings.TrimRight(line, " \t\r")
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		r := rule{pattern: line}
+		if strings.HasPrefix(line, "!") {
+			r.negate = true
+			line = line[1:]
+		} else if strings.HasPrefix(line, `\`) {
+			// Escaped leading "#" or "!"
+			line = line[1:]
+		}
+
+		r.dirOnly = strings.HasSuffix(line, "/")
+		line = strings.TrimSuffix(line, "/")
+
+		// A slash at the start or in the middle anchors the pattern to the base directory
+		anchored := strings.Contains(line, "/")
+		line = strings.TrimPrefix(line, "/")
+		if line == "" {
+			continue
+		}
+
+		expr := globToRegex(line)
+		if !anchored {
+			expr = "(.*/)?" + expr
+		}
+
+		// Paths below a matching directory are handled by MatchPattern
+		regex, err := regexp.Compile("^" + expr + "$")
+		if err != nil {
+			continue
+		}
+		r.regex = regex
+		m.rules = append(m.rules, r)
+	}
+
+	return m
+}
+
+// Match reports whether the slash-separated file path (relative to the ignore file's
+// directory) is ignored. Later patterns override earlier ones, so "!" can re-include a
+// path - but, as in git, not one below an ignored directory.
+func (m *Matcher) Match(relPath string) bool {
+	ignored, _ := m.MatchPattern(relPath)
+	return ignored
+}
+
+// MatchPattern is Match that also returns the pattern deciding the result
+// ("" when no pattern matches the path)
+func (m *Matcher) MatchPattern(relPath string) (bool, string) {
+	if m == nil {
+		return false, ""
+	}
+
+	relPath = strings.TrimPrefix(filepath.ToSlash(relPath), "./")
+
+	// An ignored directory excludes everything below it
+	for i, c := range relPath {
+		if c != '/' {
+			continue
+		}
+		if ignored, pattern := m.matchRules(relPath[:i], true); ignored {
+			return true, pattern
+		}
+	}
+	return m.matchRules(relPath, false)
+}
+
+// matchRules applies the rules to a single path; the last matching rule decides
+func (m *Matcher) matchRules(path string, isDir bool) (bool, string) {
+	ignored, pattern := false, ""
+	for _, r := range m.rules {
+		if r.dirOnly && !isDir {
+			continue
+		}
+		if r.regex.MatchString(path) {
+			ignored, pattern = !r.negate, r.pattern
+		}
+	}
+	return ignored, pattern
+}
+
+// Patterns returns the raw pattern lines of the matcher
+func (m *Matcher) Patterns() []string {
+	if m == nil {
+		return nil
+	}
+
+	patterns := make([]string, 0, len(m.rules))
+	for _, r := range m.rules {
+		patterns = append(patterns, r.pattern)
+	}
+	return patterns
+}
+
+// globToRegex converts a gitignore glob into a regular expression fragment
+func globToRegex(glob string) string {
+	var b strings.Builder
+
+	for i := 0; i < len(glob); i++ {
+		c := glob[i]
+		switch c {
+		case '*':
+			if i+1 < len(glob) && glob[i+1] == '*' {
+				// "**/" matches zero or more directories, a trailing "**" matches everything
+				if i+2 < len(glob) && glob[i+2] == '/' {
+					b.WriteString("(.*/)?")
+					i += 2
+				} else {
+					b.WriteString(".*")
+					i++
+				}
+			} else {
+				b.WriteString("[^/]*")
+			}
+		case '?':
+			b.WriteString("[^/]")
+		case '[':
+			end := strings.IndexByte(glob[i+1:], ']')
+			if end < 0 {
+				b.WriteString(regexp.QuoteMeta(string(c)))
+				continue
+			}
+			class := glob[i+1 : i+1+end]
+			if strings.HasPrefix(class, "!") {
+				class = "^" + class[1:]
+			}
+			b.WriteString("[" + class + "]")
+			i += end + 1
+		default:
+			b.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+
+	return b.String()
+}
@@ -0,0 +1,48 @@
+package ignore
+
+import "testing"
+
+func TestMatchPattern(t *testing.T) {
+	tests := []struct {
+		name        string
+		patterns    []string
+		path        string
+		wantIgnored bool
+		wantPattern string
+	}{
+		{"no patterns", nil, "settings.json", false, ""},
+		{"unanchored name at top level", []string{"*.log"}, "main.log", true, "*.log"},
+		{"unanchored name in subdirectory", []string{"*.log"}, "logs/main.log", true, "*.log"},
+		{"star stays within a directory", []string{"snippets/*.json"}, "snippets/a/go.json", false, ""},
+		{"leading slash anchors", []string{"/settings.json"}, "profiles/settings.json", false, ""},
+		{"leading slash matches top level", []string{"/settings.json"}, "settings.json", true, "/settings.json"},
+		{"middle slash anchors", []string{"snippets/go.json"}, "old/snippets/go.json", false, ""},
+		{"leading double star", []string{"**/cache.json"}, "a/b/cache.json", true, "**/cache.json"},
+		{"leading double star at top level", []string{"**/cache.json"}, "cache.json", true, "**/cache.json"},
+		{"middle double star", []string{"a/**/b.json"}, "a/x/y/b.json", true, "a/**/b.json"},
+		{"middle double star without directories", []string{"a/**/b.json"}, "a/b.json", true, "a/**/b.json"},
+		{"trailing double star", []string{"history/**"}, "history/x/entries.json", true, "history/**"},
+		{"file pattern matches directory contents", []string{"History"}, "History/a/entries.json", true, "History"},
+		{"directory pattern matches contents", []string{"logs/"}, "logs/main.log", true, "logs/"},
+		{"directory pattern skips files of that name", []string{"logs/"}, "logs", false, ""},
+		{"directory pattern unanchored", []string{"logs/"}, "a/logs/main.log", true, "logs/"},
+		{"negation re-includes a file", []string{"*.json", "!settings.json"}, "settings.json", false, "!settings.json"},
+		{"later pattern wins", []string{"!settings.json", "*.json"}, "settings.json", true, "*.json"},
+		{"negation below ignored directory", []string{"logs/", "!logs/keep.log"}, "logs/keep.log", true, "logs/"},
+		{"negation below ignored directory contents", []string{"logs/*", "!logs/keep.log"}, "logs/keep.log", false, "!logs/keep.log"},
+		{"negated directory re-included", []string{"snippets/*", "!snippets/go/"}, "snippets/go/a.json", false, ""},
+		{"escaped hash", []string{`\#notes`}, "#notes", true, `\#notes`},
+		{"comment skipped", []string{"# settings.json"}, "settings.json", false, ""},
+		{"question mark", []string{"file?.json"}, "file1.json", true, "file?.json"},
+		{"character class", []string{"file[0-9].json"}, "filea.json", false, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ignored, pattern := Parse(tt.patterns).MatchPattern(tt.path)
+			if ignored != tt.wantIgnored || pattern != tt.wantPattern {
+				t.Errorf("MatchPattern(%q) = %v, %q, want %v, %q", tt.path, ignored, pattern, tt.wantIgnored, tt.wantPattern)
+			}
+		})
+	}
+}
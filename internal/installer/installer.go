@@ -10,6 +10,7 @@ import (
 	"cursor-sync/internal/auth"
 	"cursor-sync/internal/config"
 	"cursor-sync/internal/logger"
+	"cursor-sync/internal/paths"
 	"cursor-sync/internal/privacy"
 )
 
@@ -27,6 +28,26 @@ func New(repoURL string, force bool) *Installer {
 	}
 }
 
+// launchAgentPath returns where the daemon's LaunchAgent plist is installed
+func launchAgentPath(home string) string {
+	return filepath.Join(home, "Library", "LaunchAgents", "com.user.cursorsync.plist")
+}
+
+// IsInstalled reports whether the daemon's LaunchAgent has been installed
+func IsInstalled() (bool, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return false, fmt.Errorf("failed to get home directory: %w", err)
+	}
+	if _, err := os.Stat(launchAgentPath(home)); err != nil {
+		if os.IsNotExist(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
 // Install performs the full installation process
 func (i *Installer) Install() error {
 	logger.Info("Starting cursor-sync installation...")
@@ -45,16 +66,19 @@ func (i *Installer) Install() error {
 		return fmt.Errorf("failed to get home directory: %w", err)
 	}
 
+	configDir, err := paths.ConfigDir()
+	if err != nil {
+		return err
+	}
+
 	// Check if already installed
 	if !i.force {
-		configDir := filepath.Join(home, ".cursor-sync")
 		if _, err := os.Stat(configDir); err == nil {
 			return fmt.Errorf("cursor-sync is already installed. Use --force to reinstall")
 		}
 	}
 
 	// Create configuration directory
-	configDir := filepath.Join(home, ".cursor-sync")
 	if err := os.MkdirAll(configDir, 0755); err != nil {
 		return fmt.Errorf("failed to create config directory: %w", err)
 	}
@@ -178,7 +202,11 @@ func (i *Installer) createLaunchAgent(home string) error {
 	}
 
 	binaryPath := filepath.Join(wd, "bin", "cursor-sync")
-	logPath := filepath.Join(home, ".cursor-sync", "logs", "daemon.log")
+	stateDir, err := paths.StateDir()
+	if err != nil {
+		return err
+	}
+	logPath := filepath.Join(stateDir, "logs", "daemon.log")
 
 	plistContent := fmt.Sprintf(`<?xml version="1.0" encoding="UTF-8"?>
 <!DOCTYPE plist PUBLIC "-//Apple//DTD PLIST 1.0//EN" "http://www.apple.com/DTDs/PropertyList-1.0.dtd">
@@ -218,7 +246,7 @@ func (i *Installer) createLaunchAgent(home string) error {
 	}
 
 	// Write plist file
-	plistPath := filepath.Join(launchAgentsDir, "com.user.cursorsync.plist")
+	plistPath := launchAgentPath(home)
 	if err := os.WriteFile(plistPath, []byte(plistContent), 0644); err != nil {
 		return fmt.Errorf("failed to write plist file: %w", err)
 	}
@@ -230,7 +258,7 @@ func (i *Installer) createLaunchAgent(home string) error {
 func (i *Installer) loadLaunchAgent(home string) error {
 	logger.Info("Loading LaunchAgent...")
 
-	plistPath := filepath.Join(home, "Library", "LaunchAgents", "com.user.cursorsync.plist")
+	plistPath := launchAgentPath(home)
 
 	// Unload first in case it's already loaded
 	exec.Command("launchctl", "unload", plistPath).Run()
@@ -261,7 +289,7 @@ func (i *Installer) checkRepositoryPrivacy() error {
 
 	logger.Info("Verifying repository privacy for: %s", repoURL)
 
-	checker := privacy.NewRepositoryChecker()
+	checker := privacy.NewRepositoryChecker(cfg.GitHubEndpoint())
 	isPrivate, err := checker.CheckRepositoryPrivacy(repoURL)
 
 	if err != nil {
@@ -0,0 +1,212 @@
+package interactive
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// largeDirThreshold is the size above which a User subdirectory is offered for exclusion
+const largeDirThreshold = 5 * 1024 * 1024
+
+// knownNoisyDirs are User subdirectories that are large, machine-specific or change constantly
+var knownNoisyDirs = map[string]string{
+	"workspaceStorage": "per-workspace state, machine-specific",
+	"History":          "local edit history, changes constantly",
+	"globalStorage":    "extension databases (state.vscdb), changes constantly",
+	"CachedData":       "cache",
+	"logs":             "log files",
+}
+
+// excludeCandidate is a User subdirectory offered in the exclude-path picker
+type excludeCandidate struct {
+	name     string
+	size     int64
+	reason   string
+	selected bool
+}
+
+// setupExcludePaths lets the user pick which User subdirectories to exclude from sync
+func (s *SetupWizard) setupExcludePaths() error {
+	fmt.Println("🧹 Exclude Paths Configuration")
+	fmt.Println(strings.Repeat("-", 50))
+	fmt.Println()
+
+	cfg, err := s.loadOrCreateConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	userPath := filepath.Join(cfg.Cursor.ConfigPath, "User")
+	candidates, err := s.scanExcludeCandidates(userPath, cfg.Cursor.ExcludePaths)
+	if err != nil {
+		fmt.Printf("⚠️  Could not scan %s: %v\n", userPath, err)
+		fmt.Println("Keeping the current exclusions.")
+		fmt.Println()
+		return nil
+	}
+
+	if len(candidates) == 0 {
+		fmt.Println("✅ No large or cache-like directories found - nothing to exclude")
+		fmt.Println()
+		return nil
+	}
+
+	fmt.Println("These folders are large or change constantly. Excluded folders are not synced.")
+	fmt.Println("Known-noisy folders are pre-selected.")
+	fmt.Println()
+
+	for {
+		for i, candidate := range candidates {
+			mark := " "
+			if candidate.selected {
+				mark = "x"
+			}
+			fmt.Printf("  %d. [%s] User/%s/ (%s", i+1, mark, candidate.name, formatSize(candidate.size))
+			if candidate.reason != "" {
+				fmt.Printf(", %s", candidate.reason)
+			}
+			fmt.Println(")")
+		}
+		fmt.Println()
+		fmt.Print("Enter numbers to toggle (e.g. '1 3'), or press Enter to accept: ")
+
+		if !s.scanner.Scan() {
+			break
+		}
+
+		input := strings.TrimSpace(s.scanner.Text())
+		if input == "" {
+			break
+		}
+
+		for _, field := range strings.FieldsFunc(input, func(r rune) bool { return r == ' ' || r == ',' }) {
+			n, err := strconv.Atoi(field)
+			if err != nil || n < 1 || n > len(candidates) {
+				fmt.Printf("❌ Invalid choice: %s\n", field)
+				continue
+			}
+			candidates[n-1].selected = !candidates[n-1].selected
+		}
+		fmt.Println()
+	}
+
+	cfg.Cursor.ExcludePaths = applyExcludeSelection(cfg.Cursor.ExcludePaths, candidates)
+
+	if err := s.saveConfig(cfg); err != nil {
+		return fmt.Errorf("failed to save config: %w", err)
+	}
+
+	fmt.Println("✅ Exclude paths saved")
+	fmt.Println()
+	return nil
+}
+
+// scanExcludeCandidates lists User subdirectories that are known-noisy or larger than the threshold
+func (s *SetupWizard) scanExcludeCandidates(userPath string, currentExcludes []string) ([]excludeCandidate, error) {
+	entries, err := os.ReadDir(userPath)
+	if err != nil {
+		return nil, err
+	}
+
+	var candidates []excludeCandidate
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+
+		name := entry.Name()
+		size := dirSize(filepath.Join(userPath, name))
+		reason, noisy := knownNoisyDirs[name]
+
+		if !noisy && size < largeDirThreshold {
+			continue
+		}
+
+		candidates = append(candidates, excludeCandidate{
+			name:     name,
+			size:     size,
+			reason:   reason,
+			selected: noisy || containsExclude(currentExcludes, excludePathFor(name)),
+		})
+	}
+
+	sort.Slice(candidates, func(i, j int) bool {
+		return candidates[i].size > candidates[j].size
+	})
+
+	return candidates, nil
+}
+
+// applyExcludeSelection adds selected candidates to the exclude list and removes deselected ones
+func applyExcludeSelection(excludes []string, candidates []excludeCandidate) []string {
+	result := make([]string, 0, len(excludes)+len(candidates))
+
+	deselected := make(map[string]bool)
+	for _, candidate := range candidates {
+		if !candidate.selected {
+			deselected[excludePathFor(candidate.name)] = true
+		}
+	}
+
+	for _, exclude := range excludes {
+		if !deselected[exclude] {
+			result = append(result, exclude)
+		}
+	}
+
+	for _, candidate := range candidates {
+		path := excludePathFor(candidate.name)
+		if candidate.selected && !containsExclude(result, path) {
+			result = append(result, path)
+		}
+	}
+
+	return result
+}
+
+// excludePathFor returns the exclude_paths entry for a User subdirectory
+func excludePathFor(name string) string {
+	return "User/" + name + "/"
+}
+
+func containsExclude(excludes []string, path string) bool {
+	for _, exclude := range excludes {
+		if exclude == path {
+			return true
+		}
+	}
+	return false
+}
+
+// dirSize returns the total size of all files below a directory
+func dirSize(path string) int64 {
+	var size int64
+	filepath.Walk(path, func(_ string, info os.FileInfo, err error) error {
+		if err != nil {
+			return nil // Skip inaccessible files
+		}
+		if !info.IsDir() {
+			size += info.Size()
+		}
+		return nil
+	})
+	return size
+}
+
+// formatSize formats a byte count for display
+func formatSize(size int64) string {
+	switch {
+	case size >= 1024*1024*1024:
+		return fmt.Sprintf("%.1f GB", float64(size)/(1024*1024*1024))
+	case size >= 1024*1024:
+		return fmt.Sprintf("%.1f MB", float64(size)/(1024*1024))
+	case size >= 1024:
+		return fmt.Sprintf("%.1f KB", float64(size)/1024)
+	default:
+		return fmt.Sprintf("%d B", size)
+	}
+}
@@ -0,0 +1,99 @@
+package interactive
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"cursor-sync/internal/auth"
+	"cursor-sync/internal/privacy"
+)
+
+// NonInteractiveOptions holds the values used by non-interactive setup
+type NonInteractiveOptions struct {
+	RepoURL    string // Repository URL (required)
+	Branch     string // Repository branch (defaults to main)
+	Token      string // GitHub token to save (optional if a token is already configured)
+	CursorPath string // Cursor config path (optional, defaults to the configured/OS default path)
+}
+
+// RunNonInteractiveSetup performs the same validation and config writing as the wizard
+// without prompting, failing with a clear error when a required value is missing
+func (s *SetupWizard) RunNonInteractiveSetup(opts NonInteractiveOptions) error {
+	fmt.Println("🚀 CURSOR-SYNC NON-INTERACTIVE SETUP")
+	fmt.Println()
+
+	cfg, err := s.loadOrCreateConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	// Step 1: GitHub token
+	if token := strings.TrimSpace(opts.Token); token != "" {
+		if err := auth.SaveGitHubToken(token); err != nil {
+			return fmt.Errorf("failed to save GitHub token: %w", err)
+		}
+	} else if !auth.HasValidToken() {
+		return fmt.Errorf("GitHub token required: set it in the environment and pass --token-env, or run 'cursor-sync token <token>' first")
+	}
+
+	// Verify the token against the repository's own host (github.com or a GitHub Enterprise Server)
+	endpoint := auth.NewEndpoint(opts.RepoURL, "", s.timeout(cfg))
+	if _, err := auth.NewGitHubAuth(endpoint); err != nil {
+		return fmt.Errorf("GitHub token validation failed: %w", err)
+	}
+	fmt.Println("✅ GitHub token configured and validated")
+
+	// Step 2: Repository
+	repoURL := strings.TrimSpace(opts.RepoURL)
+	if repoURL == "" {
+		return fmt.Errorf("repository URL required: pass --repo <url>")
+	}
+
+	checker := privacy.NewRepositoryChecker(endpoint)
+	isPrivate, err := checker.CheckRepositoryPrivacy(repoURL)
+	if err != nil {
+		return fmt.Errorf("failed to verify repository privacy: %w", err)
+	}
+	if !isPrivate {
+		return fmt.Errorf("repository %s is PUBLIC - cursor-sync only works with private repositories", repoURL)
+	}
+	fmt.Println("✅ Repository is private")
+
+	cfg.Repository.URL = repoURL
+	if opts.Branch != "" {
+		cfg.Repository.Branch = opts.Branch
+	}
+	if cfg.Repository.Branch == "" {
+		cfg.Repository.Branch = "main"
+	}
+
+	// Step 3: Cursor installation path
+	if cursorPath := strings.TrimSpace(opts.CursorPath); cursorPath != "" {
+		if strings.HasPrefix(cursorPath, "~") {
+			home, err := os.UserHomeDir()
+			if err != nil {
+				return fmt.Errorf("failed to get home directory: %w", err)
+			}
+			cursorPath = filepath.Join(home, cursorPath[1:])
+		}
+
+		if err := s.validateIDEPath(cursorPath); err != nil {
+			return fmt.Errorf("invalid Cursor path: %w", err)
+		}
+		cfg.Cursor.ConfigPath = cursorPath
+	}
+
+	// Step 4: Save configuration
+	if err := s.saveConfig(cfg); err != nil {
+		return fmt.Errorf("failed to save configuration: %w", err)
+	}
+
+	fmt.Println("✅ Repository configuration saved")
+	fmt.Printf("   Repository: %s (%s)\n", cfg.Repository.URL, cfg.Repository.Branch)
+	fmt.Printf("   Cursor Path: %s\n", cfg.Cursor.ConfigPath)
+	fmt.Println()
+
+	return nil
+}
@@ -8,6 +8,7 @@ import (
 	"runtime"
 	"strings"
 	"syscall"
+	"time"
 
 	"golang.org/x/term"
 	"gopkg.in/yaml.v3"
@@ -15,7 +16,10 @@ import (
 	"cursor-sync/internal/auth"
 	"cursor-sync/internal/config"
 	"cursor-sync/internal/cursor"
+	"cursor-sync/internal/github"
+	"cursor-sync/internal/paths"
 	"cursor-sync/internal/privacy"
+	"cursor-sync/internal/urlutil"
 )
 
 // min returns the minimum of two integers (Go 1.21+ has this built-in)
@@ -28,13 +32,16 @@ func min(a, b int) int {
 
 // SetupWizard handles interactive configuration setup
 type SetupWizard struct {
-	scanner *bufio.Scanner
+	scanner        *bufio.Scanner
+	networkTimeout time.Duration // --timeout (0 = network.timeout from the config)
 }
 
-// NewSetupWizard creates a new interactive setup wizard
-func NewSetupWizard() *SetupWizard {
+// NewSetupWizard creates a new interactive setup wizard whose GitHub requests use
+// networkTimeout (0 = network.timeout from the config)
+func NewSetupWizard(networkTimeout time.Duration) *SetupWizard {
 	return &SetupWizard{
-		scanner: bufio.NewScanner(os.Stdin),
+		scanner:        bufio.NewScanner(os.Stdin),
+		networkTimeout: networkTimeout,
 	}
 }
 
@@ -58,9 +65,15 @@ func (s *SetupWizard) RunInteractiveSetup() error {
 		return fmt.Errorf("failed to setup repository configuration: %w", err)
 	}
 
+	// Step 3: Choose which User folders to exclude from sync
+	if err := s.setupExcludePaths(); err != nil {
+		return fmt.Errorf("failed to setup exclude paths: %w", err)
+	}
+
 	fmt.Println("\n🎉 Setup completed successfully!")
 	fmt.Println("✅ GitHub token configured")
 	fmt.Println("✅ Repository configuration saved")
+	fmt.Println("✅ Exclude paths configured")
 	fmt.Println()
 	fmt.Println("Next steps:")
 	fmt.Println("  cursor-sync install    # Install the daemon")
@@ -278,6 +291,17 @@ func (s *SetupWizard) setupRepositoryConfig() error {
 		}
 	}
 
+	// Offer to create the repository via the GitHub API (requires a valid token)
+	if auth.HasValidToken() && s.promptYesNo("Create the private repository for me?") {
+		if err := s.createRepositoryAutomatically(cfg); err != nil {
+			fmt.Printf("❌ Automatic repository creation failed: %v\n", err)
+			fmt.Println("Let's create it manually instead...")
+			fmt.Println()
+		} else {
+			return nil
+		}
+	}
+
 	// Repository setup retry loop
 	for {
 		fmt.Println("📋 STEP-BY-STEP REPOSITORY CREATION:")
@@ -332,8 +356,8 @@ func (s *SetupWizard) setupRepositoryConfig() error {
 			continue
 		}
 
-		// Basic URL format validation
-		if !strings.Contains(repoURL, "github.com") {
+		// Basic URL format validation (GitHub Enterprise Server hosts are accepted too)
+		if _, err := urlutil.ParseGitHubURL(repoURL); err != nil {
 			fmt.Printf("⚠️  This doesn't look like a GitHub URL: %s\n", repoURL)
 			fmt.Println("Expected format: https://github.com/username/repo.git")
 			if !s.promptYesNo("Continue anyway?") {
@@ -343,7 +367,7 @@ func (s *SetupWizard) setupRepositoryConfig() error {
 
 		// Validate repository accessibility and privacy
 		fmt.Println("🔍 Validating repository...")
-		if err := s.validateRepositoryURL(repoURL); err != nil {
+		if err := s.validateRepositoryURL(cfg, repoURL); err != nil {
 			fmt.Printf("❌ Repository validation failed: %v\n", err)
 			fmt.Println()
 			fmt.Println("Common issues:")
@@ -384,6 +408,55 @@ func (s *SetupWizard) setupRepositoryConfig() error {
 	return nil
 }
 
+// createRepositoryAutomatically creates a private repository via the GitHub API and saves its URL
+func (s *SetupWizard) createRepositoryAutomatically(cfg *config.Config) error {
+	fmt.Println()
+	fmt.Print("📝 Repository name (press Enter for 'cursor-sync-bucket'): ")
+	repoName := "cursor-sync-bucket"
+	if s.scanner.Scan() {
+		if name := strings.TrimSpace(s.scanner.Text()); name != "" {
+			repoName = name
+		}
+	}
+
+	fmt.Print("🏢 Organization (press Enter to create under your account): ")
+	owner := ""
+	if s.scanner.Scan() {
+		owner = strings.TrimSpace(s.scanner.Text())
+	}
+
+	githubAPI, err := github.New(auth.NewEndpoint(cfg.Repository.URL, cfg.Repository.APIBaseURL, s.timeout(cfg)))
+	if err != nil {
+		return fmt.Errorf("failed to create GitHub API client: %w", err)
+	}
+
+	fmt.Println("🔧 Creating private repository on GitHub...")
+	repo, err := githubAPI.CreateRepository(owner, repoName, "Cursor IDE settings sync repository - managed by cursor-sync", cfg.Repository.AutoInit)
+	if err != nil {
+		return err
+	}
+
+	if !repo.Private {
+		return fmt.Errorf("repository %s was created but is not private - please make it private on GitHub", repo.FullName)
+	}
+
+	fmt.Printf("✅ Repository created: %s\n", repo.HTMLURL)
+
+	cfg.Repository.URL = repo.CloneURL
+	if cfg.Repository.Branch == "" {
+		cfg.Repository.Branch = "main"
+	}
+
+	fmt.Println("💾 Saving configuration...")
+	if err := s.saveConfig(cfg); err != nil {
+		return fmt.Errorf("failed to save configuration: %w", err)
+	}
+
+	fmt.Println("✅ Repository configuration saved successfully!")
+	fmt.Println("🔒 Repository is private - your settings are secure!")
+	return nil
+}
+
 // setupCursorInstallationPath handles interactive Cursor installation path configuration
 func (s *SetupWizard) setupCursorInstallationPath() error {
 	fmt.Println("📂 Cursor Installation Path Configuration")
@@ -578,15 +651,16 @@ func (s *SetupWizard) validateIDEPath(path string) error {
 }
 
 // validateRepositoryURL validates the repository URL and checks privacy
-func (s *SetupWizard) validateRepositoryURL(repoURL string) error {
+func (s *SetupWizard) validateRepositoryURL(cfg *config.Config, repoURL string) error {
 	// Basic URL validation
-	if !strings.Contains(repoURL, "github.com") {
+	if _, err := urlutil.ParseGitHubURL(repoURL); err != nil {
 		return fmt.Errorf("currently only GitHub repositories are supported")
 	}
 
 	// Check repository privacy if we have a token
 	if auth.HasValidToken() {
-		checker := privacy.NewRepositoryChecker()
+		// Query the repository's own host (github.com or a GitHub Enterprise Server)
+		checker := privacy.NewRepositoryChecker(auth.NewEndpoint(repoURL, "", s.timeout(cfg)))
 		isPrivate, err := checker.CheckRepositoryPrivacy(repoURL)
 		if err != nil {
 			return fmt.Errorf("failed to verify repository privacy: %w", err)
@@ -621,7 +695,7 @@ func (s *SetupWizard) loadOrCreateConfig() (*config.Config, error) {
 	}
 
 	// Create default config if loading fails
-	home, err := os.UserHomeDir()
+	stateDir, err := paths.StateDir()
 	if err != nil {
 		return nil, err
 	}
@@ -629,7 +703,7 @@ func (s *SetupWizard) loadOrCreateConfig() (*config.Config, error) {
 	return &config.Config{
 		Repository: config.Repository{
 			URL:       "",
-			LocalPath: filepath.Join(home, ".cursor-sync", "settings"),
+			LocalPath: filepath.Join(stateDir, "settings"),
 			Branch:    "main",
 		},
 		Sync: config.Sync{
@@ -639,7 +713,7 @@ func (s *SetupWizard) loadOrCreateConfig() (*config.Config, error) {
 			ConflictResolve: "newer",
 		},
 		Cursor: config.Cursor{
-			ConfigPath: filepath.Join(home, "Library", "Application Support", "Cursor"),
+			ConfigPath: cursor.GetDefaultCursorPath(), // OS-specific location
 			ExcludePaths: []string{
 				"logs/", "CachedExtensions/", "CachedExtensionVSIXs/",
 				"tmp/", "GPUCache/", "Crashpad/", "CachedData/",
@@ -649,7 +723,8 @@ func (s *SetupWizard) loadOrCreateConfig() (*config.Config, error) {
 		},
 		Logging: config.Logging{
 			Level:    "info",
-			LogDir:   filepath.Join(home, ".cursor-sync", "logs"),
+			Format:   "text",
+			LogDir:   filepath.Join(stateDir, "logs"),
 			MaxSize:  10,
 			MaxDays:  30,
 			Compress: true,
@@ -657,14 +732,22 @@ func (s *SetupWizard) loadOrCreateConfig() (*config.Config, error) {
 	}, nil
 }
 
+// timeout returns how long a GitHub request may take: --timeout, or network.timeout from
+// cfg (a config that doesn't exist yet has none and gets the default)
+func (s *SetupWizard) timeout(cfg *config.Config) time.Duration {
+	if s.networkTimeout > 0 {
+		return s.networkTimeout
+	}
+	return cfg.Network.Timeout
+}
+
 // saveConfig saves the configuration to the config file
 func (s *SetupWizard) saveConfig(cfg *config.Config) error {
-	home, err := os.UserHomeDir()
+	configDir, err := paths.ConfigDir()
 	if err != nil {
 		return err
 	}
 
-	configDir := filepath.Join(home, ".cursor-sync")
 	if err := os.MkdirAll(configDir, 0755); err != nil {
 		return err
 	}
@@ -681,7 +764,7 @@ func (s *SetupWizard) saveConfig(cfg *config.Config) error {
 
 // validateAndSaveConfig validates and saves the current configuration
 func (s *SetupWizard) validateAndSaveConfig(cfg *config.Config) error {
-	if err := s.validateRepositoryURL(cfg.Repository.URL); err != nil {
+	if err := s.validateRepositoryURL(cfg, cfg.Repository.URL); err != nil {
 		return err
 	}
 	return s.saveConfig(cfg)
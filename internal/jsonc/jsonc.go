@@ -0,0 +1,508 @@
+// Package jsonc parses and serializes JSONC (JSON with comments and trailing commas),
+// the format used by Cursor's settings.json and keybindings.json.
+//
+// Documents are parsed losslessly: comments, whitespace, key order and trailing commas
+// survive a Parse/Bytes round-trip unchanged, and only the members that are edited
+// through Set or Delete are rewritten.
+package jsonc
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Kind is the type of a document's root value
+type Kind int
+
+const (
+	// Object is a root JSON object (settings.json)
+	Object Kind = iota
+	// Array is a root JSON array (keybindings.json)
+	Array
+)
+
+// Member is a single object member or array element of the root value
+type Member struct {
+	Key        string // Decoded key (empty for array elements)
+	leading    string // Whitespace and comments before the member
+	rawKey     string // Quoted key as written (empty for array elements)
+	separator  string // Whitespace, comments and colon between key and value
+	rawValue   string // Value as written, including any nested comments
+	afterValue string // Whitespace and comments between the value and the following comma
+}
+
+// RawValue returns the member's value exactly as written in the document
+func (m *Member) RawValue() string {
+	return m.rawValue
+}
+
+// Document is a losslessly parsed JSONC document
+type Document struct {
+	kind          Kind
+	prefix        string // Everything up to and including the opening bracket
+	members       []*Member
+	trailingComma bool   // Whether the last member is followed by a comma
+	suffix        string // Everything from the trivia before the closing bracket to the end
+}
+
+// Parse parses a JSONC document whose root is an object or an array
+func Parse(data []byte) (*Document, error) {
+	p := &parser{src: string(data)}
+	doc := &Document{}
+
+	start := p.pos
+	if err := p.skipTrivia(); err != nil {
+		return nil, err
+	}
+
+	var closing byte
+	switch p.peek() {
+	case '{':
+		doc.kind = Object
+		closing = '}'
+	case '[':
+		doc.kind = Array
+		closing = ']'
+	default:
+		return nil, p.errorf("expected '{' or '[' at document root")
+	}
+	p.pos++
+	doc.prefix = p.src[start:p.pos]
+
+	for {
+		triviaStart := p.pos
+		if err := p.skipTrivia(); err != nil {
+			return nil, err
+		}
+
+		if p.peek() == closing {
+			doc.suffix = p.src[triviaStart:]
+			break
+		}
+
+		if len(doc.members) > 0 && !doc.trailingComma {
+			return nil, p.errorf("expected ',' or '%c'", closing)
+		}
+		doc.trailingComma = false
+
+		member := &Member{leading: p.src[triviaStart:p.pos]}
+
+		if doc.kind == Object {
+			keyStart := p.pos
+			if p.peek() != '"' {
+				return nil, p.errorf("expected string key")
+			}
+			if err := p.skipString(); err != nil {
+				return nil, err
+			}
+			member.rawKey = p.src[keyStart:p.pos]
+			if err := json.Unmarshal([]byte(member.rawKey), &member.Key); err != nil {
+				return nil, p.errorf("invalid key %s: %v", member.rawKey, err)
+			}
+
+			sepStart := p.pos
+			if err := p.skipTrivia(); err != nil {
+				return nil, err
+			}
+			if p.peek() != ':' {
+				return nil, p.errorf("expected ':' after key %s", member.rawKey)
+			}
+			p.pos++
+			if err := p.skipTrivia(); err != nil {
+				return nil, err
+			}
+			member.separator = p.src[sepStart:p.pos]
+		}
+
+		valueStart := p.pos
+		if err := p.skipValue(); err != nil {
+			return nil, err
+		}
+		member.rawValue = p.src[valueStart:p.pos]
+
+		afterStart := p.pos
+		if err := p.skipTrivia(); err != nil {
+			return nil, err
+		}
+
+		doc.members = append(doc.members, member)
+
+		switch p.peek() {
+		case ',':
+			member.afterValue = p.src[afterStart:p.pos]
+			p.pos++
+			doc.trailingComma = true
+		case closing:
+			// Rewind so the trivia before the closing bracket becomes part of the suffix
+			p.pos = afterStart
+		default:
+			return nil, p.errorf("expected ',' or '%c'", closing)
+		}
+	}
+
+	return doc, nil
+}
+
+// Bytes serializes the document, preserving comments, formatting and key order
+func (d *Document) Bytes() []byte {
+	var b strings.Builder
+
+	b.WriteString(d.prefix)
+	for i, member := range d.members {
+		b.WriteString(member.leading)
+		if d.kind == Object {
+			b.WriteString(member.rawKey)
+			b.WriteString(member.separator)
+		}
+		b.WriteString(member.rawValue)
+		b.WriteString(member.afterValue)
+		if i < len(d.members)-1 || d.trailingComma {
+			b.WriteString(",")
+		}
+	}
+	b.WriteString(d.suffix)
+
+	return []byte(b.String())
+}
+
+// Kind returns the type of the document's root value
+func (d *Document) Kind() Kind {
+	return d.kind
+}
+
+// Members returns the members of the root value in document order
+func (d *Document) Members() []*Member {
+	return d.members
+}
+
+// Keys returns the keys of the root object in document order
+func (d *Document) Keys() []string {
+	keys := make([]string, 0, len(d.members))
+	for _, member := range d.members {
+		keys = append(keys, member.Key)
+	}
+	return keys
+}
+
+// Lookup returns the member with the given key, or nil if the key isn't present
+// If a key occurs more than once the last occurrence wins, as in Cursor
+func (d *Document) Lookup(key string) *Member {
+	for i := len(d.members) - 1; i >= 0; i-- {
+		if d.kind == Object && d.members[i].Key == key {
+			return d.members[i]
+		}
+	}
+	return nil
+}
+
+// Get decodes the value of the given key into v
+func (d *Document) Get(key string, v interface{}) (bool, error) {
+	member := d.Lookup(key)
+	if member == nil {
+		return false, nil
+	}
+	if err := Unmarshal([]byte(member.rawValue), v); err != nil {
+		return true, fmt.Errorf("failed to decode %q: %w", key, err)
+	}
+	return true, nil
+}
+
+// Set sets the value of a root object key, keeping the member's position and comments
+// New keys are appended after the last member using its indentation
+func (d *Document) Set(key string, value interface{}) error {
+	if d.kind != Object {
+		return fmt.Errorf("cannot set key %q on a JSONC array", key)
+	}
+
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("failed to encode %q: %w", key, err)
+	}
+
+	return d.SetRaw(key, string(raw))
+}
+
+// SetRaw sets the value of a root object key to raw JSONC text
+func (d *Document) SetRaw(key, rawValue string) error {
+	if d.kind != Object {
+		return fmt.Errorf("cannot set key %q on a JSONC array", key)
+	}
+
+	if member := d.Lookup(key); member != nil {
+		member.rawValue = rawValue
+		return nil
+	}
+
+	rawKey, err := json.Marshal(key)
+	if err != nil {
+		return fmt.Errorf("failed to encode key %q: %w", key, err)
+	}
+
+	d.appendMember(&Member{
+		Key:       key,
+		rawKey:    string(rawKey),
+		separator: ": ",
+		rawValue:  rawValue,
+	})
+	return nil
+}
+
+// appendMember adds a member after the last one, indented like it
+func (d *Document) appendMember(member *Member) {
+	member.leading = d.memberIndent()
+	d.members = append(d.members, member)
+
+	// Keep the closing bracket on its own line
+	if !strings.HasPrefix(strings.TrimLeft(d.suffix, " \t"), "\n") && !strings.HasPrefix(d.suffix, "\r\n") {
+		d.suffix = "\n" + d.suffix
+	}
+}
+
+// Delete removes a root object key (all occurrences); returns whether it was present
+// Comments above the deleted member are removed with it, while a comment trailing the
+// previous member on the same line is kept
+func (d *Document) Delete(key string) bool {
+	return d.deleteMembers(func(member *Member) bool {
+		return d.kind == Object && member.Key == key
+	})
+}
+
+// deleteMembers removes the members matching remove, with the comments above them
+func (d *Document) deleteMembers(remove func(*Member) bool) bool {
+	found := false
+	carry := ""
+	kept := d.members[:0]
+	for _, member := range d.members {
+		if remove(member) {
+			found = true
+			if idx := strings.Index(member.leading, "\n"); idx >= 0 {
+				carry += member.leading[:idx]
+			} else {
+				carry += member.leading
+			}
+			continue
+		}
+		member.leading = carry + member.leading
+		carry = ""
+		kept = append(kept, member)
+	}
+	d.members = kept
+	d.suffix = carry + d.suffix
+	if len(d.members) == 0 {
+		d.trailingComma = false
+	}
+	return found
+}
+
+// memberIndent returns the leading whitespace to use for a new member
+func (d *Document) memberIndent() string {
+	for i := len(d.members) - 1; i >= 0; i-- {
+		leading := d.members[i].leading
+		if idx := strings.LastIndex(leading, "\n"); idx >= 0 {
+			return "\n" + leading[idx+1:]
+		}
+	}
+	return "\n    "
+}
+
+// Unmarshal decodes JSONC data into v, ignoring comments and trailing commas
+func Unmarshal(data []byte, v interface{}) error {
+	clean, err := Strip(data)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(clean, v)
+}
+
+// Strip converts JSONC to plain JSON by removing comments and trailing commas
+// Offsets of the remaining content shift, but line numbers are preserved
+func Strip(data []byte) ([]byte, error) {
+	p := &parser{src: string(data)}
+	out := make([]byte, 0, len(data))
+
+	pendingComma := -1
+	for p.pos < len(p.src) {
+		c := p.src[p.pos]
+		switch {
+		case c == '"':
+			start := p.pos
+			if err := p.skipString(); err != nil {
+				return nil, err
+			}
+			pendingComma = -1
+			out = append(out, p.src[start:p.pos]...)
+		case c == '/' && p.pos+1 < len(p.src) && (p.src[p.pos+1] == '/' || p.src[p.pos+1] == '*'):
+			start := p.pos
+			if err := p.skipComment(); err != nil {
+				return nil, err
+			}
+			// Keep newlines so error line numbers still match the original
+			out = append(out, strings.Repeat("\n", strings.Count(p.src[start:p.pos], "\n"))...)
+		case c == ',':
+			pendingComma = len(out)
+			out = append(out, c)
+			p.pos++
+		case c == '}' || c == ']':
+			if pendingComma >= 0 {
+				// Drop the trailing comma
+				out = append(out[:pendingComma], out[pendingComma+1:]...)
+				pendingComma = -1
+			}
+			out = append(out, c)
+			p.pos++
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			out = append(out, c)
+			p.pos++
+		default:
+			pendingComma = -1
+			out = append(out, c)
+			p.pos++
+		}
+	}
+
+	return out, nil
+}
+
+// Valid reports whether data is well-formed JSONC and returns the parse error if not
+func Valid(data []byte) error {
+	clean, err := Strip(data)
+	if err != nil {
+		return err
+	}
+
+	var v interface{}
+	if err := json.Unmarshal(clean, &v); err != nil {
+		if syntaxErr, ok := err.(*json.SyntaxError); ok {
+			line := bytes.Count(clean[:syntaxErr.Offset], []byte("\n")) + 1
+			return fmt.Errorf("line %d: %w", line, err)
+		}
+		return err
+	}
+	return nil
+}
+
+// parser is a minimal JSONC scanner
+type parser struct {
+	src string
+	pos int
+}
+
+func (p *parser) peek() byte {
+	if p.pos >= len(p.src) {
+		return 0
+	}
+	return p.src[p.pos]
+}
+
+func (p *parser) errorf(format string, args ...interface{}) error {
+	line := strings.Count(p.src[:min(p.pos, len(p.src))], "\n") + 1
+	return fmt.Errorf("jsonc: line %d: %s", line, fmt.Sprintf(format, args...))
+}
+
+// skipTrivia skips whitespace and comments
+func (p *parser) skipTrivia() error {
+	for p.pos < len(p.src) {
+		switch c := p.src[p.pos]; {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			p.pos++
+		case c == '/' && p.pos+1 < len(p.src) && (p.src[p.pos+1] == '/' || p.src[p.pos+1] == '*'):
+			if err := p.skipComment(); err != nil {
+				return err
+			}
+		default:
+			return nil
+		}
+	}
+	return nil
+}
+
+// skipComment skips a // line comment or a /* block comment */
+func (p *parser) skipComment() error {
+	if p.src[p.pos+1] == '/' {
+		end := strings.IndexByte(p.src[p.pos:], '\n')
+		if end < 0 {
+			p.pos = len(p.src)
+		} else {
+			p.pos += end
+		}
+		return nil
+	}
+
+	end := strings.Index(p.src[p.pos+2:], "*/")
+	if end < 0 {
+		return p.errorf("unterminated block comment")
+	}
+	p.pos += end + 4
+	return nil
+}
+
+// skipString skips a double-quoted string including escapes
+func (p *parser) skipString() error {
+	p.pos++ // opening quote
+	for p.pos < len(p.src) {
+		switch p.src[p.pos] {
+		case '\\':
+			p.pos += 2
+		case '"':
+			p.pos++
+			return nil
+		case '\n':
+			return p.errorf("unterminated string")
+		default:
+			p.pos++
+		}
+	}
+	return p.errorf("unterminated string")
+}
+
+// skipValue skips a complete value, including nested objects/arrays and their comments
+func (p *parser) skipValue() error {
+	switch c := p.peek(); c {
+	case '"':
+		return p.skipString()
+	case '{', '[':
+		depth := 0
+		for p.pos < len(p.src) {
+			switch ch := p.src[p.pos]; {
+			case ch == '"':
+				if err := p.skipString(); err != nil {
+					return err
+				}
+				continue
+			case ch == '/' && p.pos+1 < len(p.src) && (p.src[p.pos+1] == '/' || p.src[p.pos+1] == '*'):
+				if err := p.skipComment(); err != nil {
+					return err
+				}
+				continue
+			case ch == '{' || ch == '[':
+				depth++
+			case ch == '}' || ch == ']':
+				depth--
+				if depth == 0 {
+					p.pos++
+					return nil
+				}
+			}
+			p.pos++
+		}
+		return p.errorf("unterminated %c", c)
+	case 0:
+		return p.errorf("unexpected end of input")
+	default:
+		// Literal: number, true, false, null
+		start := p.pos
+		for p.pos < len(p.src) {
+			ch := p.src[p.pos]
+			if ch == ',' || ch == '}' || ch == ']' || ch == ' ' || ch == '\t' || ch == '\n' || ch == '\r' || ch == '/' {
+				break
+			}
+			p.pos++
+		}
+		if p.pos == start {
+			return p.errorf("unexpected character %q", c)
+		}
+		return nil
+	}
+}
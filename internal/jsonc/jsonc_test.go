@@ -0,0 +1,104 @@
+package jsonc
+
+import (
+	"strings"
+	"testing"
+)
+
+const settingsWithComments = `// Cursor settings
+{
+    // Editor
+    "editor.fontSize": 14, // Bigger on the laptop
+    /* Block comment
+       spanning lines */
+    "editor.tabSize": 4,
+    "files.exclude": {
+        "**/.git": true, // nested comment
+    },
+    "url": "https://example.com/*not-a-comment*/",
+}
+// Trailing comment
+`
+
+func TestParseBytesRoundTrip(t *testing.T) {
+	for _, input := range []string{
+		settingsWithComments,
+		"{}",
+		"[]",
+		"  {\n}\n",
+		`[{"key": "ctrl+a", "command": "a"}, /* c */ {"key": "ctrl+b", "command": "b"},]`,
+	} {
+		doc, err := Parse([]byte(input))
+		if err != nil {
+			t.Errorf("Parse(%q) failed: %v", input, err)
+			continue
+		}
+		if got := string(doc.Bytes()); got != input {
+			t.Errorf("round-trip changed the document:\n got: %q\nwant: %q", got, input)
+		}
+	}
+}
+
+func TestSetKeepsComments(t *testing.T) {
+	doc, err := Parse([]byte(settingsWithComments))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := doc.Set("editor.fontSize", 16); err != nil {
+		t.Fatal(err)
+	}
+	if err := doc.Set("editor.wordWrap", "on"); err != nil {
+		t.Fatal(err)
+	}
+
+	got := string(doc.Bytes())
+	want := strings.Replace(settingsWithComments, `"editor.fontSize": 14,`, `"editor.fontSize": 16,`, 1)
+	want = strings.Replace(want, `    "url": "https://example.com/*not-a-comment*/",
+}`, `    "url": "https://example.com/*not-a-comment*/",
+    "editor.wordWrap": "on",
+}`, 1)
+	if got != want {
+		t.Errorf("Set rewrote more than the edited members:\n got: %s\nwant: %s", got, want)
+	}
+
+	var size int
+	if ok, err := doc.Get("editor.fontSize", &size); !ok || err != nil || size != 16 {
+		t.Errorf("Get(editor.fontSize) = %d, %v, %v; want 16", size, ok, err)
+	}
+}
+
+func TestDeleteRemovesLeadingComment(t *testing.T) {
+	doc, err := Parse([]byte(settingsWithComments))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !doc.Delete("editor.tabSize") {
+		t.Fatal("editor.tabSize not found")
+	}
+
+	got := string(doc.Bytes())
+	if strings.Contains(got, "editor.tabSize") || strings.Contains(got, "Block comment") {
+		t.Errorf("deleted member or its comment is still there:\n%s", got)
+	}
+	if !strings.Contains(got, "// Bigger on the laptop") {
+		t.Errorf("comment trailing the previous member was removed:\n%s", got)
+	}
+	if err := Valid(doc.Bytes()); err != nil {
+		t.Errorf("document is invalid after Delete: %v", err)
+	}
+}
+
+func TestStripKeepsStrings(t *testing.T) {
+	stripped, err := Strip([]byte(settingsWithComments))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var settings map[string]interface{}
+	if err := Unmarshal(stripped, &settings); err != nil {
+		t.Fatalf("stripped document is not valid JSON: %v\n%s", err, stripped)
+	}
+	if url := settings["url"]; url != "https://example.com/*not-a-comment*/" {
+		t.Errorf("comment markers inside a string were stripped: %v", url)
+	}
+}
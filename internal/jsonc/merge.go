@@ -0,0 +1,176 @@
+package jsonc
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// Identity names the root array element that represents the same entry in every version
+// of a document, e.g. a keybinding's key and command. Object members are identified by key
+type Identity func(m *Member) string
+
+// ConflictError is returned by Merge when both sides changed the same entries differently
+type ConflictError struct {
+	Entries []string // Identities of the conflicting entries, sorted
+}
+
+func (e *ConflictError) Error() string {
+	return fmt.Sprintf("both sides changed %s", strings.Join(e.Entries, ", "))
+}
+
+// Merge combines two versions of a document that both changed since base, entry by entry:
+// additions, edits and removals made on only one side are all kept. The result is the
+// remote document with the local changes applied, so its comments and formatting survive;
+// entries added locally are appended. An empty base stands for a document that didn't exist.
+// Entries changed differently on both sides make the merge fail with a *ConflictError.
+// identity is only used for arrays and may be nil for objects
+func Merge(base, local, remote []byte, identity Identity) ([]byte, error) {
+	localDoc, err := Parse(local)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse local version: %w", err)
+	}
+	remoteDoc, err := Parse(remote)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse remote version: %w", err)
+	}
+	if localDoc.kind != remoteDoc.kind {
+		return nil, fmt.Errorf("local and remote versions have different root types")
+	}
+	if localDoc.kind == Array && identity == nil {
+		return nil, fmt.Errorf("merging arrays needs an element identity")
+	}
+
+	baseEntries := map[string]*Member{}
+	if len(bytes.TrimSpace(base)) > 0 {
+		baseDoc, err := Parse(base)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse common ancestor: %w", err)
+		}
+		if baseDoc.kind != localDoc.kind {
+			return nil, fmt.Errorf("common ancestor has a different root type")
+		}
+		baseEntries, _ = baseDoc.entries(identity)
+	}
+	localEntries, localOrder := localDoc.entries(identity)
+	remoteEntries, _ := remoteDoc.entries(identity)
+
+	var conflicts []string
+	for _, id := range localOrder {
+		localMember := localEntries[id]
+		baseMember, inBase := baseEntries[id]
+		remoteMember, inRemote := remoteEntries[id]
+
+		if inBase && sameValue(localMember, baseMember) {
+			continue // Unchanged locally
+		}
+		if inRemote && sameValue(localMember, remoteMember) {
+			continue // Same change on both sides
+		}
+		if inRemote != inBase || (inRemote && !sameValue(remoteMember, baseMember)) {
+			conflicts = append(conflicts, id)
+			continue
+		}
+
+		if inRemote {
+			remoteMember.rawValue = localMember.rawValue
+			continue
+		}
+		remoteDoc.appendMember(&Member{
+			Key:       localMember.Key,
+			rawKey:    localMember.rawKey,
+			separator: ": ",
+			rawValue:  localMember.rawValue,
+		})
+	}
+
+	removed := map[*Member]bool{}
+	for id, baseMember := range baseEntries {
+		if _, inLocal := localEntries[id]; inLocal {
+			continue
+		}
+		remoteMember, inRemote := remoteEntries[id]
+		if !inRemote {
+			continue // Removed on both sides
+		}
+		if !sameValue(remoteMember, baseMember) {
+			conflicts = append(conflicts, id)
+			continue
+		}
+		removed[remoteMember] = true
+	}
+
+	if len(conflicts) > 0 {
+		sort.Strings(conflicts)
+		return nil, &ConflictError{Entries: conflicts}
+	}
+	if len(removed) > 0 {
+		// Object keys may occur more than once; every occurrence goes
+		remoteDoc.deleteMembers(func(member *Member) bool {
+			return removed[member] || (remoteDoc.kind == Object && removed[remoteEntries[member.Key]])
+		})
+	}
+	return remoteDoc.Bytes(), nil
+}
+
+// entries indexes the root members by identity, in document order. Object keys that occur
+// more than once resolve to the last occurrence, as in Cursor; repeated array identities
+// are numbered by occurrence
+func (d *Document) entries(identity Identity) (map[string]*Member, []string) {
+	entries := make(map[string]*Member, len(d.members))
+	var order []string
+	seen := map[string]int{}
+	for _, member := range d.members {
+		id := member.Key
+		if d.kind == Array {
+			id = identity(member)
+			seen[id]++
+			if n := seen[id]; n > 1 {
+				id = fmt.Sprintf("%s #%d", id, n)
+			}
+		}
+		if _, ok := entries[id]; !ok {
+			order = append(order, id)
+		}
+		entries[id] = member
+	}
+	return entries, order
+}
+
+// sameValue reports whether two members hold equal values, ignoring comments and formatting
+func sameValue(a, b *Member) bool {
+	if a.rawValue == b.rawValue {
+		return true
+	}
+	var av, bv interface{}
+	if Unmarshal([]byte(a.rawValue), &av) != nil || Unmarshal([]byte(b.rawValue), &bv) != nil {
+		return false
+	}
+	return reflect.DeepEqual(av, bv)
+}
+
+// KeybindingIdentity identifies a keybindings.json entry by its key and command, so an
+// entry whose "when" clause or arguments changed is still the same binding
+func KeybindingIdentity(m *Member) string {
+	var binding struct {
+		Key     string `json:"key"`
+		Command string `json:"command"`
+	}
+	if err := Unmarshal([]byte(m.rawValue), &binding); err != nil || binding.Command == "" {
+		return strings.Join(strings.Fields(m.rawValue), " ")
+	}
+	return strings.ToLower(binding.Key) + " => " + binding.Command
+}
+
+// MergeKeybindings merges two versions of a keybindings.json array, see Merge
+func MergeKeybindings(base, local, remote []byte) ([]byte, error) {
+	return Merge(base, local, remote, KeybindingIdentity)
+}
+
+// MergeObjects merges two versions of a document whose root object holds independent
+// entries, such as a snippets file keyed by snippet name, see Merge
+func MergeObjects(base, local, remote []byte) ([]byte, error) {
+	return Merge(base, local, remote, nil)
+}
@@ -0,0 +1,215 @@
+package jsonc
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const baseKeybindings = `// Place your key bindings in this file to override the defaults
+[
+    {
+        "key": "cmd+k cmd+c",
+        "command": "editor.action.addCommentLine",
+        "when": "editorTextFocus && !editorReadonly"
+    },
+    {
+        "key": "cmd+shift+d",
+        "command": "editor.action.copyLinesDownAction"
+    },
+    {
+        "key": "cmd+r",
+        "command": "-workbench.action.reloadWindow"
+    }
+]
+`
+
+// decodeKeybindings returns the key and command of every binding, in document order
+func decodeKeybindings(t *testing.T, data []byte) []string {
+	t.Helper()
+	var bindings []struct {
+		Key     string `json:"key"`
+		Command string `json:"command"`
+		When    string `json:"when"`
+	}
+	if err := Unmarshal(data, &bindings); err != nil {
+		t.Fatalf("merged keybindings don't parse: %v\n%s", err, data)
+	}
+	var got []string
+	for _, b := range bindings {
+		got = append(got, b.Key+" "+b.Command+" "+b.When)
+	}
+	return got
+}
+
+func TestMergeKeybindingsCombinesBothSides(t *testing.T) {
+	// This machine adds a binding and drops the reload override
+	local := `// Place your key bindings in this file to override the defaults
+[
+    {
+        "key": "cmd+k cmd+c",
+        "command": "editor.action.addCommentLine",
+        "when": "editorTextFocus && !editorReadonly"
+    },
+    {
+        "key": "cmd+shift+d",
+        "command": "editor.action.copyLinesDownAction"
+    },
+    {
+        "key": "ctrl+shift+t",
+        "command": "workbench.action.terminal.new"
+    }
+]
+`
+	// The other machine narrows a "when" clause and adds a binding of its own
+	remote := `// Place your key bindings in this file to override the defaults
+[
+    {
+        "key": "cmd+k cmd+c",
+        "command": "editor.action.addCommentLine",
+        "when": "editorTextFocus && !editorReadonly && !inDiffEditor"
+    },
+    {
+        "key": "cmd+shift+d",
+        "command": "editor.action.copyLinesDownAction"
+    },
+    {
+        "key": "cmd+r",
+        "command": "-workbench.action.reloadWindow"
+    },
+    // Markdown preview
+    {
+        "key": "cmd+alt+v",
+        "command": "markdown.showPreviewToSide",
+        "when": "editorLangId == markdown"
+    }
+]
+`
+
+	merged, err := MergeKeybindings([]byte(baseKeybindings), []byte(local), []byte(remote))
+	if err != nil {
+		t.Fatalf("MergeKeybindings() error = %v", err)
+	}
+
+	want := []string{
+		"cmd+k cmd+c editor.action.addCommentLine editorTextFocus && !editorReadonly && !inDiffEditor",
+		"cmd+shift+d editor.action.copyLinesDownAction ",
+		"cmd+alt+v markdown.showPreviewToSide editorLangId == markdown",
+		"ctrl+shift+t workbench.action.terminal.new ",
+	}
+	if got := decodeKeybindings(t, merged); !reflect.DeepEqual(got, want) {
+		t.Errorf("merged bindings = %q, want %q\n%s", got, want, merged)
+	}
+	if err := Valid(merged); err != nil {
+		t.Errorf("merged document is invalid: %v", err)
+	}
+}
+
+func TestMergeKeybindingsReportsConflicts(t *testing.T) {
+	local := `[
+    {"key": "cmd+shift+d", "command": "editor.action.copyLinesDownAction", "when": "editorTextFocus"},
+    {"key": "cmd+r", "command": "-workbench.action.reloadWindow"}
+]`
+	remote := `[
+    {"key": "cmd+shift+d", "command": "editor.action.copyLinesDownAction", "when": "!editorReadonly"}
+]`
+	base := `[
+    {"key": "cmd+shift+d", "command": "editor.action.copyLinesDownAction"},
+    {"key": "cmd+r", "command": "-workbench.action.reloadWindow"}
+]`
+
+	_, err := MergeKeybindings([]byte(base), []byte(local), []byte(remote))
+	var conflict *ConflictError
+	if !errors.As(err, &conflict) {
+		t.Fatalf("MergeKeybindings() error = %v, want a conflict", err)
+	}
+	want := []string{"cmd+shift+d => editor.action.copyLinesDownAction"}
+	if !reflect.DeepEqual(conflict.Entries, want) {
+		t.Errorf("conflicting entries = %q, want %q", conflict.Entries, want)
+	}
+}
+
+func TestMergeKeybindingsWithoutCommonAncestor(t *testing.T) {
+	local := `[{"key": "cmd+1", "command": "a"}, {"key": "cmd+2", "command": "b"}]`
+	remote := `[{"key": "cmd+2", "command": "b"}, {"key": "cmd+3", "command": "c"}]`
+
+	merged, err := MergeKeybindings(nil, []byte(local), []byte(remote))
+	if err != nil {
+		t.Fatalf("MergeKeybindings() error = %v", err)
+	}
+	want := []string{"cmd+2 b ", "cmd+3 c ", "cmd+1 a "}
+	if got := decodeKeybindings(t, merged); !reflect.DeepEqual(got, want) {
+		t.Errorf("merged bindings = %q, want %q", got, want)
+	}
+}
+
+func TestMergeObjectsCombinesSnippets(t *testing.T) {
+	base := `{
+	"Print to console": {
+		"prefix": "log",
+		"body": ["console.log('$1');"]
+	}
+}`
+	local := `{
+	"Print to console": {
+		"prefix": "log",
+		"body": ["console.log('$1');", "$2"]
+	},
+	"Arrow function": {
+		"prefix": "af",
+		"body": ["($1) => {", "\t$0", "}"]
+	}
+}`
+	remote := `{
+	// Shared snippets
+	"Print to console": {
+		"prefix": "log",
+		"body": ["console.log('$1');"]
+	},
+	"Import": {
+		"prefix": "imp",
+		"body": ["import { $2 } from '$1';"]
+	}
+}`
+
+	merged, err := MergeObjects([]byte(base), []byte(local), []byte(remote))
+	if err != nil {
+		t.Fatalf("MergeObjects() error = %v", err)
+	}
+
+	doc, err := Parse(merged)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := doc.Keys(), []string{"Print to console", "Import", "Arrow function"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("merged snippets = %q, want %q", got, want)
+	}
+	var snippet struct {
+		Body []string `json:"body"`
+	}
+	if _, err := doc.Get("Print to console", &snippet); err != nil || len(snippet.Body) != 2 {
+		t.Errorf("local edit of \"Print to console\" lost: %+v, %v", snippet, err)
+	}
+	if !strings.Contains(string(merged), "// Shared snippets") {
+		t.Errorf("remote comment lost:\n%s", merged)
+	}
+}
+
+func TestMergeObjectsAppliesRemovals(t *testing.T) {
+	base := `{"a": 1, "b": 2, "c": 3}`
+	local := `{"a": 1, "c": 3}`
+	remote := `{"a": 1, "b": 2, "c": 3, "d": 4}`
+
+	merged, err := MergeObjects([]byte(base), []byte(local), []byte(remote))
+	if err != nil {
+		t.Fatalf("MergeObjects() error = %v", err)
+	}
+	var got map[string]int
+	if err := Unmarshal(merged, &got); err != nil {
+		t.Fatal(err)
+	}
+	if want := map[string]int{"a": 1, "c": 3, "d": 4}; !reflect.DeepEqual(got, want) {
+		t.Errorf("merged = %v, want %v", got, want)
+	}
+}
@@ -2,8 +2,10 @@ package logger
 
 import (
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/sirupsen/logrus"
@@ -11,6 +13,13 @@ import (
 
 var log *logrus.Logger
 
+const (
+	// LogFileName is the name of the log file inside each daily log directory
+	LogFileName = "cursor-sync.log"
+	// dateLayout is the layout used for daily log directory names
+	dateLayout = "2006-01-02"
+)
+
 // Init initializes the logger
 func Init(verbose bool) {
 	log = logrus.New()
@@ -29,8 +38,16 @@ func Init(verbose bool) {
 	})
 }
 
+// Quiet limits logging to warnings and errors (--quiet)
+func Quiet() {
+	log.SetLevel(logrus.WarnLevel)
+}
+
 // InitWithConfig initializes the logger with configuration
-func InitWithConfig(level, logDir string, verbose bool) error {
+// format selects the output encoding: "text" (default) or "json"
+// When verbose is set, debug logging is enabled and file logs are mirrored to stdout
+// rotation controls size-based rotation, retention and compression of the log files
+func InitWithConfig(level, format, logDir string, verbose bool, rotation Rotation) error {
 	log = logrus.New()
 
 	// Set log level
@@ -46,14 +63,15 @@ func InitWithConfig(level, logDir string, verbose bool) error {
 	log.SetLevel(logLevel)
 
 	// Set formatter
-	log.SetFormatter(&logrus.TextFormatter{
-		FullTimestamp:   true,
-		TimestampFormat: "2006-01-02 15:04:05",
-	})
+	formatter, err := newFormatter(format)
+	if err != nil {
+		return err
+	}
+	log.SetFormatter(formatter)
 
 	// Setup file logging if log directory is provided
 	if logDir != "" {
-		if err := setupFileLogging(logDir); err != nil {
+		if err := setupFileLogging(logDir, verbose, rotation); err != nil {
 			return fmt.Errorf("failed to setup file logging: %w", err)
 		}
 	}
@@ -61,31 +79,47 @@ func InitWithConfig(level, logDir string, verbose bool) error {
 	return nil
 }
 
-func setupFileLogging(logDir string) error {
+// newFormatter returns the logrus formatter for the configured log format
+func newFormatter(format string) (logrus.Formatter, error) {
+	switch strings.ToLower(format) {
+	case "", "text":
+		return &logrus.TextFormatter{
+			FullTimestamp:   true,
+			TimestampFormat: "2006-01-02 15:04:05",
+		}, nil
+	case "json":
+		return &logrus.JSONFormatter{
+			TimestampFormat: time.RFC3339,
+		}, nil
+	default:
+		return nil, fmt.Errorf("unknown log format: %s (expected 'text' or 'json')", format)
+	}
+}
+
+func setupFileLogging(logDir string, mirrorStdout bool, rotation Rotation) error {
 	// Create log directory
 	if err := os.MkdirAll(logDir, 0755); err != nil {
 		return fmt.Errorf("failed to create log directory: %w", err)
 	}
 
-	// Create daily log directory
-	today := time.Now().Format("2006-01-02")
-	dailyLogDir := filepath.Join(logDir, today)
-	if err := os.MkdirAll(dailyLogDir, 0755); err != nil {
-		return fmt.Errorf("failed to create daily log directory: %w", err)
-	}
-
-	// Create log file
-	logFile := filepath.Join(dailyLogDir, "cursor-sync.log")
-	file, err := os.OpenFile(logFile, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0666)
+	// Open today's log file with size-based rotation
+	file, err := newRotatingWriter(logDir, rotation)
 	if err != nil {
-		return fmt.Errorf("failed to open log file: %w", err)
+		return err
 	}
 
-	// Set output to both file and stdout
-	log.SetOutput(file)
+	// Write to the daily file, and also to stdout when requested so service
+	// managers (launchd StandardOutPath, journald) capture the same output
+	if mirrorStdout {
+		log.SetOutput(io.MultiWriter(os.Stdout, file))
+	} else {
+		log.SetOutput(file)
+	}
 
 	// Clean up old logs
-	go cleanupOldLogs(logDir, 30)
+	if rotation.MaxDays > 0 {
+		go cleanupOldLogs(logDir, rotation.MaxDays)
+	}
 
 	return nil
 }
@@ -104,7 +138,7 @@ func cleanupOldLogs(logDir string, maxDays int) {
 		}
 
 		// Parse directory name as date
-		date, err := time.Parse("2006-01-02", entry.Name())
+		date, err := time.Parse(dateLayout, entry.Name())
 		if err != nil {
 			continue
 		}
@@ -112,9 +146,40 @@ func cleanupOldLogs(logDir string, maxDays int) {
 		if date.Before(cutoff) {
 			oldDir := filepath.Join(logDir, entry.Name())
 			os.RemoveAll(oldDir)
-			log.Debugf("Cleaned up old log directory: %s", oldDir)
+			Debug("Cleaned up old log directory: %s", oldDir)
+		}
+	}
+}
+
+// LogFilePath returns the log file path for the given day: <logDir>/<YYYY-MM-DD>/cursor-sync.log
+// Both the log writer and the logs command use it so they always agree on the location
+func LogFilePath(logDir string, day time.Time) string {
+	return filepath.Join(logDir, day.Format(dateLayout), LogFileName)
+}
+
+// LogDates returns the YYYY-MM-DD dates that have a log file in logDir, newest first
+func LogDates(logDir string) []string {
+	entries, err := os.ReadDir(logDir)
+	if err != nil {
+		return nil
+	}
+
+	var dates []string
+	for i := len(entries) - 1; i >= 0; i-- { // ReadDir sorts by name, i.e. by date
+		name := entries[i].Name()
+		if _, err := time.Parse(dateLayout, name); err != nil {
+			continue
+		}
+		if _, err := os.Stat(filepath.Join(logDir, name, LogFileName)); err == nil {
+			dates = append(dates, name)
 		}
 	}
+	return dates
+}
+
+// ParseLogDate parses a YYYY-MM-DD date as used for daily log directories
+func ParseLogDate(date string) (time.Time, error) {
+	return time.Parse(dateLayout, date)
 }
 
 // Debug logs a debug message
@@ -0,0 +1,165 @@
+package logger
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Rotation controls how log files are rotated and retained
+type Rotation struct {
+	MaxSize  int  // Maximum size of a log file in MB before it is rotated (0 disables size rotation)
+	MaxDays  int  // Number of days of log directories to keep (0 keeps everything)
+	Compress bool // Gzip-compress rotated log files
+}
+
+// rotatingWriter writes to the daily log file and rotates it when it grows past MaxSize
+// A new daily file is opened automatically when the date changes
+type rotatingWriter struct {
+	logDir   string
+	rotation Rotation
+	mu       sync.Mutex
+	file     *os.File
+	day      string
+	size     int64
+}
+
+// newRotatingWriter opens today's log file for appending
+func newRotatingWriter(logDir string, rotation Rotation) (*rotatingWriter, error) {
+	w := &rotatingWriter{
+		logDir:   logDir,
+		rotation: rotation,
+	}
+
+	if err := w.openCurrent(time.Now()); err != nil {
+		return nil, err
+	}
+
+	return w, nil
+}
+
+// Write implements io.Writer
+func (w *rotatingWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	now := time.Now()
+
+	// Start a new daily file when the date changes
+	if now.Format(dateLayout) != w.day {
+		if err := w.openCurrent(now); err != nil {
+			return 0, err
+		}
+		if w.rotation.MaxDays > 0 {
+			go cleanupOldLogs(w.logDir, w.rotation.MaxDays)
+		}
+	}
+
+	// Rotate when the write would push the file past the size limit
+	maxBytes := int64(w.rotation.MaxSize) * 1024 * 1024
+	if maxBytes > 0 && w.size > 0 && w.size+int64(len(p)) > maxBytes {
+		if err := w.rotate(now); err != nil {
+			return 0, err
+		}
+	}
+
+	n, err := w.file.Write(p)
+	w.size += int64(n)
+	return n, err
+}
+
+// Close closes the current log file
+func (w *rotatingWriter) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.file == nil {
+		return nil
+	}
+	err := w.file.Close()
+	w.file = nil
+	return err
+}
+
+// openCurrent opens (or creates) the log file for the given day
+func (w *rotatingWriter) openCurrent(now time.Time) error {
+	if w.file != nil {
+		w.file.Close()
+		w.file = nil
+	}
+
+	logFile := LogFilePath(w.logDir, now)
+	if err := os.MkdirAll(filepath.Dir(logFile), 0755); err != nil {
+		return fmt.Errorf("failed to create daily log directory: %w", err)
+	}
+
+	file, err := os.OpenFile(logFile, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0666)
+	if err != nil {
+		return fmt.Errorf("failed to open log file: %w", err)
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return fmt.Errorf("failed to stat log file: %w", err)
+	}
+
+	w.file = file
+	w.day = now.Format(dateLayout)
+	w.size = info.Size()
+	return nil
+}
+
+// rotate moves the current log file aside and opens a fresh one
+func (w *rotatingWriter) rotate(now time.Time) error {
+	current := LogFilePath(w.logDir, now)
+	if w.file != nil {
+		w.file.Close()
+		w.file = nil
+	}
+
+	base := strings.TrimSuffix(LogFileName, filepath.Ext(LogFileName))
+	rotated := filepath.Join(filepath.Dir(current),
+		fmt.Sprintf("%s-%s%s", base, now.Format("150405.000"), filepath.Ext(LogFileName)))
+
+	if err := os.Rename(current, rotated); err != nil {
+		return fmt.Errorf("failed to rotate log file: %w", err)
+	}
+
+	if w.rotation.Compress {
+		go compressLogFile(rotated)
+	}
+
+	return w.openCurrent(now)
+}
+
+// compressLogFile gzips a rotated log file and removes the original
+func compressLogFile(path string) {
+	src, err := os.Open(path)
+	if err != nil {
+		return
+	}
+	defer src.Close()
+
+	dst, err := os.OpenFile(path+".gz", os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+	if err != nil {
+		return
+	}
+
+	gz := gzip.NewWriter(dst)
+	_, copyErr := io.Copy(gz, src)
+	closeErr := gz.Close()
+	dst.Close()
+
+	if copyErr != nil || closeErr != nil {
+		os.Remove(path + ".gz")
+		return
+	}
+
+	os.Remove(path)
+}
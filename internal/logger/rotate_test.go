@@ -0,0 +1,134 @@
+package logger
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRotatingWriterRotatesAtMaxSize(t *testing.T) {
+	logDir := t.TempDir()
+	w, err := newRotatingWriter(logDir, Rotation{MaxSize: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Close()
+
+	line := append(bytes.Repeat([]byte("x"), 1023), '\n')
+	for i := 0; i < 1024; i++ { // Exactly 1 MB
+		if _, err := w.Write(line); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := w.Write([]byte("after rotation\n")); err != nil {
+		t.Fatal(err)
+	}
+
+	current := LogFilePath(logDir, time.Now())
+	data, err := os.ReadFile(current)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "after rotation\n" {
+		t.Errorf("current log holds %d bytes, want only the write after rotation", len(data))
+	}
+
+	rotated := rotatedFiles(t, filepath.Dir(current))
+	if len(rotated) != 1 || !strings.HasSuffix(rotated[0], ".log") {
+		t.Fatalf("rotated files = %v, want one uncompressed log", rotated)
+	}
+	if info, err := os.Stat(filepath.Join(filepath.Dir(current), rotated[0])); err != nil || info.Size() != 1024*1024 {
+		t.Errorf("rotated log = %v, %v; want the full 1 MB", info, err)
+	}
+}
+
+func TestRotatingWriterCompressesRotatedFiles(t *testing.T) {
+	logDir := t.TempDir()
+	w, err := newRotatingWriter(logDir, Rotation{MaxSize: 1, Compress: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Close()
+
+	if _, err := w.Write(bytes.Repeat([]byte("x"), 1024*1024)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := w.Write([]byte("after rotation\n")); err != nil {
+		t.Fatal(err)
+	}
+
+	// Compression runs in the background
+	dayDir := filepath.Dir(LogFilePath(logDir, time.Now()))
+	var rotated []string
+	for i := 0; i < 100; i++ {
+		rotated = rotatedFiles(t, dayDir)
+		if len(rotated) == 1 && strings.HasSuffix(rotated[0], ".log.gz") {
+			return
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	t.Errorf("rotated files = %v, want one .log.gz", rotated)
+}
+
+func TestRotatingWriterStartsNewDayAndPrunes(t *testing.T) {
+	logDir := t.TempDir()
+	now := time.Now()
+	old := filepath.Dir(LogFilePath(logDir, now.AddDate(0, 0, -10)))
+	recent := filepath.Dir(LogFilePath(logDir, now.AddDate(0, 0, -2)))
+	other := filepath.Join(logDir, "notes")
+	for _, dir := range []string{old, recent, other} {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	w, err := newRotatingWriter(logDir, Rotation{MaxDays: 7})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Close()
+
+	// A writer left open since yesterday moves on to today's file at the next write
+	w.day = now.AddDate(0, 0, -1).Format(dateLayout)
+	if _, err := w.Write([]byte("next day\n")); err != nil {
+		t.Fatal(err)
+	}
+	if data, err := os.ReadFile(LogFilePath(logDir, now)); err != nil || !strings.Contains(string(data), "next day") {
+		t.Errorf("today's log = %q, %v; want the write after the date changed", data, err)
+	}
+
+	// Pruning runs in the background
+	for i := 0; i < 100; i++ {
+		if _, err := os.Stat(old); os.IsNotExist(err) {
+			break
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	if _, err := os.Stat(old); !os.IsNotExist(err) {
+		t.Errorf("log directory older than max_days still exists: %v", err)
+	}
+	for _, dir := range []string{recent, other} {
+		if _, err := os.Stat(dir); err != nil {
+			t.Errorf("%s was removed: %v", filepath.Base(dir), err)
+		}
+	}
+}
+
+// rotatedFiles lists the rotated log files in a daily log directory
+func rotatedFiles(t *testing.T, dayDir string) []string {
+	t.Helper()
+	entries, err := os.ReadDir(dayDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, entry := range entries {
+		if entry.Name() != LogFileName {
+			names = append(names, entry.Name())
+		}
+	}
+	return names
+}
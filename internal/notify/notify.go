@@ -0,0 +1,163 @@
+package notify
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"cursor-sync/internal/logger"
+)
+
+// Events that can be sent to the notification webhook
+const (
+	EventError    = "error"
+	EventConflict = "conflict"
+	EventSuccess  = "success"
+	// EventStale is sent when no push has succeeded for longer than sync.stale_warning
+	EventStale = "stale"
+)
+
+// Payload formats
+const (
+	FormatJSON  = "json"
+	FormatSlack = "slack"
+)
+
+// Payload is the JSON body posted to the webhook
+type Payload struct {
+	Event  string    `json:"event"`
+	Repo   string    `json:"repo"`
+	Time   time.Time `json:"time"`
+	Error  string    `json:"error,omitempty"`
+	Detail string    `json:"detail,omitempty"`
+}
+
+// slackPayload is the body accepted by Slack incoming webhooks
+type slackPayload struct {
+	Text string `json:"text"`
+}
+
+// Notifier posts sync events to a webhook URL
+type Notifier struct {
+	url    string
+	format string
+	events map[string]bool
+	client *http.Client
+}
+
+// New creates a notifier for the given webhook URL
+// Returns nil if no URL is configured; a nil notifier ignores all events
+// Without explicit events only errors are sent
+func New(url string, events []string, format string) *Notifier {
+	if url == "" {
+		return nil
+	}
+
+	if len(events) == 0 {
+		events = []string{EventError}
+	}
+	if format == "" {
+		format = FormatJSON
+	}
+
+	enabled := make(map[string]bool)
+	for _, event := range events {
+		enabled[event] = true
+	}
+
+	return &Notifier{
+		url:    url,
+		format: format,
+		events: enabled,
+		client: &http.Client{
+			Timeout: 10 * time.Second,
+		},
+	}
+}
+
+// ValidEvent reports whether event is a known notification event
+func ValidEvent(event string) bool {
+	return event == EventError || event == EventConflict || event == EventSuccess || event == EventStale
+}
+
+// Enabled reports whether notifications are sent for the event
+func (n *Notifier) Enabled(event string) bool {
+	return n != nil && n.events[event]
+}
+
+// Notify sends the event in the background if it is enabled
+// Delivery failures are logged and never affect the sync
+func (n *Notifier) Notify(event, repo string, syncErr error, detail string) {
+	if !n.Enabled(event) {
+		return
+	}
+
+	payload := Payload{
+		Event:  event,
+		Repo:   repo,
+		Time:   time.Now(),
+		Detail: detail,
+	}
+	if syncErr != nil {
+		payload.Error = syncErr.Error()
+	}
+
+	go func() {
+		if err := n.send(payload); err != nil {
+			logger.Warn("⚠️  Failed to send %s notification: %v", event, err)
+		} else {
+			logger.Debug("Sent %s notification to webhook", event)
+		}
+	}()
+}
+
+// send posts the payload to the webhook URL
+func (n *Notifier) send(payload Payload) error {
+	var body interface{} = payload
+	if n.format == FormatSlack {
+		body = slackPayload{Text: formatSlackText(payload)}
+	}
+
+	data, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("failed to marshal payload: %w", err)
+	}
+
+	resp, err := n.client.Post(n.url, "application/json", bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("failed to post notification: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook returned status %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+// formatSlackText renders the payload as a Slack message
+func formatSlackText(payload Payload) string {
+	var icon string
+	switch payload.Event {
+	case EventError:
+		icon = "❌"
+	case EventConflict:
+		icon = "⚠️"
+	case EventStale:
+		icon = "⏰"
+	default:
+		icon = "✅"
+	}
+
+	text := fmt.Sprintf("%s cursor-sync %s for %s at %s", icon, payload.Event, payload.Repo, payload.Time.Format("2006-01-02 15:04:05"))
+	if payload.Detail != "" {
+		text += "\n" + payload.Detail
+	}
+	if payload.Error != "" {
+		text += fmt.Sprintf("\n```%s```", payload.Error)
+	}
+	return text
+}
@@ -0,0 +1,54 @@
+// Package paths resolves the directories cursor-sync keeps its own files in
+package paths
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+)
+
+const (
+	// appName names the cursor-sync directory inside the XDG base directories
+	appName = "cursor-sync"
+	// legacyDirName is the directory below the home directory used on every platform
+	// before XDG support; it stays in use wherever it exists
+	legacyDirName = ".cursor-sync"
+)
+
+// ConfigDir returns the directory holding config.yaml and the GitHub token:
+// $XDG_CONFIG_HOME/cursor-sync (~/.config/cursor-sync) on Linux, ~/.cursor-sync elsewhere
+func ConfigDir() (string, error) {
+	return baseDir("XDG_CONFIG_HOME", ".config")
+}
+
+// StateDir returns the directory holding the repository clone, logs, backups, reports and
+// the daemon's runtime files: $XDG_STATE_HOME/cursor-sync (~/.local/state/cursor-sync) on
+// Linux, ~/.cursor-sync elsewhere
+func StateDir() (string, error) {
+	return baseDir("XDG_STATE_HOME", filepath.Join(".local", "state"))
+}
+
+// baseDir resolves a base directory. An existing ~/.cursor-sync wins on every platform,
+// so installations from before XDG support keep their files in one place
+func baseDir(xdgVar, xdgDefault string) (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get home directory: %w", err)
+	}
+
+	legacy := filepath.Join(home, legacyDirName)
+	if runtime.GOOS != "linux" {
+		return legacy, nil
+	}
+	if _, err := os.Stat(legacy); err == nil {
+		return legacy, nil
+	}
+
+	// The XDG spec says relative paths are invalid and must be ignored
+	base := os.Getenv(xdgVar)
+	if !filepath.IsAbs(base) {
+		base = filepath.Join(home, xdgDefault)
+	}
+	return filepath.Join(base, appName), nil
+}
@@ -0,0 +1,39 @@
+package paths
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+func TestBaseDirs(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(home, "xdg-config"))
+	t.Setenv("XDG_STATE_HOME", "relative/state") // Invalid, so the default is used
+
+	legacy := filepath.Join(home, ".cursor-sync")
+	wantConfig, wantState := legacy, legacy
+	if runtime.GOOS == "linux" {
+		wantConfig = filepath.Join(home, "xdg-config", "cursor-sync")
+		wantState = filepath.Join(home, ".local", "state", "cursor-sync")
+	}
+	if dir, err := ConfigDir(); err != nil || dir != wantConfig {
+		t.Errorf("ConfigDir() = %q, %v; want %q", dir, err, wantConfig)
+	}
+	if dir, err := StateDir(); err != nil || dir != wantState {
+		t.Errorf("StateDir() = %q, %v; want %q", dir, err, wantState)
+	}
+
+	// An existing legacy directory keeps being used
+	if err := os.Mkdir(legacy, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if dir, err := ConfigDir(); err != nil || dir != legacy {
+		t.Errorf("ConfigDir() with ~/.cursor-sync = %q, %v; want %q", dir, err, legacy)
+	}
+	if dir, err := StateDir(); err != nil || dir != legacy {
+		t.Errorf("StateDir() with ~/.cursor-sync = %q, %v; want %q", dir, err, legacy)
+	}
+}
@@ -0,0 +1,109 @@
+package privacy
+
+import (
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+
+	"cursor-sync/internal/logger"
+	"cursor-sync/internal/paths"
+	"cursor-sync/internal/ratelimit"
+)
+
+// cacheFileName stores privacy results in the state directory so they survive restarts
+const cacheFileName = "privacy-cache.json"
+
+// lowRateLimitThreshold is the remaining request count below which cached results are
+// used until the rate limit resets
+const lowRateLimitThreshold = 10
+
+// cacheEntry is a cached privacy result for one repository
+type cacheEntry struct {
+	Private      bool      `json:"private"`
+	ETag         string    `json:"etag,omitempty"`
+	CheckedAt    time.Time `json:"checked_at"`
+	BackoffUntil time.Time `json:"backoff_until,omitempty"`
+}
+
+var cacheMutex sync.Mutex
+
+func cachePath() (string, error) {
+	stateDir, err := paths.StateDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(stateDir, cacheFileName), nil
+}
+
+func readCache() map[string]cacheEntry {
+	entries := make(map[string]cacheEntry)
+
+	path, err := cachePath()
+	if err != nil {
+		return entries
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return entries
+	}
+
+	if err := json.Unmarshal(data, &entries); err != nil {
+		logger.Debug("Ignoring unreadable privacy cache: %v", err)
+		return make(map[string]cacheEntry)
+	}
+	return entries
+}
+
+// loadCacheEntry returns the cached privacy result for owner/repo
+func loadCacheEntry(key string) (cacheEntry, bool) {
+	cacheMutex.Lock()
+	defer cacheMutex.Unlock()
+
+	entry, ok := readCache()[key]
+	return entry, ok
+}
+
+// saveCacheEntry stores the privacy result for owner/repo
+// Failures only cost an extra API call next time, so they are logged and ignored
+func saveCacheEntry(key string, entry cacheEntry) {
+	cacheMutex.Lock()
+	defer cacheMutex.Unlock()
+
+	path, err := cachePath()
+	if err != nil {
+		return
+	}
+
+	entries := readCache()
+	entries[key] = entry
+
+	data, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		logger.Debug("Failed to create privacy cache directory: %v", err)
+		return
+	}
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		logger.Debug("Failed to write privacy cache: %v", err)
+	}
+}
+
+// rateLimitBackoff returns until when cached results should be preferred because few
+// API requests remain, or the zero time if the rate limit is healthy
+func rateLimitBackoff(resp *http.Response) time.Time {
+	remaining, err := strconv.Atoi(resp.Header.Get("X-RateLimit-Remaining"))
+	if err != nil || remaining >= lowRateLimitThreshold {
+		return time.Time{}
+	}
+
+	logger.Warn("⚠️  Only %d GitHub API requests left - backing off privacy checks until the limit resets", remaining)
+	return ratelimit.ResetTime(resp)
+}
@@ -1,16 +1,19 @@
 package privacy
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
 	"os"
-	"path/filepath"
-	"regexp"
 	"strings"
 	"time"
 
+	"cursor-sync/internal/auth"
 	"cursor-sync/internal/logger"
+	"cursor-sync/internal/ratelimit"
+	"cursor-sync/internal/urlutil"
+	"cursor-sync/internal/version"
 )
 
 // RepoInfo represents basic repository information
@@ -20,23 +23,54 @@ type RepoInfo struct {
 	FullName string `json:"full_name"`
 }
 
+// repoPrivacyClient sends the repository API requests of a privacy check; *http.Client in
+// production, a fake answering with canned responses in tests
+type repoPrivacyClient interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// Checker reports whether a sync repository is private; RepositoryChecker asks the GitHub
+// API, tests substitute an answer without network access
+type Checker interface {
+	CheckRepositoryPrivacy(repoURL string) (bool, error)
+	ForceRefresh()
+}
+
+var _ Checker = (*RepositoryChecker)(nil)
+
 // RepositoryChecker checks repository privacy settings
 type RepositoryChecker struct {
-	httpClient *http.Client
+	httpClient   repoPrivacyClient
+	endpoint     auth.Endpoint // GitHub API queried and its timeout
+	cacheTTL     time.Duration // How long a "private" result is trusted (0 = always check)
+	forceRefresh bool          // Bypass the cache on the next check
 }
 
-// NewRepositoryChecker creates a new repository checker
-func NewRepositoryChecker() *RepositoryChecker {
+// NewRepositoryChecker creates a new repository checker that always queries the GitHub
+// API at endpoint
+func NewRepositoryChecker(endpoint auth.Endpoint) *RepositoryChecker {
 	return &RepositoryChecker{
-		httpClient: &http.Client{
-			Timeout: 10 * time.Second,
-		},
+		httpClient: &http.Client{},
+		endpoint:   endpoint,
 	}
 }
 
+// NewCachedRepositoryChecker creates a repository checker that trusts a cached
+// "private" result for ttl and revalidates it with the API's ETag afterwards
+func NewCachedRepositoryChecker(endpoint auth.Endpoint, ttl time.Duration) *RepositoryChecker {
+	rc := NewRepositoryChecker(endpoint)
+	rc.cacheTTL = ttl
+	return rc
+}
+
+// ForceRefresh makes the next check bypass the cache
+func (rc *RepositoryChecker) ForceRefresh() {
+	rc.forceRefresh = true
+}
+
 // CheckRepositoryPrivacy checks if a Git repository is private
 func (rc *RepositoryChecker) CheckRepositoryPrivacy(repoURL string) (bool, error) {
-	owner, repo, err := parseGitHubURL(repoURL)
+	parsed, err := urlutil.ParseGitHubURL(repoURL)
 	if err != nil {
 		// If we can't parse as GitHub URL, we can't check privacy
 		// For safety, assume it might be public and warn
@@ -44,22 +78,57 @@ func (rc *RepositoryChecker) CheckRepositoryPrivacy(repoURL string) (bool, error
 		return false, fmt.Errorf("cannot determine repository privacy: %w", err)
 	}
 
-	return rc.checkGitHubRepositoryPrivacy(owner, repo)
+	return rc.checkGitHubRepositoryPrivacy(parsed)
 }
 
 // checkGitHubRepositoryPrivacy checks if a GitHub repository is private
-func (rc *RepositoryChecker) checkGitHubRepositoryPrivacy(owner, repo string) (bool, error) {
-	apiURL := fmt.Sprintf("https://api.github.com/repos/%s/%s", owner, repo)
+func (rc *RepositoryChecker) checkGitHubRepositoryPrivacy(parsed *urlutil.GitHubURL) (bool, error) {
+	owner, repo := parsed.Owner, parsed.Repo
+
+	// Repositories on github.com keep their original owner/repo key; the same name on
+	// another server is a different repository
+	key := owner + "/" + repo
+	if parsed.Host != urlutil.DefaultHost {
+		key = parsed.WebHost() + "/" + key
+	}
+	force := rc.forceRefresh
+	rc.forceRefresh = false
+
+	// Only "private" results are cached: a repository that was public is re-checked
+	// every time so that fixing it takes effect immediately
+	entry, cached := loadCacheEntry(key)
+	cached = cached && entry.Private && rc.cacheTTL > 0
+
+	if cached && !force {
+		if time.Since(entry.CheckedAt) < rc.cacheTTL {
+			logger.Debug("Using cached privacy result for %s (checked %v ago)", key, time.Since(entry.CheckedAt).Round(time.Second))
+			return true, nil
+		}
+		if time.Now().Before(entry.BackoffUntil) {
+			logger.Debug("GitHub API rate limit low - using cached privacy result for %s until %s", key, entry.BackoffUntil.Format("15:04:05"))
+			return true, nil
+		}
+	}
+
+	apiURL := fmt.Sprintf("%s/repos/%s/%s", rc.endpoint.BaseURL(), owner, repo)
 
 	logger.Debug("Checking repository privacy: %s/%s", owner, repo)
 
-	req, err := http.NewRequest("GET", apiURL, nil)
+	ctx, cancel := context.WithTimeout(context.Background(), rc.endpoint.NetworkTimeout())
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, "GET", apiURL, nil)
 	if err != nil {
 		return false, fmt.Errorf("failed to create request: %w", err)
 	}
 
 	// Set User-Agent (GitHub API requires it)
-	req.Header.Set("User-Agent", "cursor-sync/1.0")
+	req.Header.Set("User-Agent", "cursor-sync/"+version.Version)
+
+	// Conditional request - a 304 doesn't count against the rate limit
+	if cached && entry.ETag != "" {
+		req.Header.Set("If-None-Match", entry.ETag)
+	}
 
 	// Add GitHub token authentication if available
 	if token, err := rc.loadGitHubToken(); err == nil {
@@ -75,6 +144,24 @@ func (rc *RepositoryChecker) checkGitHubRepositoryPrivacy(owner, repo string) (b
 	}
 	defer resp.Body.Close()
 
+	if resp.StatusCode == http.StatusNotModified && cached {
+		logger.Debug("Repository %s unchanged since last privacy check (ETag match)", key)
+		entry.CheckedAt = time.Now()
+		entry.BackoffUntil = rateLimitBackoff(resp)
+		saveCacheEntry(key, entry)
+		return true, nil
+	}
+
+	if rateErr := ratelimit.FromResponse(resp); rateErr != nil {
+		if cached {
+			logger.Warn("⚠️  GitHub API rate limit exceeded - using cached privacy result until %s", rateErr.Reset.Format("15:04:05"))
+			entry.BackoffUntil = rateErr.Reset
+			saveCacheEntry(key, entry)
+			return true, nil
+		}
+		return false, rateErr
+	}
+
 	if resp.StatusCode == 404 {
 		// Repository not found or private (and we don't have access)
 		// For safety, we'll assume it's private if we get 404
@@ -91,19 +178,26 @@ func (rc *RepositoryChecker) checkGitHubRepositoryPrivacy(owner, repo string) (b
 		return false, fmt.Errorf("failed to decode repository info: %w", err)
 	}
 
+	if rc.cacheTTL > 0 {
+		saveCacheEntry(key, cacheEntry{
+			Private:      repoInfo.Private,
+			ETag:         resp.Header.Get("ETag"),
+			CheckedAt:    time.Now(),
+			BackoffUntil: rateLimitBackoff(resp),
+		})
+	}
+
 	logger.Debug("Repository %s/%s is private: %t", owner, repo, repoInfo.Private)
 	return repoInfo.Private, nil
 }
 
 // loadGitHubToken loads the GitHub token from file
 func (rc *RepositoryChecker) loadGitHubToken() (string, error) {
-	home, err := os.UserHomeDir()
+	tokenPath, err := auth.TokenFilePath()
 	if err != nil {
-		return "", fmt.Errorf("failed to get home directory: %w", err)
+		return "", err
 	}
 
-	tokenPath := filepath.Join(home, ".cursor-sync", ".github")
-
 	// Check if token file exists
 	if _, err := os.Stat(tokenPath); os.IsNotExist(err) {
 		return "", fmt.Errorf("GitHub token not found")
@@ -123,35 +217,6 @@ func (rc *RepositoryChecker) loadGitHubToken() (string, error) {
 	return token, nil
 }
 
-// parseGitHubURL parses a GitHub repository URL and extracts owner and repo name
-func parseGitHubURL(repoURL string) (owner, repo string, err error) {
-	// Handle various GitHub URL formats:
-	// https://github.com/owner/repo.git
-	// https://github.com/owner/repo
-	// git@github.com:owner/repo.git
-	// github.com/owner/repo
-
-	// Remove common prefixes and suffixes
-	url := strings.TrimSpace(repoURL)
-	url = strings.TrimPrefix(url, "https://")
-	url = strings.TrimPrefix(url, "http://")
-	url = strings.TrimPrefix(url, "git@")
-	url = strings.TrimSuffix(url, ".git")
-
-	// Replace : with / for SSH format
-	url = strings.Replace(url, ":", "/", 1)
-
-	// Extract owner and repo using regex
-	re := regexp.MustCompile(`github\.com[:/]([^/]+)/([^/\s]+)`)
-	matches := re.FindStringSubmatch(url)
-
-	if len(matches) != 3 {
-		return "", "", fmt.Errorf("invalid GitHub URL format: %s", repoURL)
-	}
-
-	return matches[1], matches[2], nil
-}
-
 // ShowPrivacyWarning displays a prominent privacy warning
 func ShowPrivacyWarning(repoURL string) {
 	fmt.Println("\n" + strings.Repeat("=", 80))
@@ -0,0 +1,102 @@
+package privacy
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"cursor-sync/internal/ratelimit"
+)
+
+// fakeGitHub answers repository requests with a canned response and records them
+type fakeGitHub struct {
+	status   int
+	header   http.Header
+	body     string
+	requests []*http.Request
+}
+
+func (f *fakeGitHub) Do(req *http.Request) (*http.Response, error) {
+	f.requests = append(f.requests, req)
+	rec := httptest.NewRecorder()
+	for name, values := range f.header {
+		rec.Header()[name] = values
+	}
+	rec.WriteHeader(f.status)
+	rec.WriteString(f.body)
+	return rec.Result(), nil
+}
+
+func TestCheckRepositoryPrivacyResponses(t *testing.T) {
+	tests := []struct {
+		name        string
+		github      fakeGitHub
+		wantPrivate bool
+		wantErr     bool
+		rateLimited bool
+	}{
+		{name: "private", github: fakeGitHub{status: http.StatusOK, body: `{"private": true}`}, wantPrivate: true},
+		{name: "public", github: fakeGitHub{status: http.StatusOK, body: `{"private": false}`}},
+		{name: "not found", github: fakeGitHub{status: http.StatusNotFound}, wantPrivate: true},
+		{name: "forbidden", github: fakeGitHub{status: http.StatusForbidden}, wantErr: true},
+		{
+			name: "rate limited",
+			github: fakeGitHub{status: http.StatusForbidden, header: http.Header{
+				"X-Ratelimit-Remaining": {"0"},
+				"X-Ratelimit-Reset":     {"4102444800"},
+			}},
+			wantErr:     true,
+			rateLimited: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("HOME", t.TempDir())
+			rc := &RepositoryChecker{httpClient: &tt.github}
+
+			private, err := rc.CheckRepositoryPrivacy("https://github.com/me/settings.git")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("CheckRepositoryPrivacy() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if private != tt.wantPrivate {
+				t.Errorf("private = %v, want %v", private, tt.wantPrivate)
+			}
+			if _, ok := ratelimit.As(err); ok != tt.rateLimited {
+				t.Errorf("rate limit error = %v, want %v", ok, tt.rateLimited)
+			}
+			if len(tt.github.requests) != 1 || tt.github.requests[0].URL.Path != "/repos/me/settings" {
+				t.Errorf("requests = %v, want one for /repos/me/settings", tt.github.requests)
+			}
+		})
+	}
+}
+
+func TestCachedCheckUsesETagAndRateLimitFallback(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	github := &fakeGitHub{status: http.StatusOK, header: http.Header{"Etag": {`"v1"`}}, body: `{"private": true}`}
+	rc := &RepositoryChecker{httpClient: github, cacheTTL: time.Hour}
+
+	if private, err := rc.CheckRepositoryPrivacy("https://github.com/me/settings.git"); err != nil || !private {
+		t.Fatalf("first check = %v, %v", private, err)
+	}
+
+	// Within the TTL the cached result is used without asking GitHub
+	if private, err := rc.CheckRepositoryPrivacy("https://github.com/me/settings.git"); err != nil || !private {
+		t.Fatalf("cached check = %v, %v", private, err)
+	}
+	if len(github.requests) != 1 {
+		t.Fatalf("%d requests, want the second check served from cache", len(github.requests))
+	}
+
+	// A forced refresh revalidates with the ETag; rate limiting keeps the cached result
+	*github = fakeGitHub{status: http.StatusTooManyRequests, header: http.Header{"Retry-After": {"60"}}}
+	rc.ForceRefresh()
+	if private, err := rc.CheckRepositoryPrivacy("https://github.com/me/settings.git"); err != nil || !private {
+		t.Fatalf("rate limited check = %v, %v, want cached private result", private, err)
+	}
+	if len(github.requests) != 1 || github.requests[0].Header.Get("If-None-Match") != `"v1"` {
+		t.Errorf("revalidation request didn't send the ETag: %v", github.requests)
+	}
+}
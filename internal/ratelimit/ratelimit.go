@@ -0,0 +1,130 @@
+package ratelimit
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/google/go-github/v56/github"
+)
+
+// defaultWait is used when GitHub doesn't say when the limit resets
+const defaultWait = time.Minute
+
+// Error is returned when a GitHub API request was rejected because of rate limiting
+// Callers can wait until Reset and retry instead of treating it as a permission error
+type Error struct {
+	Reset     time.Time // When requests will be accepted again
+	Secondary bool      // Secondary (abuse) rate limit rather than the hourly quota
+}
+
+func (e *Error) Error() string {
+	kind := "rate limit"
+	if e.Secondary {
+		kind = "secondary rate limit"
+	}
+	return fmt.Sprintf("GitHub API %s exceeded (retry after %s)", kind, e.Reset.Format("15:04:05"))
+}
+
+// Wait returns how long to wait before retrying
+func (e *Error) Wait() time.Duration {
+	if wait := time.Until(e.Reset); wait > 0 {
+		return wait
+	}
+	return 0
+}
+
+// RetryWait returns Wait plus up to a second of jitter, so clients limited together
+// don't all retry in the same instant
+func (e *Error) RetryWait() time.Duration {
+	return e.Wait() + Jitter(time.Second)
+}
+
+// Jitter returns a random duration in [0, max)
+func Jitter(max time.Duration) time.Duration {
+	if max <= 0 {
+		return 0
+	}
+	return time.Duration(rand.Int63n(int64(max)))
+}
+
+// Backoff returns how long to wait before poll attempt n (starting at 1): base doubled
+// with every attempt up to max, of which the upper half is random jitter
+func Backoff(attempt int, base, max time.Duration) time.Duration {
+	delay := base
+	for i := 1; i < attempt && delay < max; i++ {
+		delay *= 2
+	}
+	if delay > max {
+		delay = max
+	}
+	return delay/2 + Jitter(delay/2)
+}
+
+// As reports whether err is (or wraps) a rate limit error
+func As(err error) (*Error, bool) {
+	var rateErr *Error
+	if errors.As(err, &rateErr) {
+		return rateErr, true
+	}
+	return nil, false
+}
+
+// FromResponse returns a rate limit error if the response was rejected because of
+// rate limiting, or nil otherwise
+// A 403 is only treated as rate limiting when GitHub says so through the headers,
+// so real permission errors are still reported as such
+func FromResponse(resp *http.Response) *Error {
+	if resp == nil {
+		return nil
+	}
+
+	switch {
+	case resp.StatusCode == http.StatusTooManyRequests:
+	case resp.StatusCode == http.StatusForbidden && resp.Header.Get("X-RateLimit-Remaining") == "0":
+	case resp.StatusCode == http.StatusForbidden && resp.Header.Get("Retry-After") != "":
+	default:
+		return nil
+	}
+
+	rateErr := &Error{Reset: ResetTime(resp)}
+
+	if retryAfter, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil {
+		rateErr.Reset = time.Now().Add(time.Duration(retryAfter) * time.Second)
+		rateErr.Secondary = resp.Header.Get("X-RateLimit-Remaining") != "0"
+	}
+
+	return rateErr
+}
+
+// FromGitHubError converts go-github rate limit errors into an Error
+// Other errors are returned unchanged
+func FromGitHubError(err error) error {
+	var primary *github.RateLimitError
+	if errors.As(err, &primary) {
+		return &Error{Reset: primary.Rate.Reset.Time}
+	}
+
+	var secondary *github.AbuseRateLimitError
+	if errors.As(err, &secondary) {
+		wait := defaultWait
+		if secondary.RetryAfter != nil {
+			wait = *secondary.RetryAfter
+		}
+		return &Error{Reset: time.Now().Add(wait), Secondary: true}
+	}
+
+	return err
+}
+
+// ResetTime returns when the rate limit resets according to X-RateLimit-Reset
+// (one minute from now if the header is missing)
+func ResetTime(resp *http.Response) time.Time {
+	if reset, err := strconv.ParseInt(resp.Header.Get("X-RateLimit-Reset"), 10, 64); err == nil {
+		return time.Unix(reset, 0)
+	}
+	return time.Now().Add(defaultWait)
+}
@@ -0,0 +1,54 @@
+package ratelimit
+
+import (
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestBackoffDoublesWithinJitterBounds(t *testing.T) {
+	base, max := time.Second, 8*time.Second
+	cases := []struct {
+		attempt int
+		ceiling time.Duration
+	}{
+		{1, time.Second},
+		{2, 2 * time.Second},
+		{3, 4 * time.Second},
+		{4, 8 * time.Second},
+		{10, 8 * time.Second},
+	}
+
+	for _, c := range cases {
+		for i := 0; i < 50; i++ {
+			delay := Backoff(c.attempt, base, max)
+			if delay < c.ceiling/2 || delay >= c.ceiling {
+				t.Fatalf("Backoff(%d) = %v, want within [%v, %v)", c.attempt, delay, c.ceiling/2, c.ceiling)
+			}
+		}
+	}
+}
+
+func TestFromResponseHonoursRetryAfter(t *testing.T) {
+	resp := &http.Response{StatusCode: http.StatusForbidden, Header: http.Header{}}
+	resp.Header.Set("Retry-After", "30")
+
+	rateErr := FromResponse(resp)
+	if rateErr == nil {
+		t.Fatal("FromResponse() = nil for a 403 with Retry-After")
+	}
+	if !rateErr.Secondary {
+		t.Error("Retry-After without an exhausted quota should be a secondary rate limit")
+	}
+	if wait := rateErr.Wait(); wait < 29*time.Second || wait > 30*time.Second {
+		t.Errorf("Wait() = %v, want ~30s", wait)
+	}
+	if wait := rateErr.RetryWait(); wait < rateErr.Wait() || wait > 31*time.Second {
+		t.Errorf("RetryWait() = %v, want Wait() plus under a second", wait)
+	}
+
+	resp = &http.Response{StatusCode: http.StatusForbidden, Header: http.Header{}}
+	if FromResponse(resp) != nil {
+		t.Error("plain 403 treated as rate limiting")
+	}
+}
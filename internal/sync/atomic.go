@@ -0,0 +1,62 @@
+package sync
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// criticalFiles are settings files Cursor cannot start cleanly without;
+// writes to them are flushed to disk before being renamed into place
+var criticalFiles = map[string]bool{
+	"settings.json":    true,
+	"keybindings.json": true,
+}
+
+// isCriticalFile reports whether path names a critical settings file
+func isCriticalFile(path string) bool {
+	return criticalFiles[filepath.Base(path)]
+}
+
+// writeFileAtomic writes data to a temporary file in the destination directory
+// and renames it into place, so a crash or full disk never leaves a truncated file
+// With syncData set the temporary file is fsynced before the rename
+func writeFileAtomic(path string, data []byte, perm os.FileMode, syncData bool) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary file: %w", err)
+	}
+	tmpPath := tmp.Name()
+
+	// Remove the temporary file unless it has been renamed into place
+	renamed := false
+	defer func() {
+		if !renamed {
+			os.Remove(tmpPath)
+		}
+	}()
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write temporary file: %w", err)
+	}
+	if syncData {
+		if err := tmp.Sync(); err != nil {
+			tmp.Close()
+			return fmt.Errorf("failed to sync temporary file: %w", err)
+		}
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to close temporary file: %w", err)
+	}
+	if err := os.Chmod(tmpPath, perm); err != nil {
+		return fmt.Errorf("failed to set file mode: %w", err)
+	}
+
+	if err := os.Rename(tmpPath, path); err != nil {
+		return fmt.Errorf("failed to rename temporary file: %w", err)
+	}
+	renamed = true
+
+	return nil
+}
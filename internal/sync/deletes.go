@@ -0,0 +1,42 @@
+package sync
+
+import (
+	"errors"
+	"fmt"
+
+	"cursor-sync/internal/logger"
+)
+
+// ErrTooManyDeletes is returned when a sync would delete more files than
+// sync.max_deletes or sync.max_delete_percent allow
+var ErrTooManyDeletes = errors.New("too many deletions")
+
+// minDeletesForPercent is the fewest deletions the percentage limit applies to,
+// so removing a couple of files from a small profile never trips it
+const minDeletesForPercent = 10
+
+// ConfirmDeletes lets syncs propagate deletions over the mass-delete limits
+func (s *Syncer) ConfirmDeletes(confirm bool) {
+	s.confirmDeletes = confirm
+}
+
+// checkDeleteThreshold refuses to delete more than sync.max_deletes files, or more than
+// sync.max_delete_percent of the tracked files, unless deletions were confirmed
+// Refused deletions are recorded in the status file for the status command
+func (s *Syncer) checkDeleteThreshold(operation, where string, deletes, tracked int) error {
+	maxDeletes := s.config.Sync.MaxDeletes
+	maxPercent := s.config.Sync.MaxDeletePercent
+	overCount := maxDeletes > 0 && deletes > maxDeletes
+	overPercent := maxPercent > 0 && deletes >= minDeletesForPercent && deletes*100 > tracked*maxPercent
+	if deletes == 0 || s.confirmDeletes || (!overCount && !overPercent) {
+		clearHeldDeletes(operation)
+		return nil
+	}
+
+	detail := fmt.Sprintf("%d of %d files would be deleted %s", deletes, tracked, where)
+	recordHeldDeletes(operation, detail)
+	logger.Warn("🛑 Sync would delete %d of %d files %s - nothing was deleted", deletes, tracked, where)
+	logger.Warn("🛑 If this is intended, run 'cursor-sync sync --confirm-deletes'")
+	return fmt.Errorf("%w: %s (sync.max_deletes: %d, sync.max_delete_percent: %d%%) - "+
+		"rerun with --confirm-deletes if this is intended", ErrTooManyDeletes, detail, maxDeletes, maxPercent)
+}
@@ -0,0 +1,68 @@
+package sync
+
+import (
+	pathpkg "path"
+	"path/filepath"
+	"strings"
+
+	"cursor-sync/internal/git"
+	"cursor-sync/internal/ignore"
+)
+
+// syncMarkerFile marks a Cursor installation whose settings have been synced before
+// It is local state and never synced
+const syncMarkerFile = git.SyncMarkerFile
+
+// osJunkPatterns are file names operating systems scatter into folders
+var osJunkPatterns = []string{".DS_Store", "._*", "Thumbs.db", "ehthumbs.db", "desktop.ini"}
+
+// isOSJunk reports whether a file is operating system metadata (.DS_Store, Thumbs.db, ...)
+func isOSJunk(path string) bool {
+	name := filepath.Base(path)
+	for _, pattern := range osJunkPatterns {
+		if matched, _ := pathpkg.Match(pattern, name); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// gitIgnorePatterns returns the .gitignore patterns of the clone: the files excludeReason
+// always excludes, plus cursor.exclude_paths anchored at every target's folder
+// Exclusions overlapping globalStorage (workspaceStorage) are left out while
+// cursor.global_storage_allow (cursor.workspace_allow) is set - git can't re-include
+// files in an ignored folder, so the allowed files would never be committed
+func (s *Syncer) gitIgnorePatterns() []string {
+	patterns := append([]string{syncMarkerFile, ignore.FileName}, osJunkPatterns...)
+
+	for _, target := range s.config.Cursor.SyncTargets() {
+		prefix := pathpkg.Join("/", filepath.ToSlash(s.config.Repository.Subdir), filepath.ToSlash(target.Subdir))
+		for _, exclude := range s.config.Cursor.ExcludePaths {
+			exclude = filepath.ToSlash(exclude)
+			if len(s.config.Cursor.GlobalStorageAllow) > 0 &&
+				(strings.HasPrefix(globalStoragePrefix, exclude) || strings.HasPrefix(exclude, globalStoragePrefix)) {
+				continue
+			}
+			if len(s.config.Cursor.WorkspaceAllow) > 0 &&
+				(strings.HasPrefix(workspaceStoragePrefix, exclude) || strings.HasPrefix(exclude, workspaceStoragePrefix)) {
+				continue
+			}
+
+			pattern := pathpkg.Join(prefix, exclude)
+			if strings.HasSuffix(exclude, "/") {
+				pattern += "/"
+			}
+			patterns = append(patterns, pattern)
+		}
+	}
+	return patterns
+}
+
+// writeGitIgnore brings the generated block of the clone's .gitignore up to date
+// A receiving mirror never commits, so its clone is left as the remote has it
+func (s *Syncer) writeGitIgnore() error {
+	if !s.config.Sync.Pushes() {
+		return nil
+	}
+	return s.repo.WriteGitIgnore(s.gitIgnorePatterns())
+}
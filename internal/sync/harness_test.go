@@ -0,0 +1,147 @@
+package sync
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	gogit "github.com/go-git/go-git/v5"
+
+	"cursor-sync/internal/config"
+	"cursor-sync/internal/git"
+)
+
+// fixedPrivacy is a privacy checker answering without the GitHub API: true = private
+type fixedPrivacy bool
+
+func (p fixedPrivacy) CheckRepositoryPrivacy(string) (bool, error) { return bool(p), nil }
+func (fixedPrivacy) ForceRefresh()                                 {}
+
+// testRemote is a bare repository on disk standing in for the GitHub sync repository
+type testRemote struct {
+	t   *testing.T
+	dir string
+	// Makes the privacy check of machines created afterwards report a public repository
+	public bool
+}
+
+// newTestRemote creates an empty remote. The state directory is moved to a temporary
+// home so sync history of the test machines doesn't leak into the user's
+func newTestRemote(t *testing.T) *testRemote {
+	t.Helper()
+	t.Setenv("HOME", t.TempDir())
+
+	dir := t.TempDir()
+	if _, err := gogit.PlainInit(dir, true); err != nil {
+		t.Fatal(err)
+	}
+	return &testRemote{t: t, dir: dir}
+}
+
+// testMachine is one computer syncing its Cursor settings through a testRemote
+type testMachine struct {
+	t         *testing.T
+	cursorDir string
+	syncer    *Syncer
+}
+
+// newMachine creates a machine with its own Cursor directory and clone. configure may
+// adjust the configuration before the syncer is created
+func (r *testRemote) newMachine(configure func(*config.Config)) *testMachine {
+	r.t.Helper()
+	cursorDir := r.t.TempDir()
+	if err := os.MkdirAll(filepath.Join(cursorDir, "User"), 0755); err != nil {
+		r.t.Fatal(err)
+	}
+
+	cfg := &config.Config{
+		Repository: config.Repository{
+			URL:       r.dir,
+			Branch:    "main",
+			LocalPath: filepath.Join(r.t.TempDir(), "repo"),
+		},
+		Cursor: config.Cursor{ConfigPath: cursorDir},
+		Sync: config.Sync{
+			ConflictResolve:    "newer",
+			AutoPush:           true,
+			Mode:               config.ModeBidirectional,
+			HashPollingTimeout: 10 * time.Second,
+		},
+	}
+	if configure != nil {
+		configure(cfg)
+	}
+
+	repo := git.NewLocal(cfg.Repository.LocalPath, "origin", cfg.Repository.Branch, cfg.Repository.URL)
+	s := newSyncer(cfg, repo, fixedPrivacy(!r.public))
+	r.t.Cleanup(func() { s.Close() })
+	return &testMachine{t: r.t, cursorDir: cursorDir, syncer: s}
+}
+
+// write changes a file in the machine's Cursor directory, relative to it
+func (m *testMachine) write(name, content string) {
+	m.t.Helper()
+	path := filepath.Join(m.cursorDir, filepath.FromSlash(name))
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		m.t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		m.t.Fatal(err)
+	}
+}
+
+// read returns a file in the machine's Cursor directory, or "" when it doesn't exist
+func (m *testMachine) read(name string) string {
+	m.t.Helper()
+	data, err := os.ReadFile(filepath.Join(m.cursorDir, filepath.FromSlash(name)))
+	if os.IsNotExist(err) {
+		return ""
+	}
+	if err != nil {
+		m.t.Fatal(err)
+	}
+	return string(data)
+}
+
+// initialize runs the machine's first sync
+func (m *testMachine) initialize() {
+	m.t.Helper()
+	if err := m.syncer.Initialize(); err != nil {
+		m.t.Fatalf("Initialize() error = %v", err)
+	}
+}
+
+// join runs the first sync of a machine whose clone was made before it ever synced, as
+// when the repository was set up earlier: without a sync marker it takes the remote settings
+func (m *testMachine) join() {
+	m.t.Helper()
+	if err := m.syncer.repo.Clone(m.syncer.config.Repository.URL); err != nil {
+		m.t.Fatalf("Clone() error = %v", err)
+	}
+	m.initialize()
+}
+
+// push runs SyncToRemote
+func (m *testMachine) push() {
+	m.t.Helper()
+	if err := m.syncer.SyncToRemote(); err != nil {
+		m.t.Fatalf("SyncToRemote() error = %v", err)
+	}
+}
+
+// pull runs SyncFromRemote
+func (m *testMachine) pull() {
+	m.t.Helper()
+	if err := m.syncer.SyncFromRemote(); err != nil {
+		m.t.Fatalf("SyncFromRemote() error = %v", err)
+	}
+}
+
+// remove deletes a file in the machine's Cursor directory
+func (m *testMachine) remove(name string) {
+	m.t.Helper()
+	if err := os.Remove(filepath.Join(m.cursorDir, filepath.FromSlash(name))); err != nil {
+		m.t.Fatal(err)
+	}
+}
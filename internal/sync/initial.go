@@ -0,0 +1,170 @@
+package sync
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"cursor-sync/internal/config"
+	"cursor-sync/internal/git"
+	"cursor-sync/internal/logger"
+	"cursor-sync/internal/paths"
+)
+
+// initialConflict is a local file the initial overwrite would replace although it differs
+// from the repository's version and was changed after the repository's last commit
+type initialConflict struct {
+	target  config.Target
+	relPath string // Relative to the User directory
+	name    string // Repository-relative, as recorded in the conflict log
+	reset   bool   // The target's marker was reset: the repository's version always wins
+}
+
+// SetInitialConflictPrompt sets the function used to ask the user whether the initial
+// overwrite keeps ("local") or replaces ("remote") local files that are newer than the
+// repository. Without it sync.conflict_resolve decides
+func (s *Syncer) SetInitialConflictPrompt(prompt func(files []string) string) {
+	s.initialConflictPrompt = prompt
+}
+
+// findInitialConflicts compares the never-synced targets with the repository
+// Without the repository's last commit time every differing local file counts as newer
+func (s *Syncer) findInitialConflicts() []initialConflict {
+	var remoteTime time.Time
+	if s.repo != nil {
+		var err error
+		if remoteTime, err = s.repo.GetLastCommitTime(); err != nil {
+			logger.Debug("Failed to get the repository's last commit time: %v", err)
+		}
+	}
+
+	var conflicts []initialConflict
+	for _, target := range s.config.Cursor.SyncTargets() {
+		if s.hasTargetSyncMarker(target) {
+			continue
+		}
+		reset := markerResetRequested(target)
+
+		userPath, repoUserPath := s.targetUserPaths(target)
+		filepath.Walk(repoUserPath, func(path string, info os.FileInfo, err error) error {
+			if err != nil || info.IsDir() || isSymlink(info) {
+				return nil
+			}
+			relPath, err := filepath.Rel(repoUserPath, path)
+			if err != nil || isSQLiteSidecar(relPath) {
+				return nil
+			}
+			// LFS pointers can't be compared with the local content
+			if _, ok := readLFSPointer(path); ok {
+				return nil
+			}
+
+			localPath := filepath.Join(userPath, relPath)
+			localInfo, err := os.Stat(localPath)
+			if err != nil || !localInfo.ModTime().After(remoteTime) {
+				return nil
+			}
+			if differs, _ := s.copyDecision(path, localPath, info); differs {
+				conflicts = append(conflicts, initialConflict{
+					target:  target,
+					relPath: relPath,
+					name:    filepath.ToSlash(filepath.Join(s.config.Repository.Subdir, target.Subdir, "User", relPath)),
+					reset:   reset,
+				})
+			}
+			return nil
+		})
+	}
+	return conflicts
+}
+
+// resolveInitialConflicts decides whether the initial overwrite keeps local files that
+// are newer than the repository and returns the names of those to keep
+// The user is asked when a prompt is set. Otherwise sync.conflict_resolve decides: "newer"
+// and "local" keep them for the next push to publish, erring towards caution since a
+// missing marker may also mean it was lost, and only "remote" overwrites them. Local
+// versions are backed up before they are overwritten, also in targets whose marker was
+// reset, which are never kept
+func (s *Syncer) resolveInitialConflicts() (map[string]bool, error) {
+	var conflicts, reset []initialConflict
+	for _, conflict := range s.findInitialConflicts() {
+		if conflict.reset {
+			reset = append(reset, conflict)
+		} else {
+			conflicts = append(conflicts, conflict)
+		}
+	}
+
+	// 'marker --reset' promised an overwrite from the repository
+	if len(reset) > 0 {
+		logger.Info("🔄 Sync marker was reset - overwriting %d newer local file(s) as well", len(reset))
+		if _, err := backupInitialConflicts(reset); err != nil {
+			return nil, fmt.Errorf("failed to back up local settings before the initial overwrite: %w", err)
+		}
+	}
+	if len(conflicts) == 0 {
+		return nil, nil
+	}
+
+	files := make([]string, len(conflicts))
+	for i, conflict := range conflicts {
+		files[i] = conflict.name
+	}
+	logger.Warn("⚠️  %d local file(s) differ from the repository and changed after its last commit: %s",
+		len(files), summarizeFiles(files, 5))
+
+	winner := "remote"
+	if s.initialConflictPrompt != nil {
+		winner = s.initialConflictPrompt(files)
+	} else if s.config.Sync.ConflictResolve != "remote" {
+		winner = "local"
+	}
+
+	event := git.ConflictEvent{Time: time.Now(), Strategy: s.config.Sync.ConflictResolve, Winner: winner, Files: files}
+	if winner == "remote" {
+		backup, err := backupInitialConflicts(conflicts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to back up local settings before the initial overwrite: %w", err)
+		}
+		event.Backup = backup
+		s.handleConflict(event)
+		return nil, nil
+	}
+
+	logger.Info("🛡️  Keeping %d newer local file(s) - the next push publishes them", len(files))
+	s.handleConflict(event)
+
+	keep := make(map[string]bool, len(files))
+	for _, file := range files {
+		keep[file] = true
+	}
+	return keep, nil
+}
+
+// backupInitialConflicts copies the local files to backups/<timestamp> in the state
+// directory and returns the backup directory
+func backupInitialConflicts(conflicts []initialConflict) (string, error) {
+	stateDir, err := paths.StateDir()
+	if err != nil {
+		return "", err
+	}
+	backupDir := filepath.Join(stateDir, "backups", time.Now().Format("20060102-150405"))
+
+	for _, conflict := range conflicts {
+		data, err := os.ReadFile(filepath.Join(conflict.target.ConfigPath, "User", conflict.relPath))
+		if err != nil {
+			return "", err
+		}
+		dest := filepath.Join(backupDir, filepath.FromSlash(conflict.name))
+		if err := os.MkdirAll(filepath.Dir(dest), 0755); err != nil {
+			return "", fmt.Errorf("failed to create backup directory: %w", err)
+		}
+		if err := os.WriteFile(dest, data, 0644); err != nil {
+			return "", fmt.Errorf("failed to back up %s: %w", conflict.name, err)
+		}
+	}
+
+	logger.Info("💾 Backed up %d local file(s) to %s", len(conflicts), backupDir)
+	return backupDir, nil
+}
@@ -0,0 +1,314 @@
+package sync
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	gogit "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+
+	"cursor-sync/internal/config"
+)
+
+func TestSyncBetweenMachines(t *testing.T) {
+	remote := newTestRemote(t)
+
+	laptop := remote.newMachine(nil)
+	laptop.write("User/settings.json", `{"editor.fontSize": 14}`)
+	laptop.initialize()
+
+	desktop := remote.newMachine(nil)
+	desktop.join()
+	desktop.pull()
+	if got := desktop.read("User/settings.json"); got != `{"editor.fontSize": 14}` {
+		t.Fatalf("desktop settings after first pull = %q", got)
+	}
+
+	// Same size as before: only the content hash tells the versions apart
+	laptop.write("User/settings.json", `{"editor.fontSize": 16}`)
+	laptop.write("User/snippets/go.json", `{"Print": {"prefix": "pr", "body": ["fmt.Println($1)"]}}`)
+	laptop.push()
+	desktop.pull()
+	if got := desktop.read("User/settings.json"); got != `{"editor.fontSize": 16}` {
+		t.Errorf("desktop settings = %q, want the laptop's edit", got)
+	}
+	if got := desktop.read("User/snippets/go.json"); got == "" {
+		t.Error("snippet added on the laptop didn't reach the desktop")
+	}
+
+	desktop.remove("User/snippets/go.json")
+	desktop.push()
+	laptop.pull()
+	if got := laptop.read("User/snippets/go.json"); got != "" {
+		t.Errorf("snippet deleted on the desktop still on the laptop: %q", got)
+	}
+}
+
+func TestFreshMachineTakesSettingsAlreadyPushed(t *testing.T) {
+	remote := newTestRemote(t)
+	laptop := remote.newMachine(nil)
+	laptop.write("User/settings.json", `{"editor.fontSize": 14}`)
+	laptop.write("User/keybindings.json", `[]`)
+	laptop.initialize()
+
+	// A new machine clones on its first sync; pushing what it has (nothing) would delete
+	// the laptop's settings from the repository
+	desktop := remote.newMachine(nil)
+	desktop.initialize()
+	if got := desktop.read("User/settings.json"); got != `{"editor.fontSize": 14}` {
+		t.Errorf("desktop settings = %q, want the laptop's", got)
+	}
+
+	laptop.pull()
+	if got := laptop.read("User/keybindings.json"); got != `[]` {
+		t.Errorf("laptop keybindings = %q, want them kept", got)
+	}
+}
+
+func TestSyncRefusesPublicRepository(t *testing.T) {
+	remote := newTestRemote(t)
+	remote.public = true
+	laptop := remote.newMachine(nil)
+	laptop.write("User/settings.json", `{"editor.fontSize": 14}`)
+
+	if err := laptop.syncer.Initialize(); err == nil {
+		t.Fatal("Initialize() succeeded for a public repository")
+	}
+	bare, err := gogit.PlainOpen(remote.dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := bare.Head(); err == nil {
+		t.Error("settings were pushed to a public repository")
+	}
+}
+
+func TestSyncResolvesConflictingEdits(t *testing.T) {
+	tests := []struct {
+		strategy string
+		want     string
+	}{
+		{"local", `{"editor.fontSize": 12}`},
+		{"remote", `{"editor.fontSize": 16}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.strategy, func(t *testing.T) {
+			remote := newTestRemote(t)
+			useStrategy := func(cfg *config.Config) { cfg.Sync.ConflictResolve = tt.strategy }
+
+			laptop := remote.newMachine(useStrategy)
+			laptop.write("User/settings.json", `{"editor.fontSize": 14}`)
+			laptop.initialize()
+			desktop := remote.newMachine(useStrategy)
+			desktop.join()
+			desktop.pull()
+
+			// Both machines change the same file; the desktop pushes second
+			laptop.write("User/settings.json", `{"editor.fontSize": 16}`)
+			desktop.write("User/settings.json", `{"editor.fontSize": 12}`)
+			laptop.push()
+			desktop.push()
+
+			laptop.pull()
+			desktop.pull()
+			for name, m := range map[string]*testMachine{"laptop": laptop, "desktop": desktop} {
+				if got := m.read("User/settings.json"); got != tt.want {
+					t.Errorf("%s settings = %q, want %q", name, got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestSyncMergesKeybindingsAddedOnBothMachines(t *testing.T) {
+	remote := newTestRemote(t)
+
+	laptop := remote.newMachine(nil)
+	laptop.write("User/keybindings.json", `[
+    {"key": "cmd+shift+d", "command": "editor.action.copyLinesDownAction"}
+]`)
+	laptop.initialize()
+	desktop := remote.newMachine(nil)
+	desktop.join()
+	desktop.pull()
+
+	laptop.write("User/keybindings.json", `[
+    {"key": "cmd+shift+d", "command": "editor.action.copyLinesDownAction"},
+    {"key": "ctrl+shift+t", "command": "workbench.action.terminal.new"}
+]`)
+	desktop.write("User/keybindings.json", `[
+    {"key": "cmd+shift+d", "command": "editor.action.copyLinesDownAction"},
+    {"key": "cmd+alt+v", "command": "markdown.showPreviewToSide"}
+]`)
+	laptop.push()
+	desktop.push()
+	laptop.pull()
+	desktop.pull()
+
+	for name, m := range map[string]*testMachine{"laptop": laptop, "desktop": desktop} {
+		got := m.read("User/keybindings.json")
+		for _, command := range []string{"workbench.action.terminal.new", "markdown.showPreviewToSide"} {
+			if !strings.Contains(got, command) {
+				t.Errorf("%s keybindings lost %s:\n%s", name, command, got)
+			}
+		}
+	}
+}
+
+func TestSyncWithPushLease(t *testing.T) {
+	remote := newTestRemote(t)
+	withLease := func(cfg *config.Config) { cfg.Sync.PushLease = time.Minute }
+
+	laptop := remote.newMachine(withLease)
+	laptop.write("User/settings.json", `{"editor.fontSize": 14}`)
+	laptop.initialize()
+	desktop := remote.newMachine(withLease)
+	desktop.join()
+
+	laptop.write("User/settings.json", `{"editor.fontSize": 16}`)
+	laptop.push()
+	desktop.pull()
+	if got := desktop.read("User/settings.json"); got != `{"editor.fontSize": 16}` {
+		t.Errorf("desktop settings = %q, want the laptop's edit", got)
+	}
+
+	// Leases are released once the push is done
+	bare, err := gogit.PlainOpen(remote.dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	refs, err := bare.References()
+	if err != nil {
+		t.Fatal(err)
+	}
+	refs.ForEach(func(ref *plumbing.Reference) error {
+		if strings.HasPrefix(ref.Name().String(), "refs/cursor-sync-lock/") {
+			t.Errorf("lease %s left on the remote", ref.Name())
+		}
+		return nil
+	})
+}
+
+func TestStatusFileRecordsCommitsPendingPush(t *testing.T) {
+	remote := newTestRemote(t)
+	laptop := remote.newMachine(func(cfg *config.Config) { cfg.Sync.AutoPush = false })
+	laptop.write("User/settings.json", `{"editor.fontSize": 14}`)
+	laptop.initialize()
+
+	laptop.write("User/settings.json", `{"editor.fontSize": 16}`)
+	laptop.push()
+	status, err := ReadStatus()
+	if err != nil {
+		t.Fatalf("ReadStatus() error = %v", err)
+	}
+	if pending, _ := laptop.syncer.PendingPushCount(); pending == 0 || status.PendingPush != pending {
+		t.Errorf("pending push after held commits = %d, want %d", status.PendingPush, pending)
+	}
+
+	if _, err := laptop.syncer.PushPending(); err != nil {
+		t.Fatalf("PushPending() error = %v", err)
+	}
+	if status, err = ReadStatus(); err != nil {
+		t.Fatalf("ReadStatus() error = %v", err)
+	}
+	if status.PendingPush != 0 || status.LastPushed.IsZero() {
+		t.Errorf("after pushing: pending push = %d, last pushed = %v, want 0 and set", status.PendingPush, status.LastPushed)
+	}
+}
+
+func TestStatusFileRecordsHeldDeletes(t *testing.T) {
+	remote := newTestRemote(t)
+	laptop := remote.newMachine(func(cfg *config.Config) { cfg.Sync.MaxDeletes = 1 })
+	laptop.write("User/settings.json", `{"editor.fontSize": 14}`)
+	laptop.write("User/snippets/go.json", `{}`)
+	laptop.write("User/snippets/rust.json", `{}`)
+	laptop.initialize()
+
+	laptop.remove("User/snippets/go.json")
+	laptop.remove("User/snippets/rust.json")
+	if err := laptop.syncer.SyncToRemote(); !errors.Is(err, ErrTooManyDeletes) {
+		t.Fatalf("SyncToRemote() error = %v, want ErrTooManyDeletes", err)
+	}
+	status, err := ReadStatus()
+	if err != nil {
+		t.Fatalf("ReadStatus() error = %v", err)
+	}
+	if held, ok := status.HeldDeletes["push"]; !ok || !strings.Contains(held.Detail, "2 of") {
+		t.Errorf("held deletes after the guard tripped = %+v, want the push's 2 deletions", status.HeldDeletes)
+	}
+
+	laptop.syncer.ConfirmDeletes(true)
+	laptop.push()
+	if status, err = ReadStatus(); err != nil {
+		t.Fatalf("ReadStatus() error = %v", err)
+	}
+	if len(status.HeldDeletes) != 0 {
+		t.Errorf("held deletes after confirming = %+v, want none", status.HeldDeletes)
+	}
+}
+
+func TestScopedSyncLeavesOtherFilesAlone(t *testing.T) {
+	remote := newTestRemote(t)
+	laptop := remote.newMachine(nil)
+	laptop.write("User/settings.json", `{"editor.fontSize": 14}`)
+	laptop.write("User/keybindings.json", `[]`)
+	laptop.initialize()
+	desktop := remote.newMachine(nil)
+	desktop.join()
+
+	// Only settings.json is pushed; the deleted keybindings stay in the repository
+	laptop.write("User/settings.json", `{"editor.fontSize": 16}`)
+	laptop.remove("User/keybindings.json")
+	laptop.syncer.SetScope([]string{"User/settings.json"}, nil)
+	laptop.push()
+	laptop.syncer.SetScope(nil, nil)
+
+	desktop.pull()
+	if got := desktop.read("User/settings.json"); got != `{"editor.fontSize": 16}` {
+		t.Errorf("desktop settings = %q, want the laptop's edit", got)
+	}
+	if got := desktop.read("User/keybindings.json"); got != `[]` {
+		t.Errorf("desktop keybindings = %q, want them kept by the scoped push", got)
+	}
+
+	// A pull leaving settings.json out keeps the local version
+	desktop.write("User/settings.json", `{"editor.fontSize": 12}`)
+	laptop.write("User/settings.json", `{"editor.fontSize": 18}`)
+	laptop.push()
+	desktop.syncer.SetScope(nil, []string{"User/settings.json"})
+	desktop.pull()
+	if got := desktop.read("User/settings.json"); got != `{"editor.fontSize": 12}` {
+		t.Errorf("desktop settings = %q, want the local version outside the pull's scope", got)
+	}
+}
+
+func TestSyncCommitsUseConfiguredAuthor(t *testing.T) {
+	remote := newTestRemote(t)
+	laptop := remote.newMachine(func(cfg *config.Config) {
+		cfg.Sync.CommitAuthorName = "Jane Doe"
+		cfg.Sync.CommitAuthorEmail = "jane@example.com"
+	})
+	laptop.write("User/settings.json", `{"editor.fontSize": 14}`)
+	laptop.initialize()
+	laptop.write("User/settings.json", `{"editor.fontSize": 16}`)
+	laptop.push()
+
+	bare, err := gogit.PlainOpen(remote.dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ref, err := bare.Reference(plumbing.NewBranchReferenceName("main"), true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	commit, err := bare.CommitObject(ref.Hash())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if commit.Author.Name != "Jane Doe" || commit.Author.Email != "jane@example.com" {
+		t.Errorf("commit author = %s <%s>, want Jane Doe <jane@example.com>", commit.Author.Name, commit.Author.Email)
+	}
+}
@@ -0,0 +1,139 @@
+package sync
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"cursor-sync/internal/config"
+	"cursor-sync/internal/git"
+	"cursor-sync/internal/logger"
+)
+
+// enableLFS turns on Git LFS for the clone when sync.use_lfs is set
+func (s *Syncer) enableLFS() error {
+	if !s.config.Sync.UseLFS {
+		return nil
+	}
+
+	if err := s.repo.EnableLFS(s.config.Sync.LFSPatterns); err != nil {
+		if errors.Is(err, git.ErrLFSNotInstalled) {
+			return fmt.Errorf("sync.use_lfs is enabled but %w - install it from https://git-lfs.com or disable sync.use_lfs", err)
+		}
+		return fmt.Errorf("failed to enable Git LFS: %w", err)
+	}
+	return nil
+}
+
+// lfsTracked reports whether a file in the clone is stored in Git LFS
+func (s *Syncer) lfsTracked(repoPath string) bool {
+	if s.repo == nil || !s.repo.LFSEnabled() {
+		return false
+	}
+	relPath, err := filepath.Rel(s.config.Repository.LocalPath, repoPath)
+	if err != nil {
+		return false
+	}
+	return s.repo.LFSTracked(relPath)
+}
+
+// readLFSPointer returns the pointer stored in a file of the clone, if it is one
+// Pointers are recognised even with sync.use_lfs off, so another machine's LFS files
+// are never copied over local settings as pointer text
+func readLFSPointer(path string) (git.LFSPointer, bool) {
+	info, err := os.Lstat(path)
+	if err != nil || !info.Mode().IsRegular() || info.Size() > 1024 {
+		return git.LFSPointer{}, false
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return git.LFSPointer{}, false
+	}
+	return git.ParseLFSPointer(data)
+}
+
+// copyToLFS stores src as an LFS object and writes its pointer to dest in the clone
+// Returns the copy decision (DecisionSkipped when the pointer is unchanged)
+func (s *Syncer) copyToLFS(src, dest string) (string, error) {
+	data, err := os.ReadFile(src)
+	if err != nil {
+		return "", fmt.Errorf("failed to read source file: %w", err)
+	}
+
+	decision := DecisionNew
+	if existing, err := os.ReadFile(dest); err == nil {
+		if bytes.Equal(existing, git.NewLFSPointer(data).Encode()) {
+			return DecisionSkipped, nil
+		}
+		decision = DecisionHashDiff
+	}
+
+	pointer, err := s.repo.LFSClean(data)
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(filepath.Dir(dest), 0755); err != nil {
+		return "", fmt.Errorf("failed to create destination directory: %w", err)
+	}
+	if err := writeFileAtomic(dest, pointer, 0644, false); err != nil {
+		return "", fmt.Errorf("failed to write LFS pointer: %w", err)
+	}
+	return decision, nil
+}
+
+// copyFromLFS writes the content an LFS pointer refers to over dest
+// Unless forced, dest is left alone when it already has that content
+// Returns the copy decision; DecisionLFSMissing when the object hasn't been downloaded
+func (s *Syncer) copyFromLFS(pointer git.LFSPointer, dest string, force bool) (string, error) {
+	decision := DecisionForced
+	if !force {
+		decision = DecisionNew
+		if existing, err := os.ReadFile(dest); err == nil {
+			if git.NewLFSPointer(existing) == pointer {
+				return DecisionSkipped, nil
+			}
+			decision = DecisionHashDiff
+		}
+	}
+
+	data, err := s.repo.ReadLFSObject(pointer)
+	if errors.Is(err, git.ErrLFSObjectMissing) {
+		return DecisionLFSMissing, nil
+	}
+	if err != nil {
+		return "", err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(dest), 0755); err != nil {
+		return "", fmt.Errorf("failed to create destination directory: %w", err)
+	}
+	if err := writeFileAtomic(dest, data, 0644, isCriticalFile(dest)); err != nil {
+		return "", fmt.Errorf("failed to write destination file: %w", err)
+	}
+	return decision, nil
+}
+
+// restoreLFSFile copies the content of an LFS pointer in the clone to destPath, logging
+// and recording the outcome. Returns the copy decision; DecisionCopyFailed when it failed
+func (s *Syncer) restoreLFSFile(target config.Target, relPath string, pointer git.LFSPointer, destPath string, force bool, started time.Time) string {
+	decision, err := s.copyFromLFS(pointer, destPath, force)
+	if err != nil {
+		logger.Warn("Failed to copy Git LFS file %s: %v", relPath, err)
+		decision = DecisionCopyFailed
+	} else if decision == DecisionLFSMissing {
+		logger.Warn("📦 Git LFS object of %s hasn't been downloaded - keeping the local file", relPath)
+	}
+	s.recordDecision(filepath.Join(target.Subdir, relPath), "repo-to-local", decision, started, err)
+	return decision
+}
+
+// fetchLFSObjects downloads the LFS objects of the pulled branch
+// Failures only delay the affected files, which copyFromRepository leaves alone until then
+func (s *Syncer) fetchLFSObjects() {
+	if err := s.repo.FetchLFSObjects(); err != nil {
+		logger.Warn("Failed to download Git LFS objects: %v", err)
+	}
+}
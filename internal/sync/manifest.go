@@ -0,0 +1,141 @@
+package sync
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/go-git/go-git/v5/plumbing/object"
+
+	"cursor-sync/internal/config"
+	"cursor-sync/internal/logger"
+)
+
+// manifestPath is the file manifest, relative to the repository content directory
+const manifestPath = ".cursor-sync/manifest.json"
+
+// ManifestEntry records which machine last changed a synced file
+type ManifestEntry struct {
+	Host     string    `json:"last_hostname"`
+	Modified time.Time `json:"last_modified"`
+	Hash     string    `json:"hash"` // SHA-256 of the content that machine pushed
+}
+
+// String describes the entry for humans
+func (e ManifestEntry) String() string {
+	return fmt.Sprintf("last changed by %s at %s", e.Host, e.Modified.Local().Format("2006-01-02 15:04:05"))
+}
+
+// manifest maps content-relative paths (e.g. "User/settings.json") to their entries
+type manifest map[string]ManifestEntry
+
+// manifestKey returns the manifest key of a file below a target's User directory
+func manifestKey(target config.Target, relPath string) string {
+	return filepath.ToSlash(filepath.Join(target.Subdir, "User", relPath))
+}
+
+// parseManifest decodes a manifest file
+func parseManifest(data []byte) (manifest, error) {
+	m := manifest{}
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", manifestPath, err)
+	}
+	return m, nil
+}
+
+// loadManifest reads the manifest from the local clone
+// A missing or unreadable manifest yields an empty one, which is rebuilt as files change
+func (s *Syncer) loadManifest() manifest {
+	data, err := os.ReadFile(filepath.Join(s.config.Repository.ContentPath(), manifestPath))
+	if err != nil {
+		if !os.IsNotExist(err) {
+			logger.Warn("Failed to read file manifest: %v", err)
+		}
+		return manifest{}
+	}
+
+	m, err := parseManifest(data)
+	if err != nil {
+		logger.Warn("Ignoring corrupt file manifest: %v", err)
+		return manifest{}
+	}
+	return m
+}
+
+// saveManifest writes the manifest to the local clone so it is committed with the files
+func (s *Syncer) saveManifest(m manifest) error {
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal file manifest: %w", err)
+	}
+
+	path := filepath.Join(s.config.Repository.ContentPath(), manifestPath)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create manifest directory: %w", err)
+	}
+	return writeFileAtomic(path, append(data, '\n'), 0644, false)
+}
+
+// record marks a file just copied into the repository as changed by this machine
+func (m manifest) record(key, path, host string) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		logger.Debug("Failed to hash %s for the file manifest: %v", key, err)
+		return
+	}
+	m[key] = ManifestEntry{
+		Host:     host,
+		Modified: time.Now().UTC().Truncate(time.Second),
+		Hash:     fmt.Sprintf("%x", sha256.Sum256(data)),
+	}
+}
+
+// remoteManifest reads the manifest from the remote-tracking branch
+// A remote without a manifest yields an empty one
+func (s *Syncer) remoteManifest() (manifest, error) {
+	path := filepath.ToSlash(filepath.Join(s.config.Repository.Subdir, manifestPath))
+	data, err := s.repo.RemoteFile(path)
+	if errors.Is(err, object.ErrFileNotFound) {
+		return manifest{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return parseManifest(data)
+}
+
+// conflictAttribution returns the machine that last changed each conflicting file on the
+// remote, keyed by the repository-relative paths of the conflict event
+func (s *Syncer) conflictAttribution(files []string) map[string]string {
+	if len(files) == 0 {
+		return nil
+	}
+
+	m, err := s.remoteManifest()
+	if err != nil {
+		logger.Debug("Failed to read remote file manifest: %v", err)
+		return nil
+	}
+
+	prefix := ""
+	if s.config.Repository.Subdir != "" {
+		prefix = filepath.ToSlash(filepath.Clean(s.config.Repository.Subdir)) + "/"
+	}
+
+	hosts := make(map[string]string)
+	for _, file := range files {
+		if entry, ok := m[strings.TrimPrefix(file, prefix)]; ok && entry.Host != "" {
+			hosts[file] = entry.Host
+			logger.Info("📝 Remote %s was %s", file, entry)
+		}
+	}
+	if len(hosts) == 0 {
+		return nil
+	}
+	return hosts
+}
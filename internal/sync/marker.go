@@ -0,0 +1,92 @@
+package sync
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"cursor-sync/internal/config"
+)
+
+// MarkerStatus describes the sync marker of one sync target
+type MarkerStatus struct {
+	Target     string
+	Path       string
+	Exists     bool
+	ModTime    time.Time
+	LastSync   string // "Last sync" recorded in the marker ("" if missing)
+	Repository string // Repository recorded in the marker ("" if missing)
+}
+
+// markerResetFile next to the sync marker records a 'marker --reset': the next sync then
+// overwrites every local file, including ones that look newer than the repository's
+const markerResetFile = syncMarkerFile + ".reset"
+
+// syncMarkerPath returns where a target's sync marker lives
+func syncMarkerPath(target config.Target) string {
+	return filepath.Join(target.ConfigPath, syncMarkerFile)
+}
+
+// markerResetPath returns where a target's reset request lives
+func markerResetPath(target config.Target) string {
+	return filepath.Join(target.ConfigPath, markerResetFile)
+}
+
+// markerResetRequested reports whether the target's marker was reset and the forced
+// overwrite hasn't happened yet
+func markerResetRequested(target config.Target) bool {
+	_, err := os.Stat(markerResetPath(target))
+	return err == nil
+}
+
+// SyncMarkers reports the sync marker of every sync target
+// Without its marker, the next sync treats a target's local settings as never synced
+// and overwrites them from the repository
+func SyncMarkers(cfg *config.Config) []MarkerStatus {
+	var statuses []MarkerStatus
+	for _, target := range cfg.Cursor.SyncTargets() {
+		status := MarkerStatus{Target: target.Name, Path: syncMarkerPath(target)}
+
+		if info, err := os.Stat(status.Path); err == nil {
+			status.Exists = true
+			status.ModTime = info.ModTime()
+		}
+		if data, err := os.ReadFile(status.Path); err == nil {
+			for _, line := range strings.Split(string(data), "\n") {
+				if value, ok := strings.CutPrefix(line, "Last sync: "); ok {
+					status.LastSync = strings.TrimSpace(value)
+				} else if value, ok := strings.CutPrefix(line, "Repository: "); ok {
+					status.Repository = strings.TrimSpace(value)
+				}
+			}
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses
+}
+
+// ResetSyncMarkers deletes the sync marker of every sync target and returns the paths
+// removed. It also records the reset, so the next sync overwrites all local settings
+// from the repository rather than keeping local files that look newer
+func ResetSyncMarkers(cfg *config.Config) ([]string, error) {
+	var removed []string
+	for _, target := range cfg.Cursor.SyncTargets() {
+		resetPath := markerResetPath(target)
+		if err := os.WriteFile(resetPath, []byte("cursor-sync marker reset - the next sync overwrites local settings\n"), 0644); err != nil {
+			return removed, fmt.Errorf("failed to record the marker reset: %w", err)
+		}
+
+		markerPath := syncMarkerPath(target)
+		err := os.Remove(markerPath)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return removed, fmt.Errorf("failed to remove %s: %w", markerPath, err)
+		}
+		removed = append(removed, markerPath)
+	}
+	return removed, nil
+}
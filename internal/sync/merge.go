@@ -0,0 +1,77 @@
+package sync
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"strings"
+
+	"cursor-sync/internal/git"
+	"cursor-sync/internal/jsonc"
+)
+
+// mergeFile returns how a repository file that local and remote both changed is combined,
+// or nil when one side's version has to win. Keybindings are combined binding by binding
+// and snippet files snippet by snippet, so additions on both machines survive a conflict
+func mergeFile(name string) git.MergeFunc {
+	switch {
+	case path.Base(name) == "keybindings.json":
+		return jsonc.MergeKeybindings
+	case path.Base(path.Dir(name)) == "snippets" && (path.Ext(name) == ".json" || path.Ext(name) == ".code-snippets"):
+		return jsonc.MergeObjects
+	case name == manifestPath || strings.HasSuffix(name, "/"+manifestPath):
+		return mergeManifests
+	}
+	return nil
+}
+
+// mergeManifests combines the file manifests of both sides. Every push records its files,
+// so both sides change the manifest whenever they diverge; an entry both changed keeps
+// the more recent change
+func mergeManifests(base, local, remote []byte) ([]byte, error) {
+	baseManifest := manifest{}
+	if len(base) > 0 {
+		m, err := parseManifest(base)
+		if err != nil {
+			return nil, err
+		}
+		baseManifest = m
+	}
+	localManifest, err := parseManifest(local)
+	if err != nil {
+		return nil, err
+	}
+	merged, err := parseManifest(remote)
+	if err != nil {
+		return nil, err
+	}
+
+	for key, entry := range localManifest {
+		if baseEntry, ok := baseManifest[key]; ok && sameEntry(baseEntry, entry) {
+			continue // Unchanged locally
+		}
+		if remoteEntry, ok := merged[key]; ok && remoteEntry.Modified.After(entry.Modified) {
+			continue
+		}
+		merged[key] = entry
+	}
+	for key, baseEntry := range baseManifest {
+		if _, ok := localManifest[key]; ok {
+			continue
+		}
+		if remoteEntry, ok := merged[key]; ok && sameEntry(remoteEntry, baseEntry) {
+			delete(merged, key) // Removed locally, unchanged remotely
+		}
+	}
+
+	data, err := json.MarshalIndent(merged, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal file manifest: %w", err)
+	}
+	return append(data, '\n'), nil
+}
+
+// sameEntry reports whether two manifest entries record the same change
+func sameEntry(a, b ManifestEntry) bool {
+	return a.Host == b.Host && a.Hash == b.Hash && a.Modified.Equal(b.Modified)
+}
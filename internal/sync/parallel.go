@@ -0,0 +1,92 @@
+package sync
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"cursor-sync/internal/config"
+	"cursor-sync/internal/logger"
+)
+
+// pendingCopy is a file queued for copying into an empty repository directory
+type pendingCopy struct {
+	src     string
+	dest    string
+	relPath string
+	size    int64
+}
+
+// isEmptyDir reports whether dir is missing or has no entries
+func isEmptyDir(dir string) bool {
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return true
+	}
+	return err == nil && len(entries) == 0
+}
+
+// copyFilesParallel copies files without comparing them first, using as many goroutines
+// as there are hash workers, and returns the error of each copy
+func (s *Syncer) copyFilesParallel(copies []pendingCopy, progress *progressTracker) []error {
+	errs := make([]error, len(copies))
+
+	workers := s.hashWorkers
+	if workers < 1 {
+		workers = 1
+	}
+
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				errs[i] = s.copyFile(copies[i].src, copies[i].dest)
+				progress.add(copies[i].size)
+			}
+		}()
+	}
+
+	for i := range copies {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	return errs
+}
+
+// flushInitialCopies copies the files queued by an initial copyToRepository and records
+// them in the report and the file manifest. Returns how many files were copied
+func (s *Syncer) flushInitialCopies(target config.Target, copies []pendingCopy, fileManifest manifest, hostname string) int {
+	if len(copies) == 0 {
+		return 0
+	}
+
+	logger.Info("🚀 Initial sync: copying %d files to the repository without comparing", len(copies))
+	started := time.Now()
+	var totalBytes int64
+	for _, pending := range copies {
+		totalBytes += pending.size
+	}
+	progress := s.newProgress("Initial sync to repository", len(copies), totalBytes)
+	errs := s.copyFilesParallel(copies, progress)
+	progress.finish()
+
+	var filesCopied int
+	for i, pending := range copies {
+		reportPath := filepath.Join(target.Subdir, pending.relPath)
+		if errs[i] != nil {
+			logger.Warn("Failed to copy file %s: %v", pending.relPath, errs[i])
+			s.recordDecision(reportPath, "local-to-repo", DecisionCopyFailed, started, errs[i])
+			continue
+		}
+		filesCopied++
+		fileManifest.record(manifestKey(target, pending.relPath), pending.dest, hostname)
+		s.recordDecision(reportPath, "local-to-repo", DecisionNew, started, nil)
+	}
+	return filesCopied
+}
@@ -0,0 +1,169 @@
+package sync
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"cursor-sync/internal/logger"
+)
+
+// progressInterval is how often progress of a long-running copy or clone is reported
+const progressInterval = 2 * time.Second
+
+// SetProgressOutput prints the progress of long-running copies and clones to w (e.g.
+// os.Stderr for interactive commands). Without it progress is logged at info level
+func (s *Syncer) SetProgressOutput(w io.Writer) {
+	s.progressOutput = w
+	s.repo.SetCloneProgress(w)
+}
+
+// progressTracker reports "copied X/Y files (Z MB)" with an ETA while files are copied
+// It is safe for concurrent use
+type progressTracker struct {
+	mu         sync.Mutex
+	output     io.Writer // nil = log
+	operation  string
+	total      int
+	totalBytes int64
+	done       int
+	bytes      int64
+	started    time.Time
+	lastReport time.Time
+	reported   bool
+}
+
+// newProgress starts tracking a copy of total files with totalBytes bytes
+func (s *Syncer) newProgress(operation string, total int, totalBytes int64) *progressTracker {
+	now := time.Now()
+	return &progressTracker{
+		output:     s.progressOutput,
+		operation:  operation,
+		total:      total,
+		totalBytes: totalBytes,
+		started:    now,
+		lastReport: now,
+	}
+}
+
+// add records a copied file of size bytes and reports progress when it is due
+func (p *progressTracker) add(size int64) {
+	if p == nil {
+		return
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.done++
+	p.bytes += size
+	if now := time.Now(); now.Sub(p.lastReport) >= progressInterval {
+		p.lastReport = now
+		p.reported = true
+		p.print(p.line(now))
+	}
+}
+
+// finish reports the final count, but only when progress was reported before, so
+// quick copies stay silent
+func (p *progressTracker) finish() {
+	if p == nil {
+		return
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.reported {
+		p.print(fmt.Sprintf("%s: copied %d/%d files (%s) in %s", p.operation, p.done, p.total,
+			FormatSize(p.bytes), time.Since(p.started).Round(time.Second)))
+	}
+}
+
+// line formats the current progress with an ETA based on the throughput so far
+func (p *progressTracker) line(now time.Time) string {
+	line := fmt.Sprintf("%s: copied %d/%d files (%s of %s)", p.operation, p.done, p.total,
+		FormatSize(p.bytes), FormatSize(p.totalBytes))
+
+	elapsed := now.Sub(p.started)
+	if eta, ok := estimateRemaining(elapsed, p.done, p.total, p.bytes, p.totalBytes); ok {
+		line += fmt.Sprintf(", ETA %s", eta.Round(time.Second))
+	}
+	return line
+}
+
+// print writes a progress line to the output, or logs it
+func (p *progressTracker) print(line string) {
+	if p.output == nil {
+		logger.Info("⏳ %s", line)
+		return
+	}
+	fmt.Fprintf(p.output, "⏳ %s\n", line)
+}
+
+// estimateRemaining extrapolates the time left from the throughput so far
+// Bytes are used when sizes are known, since a few large files dominate a settings tree
+func estimateRemaining(elapsed time.Duration, done, total int, bytes, totalBytes int64) (time.Duration, bool) {
+	var fraction float64
+	switch {
+	case totalBytes > 0 && bytes > 0:
+		fraction = float64(bytes) / float64(totalBytes)
+	case total > 0 && done > 0:
+		fraction = float64(done) / float64(total)
+	default:
+		return 0, false
+	}
+	if fraction >= 1 {
+		return 0, true
+	}
+	return time.Duration(float64(elapsed) * (1 - fraction) / fraction), true
+}
+
+// countFiles returns how many regular files are below root and their total size
+func countFiles(root string) (int, int64) {
+	var files int
+	var size int64
+	filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err == nil && info.Mode().IsRegular() {
+			files++
+			size += info.Size()
+		}
+		return nil
+	})
+	return files, size
+}
+
+// progressLogWriter logs git's clone progress ("Receiving objects: 45% ...") at info
+// level, at most once per progressInterval
+type progressLogWriter struct {
+	mu         sync.Mutex
+	buffer     []byte
+	lastReport time.Time
+}
+
+// Write implements io.Writer
+func (w *progressLogWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.buffer = append(w.buffer, p...)
+
+	// Git redraws progress lines with \r; only complete lines are considered
+	end := bytes.LastIndexAny(w.buffer, "\r\n")
+	if end < 0 {
+		return len(p), nil
+	}
+	lines := strings.FieldsFunc(string(w.buffer[:end]), func(r rune) bool { return r == '\r' || r == '\n' })
+	w.buffer = w.buffer[end+1:]
+
+	if len(lines) > 0 && time.Since(w.lastReport) >= progressInterval {
+		w.lastReport = time.Now()
+		logger.Info("⏳ Cloning: %s", strings.TrimSpace(lines[len(lines)-1]))
+	}
+	return len(p), nil
+}
@@ -0,0 +1,130 @@
+package sync
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"cursor-sync/internal/logger"
+	"cursor-sync/internal/paths"
+)
+
+// Copy decisions recorded in debug reports
+const (
+	DecisionNew        = "new"         // Destination didn't exist
+	DecisionSizeDiff   = "size-diff"   // Sizes differ
+	DecisionHashDiff   = "hash-diff"   // Same size, different content hash
+	DecisionHashError  = "hash-error"  // Hash couldn't be calculated, copied to be safe
+	DecisionSkipped    = "skipped"     // Identical content
+	DecisionForced     = "forced"      // Forced overwrite (initial sync)
+	DecisionCopyFailed = "copy-failed" // Copy was attempted but failed
+	DecisionLocked     = "locked"      // Database in use by Cursor, skipped until it can be read consistently
+	DecisionSidecar    = "sidecar"     // SQLite WAL/SHM/journal file, never synced
+	DecisionSymlink    = "symlink"     // Symbolic link, never followed
+	DecisionLFSMissing = "lfs-missing" // Git LFS object not downloaded, local file left alone
+	DecisionKeptLocal  = "kept-local"  // Local file newer than the repository, kept by the initial sync
+	DecisionInvalid    = "invalid"     // JSON settings file that doesn't parse, the repository keeps its last good version
+)
+
+// ReportEntry describes the sync decision made for a single file
+type ReportEntry struct {
+	Path       string `json:"path"`
+	Direction  string `json:"direction"`
+	Decision   string `json:"decision"`
+	DurationMs int64  `json:"duration_ms"`
+	Error      string `json:"error,omitempty"`
+}
+
+// SyncReport is a structured per-sync report of every file decision
+type SyncReport struct {
+	Operation  string        `json:"operation"`
+	StartedAt  time.Time     `json:"started_at"`
+	FinishedAt time.Time     `json:"finished_at"`
+	Copied     int           `json:"copied"`
+	Skipped    int           `json:"skipped"`
+	Files      []ReportEntry `json:"files"`
+	mutex      sync.Mutex
+}
+
+// beginReport starts collecting a debug report if sync.debug_report is enabled
+func (s *Syncer) beginReport(operation string) {
+	if !s.config.Sync.DebugReport {
+		return
+	}
+
+	s.report = &SyncReport{
+		Operation: operation,
+		StartedAt: time.Now(),
+	}
+}
+
+// recordDecision records a file decision in the active debug report (if any)
+func (s *Syncer) recordDecision(relPath, direction, decision string, started time.Time, err error) {
+	report := s.report
+	if report == nil {
+		return
+	}
+
+	entry := ReportEntry{
+		Path:       relPath,
+		Direction:  direction,
+		Decision:   decision,
+		DurationMs: time.Since(started).Milliseconds(),
+	}
+	if err != nil {
+		entry.Error = err.Error()
+	}
+
+	report.mutex.Lock()
+	defer report.mutex.Unlock()
+
+	report.Files = append(report.Files, entry)
+	switch decision {
+	case DecisionSkipped, DecisionSidecar, DecisionSymlink:
+		report.Skipped++
+	case DecisionCopyFailed, DecisionLocked:
+	default:
+		report.Copied++
+	}
+}
+
+// finishReport writes the active debug report to reports/<timestamp>.json in the state directory
+func (s *Syncer) finishReport() {
+	report := s.report
+	if report == nil {
+		return
+	}
+	s.report = nil
+
+	report.FinishedAt = time.Now()
+
+	stateDir, err := paths.StateDir()
+	if err != nil {
+		logger.Warn("Failed to write sync report: %v", err)
+		return
+	}
+
+	reportsDir := filepath.Join(stateDir, "reports")
+	if err := os.MkdirAll(reportsDir, 0755); err != nil {
+		logger.Warn("Failed to create reports directory: %v", err)
+		return
+	}
+
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		logger.Warn("Failed to encode sync report: %v", err)
+		return
+	}
+
+	name := fmt.Sprintf("%s-%s.json", report.StartedAt.Format("20060102-150405.000"), report.Operation)
+	reportPath := filepath.Join(reportsDir, name)
+	if err := os.WriteFile(reportPath, data, 0644); err != nil {
+		logger.Warn("Failed to write sync report: %v", err)
+		return
+	}
+
+	logger.Info("📝 Sync report written: %s (%d copied, %d skipped)", reportPath, report.Copied, report.Skipped)
+}
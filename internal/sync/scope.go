@@ -0,0 +1,58 @@
+package sync
+
+import (
+	"strings"
+
+	"cursor-sync/internal/logger"
+)
+
+// syncScope narrows the syncs of a single command to some files, on top of the exclusion
+// rules. Patterns work like cursor.exclude_paths, relative to the Cursor config directory
+type syncScope struct {
+	include []string // When set, only matching files are synced
+	exclude []string // Matching files are left alone
+}
+
+// SetScope limits the following syncs to files matching include (all when empty) and not
+// matching exclude, e.g. for 'cursor-sync sync --include User/settings.json'. Files outside
+// the scope are neither copied nor deleted on either side, and unlike excluded files they
+// stay in the repository. SetScope(nil, nil) restores the full scope
+func (s *Syncer) SetScope(include, exclude []string) {
+	s.scope = syncScope{include: include, exclude: exclude}
+	if len(include) > 0 {
+		logger.Info("🎯 Limiting sync to %s", strings.Join(include, ", "))
+	}
+	if len(exclude) > 0 {
+		logger.Info("🎯 Leaving out %s", strings.Join(exclude, ", "))
+	}
+}
+
+// outOfScope reports whether a file (a path like "User/settings.json") is left alone by
+// the current sync
+func (s *Syncer) outOfScope(path string) bool {
+	for _, pattern := range s.scope.exclude {
+		if s.matchesPathPattern(path, pattern) {
+			return true
+		}
+	}
+	if len(s.scope.include) == 0 {
+		return false
+	}
+	for _, pattern := range s.scope.include {
+		if s.matchesPathPattern(path, pattern) {
+			return false
+		}
+	}
+	return true
+}
+
+// scopeExcludesDir reports whether a directory is left out as a whole, so walks can skip
+// it. Directories are never skipped for include patterns, which may match files inside
+func (s *Syncer) scopeExcludesDir(path string) bool {
+	for _, pattern := range s.scope.exclude {
+		if s.matchesPathPattern(path, pattern) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,89 @@
+package sync
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"cursor-sync/internal/logger"
+)
+
+// largestPathsShown is how many of the largest files a size limit error lists
+const largestPathsShown = 5
+
+// sizedPath is a repository file and its size in bytes
+type sizedPath struct {
+	path string
+	size int64
+}
+
+// checkRepoSize refuses a commit whose tree would exceed sync.max_repo_size (MB, 0 = no limit)
+// The returned error lists the largest files so the user knows what to exclude
+func (s *Syncer) checkRepoSize() error {
+	limitMB := s.config.Sync.MaxRepoSize
+	if limitMB <= 0 {
+		return nil
+	}
+
+	root := s.config.Repository.LocalPath
+	var total int64
+	var files []sizedPath
+
+	err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return nil // Skip inaccessible files
+		}
+		if info.IsDir() {
+			if info.Name() == ".git" {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		relPath, err := filepath.Rel(root, path)
+		if err != nil {
+			return nil
+		}
+		total += info.Size()
+		files = append(files, sizedPath{path: relPath, size: info.Size()})
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to measure repository size: %w", err)
+	}
+
+	limit := int64(limitMB) * 1024 * 1024
+	logger.Debug("Repository tree size: %s (limit %s)", FormatSize(total), FormatSize(limit))
+	if total <= limit {
+		return nil
+	}
+
+	sort.Slice(files, func(i, j int) bool { return files[i].size > files[j].size })
+	if len(files) > largestPathsShown {
+		files = files[:largestPathsShown]
+	}
+	largest := make([]string, len(files))
+	for i, file := range files {
+		largest[i] = fmt.Sprintf("%s (%s)", file.path, FormatSize(file.size))
+	}
+
+	return fmt.Errorf("repository would grow to %s, over the %d MB sync.max_repo_size limit - nothing was committed. "+
+		"Largest files: %s. Exclude them via cursor.exclude_paths or .cursorsyncignore, or raise sync.max_repo_size",
+		FormatSize(total), limitMB, strings.Join(largest, ", "))
+}
+
+// FormatSize formats a byte count for humans
+func FormatSize(bytes int64) string {
+	const unit = 1024
+	if bytes < unit {
+		return fmt.Sprintf("%d B", bytes)
+	}
+	div, exp := int64(unit), 0
+	for n := bytes / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %cB", float64(bytes)/float64(div), "KMGTPE"[exp])
+}
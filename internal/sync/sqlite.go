@@ -0,0 +1,89 @@
+package sync
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"cursor-sync/internal/logger"
+)
+
+// sqliteSidecarSuffixes are the files SQLite keeps next to an open database;
+// they are only meaningful together with the live database and are never synced
+var sqliteSidecarSuffixes = []string{"-wal", "-shm", "-journal"}
+
+// sqliteSnapshotTimeout bounds how long a single database snapshot may take
+const sqliteSnapshotTimeout = 30 * time.Second
+
+// isSQLiteSidecar reports whether path is a SQLite WAL, shared-memory or rollback journal file
+func isSQLiteSidecar(path string) bool {
+	for _, suffix := range sqliteSidecarSuffixes {
+		if strings.HasSuffix(path, suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+// isSQLiteDatabase reports whether path is a VS Code/Cursor state database
+func isSQLiteDatabase(path string) bool {
+	return filepath.Ext(path) == ".vscdb"
+}
+
+// hasActiveWAL reports whether the database has writes that are still only in its WAL,
+// in which case the main database file alone is not a consistent copy
+func hasActiveWAL(dbPath string) bool {
+	info, err := os.Stat(dbPath + "-wal")
+	return err == nil && info.Size() > 0
+}
+
+// snapshotSQLite makes a consistent copy of a live database with the sqlite3 backup command
+// Returns the path of the snapshot, which the caller must remove
+func snapshotSQLite(dbPath string) (string, error) {
+	sqlite, err := exec.LookPath("sqlite3")
+	if err != nil {
+		return "", fmt.Errorf("sqlite3 not found: %w", err)
+	}
+
+	tmp, err := os.CreateTemp("", "cursor-sync-*.vscdb")
+	if err != nil {
+		return "", fmt.Errorf("failed to create snapshot file: %w", err)
+	}
+	snapshot := tmp.Name()
+	tmp.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), sqliteSnapshotTimeout)
+	defer cancel()
+
+	// The backup API copies a consistent view, including writes still in the WAL
+	backup := fmt.Sprintf(".backup '%s'", strings.ReplaceAll(snapshot, "'", "''"))
+	if output, err := exec.CommandContext(ctx, sqlite, "-readonly", dbPath, backup).CombinedOutput(); err != nil {
+		os.Remove(snapshot)
+		return "", fmt.Errorf("sqlite3 backup failed: %v: %s", err, strings.TrimSpace(string(output)))
+	}
+
+	return snapshot, nil
+}
+
+// prepareDatabaseCopy decides how a state database is copied into the repository
+// Returns the path of a consistent snapshot to copy instead of the live file ("" = copy
+// the file itself), or an error when the database is locked and must be skipped
+func (s *Syncer) prepareDatabaseCopy(dbPath string) (string, error) {
+	if s.config.Sync.SnapshotDatabases {
+		snapshot, err := snapshotSQLite(dbPath)
+		if err == nil {
+			return snapshot, nil
+		}
+		logger.Debug("Could not snapshot %s: %v", filepath.Base(dbPath), err)
+	}
+
+	if hasActiveWAL(dbPath) {
+		return "", fmt.Errorf("database is in use (uncheckpointed write-ahead log)")
+	}
+
+	return "", nil
+}
@@ -0,0 +1,124 @@
+package sync
+
+import (
+	"fmt"
+
+	"cursor-sync/internal/logger"
+)
+
+// autoSyncMessagePrefix starts every commit message written by SyncToRemote
+const autoSyncMessagePrefix = "Auto-sync from "
+
+// commitSync commits the staged changes, squashing them into the previous auto-sync
+// commit while it is inside sync.squash_window and hasn't been pushed yet
+func (s *Syncer) commitSync(message string) error {
+	authorName, authorEmail := s.config.Sync.CommitAuthor()
+	if window := s.config.Sync.SquashWindow; window > 0 {
+		squash, err := s.repo.CanSquashHead(window, autoSyncMessagePrefix)
+		if err != nil {
+			logger.Warn("Failed to check whether to squash, creating a new commit: %v", err)
+		} else if squash {
+			logger.Debug("Squashing into the previous auto-sync commit")
+			return s.repo.SquashCommit(message, authorName, authorEmail)
+		}
+	}
+
+	return s.repo.Commit(message, authorName, authorEmail)
+}
+
+// holdPush reports whether pushing should wait: for 'cursor-sync push' when sync.auto_push
+// is off, or for sync.squash_window to close, so later syncs can still squash into the
+// unpushed HEAD. A forced or requested push is never held
+func (s *Syncer) holdPush() bool {
+	if s.forcePush || s.pushRequested {
+		return false
+	}
+	if !s.config.Sync.AutoPush {
+		return true
+	}
+
+	window := s.config.Sync.SquashWindow
+	if window <= 0 {
+		return false
+	}
+
+	hold, err := s.repo.CanSquashHead(window, autoSyncMessagePrefix)
+	return err == nil && hold
+}
+
+// pushHeldCommits pushes auto-sync commits whose squash window has closed, or that
+// sync.auto_push held back once a push is requested, when a sync has no new changes of its own
+func (s *Syncer) pushHeldCommits() {
+	if s.holdPush() {
+		return
+	}
+	// Only a squash window or sync.auto_push hold commits back, but a requested or forced
+	// push also retries commits an earlier failed push left behind
+	if !s.pushRequested && !s.forcePush && s.config.Sync.SquashWindow <= 0 && s.config.Sync.AutoPush {
+		return
+	}
+
+	unpushed, err := s.repo.HasUnpushedCommits()
+	if err != nil || !unpushed {
+		return
+	}
+
+	logger.Info("Pushing held commits")
+	if s.pushWithConflictResolution() {
+		logger.Info("Successfully synced local changes to remote")
+	}
+}
+
+// PendingPushCount returns how many local commits haven't been pushed yet
+func (s *Syncer) PendingPushCount() (int, error) {
+	return s.repo.UnpushedCommitCount()
+}
+
+// PushPending pushes the commits held back by sync.auto_push: false (or a squash window)
+// and returns how many were pushed
+func (s *Syncer) PushPending() (int, error) {
+	if !s.config.Sync.Pushes() {
+		return 0, fmt.Errorf("sync.mode %s never pushes", s.config.Sync.Mode)
+	}
+
+	pending, err := s.repo.UnpushedCommitCount()
+	if err != nil {
+		return 0, fmt.Errorf("failed to count unpushed commits: %w", err)
+	}
+	if pending == 0 {
+		return 0, nil
+	}
+
+	if err := s.checkRepositoryPrivacy(); err != nil {
+		return 0, fmt.Errorf("repository privacy check failed: %w", err)
+	}
+
+	logger.Info("📤 Pushing %d held commit(s)", pending)
+	if !s.pushWithConflictResolution() {
+		return 0, fmt.Errorf("push failed - see the log for details")
+	}
+	logger.Info("Successfully synced local changes to remote")
+	return pending, nil
+}
+
+// PushLocalChanges commits local changes and pushes them with every held commit, as for an
+// explicit 'cursor-sync push'. Unlike SyncToRemote it fails when commits remain unpushed
+func (s *Syncer) PushLocalChanges() error {
+	if !s.config.Sync.Pushes() {
+		return fmt.Errorf("sync.mode %s never pushes", s.config.Sync.Mode)
+	}
+
+	s.RequestPush()
+	if err := s.SyncToRemote(); err != nil {
+		return err
+	}
+
+	pending, err := s.repo.UnpushedCommitCount()
+	if err != nil {
+		return fmt.Errorf("failed to count unpushed commits: %w", err)
+	}
+	if pending > 0 {
+		return fmt.Errorf("%d commit(s) could not be pushed - see the log for details", pending)
+	}
+	return nil
+}
@@ -0,0 +1,272 @@
+package sync
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"cursor-sync/internal/config"
+	"cursor-sync/internal/git"
+	"cursor-sync/internal/logger"
+	"cursor-sync/internal/paths"
+)
+
+const (
+	// statusFileName records recent syncs in the state directory for the stats command
+	statusFileName = "status.json"
+	// maxSyncRecords is how many recent syncs the status file keeps
+	maxSyncRecords = 50
+)
+
+// SyncRecord describes one completed pull or push
+type SyncRecord struct {
+	Operation  string    `json:"operation"` // "pull", "push" or "initial-pull"
+	FinishedAt time.Time `json:"finished_at"`
+	DurationMs int64     `json:"duration_ms"`
+}
+
+// SyncStatus is the content of the status file: the most recent syncs, oldest first
+type SyncStatus struct {
+	Recent []SyncRecord `json:"recent"`
+	// When the remote last had every local commit: after a successful push, or a sync
+	// that found nothing to push
+	LastPushed time.Time `json:"last_pushed,omitempty"`
+	// Local commits the remote was missing after the last push attempt
+	PendingPush int `json:"pending_push"`
+	// Deletions refused by sync.max_deletes / sync.max_delete_percent, per operation
+	// ("push" or "pull"), until a sync of that operation no longer trips the limits
+	HeldDeletes map[string]HeldDeletes `json:"held_deletes,omitempty"`
+}
+
+// HeldDeletes describes deletions the mass-delete limits refused to propagate
+type HeldDeletes struct {
+	At     time.Time `json:"at"`
+	Detail string    `json:"detail"` // e.g. "60 of 80 files would be deleted locally"
+}
+
+// LastSync returns the most recent sync, if any
+func (st *SyncStatus) LastSync() (SyncRecord, bool) {
+	if len(st.Recent) == 0 {
+		return SyncRecord{}, false
+	}
+	return st.Recent[len(st.Recent)-1], true
+}
+
+// PushStale reports whether the remote has been missing local commits for longer than
+// threshold (0 = never stale) at now, and for how long. Before the first successful push
+// the oldest recorded sync counts, so pushes that never worked are reported too
+func (st *SyncStatus) PushStale(threshold time.Duration, now time.Time) (time.Duration, bool) {
+	since := st.LastPushed
+	if since.IsZero() && len(st.Recent) > 0 {
+		since = st.Recent[0].FinishedAt
+	}
+	if threshold <= 0 || since.IsZero() {
+		return 0, false
+	}
+	age := now.Sub(since)
+	return age, age > threshold
+}
+
+// AverageDuration returns the mean duration of the recorded syncs of one operation and
+// how many there were
+func (st *SyncStatus) AverageDuration(operation string) (time.Duration, int) {
+	var total time.Duration
+	var count int
+	for _, record := range st.Recent {
+		if record.Operation == operation {
+			total += time.Duration(record.DurationMs) * time.Millisecond
+			count++
+		}
+	}
+	if count == 0 {
+		return 0, 0
+	}
+	return total / time.Duration(count), count
+}
+
+// StatusFilePath returns the path of the status file
+func StatusFilePath() (string, error) {
+	stateDir, err := paths.StateDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(stateDir, statusFileName), nil
+}
+
+// ReadStatus reads the status file. A missing file yields an empty status
+func ReadStatus() (*SyncStatus, error) {
+	path, err := StatusFilePath()
+	if err != nil {
+		return nil, err
+	}
+
+	status := &SyncStatus{}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return status, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read status file: %w", err)
+	}
+	if err := json.Unmarshal(data, status); err != nil {
+		return nil, fmt.Errorf("failed to parse status file: %w", err)
+	}
+	return status, nil
+}
+
+// recordSync appends a completed sync to the status file
+// The file is informational only, so failures are logged and ignored
+func recordSync(operation string, started time.Time) {
+	updateStatus(func(status *SyncStatus) {
+		status.Recent = append(status.Recent, SyncRecord{
+			Operation:  operation,
+			FinishedAt: time.Now().UTC().Truncate(time.Second),
+			DurationMs: time.Since(started).Milliseconds(),
+		})
+		if len(status.Recent) > maxSyncRecords {
+			status.Recent = status.Recent[len(status.Recent)-maxSyncRecords:]
+		}
+	})
+}
+
+// recordPushState stores how many local commits the remote is missing in the status file;
+// none missing means the remote is up to date as of now
+func recordPushState(pending int) {
+	updateStatus(func(status *SyncStatus) {
+		status.PendingPush = pending
+		if pending == 0 {
+			status.LastPushed = time.Now().UTC().Truncate(time.Second)
+		}
+	})
+}
+
+// recordHeldDeletes stores deletions the mass-delete limits refused for an operation
+func recordHeldDeletes(operation, detail string) {
+	updateStatus(func(status *SyncStatus) {
+		if status.HeldDeletes == nil {
+			status.HeldDeletes = make(map[string]HeldDeletes)
+		}
+		status.HeldDeletes[operation] = HeldDeletes{At: time.Now().UTC().Truncate(time.Second), Detail: detail}
+	})
+}
+
+// clearHeldDeletes forgets held deletions of an operation once its deletions are within the limits
+func clearHeldDeletes(operation string) {
+	if status, err := ReadStatus(); err == nil {
+		if _, held := status.HeldDeletes[operation]; !held {
+			return
+		}
+	}
+	updateStatus(func(status *SyncStatus) {
+		delete(status.HeldDeletes, operation)
+	})
+}
+
+// recordPendingPush counts the commits the remote branch is missing and records them, so
+// commits a failed or held push left behind don't read as synced
+func (s *Syncer) recordPendingPush() {
+	pending, err := s.repo.UnpushedCommitCount()
+	if err != nil {
+		logger.Debug("Failed to count unpushed commits: %v", err)
+		return
+	}
+	recordPushState(pending)
+}
+
+// updateStatus applies update to the status file, logging and ignoring failures
+func updateStatus(update func(*SyncStatus)) {
+	status, err := ReadStatus()
+	if err != nil {
+		logger.Debug("Replacing unreadable status file: %v", err)
+		status = &SyncStatus{}
+	}
+	update(status)
+
+	path, err := StatusFilePath()
+	if err != nil {
+		logger.Debug("Failed to update status file: %v", err)
+		return
+	}
+	data, err := json.MarshalIndent(status, "", "  ")
+	if err != nil {
+		logger.Debug("Failed to marshal status file: %v", err)
+		return
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		logger.Debug("Failed to create status file directory: %v", err)
+		return
+	}
+	if err := writeFileAtomic(path, append(data, '\n'), 0644, false); err != nil {
+		logger.Debug("Failed to write status file: %v", err)
+	}
+}
+
+// Stats summarizes the repository and the sync history
+type Stats struct {
+	*git.RepositoryStats
+	SyncedFiles   int   // Non-excluded files in the repository's User directories
+	SyncedSize    int64 // Their total size
+	ExcludedFiles int   // Local files skipped by the exclusion rules
+	Status        *SyncStatus
+}
+
+// Stats aggregates repository statistics, the file counts of every target and the status
+// file. Nothing is synced or changed
+func (s *Syncer) Stats() (*Stats, error) {
+	s.loadIgnoreFile()
+
+	repoStats, err := s.repo.Stats()
+	if err != nil {
+		return nil, err
+	}
+	stats := &Stats{RepositoryStats: repoStats}
+
+	for _, target := range s.config.Cursor.SyncTargets() {
+		userPath, repoUserPath := s.targetUserPaths(target)
+
+		files, size := countFiles(repoUserPath)
+		stats.SyncedFiles += files
+		stats.SyncedSize += size
+		stats.ExcludedFiles += s.countExcludedFiles(target, userPath)
+	}
+
+	if stats.Status, err = ReadStatus(); err != nil {
+		logger.Warn("Ignoring status file: %v", err)
+		stats.Status = &SyncStatus{}
+	}
+	return stats, nil
+}
+
+// countExcludedFiles counts the local files below userPath that the exclusion rules skip,
+// including every file inside an excluded directory
+func (s *Syncer) countExcludedFiles(target config.Target, userPath string) int {
+	var excluded int
+	filepath.Walk(userPath, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return nil // Skip inaccessible files
+		}
+		relPath, err := filepath.Rel(userPath, path)
+		if err != nil || relPath == "." || strings.HasSuffix(relPath, ".sock") {
+			return nil
+		}
+
+		excludePath := "User/" + relPath
+		if !s.shouldExcludePath(target, excludePath) {
+			return nil
+		}
+		if !info.IsDir() {
+			excluded++
+			return nil
+		}
+		if s.hasAllowedFiles(excludePath) {
+			return nil // Allow-listed files below are counted one by one
+		}
+		files, _ := countFiles(path)
+		excluded += files
+		return filepath.SkipDir
+	})
+	return excluded
+}
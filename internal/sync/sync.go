@@ -1,9 +1,13 @@
 package sync
 
 import (
+	"context"
 	"crypto/sha256"
+	"errors"
 	"fmt"
+	"io"
 	"os"
+	pathpkg "path"
 	"path/filepath"
 	"runtime"
 	"strings"
@@ -11,9 +15,14 @@ import (
 	"time"
 
 	"cursor-sync/internal/config"
+	"cursor-sync/internal/conflicts"
+	"cursor-sync/internal/cursor"
 	"cursor-sync/internal/git"
+	"cursor-sync/internal/ignore"
 	"cursor-sync/internal/logger"
+	"cursor-sync/internal/notify"
 	"cursor-sync/internal/privacy"
+	"cursor-sync/internal/ratelimit"
 )
 
 // HashResult represents the result of a hash calculation
@@ -23,6 +32,13 @@ type HashResult struct {
 	Error    error
 }
 
+// hashJob asks a hash worker for the hash of a file; the worker replies on the job's own
+// channel so concurrent callers never receive each other's results
+type hashJob struct {
+	filePath string
+	result   chan HashResult
+}
+
 // Syncer handles synchronization between local and remote repositories
 type Syncer struct {
 	config    *config.Config
@@ -30,26 +46,64 @@ type Syncer struct {
 	lastSync  time.Time
 	forcePush bool
 	forcePull bool
+	// The next push was requested by the user and isn't held by sync.auto_push or a squash window
+	pushRequested bool
+	// Deletions over sync.max_deletes / sync.max_delete_percent were confirmed by the user
+	confirmDeletes bool
 	// Hash calculation throttling and parallel processing
-	hashCache      map[string]string // filepath -> hash
+	hashCache      map[string]hashCacheEntry // filepath -> hash
 	hashCacheMutex sync.RWMutex
 	hashThrottle   time.Duration
 	lastHashTime   time.Time
 	// Parallel hash calculation
-	hashWorkers    int
-	hashJobChan    chan string
-	hashResultChan chan HashResult
-	hashWg         sync.WaitGroup
-	hashStopChan   chan struct{}
+	hashWorkers  int
+	hashJobChan  chan hashJob
+	hashWg       sync.WaitGroup
+	hashStopChan chan struct{}
+	hashStopOnce sync.Once
+	// Debug report for the sync in progress (nil unless sync.debug_report is enabled)
+	report *SyncReport
+	// Patterns from each target's User/.cursorsyncignore (by target name), re-read on every sync
+	ignoreMatchers map[string]*ignore.Matcher
+	// One-off --include/--exclude patterns of the current command
+	scope syncScope
+	// Initial overwrite from remote deferred because Cursor was running
+	initialOverwritePending bool
+	// Asks the user whether to overwrite while Cursor is running (nil = never, defer instead)
+	confirmOverwrite func() bool
+	// Asks whether the initial overwrite keeps local files newer than the repository (nil = sync.conflict_resolve decides)
+	initialConflictPrompt func(files []string) string
+	// Called after a conflict between local and remote has been resolved
+	onConflict func(git.ConflictEvent)
+	// Privacy checker with a result cache shared by all syncs
+	privacyChecker privacy.Checker
+	// Where progress of long-running copies is printed (nil = logged at info level)
+	progressOutput io.Writer
+}
+
+// hashCacheEntry is a cached file hash, valid while the file keeps its size and
+// modification time
+type hashCacheEntry struct {
+	hash    string
+	size    int64
+	modTime time.Time
 }
 
+// maxRateLimitWait is the longest a sync waits for a GitHub rate limit to reset
+const maxRateLimitWait = time.Minute
+
 // New creates a new syncer
 func New(cfg *config.Config) (*Syncer, error) {
-	repo, err := git.New(cfg.Repository.LocalPath, "origin", cfg.Repository.Branch, cfg.Repository.URL)
+	endpoint := cfg.GitHubEndpoint()
+	repo, err := git.New(cfg.Repository.LocalPath, "origin", cfg.Repository.Branch, cfg.Repository.URL, endpoint)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create git repository: %w", err)
 	}
+	return newSyncer(cfg, repo, privacy.NewCachedRepositoryChecker(endpoint, cfg.Sync.PrivacyCheckTTL)), nil
+}
 
+// newSyncer creates a syncer for repo and starts its hash workers
+func newSyncer(cfg *config.Config, repo *git.Repository, checker privacy.Checker) *Syncer {
 	// Determine number of workers based on CPU cores
 	numWorkers := runtime.NumCPU()
 	if numWorkers < 2 {
@@ -62,18 +116,30 @@ func New(cfg *config.Config) (*Syncer, error) {
 	syncer := &Syncer{
 		config:         cfg,
 		repo:           repo,
-		hashCache:      make(map[string]string),
+		hashCache:      make(map[string]hashCacheEntry),
 		hashThrottle:   cfg.Sync.HashThrottleDelay,
 		hashWorkers:    numWorkers,
-		hashJobChan:    make(chan string, numWorkers*2),
-		hashResultChan: make(chan HashResult, numWorkers*2),
+		hashJobChan:    make(chan hashJob, numWorkers*2),
 		hashStopChan:   make(chan struct{}),
+		privacyChecker: checker,
 	}
 
+	repo.SetShallow(cfg.Repository.Shallow)
+	repo.SetAutoInit(cfg.Repository.AutoInit)
+	repo.SetClockSkewTolerance(cfg.Sync.ClockSkewTolerance)
+	repo.SetPushLease(cfg.Sync.MachineName(), cfg.Sync.PushLease)
+	repo.SetCommitAuthor(cfg.Sync.CommitAuthor())
+	repo.SetCloneProgress(&progressLogWriter{})
+	if cfg.Sync.IncrementalPull {
+		repo.SetIncrementalPull(cfg.Sync.IncrementalMax)
+	}
+	repo.SetConflictHandler(syncer.handleConflict)
+	repo.SetFileMerger(mergeFile)
+
 	// Start hash calculation workers
 	syncer.startHashWorkers()
 
-	return syncer, nil
+	return syncer
 }
 
 // Initialize initializes the sync repository
@@ -89,24 +155,35 @@ func (s *Syncer) Initialize() error {
 	if _, err := os.Stat(filepath.Join(s.config.Repository.LocalPath, ".git")); err == nil {
 		logger.Debug("Repository already exists, opening...")
 		if err := s.repo.Open(); err != nil {
+			if !errors.Is(err, git.ErrBrokenClone) {
+				return err
+			}
+			if err := s.recloneRepository(err); err != nil {
+				return err
+			}
+		}
+		if err := s.enableLFS(); err != nil {
+			return err
+		}
+		if err := s.writeGitIgnore(); err != nil {
 			return err
 		}
 
 		// CRITICAL LOGIC: Check if this is a fresh Cursor installation (no .custom.sync marker)
 		// If no marker exists, it means local settings have NEVER been synced before
-		// In this case, we IGNORE all local files and OVERWRITE them from remote
+		// In this case, we OVERWRITE local files from remote, except ones newer than the
+		// repository that sync.conflict_resolve (or the user) keeps
 		if !s.hasCustomSyncMarker() {
-			logger.Info("🚨 No custom sync marker found - this indicates local settings have NEVER been synced")
-			logger.Info("📥 Performing complete overwrite from remote (ignoring all local files)")
-
-			// Perform initial sync from remote, overwriting all local files
-			if err := s.syncFromRemote(); err != nil {
-				return err
+			// A publishing mirror is the source of truth and never takes remote settings
+			if !s.config.Sync.Pulls() {
+				logger.Info("📤 No sync marker found - publishing local settings (sync.mode: %s)", s.config.Sync.Mode)
+				if err := s.SyncToRemote(); err != nil {
+					return err
+				}
+				return s.createCustomSyncMarker()
 			}
-
-			// Create the marker file to indicate sync has been performed
-			logger.Info("✅ Creating sync marker to indicate local settings are now synced")
-			return s.createCustomSyncMarker()
+			logger.Info("🚨 No custom sync marker found - this indicates local settings have NEVER been synced")
+			return s.performInitialOverwrite()
 		}
 
 		logger.Debug("Custom sync marker found - local settings have been synced before")
@@ -118,6 +195,25 @@ func (s *Syncer) Initialize() error {
 	if err := s.repo.Clone(s.config.Repository.URL); err != nil {
 		return fmt.Errorf("failed to clone repository: %w", err)
 	}
+	if err := s.enableLFS(); err != nil {
+		return err
+	}
+	if err := s.writeGitIgnore(); err != nil {
+		return err
+	}
+
+	// A receiving mirror takes the remote settings as they are
+	if !s.config.Sync.Pushes() {
+		logger.Info("📥 Fresh installation - overwriting local settings from remote (sync.mode: %s)", s.config.Sync.Mode)
+		return s.performInitialOverwrite()
+	}
+
+	// Settings another machine already pushed win over a fresh installation; pushing the
+	// local files first would delete everything this machine doesn't have yet
+	if s.config.Sync.Pulls() && s.repositoryHasSettings() {
+		logger.Info("📥 Fresh installation - repository already holds settings, overwriting local settings from remote")
+		return s.performInitialOverwrite()
+	}
 
 	// For fresh installation, copy local settings TO repository first
 	logger.Info("📤 Performing initial sync from local to remote (fresh installation)")
@@ -130,23 +226,214 @@ func (s *Syncer) Initialize() error {
 	return s.createCustomSyncMarker()
 }
 
+// repositoryHasSettings reports whether the clone holds settings of any sync target
+func (s *Syncer) repositoryHasSettings() bool {
+	for _, target := range s.config.Cursor.SyncTargets() {
+		if _, repoUserPath := s.targetUserPaths(target); !isEmptyDir(repoUserPath) {
+			return true
+		}
+	}
+	return false
+}
+
+// recloneRepository replaces an unusable local clone with a fresh one
+// The old clone is moved aside rather than deleted so nothing uncommitted in it is lost
+func (s *Syncer) recloneRepository(cause error) error {
+	localPath := s.config.Repository.LocalPath
+	backup := fmt.Sprintf("%s.broken-%s", localPath, time.Now().Format("20060102-150405"))
+
+	logger.Warn("⚠️  %v - re-cloning the repository", cause)
+	if err := os.Rename(localPath, backup); err != nil {
+		return fmt.Errorf("failed to move unusable clone aside: %w", err)
+	}
+	logger.Warn("📦 Previous clone kept at %s (including any uncommitted changes)", backup)
+
+	if err := s.repo.Clone(s.config.Repository.URL); err != nil {
+		return fmt.Errorf("failed to re-clone repository: %w", err)
+	}
+	return nil
+}
+
+// SetOverwriteConfirmation sets the function used to ask the user whether the initial
+// overwrite from remote may proceed while Cursor is running. Without it the overwrite is
+// deferred until Cursor has been closed.
+func (s *Syncer) SetOverwriteConfirmation(confirm func() bool) {
+	s.confirmOverwrite = confirm
+}
+
+// SetConflictPrompt sets the function used to ask the user which side ("local" or "remote")
+// keeps files that both sides changed when commit times are too close to trust. Without it
+// the remote version is kept and the local one backed up
+func (s *Syncer) SetConflictPrompt(prompt func(git.ConflictPrompt) string) {
+	s.repo.SetConflictPrompt(prompt)
+}
+
+// ForcePrivacyCheck makes the next privacy check query GitHub instead of using the cache
+func (s *Syncer) ForcePrivacyCheck() {
+	s.privacyChecker.ForceRefresh()
+}
+
+// SetContext makes in-flight clones, pulls and pushes abort once ctx is done
+func (s *Syncer) SetContext(ctx context.Context) {
+	s.repo.SetContext(ctx)
+}
+
+// SetConflictHandler sets a function that is called after a conflict has been resolved
+func (s *Syncer) SetConflictHandler(handler func(git.ConflictEvent)) {
+	s.onConflict = handler
+}
+
+// handleConflict is called by the repository after it resolved a conflict
+func (s *Syncer) handleConflict(event git.ConflictEvent) {
+	logger.Warn("⚔️  Conflict resolved with strategy %s - %s changes won", event.Strategy, event.Winner)
+	event.LastChangedBy = s.conflictAttribution(event.Files)
+
+	if err := conflicts.Append(event); err != nil {
+		logger.Warn("Failed to record conflict: %v", err)
+	}
+
+	// Local work was discarded - make sure the user finds out now rather than later
+	if event.Winner == "remote" && len(event.Files) > 0 {
+		logger.Warn("⚠️  Local changes to %d file(s) were replaced by remote: %s", len(event.Files), strings.Join(event.Files, ", "))
+		logger.Warn("💾 Local versions backed up to: %s", event.Backup)
+
+		if s.config.Sync.DesktopNotify {
+			message := fmt.Sprintf("Remote changes replaced local edits to %s. Backup: %s", summarizeFiles(event.Files, 3), event.Backup)
+			if err := notify.Desktop("cursor-sync conflict", message); err != nil {
+				logger.Debug("Desktop notification failed: %v", err)
+			}
+		}
+	}
+
+	if s.onConflict != nil {
+		s.onConflict(event)
+	}
+}
+
+// isSymlink reports whether info (from Lstat or filepath.Walk) describes a symbolic link
+func isSymlink(info os.FileInfo) bool {
+	return info.Mode()&os.ModeSymlink != 0
+}
+
+// summarizeFiles lists up to max file names and how many more there are
+func summarizeFiles(files []string, max int) string {
+	if len(files) <= max {
+		return strings.Join(files, ", ")
+	}
+	return fmt.Sprintf("%s and %d more", strings.Join(files[:max], ", "), len(files)-max)
+}
+
+// performInitialOverwrite overwrites local settings from remote for a never-synced installation
+// If Cursor is running it may revert the overwritten files from its in-memory state on the next
+// save, so the overwrite is deferred unless the user explicitly confirms it
+func (s *Syncer) performInitialOverwrite() error {
+	running, err := cursor.IsRunning()
+	if err != nil {
+		logger.Debug("Could not determine whether Cursor is running: %v", err)
+	}
+
+	if running {
+		logger.Warn("⚠️  Cursor is running - overwriting its settings now may be reverted when Cursor saves")
+		logger.Warn("⚠️  Please quit Cursor before the initial sync from remote")
+
+		if s.confirmOverwrite == nil || !s.confirmOverwrite() {
+			logger.Warn("⏸️  Deferring initial overwrite from remote until Cursor is closed")
+			s.initialOverwritePending = true
+			return nil
+		}
+	}
+
+	s.initialOverwritePending = false
+
+	// Local edits made after the repository's last commit aren't overwritten blindly
+	keepLocal, err := s.resolveInitialConflicts()
+	if err != nil {
+		return err
+	}
+	if len(keepLocal) > 0 {
+		logger.Info("📥 Performing overwrite from remote (keeping %d newer local file(s))", len(keepLocal))
+	} else {
+		logger.Info("📥 Performing complete overwrite from remote (ignoring all local files)")
+	}
+
+	// Perform initial sync from remote, overwriting the other local files
+	if err := s.syncFromRemote(keepLocal); err != nil {
+		return err
+	}
+
+	// Create the marker file to indicate sync has been performed
+	logger.Info("✅ Creating sync marker to indicate local settings are now synced")
+	return s.createCustomSyncMarker()
+}
+
+// resumePendingOverwrite retries a deferred initial overwrite
+// Returns true if the overwrite is still pending and the current sync must be skipped
+func (s *Syncer) resumePendingOverwrite() (bool, error) {
+	if !s.initialOverwritePending {
+		return false, nil
+	}
+
+	if err := s.performInitialOverwrite(); err != nil {
+		return false, err
+	}
+
+	if s.initialOverwritePending {
+		logger.Info("⏸️  Skipping sync - initial overwrite from remote is waiting for Cursor to close")
+		return true, nil
+	}
+
+	return false, nil
+}
+
 // SyncToRemote syncs local changes to the remote repository
 func (s *Syncer) SyncToRemote() error {
+	if !s.config.Sync.Pushes() {
+		logger.Debug("Not pushing local changes (sync.mode: %s)", s.config.Sync.Mode)
+		return nil
+	}
+
 	logger.Info("Syncing local changes to remote...")
 
+	if err := s.checkUnmerged(); err != nil {
+		return err
+	}
+
+	// Never push local settings before a deferred initial overwrite has happened
+	if pending, err := s.resumePendingOverwrite(); err != nil || pending {
+		return err
+	}
+
+	started := time.Now()
+	s.beginReport("push")
+	defer s.finishReport()
+	defer func() { s.pushRequested, s.forcePush = false, false }()
+
+	// Pick up edits to the ignore file without a restart
+	s.loadIgnoreFile()
+
 	// Security check before any push operations
 	if err := s.checkRepositoryPrivacy(); err != nil {
 		return fmt.Errorf("repository privacy check failed: %w", err)
 	}
 
-	// Sync deleted files from local to repository
-	if err := s.syncDeletedFiles(); err != nil {
-		logger.Warn("Failed to sync deleted files: %v", err)
+	// First, clean up any excluded files from the repository
+	if err := s.CleanupExcludedFiles(); err != nil {
+		logger.Warn("Failed to cleanup excluded files: %v", err)
 	}
 
-	// Copy Cursor config to repository
-	if err := s.copyToRepository(); err != nil {
-		return fmt.Errorf("failed to copy config to repository: %w", err)
+	for _, target := range s.config.Cursor.SyncTargets() {
+		// Sync deleted files from local to repository
+		if err := s.syncDeletedFiles(target); err != nil {
+			if errors.Is(err, ErrTooManyDeletes) {
+				return err
+			}
+			logger.Warn("Failed to sync deleted files for %s: %v", target.Name, err)
+		}
+
+		// Copy Cursor config to repository
+		if err := s.copyToRepository(target); err != nil {
+			return fmt.Errorf("failed to copy %s config to repository: %w", target.Name, err)
+		}
 	}
 
 	// Check if there are changes to commit
@@ -155,8 +442,26 @@ func (s *Syncer) SyncToRemote() error {
 		return fmt.Errorf("failed to check for changes: %w", err)
 	}
 
-	if !hasChanges && !s.forcePush {
-		logger.Debug("No changes to sync to remote")
+	// git status can miss edits (case-insensitive filesystems, line endings), so compare
+	// content with HEAD when asked to, and whenever a push is forced
+	if !hasChanges && (s.config.Sync.ContentCheck || s.forcePush) {
+		staged, err := s.repo.StageContentChanges()
+		if err != nil {
+			logger.Warn("Content-based change detection failed: %v", err)
+		} else if len(staged) > 0 {
+			logger.Warn("🔍 git status reported no changes, but %d file(s) differ from HEAD: %s", len(staged), summarizeFiles(staged, 5))
+			hasChanges = true
+		}
+	}
+
+	if !hasChanges {
+		if s.forcePush {
+			logger.Info("🔁 Forced push: no local changes to commit, pushing any unpushed commits")
+		} else {
+			logger.Debug("No changes to sync to remote")
+		}
+		s.pushHeldCommits()
+		s.recordPendingPush()
 		// Even if no changes, ensure marker exists after successful sync
 		if !s.hasCustomSyncMarker() {
 			logger.Debug("Creating sync marker after successful sync operation")
@@ -165,30 +470,77 @@ func (s *Syncer) SyncToRemote() error {
 		return nil
 	}
 
+	// Refuse to commit a runaway tree (e.g. misconfigured exclusions)
+	if err := s.checkRepoSize(); err != nil {
+		return err
+	}
+
 	// Add all changes
 	if err := s.repo.Add("."); err != nil {
 		return fmt.Errorf("failed to add changes: %w", err)
 	}
 
 	// Commit changes
-	hostname, _ := os.Hostname()
-	commitMessage := fmt.Sprintf("Auto-sync from %s at %s", hostname, time.Now().Format("2006-01-02 15:04:05"))
+	commitMessage := fmt.Sprintf(autoSyncMessagePrefix+"%s at %s", s.config.Sync.MachineName(), time.Now().Format("2006-01-02 15:04:05"))
 
-	if err := s.repo.Commit(commitMessage, "cursor-sync", "cursor-sync@local"); err != nil {
+	if err := s.commitSync(commitMessage); err != nil {
 		return fmt.Errorf("failed to commit changes: %w", err)
 	}
 
-	// Push changes with robust conflict resolution
+	// Keep the commit local until 'cursor-sync push', or while later syncs may still squash into it
+	if s.holdPush() {
+		if !s.config.Sync.AutoPush {
+			logger.Info("📝 Committed local changes without pushing (sync.auto_push: false) - run 'cursor-sync push' to publish them")
+		} else {
+			logger.Info("Holding push until the squash window closes (sync.squash_window: %v)", s.config.Sync.SquashWindow)
+		}
+		s.lastSync = time.Now()
+		s.recordPendingPush()
+		if err := s.createCustomSyncMarker(); err != nil {
+			logger.Warn("Failed to create sync marker (non-critical): %v", err)
+		}
+		return nil
+	}
+
+	pushSuccess := s.pushWithConflictResolution()
+
+	// Even if push failed, we still want to mark the sync as successful
+	// because the local changes were committed successfully
+	if !pushSuccess {
+		logger.Warn("⚠️  Push operation failed, but local changes were committed successfully")
+		if pending, err := s.repo.UnpushedCommitCount(); err == nil {
+			logger.Warn("⚠️  %d commit(s) pending push - they will be pushed on the next successful sync cycle", pending)
+		} else {
+			logger.Warn("⚠️  Changes will be pushed on the next successful sync cycle")
+		}
+	}
+
+	s.lastSync = time.Now()
+	recordSync("push", started)
+
+	// IMPORTANT: Create marker file after every successful sync operation
+	// This indicates local settings have been synced at least once
+	if err := s.createCustomSyncMarker(); err != nil {
+		logger.Warn("Failed to create sync marker (non-critical): %v", err)
+	}
+
+	if pushSuccess {
+		logger.Info("Successfully synced local changes to remote")
+	} else {
+		logger.Info("⚠️  Sync completed with warnings (push failed but local changes committed)")
+	}
+	return nil
+}
+
+// pushWithConflictResolution pushes committed changes, pulling and resolving conflicts
+// once when the remote has diverged. Returns whether the push succeeded
+func (s *Syncer) pushWithConflictResolution() bool {
 	pushSuccess := false
 	if err := s.repo.Push(); err != nil {
 		logger.Warn("Initial push failed: %v", err)
 
 		// Check if this is a conflict error (local out of sync with remote)
-		if strings.Contains(err.Error(), "cannot lock ref") ||
-			strings.Contains(err.Error(), "rejected") ||
-			strings.Contains(err.Error(), "non-fast-forward") ||
-			strings.Contains(err.Error(), "object not found") {
-
+		if errors.Is(err, git.ErrPushConflict) {
 			logger.Warn("Push conflict detected, attempting to resolve...")
 
 			// Try to pull latest changes first to resolve the conflict
@@ -215,33 +567,33 @@ func (s *Syncer) SyncToRemote() error {
 		pushSuccess = true
 	}
 
-	// Even if push failed, we still want to mark the sync as successful
-	// because the local changes were committed successfully
-	if !pushSuccess {
-		logger.Warn("⚠️  Push operation failed, but local changes were committed successfully")
-		logger.Warn("⚠️  Changes will be pushed on the next successful sync cycle")
+	s.recordPendingPush()
+	return pushSuccess
+}
+
+// SyncFromRemote syncs remote changes to local
+func (s *Syncer) SyncFromRemote() error {
+	if !s.config.Sync.Pulls() {
+		logger.Debug("Not applying remote changes (sync.mode: %s)", s.config.Sync.Mode)
+		return nil
 	}
 
-	s.lastSync = time.Now()
-	s.forcePush = false
+	logger.Info("Syncing remote changes to local...")
 
-	// IMPORTANT: Create marker file after every successful sync operation
-	// This indicates local settings have been synced at least once
-	if err := s.createCustomSyncMarker(); err != nil {
-		logger.Warn("Failed to create sync marker (non-critical): %v", err)
+	if err := s.checkUnmerged(); err != nil {
+		return err
 	}
 
-	if pushSuccess {
-		logger.Info("Successfully synced local changes to remote")
-	} else {
-		logger.Info("⚠️  Sync completed with warnings (push failed but local changes committed)")
+	if pending, err := s.resumePendingOverwrite(); err != nil || pending {
+		return err
 	}
-	return nil
-}
 
-// SyncFromRemote syncs remote changes to local
-func (s *Syncer) SyncFromRemote() error {
-	logger.Info("Syncing remote changes to local...")
+	started := time.Now()
+	s.beginReport("pull")
+	defer s.finishReport()
+
+	// Pick up edits to the ignore file without a restart
+	s.loadIgnoreFile()
 
 	// Security check before any pull operations
 	if err := s.checkRepositoryPrivacy(); err != nil {
@@ -254,6 +606,7 @@ func (s *Syncer) SyncFromRemote() error {
 		logger.Warn("Pull with conflict resolution failed: %v", err)
 	} else {
 		pullSuccess = true
+		s.fetchLFSObjects()
 	}
 
 	// Even if pull failed, try to sync what we have locally
@@ -262,20 +615,26 @@ func (s *Syncer) SyncFromRemote() error {
 		logger.Warn("⚠️  Pull operation failed, but continuing with local sync to ensure data consistency")
 	}
 
-	// Sync deleted files from repository to local (if pull was successful)
-	if pullSuccess {
-		if err := s.syncDeletedFilesFromRemote(); err != nil {
-			logger.Warn("Failed to sync deleted files from remote: %v", err)
+	for _, target := range s.config.Cursor.SyncTargets() {
+		// Sync deleted files from repository to local (if pull was successful)
+		if pullSuccess {
+			if err := s.syncDeletedFilesFromRemote(target); err != nil {
+				if errors.Is(err, ErrTooManyDeletes) {
+					return err
+				}
+				logger.Warn("Failed to sync deleted files from remote for %s: %v", target.Name, err)
+			}
 		}
-	}
 
-	// Copy from repository to Cursor config
-	if err := s.copyFromRepository(); err != nil {
-		return fmt.Errorf("failed to copy from repository: %w", err)
+		// Copy from repository to Cursor config
+		if err := s.copyFromRepository(target); err != nil {
+			return fmt.Errorf("failed to copy %s config from repository: %w", target.Name, err)
+		}
 	}
 
 	s.lastSync = time.Now()
 	s.forcePull = false
+	recordSync("pull", started)
 
 	// IMPORTANT: Create marker file after every successful sync operation
 	// This indicates local settings have been synced at least once
@@ -292,31 +651,72 @@ func (s *Syncer) SyncFromRemote() error {
 }
 
 // syncFromRemote is the internal method for initial sync
-func (s *Syncer) syncFromRemote() error {
+// Files in keepLocal (repository-relative names) keep their local version
+func (s *Syncer) syncFromRemote(keepLocal map[string]bool) error {
 	logger.Info("Performing initial sync from remote...")
 
+	started := time.Now()
+	s.beginReport("initial-pull")
+	defer s.finishReport()
+
+	s.loadIgnoreFile()
+
 	// For initial sync (no .custom.sync marker), we want to:
 	// 1. Copy ALL files from remote to local (overwrite local files)
 	// 2. BUT NOT delete local files that don't exist in remote
 	// This ensures we get the remote settings but don't lose any local files
 
 	// Copy from repository to Cursor config with force overwrite
-	if err := s.copyFromRepositoryForce(); err != nil {
-		return fmt.Errorf("failed to copy from repository: %w", err)
+	// Targets that were synced before keep their local files
+	for _, target := range s.config.Cursor.SyncTargets() {
+		if s.hasTargetSyncMarker(target) {
+			logger.Debug("Target %s was synced before - skipping initial overwrite", target.Name)
+			continue
+		}
+		if err := s.copyFromRepositoryForce(target, keepLocal); err != nil {
+			return fmt.Errorf("failed to copy %s config from repository: %w", target.Name, err)
+		}
 	}
 
+	recordSync("initial-pull", started)
 	logger.Info("Initial sync completed")
 	return nil
 }
 
-// ForcePush forces the next push operation
+// SetConfig replaces the configuration (live config reload) and refreshes the settings the
+// syncer derives from it. The caller must make sure no sync is running
+func (s *Syncer) SetConfig(cfg *config.Config) {
+	s.config = cfg
+	s.hashThrottle = s.config.Sync.HashThrottleDelay
+	s.repo.SetClockSkewTolerance(s.config.Sync.ClockSkewTolerance)
+	s.repo.SetPushLease(s.config.Sync.MachineName(), s.config.Sync.PushLease)
+	s.repo.SetCommitAuthor(s.config.Sync.CommitAuthor())
+	if s.config.Sync.IncrementalPull {
+		s.repo.SetIncrementalPull(s.config.Sync.IncrementalMax)
+	} else {
+		s.repo.SetIncrementalPull(0)
+	}
+	s.loadIgnoreFile()
+}
+
+// ForcePush makes the next SyncToRemote re-hash every file instead of trusting cached
+// hashes, and push unpushed commits even when nothing changed or a push would be held
 func (s *Syncer) ForcePush() {
 	s.forcePush = true
+	s.clearHashCache("")
+}
+
+// RequestPush makes the next SyncToRemote push its commits even when sync.auto_push is
+// off or a squash window is open, as for an explicit 'cursor-sync push'
+func (s *Syncer) RequestPush() {
+	s.pushRequested = true
 }
 
-// ForcePull forces the next pull operation
+// ForcePull makes the next SyncFromRemote re-hash every file instead of trusting cached
+// hashes, so local files that drifted from the repository are restored
 func (s *Syncer) ForcePull() {
 	s.forcePull = true
+	s.clearHashCache("")
 }
 
 // startHashWorkers starts the parallel hash calculation workers
@@ -330,10 +730,13 @@ func (s *Syncer) startHashWorkers() {
 }
 
 // stopHashWorkers stops all hash calculation workers
+// Safe to call more than once
 func (s *Syncer) stopHashWorkers() {
-	close(s.hashStopChan)
-	s.hashWg.Wait()
-	logger.Debug("Stopped all hash calculation workers")
+	s.hashStopOnce.Do(func() {
+		close(s.hashStopChan)
+		s.hashWg.Wait()
+		logger.Debug("Stopped all hash calculation workers")
+	})
 }
 
 // hashWorker is a worker goroutine that calculates file hashes
@@ -344,11 +747,13 @@ func (s *Syncer) hashWorker(workerID int) {
 		select {
 		case <-s.hashStopChan:
 			return
-		case filePath := <-s.hashJobChan:
+		case job := <-s.hashJobChan:
 			// Calculate hash with throttling
-			hash, err := s.calculateSingleFileHash(filePath)
-			s.hashResultChan <- HashResult{
-				FilePath: filePath,
+			hash, err := s.calculateSingleFileHash(job.filePath)
+
+			// The reply channel is buffered, so this never blocks even if the caller gave up
+			job.result <- HashResult{
+				FilePath: job.filePath,
 				Hash:     hash,
 				Error:    err,
 			}
@@ -359,7 +764,9 @@ func (s *Syncer) hashWorker(workerID int) {
 // calculateSingleFileHash calculates hash for a single file with throttling
 func (s *Syncer) calculateSingleFileHash(filePath string) (string, error) {
 	// Throttle hash calculations to prevent CPU stress
+	s.hashCacheMutex.RLock()
 	timeSinceLastHash := time.Since(s.lastHashTime)
+	s.hashCacheMutex.RUnlock()
 	if timeSinceLastHash < s.hashThrottle {
 		sleepTime := s.hashThrottle - timeSinceLastHash
 		logger.Debug("Worker throttling hash calculation for %s, sleeping for %v", filepath.Base(filePath), sleepTime)
@@ -384,15 +791,13 @@ func (s *Syncer) calculateSingleFileHash(filePath string) (string, error) {
 }
 
 // syncDeletedFiles removes files from the repository that no longer exist locally
-func (s *Syncer) syncDeletedFiles() error {
+func (s *Syncer) syncDeletedFiles(target config.Target) error {
 	logger.Debug("Syncing deleted files from local to repository...")
 
-	cursorPath := s.config.Cursor.ConfigPath
-	userPath := filepath.Join(cursorPath, "User")
-	repoPath := s.config.Repository.LocalPath
-	repoUserPath := filepath.Join(repoPath, "User")
+	userPath, repoUserPath := s.targetUserPaths(target)
 
-	var filesRemoved int
+	var deleted []string
+	var tracked int
 
 	// Walk through the repository and check if files still exist locally
 	err := filepath.Walk(repoUserPath, func(path string, info os.FileInfo, err error) error {
@@ -412,20 +817,16 @@ func (s *Syncer) syncDeletedFiles() error {
 		}
 
 		// Check if this path should be excluded
-		if s.shouldExcludePath("User/" + relPath) {
+		if s.shouldExcludePath(target, "User/"+relPath) || s.outOfScope("User/"+relPath) {
 			return nil
 		}
 
+		tracked++
+
 		// Check if file exists locally
 		localPath := filepath.Join(userPath, relPath)
 		if _, err := os.Stat(localPath); os.IsNotExist(err) {
-			// File doesn't exist locally, remove it from repository
-			if err := os.Remove(path); err != nil {
-				logger.Warn("Failed to remove deleted file from repository: %s", relPath)
-				return nil
-			}
-			filesRemoved++
-			logger.Debug("🗑️  Removed deleted file from repository: %s", relPath)
+			deleted = append(deleted, relPath)
 		}
 
 		return nil
@@ -435,6 +836,29 @@ func (s *Syncer) syncDeletedFiles() error {
 		return fmt.Errorf("failed to sync deleted files: %w", err)
 	}
 
+	if err := s.checkDeleteThreshold("push", "from the repository", len(deleted), tracked); err != nil {
+		return err
+	}
+
+	var filesRemoved int
+	fileManifest := s.loadManifest()
+	for _, relPath := range deleted {
+		// File doesn't exist locally, remove it from repository
+		if err := os.Remove(filepath.Join(repoUserPath, relPath)); err != nil {
+			logger.Warn("Failed to remove deleted file from repository: %s", relPath)
+			continue
+		}
+		filesRemoved++
+		delete(fileManifest, manifestKey(target, relPath))
+		logger.Debug("🗑️  Removed deleted file from repository: %s", relPath)
+	}
+
+	if filesRemoved > 0 {
+		if err := s.saveManifest(fileManifest); err != nil {
+			logger.Warn("Failed to update file manifest: %v", err)
+		}
+	}
+
 	if filesRemoved > 0 {
 		logger.Info("🗑️  Synced deletions: %d files removed from repository", filesRemoved)
 	} else {
@@ -445,13 +869,10 @@ func (s *Syncer) syncDeletedFiles() error {
 }
 
 // syncDeletedFilesFromRemote removes files locally that no longer exist in the repository
-func (s *Syncer) syncDeletedFilesFromRemote() error {
+func (s *Syncer) syncDeletedFilesFromRemote(target config.Target) error {
 	logger.Debug("Syncing deleted files from repository to local...")
 
-	cursorPath := s.config.Cursor.ConfigPath
-	userPath := filepath.Join(cursorPath, "User")
-	repoPath := s.config.Repository.LocalPath
-	repoUserPath := filepath.Join(repoPath, "User")
+	userPath, repoUserPath := s.targetUserPaths(target)
 
 	// Check if User directory exists in repository
 	if _, err := os.Stat(repoUserPath); os.IsNotExist(err) {
@@ -459,7 +880,8 @@ func (s *Syncer) syncDeletedFilesFromRemote() error {
 		return nil
 	}
 
-	var filesRemoved int
+	var deleted []string
+	var tracked int
 
 	// Walk through local User directory and check if files still exist in repository
 	err := filepath.Walk(userPath, func(path string, info os.FileInfo, err error) error {
@@ -479,20 +901,16 @@ func (s *Syncer) syncDeletedFilesFromRemote() error {
 		}
 
 		// Check if this path should be excluded
-		if s.shouldExcludePath("User/" + relPath) {
+		if s.shouldExcludePath(target, "User/"+relPath) || s.outOfScope("User/"+relPath) {
 			return nil
 		}
 
+		tracked++
+
 		// Check if file exists in repository
 		repoPath := filepath.Join(repoUserPath, relPath)
 		if _, err := os.Stat(repoPath); os.IsNotExist(err) {
-			// File doesn't exist in repository, remove it locally
-			if err := os.Remove(path); err != nil {
-				logger.Warn("Failed to remove deleted file locally: %s", relPath)
-				return nil
-			}
-			filesRemoved++
-			logger.Debug("🗑️  Removed deleted file locally: %s", relPath)
+			deleted = append(deleted, relPath)
 		}
 
 		return nil
@@ -502,6 +920,21 @@ func (s *Syncer) syncDeletedFilesFromRemote() error {
 		return fmt.Errorf("failed to sync deleted files from remote: %w", err)
 	}
 
+	if err := s.checkDeleteThreshold("pull", "locally", len(deleted), tracked); err != nil {
+		return err
+	}
+
+	var filesRemoved int
+	for _, relPath := range deleted {
+		// File doesn't exist in repository, remove it locally
+		if err := os.Remove(filepath.Join(userPath, relPath)); err != nil {
+			logger.Warn("Failed to remove deleted file locally: %s", relPath)
+			continue
+		}
+		filesRemoved++
+		logger.Debug("🗑️  Removed deleted file locally: %s", relPath)
+	}
+
 	if filesRemoved > 0 {
 		logger.Info("🗑️  Synced deletions from remote: %d files removed locally", filesRemoved)
 	} else {
@@ -514,17 +947,10 @@ func (s *Syncer) syncDeletedFilesFromRemote() error {
 // copyToRepository copies Cursor configuration to the repository
 // Uses rsync-like logic to only copy files that have actually changed
 // Only targets the User folder
-func (s *Syncer) copyToRepository() error {
-	logger.Info("🚀 copyToRepository called - starting rsync mode")
+func (s *Syncer) copyToRepository(target config.Target) error {
+	logger.Info("🚀 copyToRepository called for %s - starting rsync mode", target.Name)
 
-	// First, clean up any excluded files from the repository
-	if err := s.CleanupExcludedFiles(); err != nil {
-		logger.Warn("Failed to cleanup excluded files: %v", err)
-	}
-
-	cursorPath := s.config.Cursor.ConfigPath
-	userPath := filepath.Join(cursorPath, "User")
-	repoPath := s.config.Repository.LocalPath
+	userPath, repoUserPath := s.targetUserPaths(target)
 
 	// Check if User directory exists
 	if _, err := os.Stat(userPath); os.IsNotExist(err) {
@@ -532,6 +958,16 @@ func (s *Syncer) copyToRepository() error {
 	}
 
 	var filesCopied, filesSkipped int
+	var lockedFiles, symlinks, invalidFiles []string
+
+	// Attribute every file copied by this sync to this machine
+	fileManifest := s.loadManifest()
+	hostname := s.config.Sync.MachineName()
+
+	// With nothing in the repository yet every file is new: skip the (throttled) hash
+	// comparison and copy them in parallel once the walk is done
+	initialCopy := isEmptyDir(repoUserPath)
+	var pending []pendingCopy
 
 	err := filepath.Walk(userPath, func(path string, info os.FileInfo, err error) error {
 		if err != nil {
@@ -550,32 +986,124 @@ func (s *Syncer) copyToRepository() error {
 			return nil
 		}
 
+		// cursor-sync's own state files stay local, whatever the exclusion rules say
+		if git.IsInternalFile(relPath) {
+			logger.Debug("Skipping cursor-sync internal file: %s", relPath)
+			return nil
+		}
+
 		// Skip if should be excluded
 		excludePath := "User/" + relPath
-		if s.shouldExcludePath(excludePath) {
+		if s.shouldExcludePath(target, excludePath) {
 			if info.IsDir() {
+				// Descend into globalStorage and workspaceStorage to pick up allow-listed files
+				if s.hasAllowedFiles(excludePath) {
+					return nil
+				}
 				return filepath.SkipDir
 			}
 			return nil
 		}
 
-		destPath := filepath.Join(repoPath, "User", relPath)
+		// Leave files outside a one-off --include/--exclude scope alone
+		if info.IsDir() && s.scopeExcludesDir(excludePath) {
+			return filepath.SkipDir
+		}
+		if !info.IsDir() && s.outOfScope(excludePath) {
+			return nil
+		}
+
+		// Never follow symlinks: their target may be outside the config directory
+		if isSymlink(info) {
+			logger.Debug("Skipping symlink: %s", relPath)
+			symlinks = append(symlinks, relPath)
+			s.recordDecision(filepath.Join(target.Subdir, relPath), "local-to-repo", DecisionSymlink, time.Now(), nil)
+			return nil
+		}
+
+		destPath := filepath.Join(repoUserPath, relPath)
 
 		if info.IsDir() {
 			// Create directory
 			return os.MkdirAll(destPath, info.Mode())
 		}
 
+		started := time.Now()
+
+		// SQLite sidecars belong to the live database and would corrupt a copied one
+		if isSQLiteSidecar(relPath) {
+			logger.Debug("Skipping SQLite sidecar file: %s", relPath)
+			s.recordDecision(filepath.Join(target.Subdir, relPath), "local-to-repo", DecisionSidecar, started, nil)
+			return nil
+		}
+
+		// Copy state databases from a consistent snapshot, or skip them while locked
+		srcPath := path
+		if isSQLiteDatabase(relPath) {
+			snapshot, err := s.prepareDatabaseCopy(path)
+			if err != nil {
+				logger.Warn("🔒 Skipping locked database %s: %v", relPath, err)
+				lockedFiles = append(lockedFiles, relPath)
+				s.recordDecision(filepath.Join(target.Subdir, relPath), "local-to-repo", DecisionLocked, started, err)
+				return nil
+			}
+			if snapshot != "" {
+				defer os.Remove(snapshot)
+				defer s.clearHashCache(snapshot)
+				snapshotInfo, err := os.Stat(snapshot)
+				if err != nil {
+					return nil
+				}
+				srcPath, info = snapshot, snapshotInfo
+			}
+		}
+
+		// Files stored in Git LFS are committed as pointers
+		if s.lfsTracked(destPath) {
+			decision, err := s.copyToLFS(srcPath, destPath)
+			if err != nil {
+				logger.Warn("Failed to store %s in Git LFS: %v", relPath, err)
+				s.recordDecision(filepath.Join(target.Subdir, relPath), "local-to-repo", DecisionCopyFailed, started, err)
+				return nil // Continue with other files
+			}
+			if decision == DecisionSkipped {
+				filesSkipped++
+			} else {
+				filesCopied++
+				fileManifest.record(manifestKey(target, relPath), srcPath, hostname)
+			}
+			s.recordDecision(filepath.Join(target.Subdir, relPath), "local-to-repo", decision, started, nil)
+			return nil
+		}
+
+		// Never propagate a broken settings file; the repository keeps the last good version
+		if err := s.checkJSONSettings(relPath, srcPath); err != nil {
+			logger.Warn("🧩 Not syncing %s: invalid JSON (%v)", relPath, err)
+			invalidFiles = append(invalidFiles, relPath)
+			s.recordDecision(filepath.Join(target.Subdir, relPath), "local-to-repo", DecisionInvalid, started, err)
+			return nil
+		}
+
+		// Database snapshots are removed when this callback returns, so only live files are queued
+		if initialCopy && srcPath == path {
+			pending = append(pending, pendingCopy{src: srcPath, dest: destPath, relPath: relPath, size: info.Size()})
+			return nil
+		}
+
 		// For files, check if we need to copy
-		if s.shouldCopyFile(path, destPath, info) {
-			if err := s.copyFile(path, destPath); err != nil {
+		if copyNeeded, decision := s.copyDecision(srcPath, destPath, info); copyNeeded {
+			if err := s.copyFile(srcPath, destPath); err != nil {
 				logger.Warn("Failed to copy file %s: %v", relPath, err)
+				s.recordDecision(filepath.Join(target.Subdir, relPath), "local-to-repo", DecisionCopyFailed, started, err)
 				return nil // Continue with other files
 			}
 			filesCopied++
+			fileManifest.record(manifestKey(target, relPath), srcPath, hostname)
+			s.recordDecision(filepath.Join(target.Subdir, relPath), "local-to-repo", decision, started, nil)
 			logger.Debug("📄 Copied changed file: %s", relPath)
 		} else {
 			filesSkipped++
+			s.recordDecision(filepath.Join(target.Subdir, relPath), "local-to-repo", decision, started, nil)
 			logger.Debug("⏭️  Skipped unchanged file: %s", relPath)
 		}
 
@@ -586,6 +1114,26 @@ func (s *Syncer) copyToRepository() error {
 		return fmt.Errorf("failed to copy to repository: %w", err)
 	}
 
+	filesCopied += s.flushInitialCopies(target, pending, fileManifest, hostname)
+
+	if filesCopied > 0 {
+		if err := s.saveManifest(fileManifest); err != nil {
+			logger.Warn("Failed to update file manifest: %v", err)
+		}
+	}
+
+	if len(symlinks) > 0 {
+		logger.Warn("🔗 %d symlink(s) were not synced (symlinks are never followed): %s", len(symlinks), summarizeFiles(symlinks, 5))
+	}
+
+	if len(lockedFiles) > 0 {
+		logger.Warn("🔒 %d locked file(s) were not synced and will be retried: %s", len(lockedFiles), summarizeFiles(lockedFiles, 5))
+	}
+
+	if len(invalidFiles) > 0 {
+		logger.Warn("🧩 %d file(s) with invalid JSON were not synced and will be retried once fixed: %s", len(invalidFiles), summarizeFiles(invalidFiles, 5))
+	}
+
 	logger.Info("📊 Local sync completed: %d files copied, %d files skipped", filesCopied, filesSkipped)
 	return nil
 }
@@ -594,13 +1142,10 @@ func (s *Syncer) copyToRepository() error {
 // Uses rsync-like logic to only copy files that have actually changed
 // copyFromRepositoryForce is used for initial sync - forces overwrite of local files
 // but does NOT delete local files that don't exist in remote
-func (s *Syncer) copyFromRepositoryForce() error {
+func (s *Syncer) copyFromRepositoryForce(target config.Target, keepLocal map[string]bool) error {
 	logger.Debug("Copying from repository to Cursor config (FORCE mode for initial sync)...")
 
-	cursorPath := s.config.Cursor.ConfigPath
-	userPath := filepath.Join(cursorPath, "User")
-	repoPath := s.config.Repository.LocalPath
-	repoUserPath := filepath.Join(repoPath, "User")
+	userPath, repoUserPath := s.targetUserPaths(target)
 
 	// Check if User directory exists in repository
 	if _, err := os.Stat(repoUserPath); os.IsNotExist(err) {
@@ -609,6 +1154,9 @@ func (s *Syncer) copyFromRepositoryForce() error {
 	}
 
 	var filesCopied int
+	total, totalBytes := countFiles(repoUserPath)
+	progress := s.newProgress("Initial sync from remote", total, totalBytes)
+	defer progress.finish()
 
 	err := filepath.Walk(repoUserPath, func(path string, info os.FileInfo, err error) error {
 		if err != nil {
@@ -621,6 +1169,12 @@ func (s *Syncer) copyFromRepositoryForce() error {
 			return nil
 		}
 
+		// Never follow symlinks in the repository: their target may be outside of it
+		if isSymlink(info) {
+			logger.Warn("🔗 Skipping symlink in repository: %s", relPath)
+			return nil
+		}
+
 		destPath := filepath.Join(userPath, relPath)
 
 		if info.IsDir() {
@@ -631,13 +1185,36 @@ func (s *Syncer) copyFromRepositoryForce() error {
 			return nil
 		}
 
+		// Never write SQLite sidecars next to a local database
+		if isSQLiteSidecar(relPath) {
+			logger.Debug("Skipping SQLite sidecar file: %s", relPath)
+			return nil
+		}
+
 		// For initial sync, ALWAYS copy files from remote to local (force overwrite)
 		// This ensures we get the remote settings but don't lose local files that aren't in remote
+		// Only local files newer than the repository that the user or strategy chose to keep are spared
+		started := time.Now()
+		if keepLocal[filepath.ToSlash(filepath.Join(s.config.Repository.Subdir, target.Subdir, "User", relPath))] {
+			logger.Debug("🛡️  Keeping newer local file (initial sync): %s", relPath)
+			s.recordDecision(filepath.Join(target.Subdir, relPath), "repo-to-local", DecisionKeptLocal, started, nil)
+			return nil
+		}
+		// Git LFS pointers are replaced by the content they refer to
+		if pointer, ok := readLFSPointer(path); ok {
+			if decision := s.restoreLFSFile(target, relPath, pointer, destPath, true, started); decision == DecisionForced {
+				filesCopied++
+			}
+			return nil
+		}
 		if err := s.copyFile(path, destPath); err != nil {
 			logger.Warn("Failed to copy file %s: %v", relPath, err)
+			s.recordDecision(filepath.Join(target.Subdir, relPath), "repo-to-local", DecisionCopyFailed, started, err)
 			return nil // Continue with other files
 		}
 		filesCopied++
+		progress.add(info.Size())
+		s.recordDecision(filepath.Join(target.Subdir, relPath), "repo-to-local", DecisionForced, started, nil)
 		logger.Debug("📄 FORCE copied file (initial sync): %s", relPath)
 
 		return nil
@@ -652,13 +1229,10 @@ func (s *Syncer) copyFromRepositoryForce() error {
 }
 
 // Only targets the User folder
-func (s *Syncer) copyFromRepository() error {
+func (s *Syncer) copyFromRepository(target config.Target) error {
 	logger.Debug("Copying from repository to Cursor config (rsync mode)...")
 
-	cursorPath := s.config.Cursor.ConfigPath
-	userPath := filepath.Join(cursorPath, "User")
-	repoPath := s.config.Repository.LocalPath
-	repoUserPath := filepath.Join(repoPath, "User")
+	userPath, repoUserPath := s.targetUserPaths(target)
 
 	// Check if User directory exists in repository
 	if _, err := os.Stat(repoUserPath); os.IsNotExist(err) {
@@ -679,8 +1253,17 @@ func (s *Syncer) copyFromRepository() error {
 			return nil
 		}
 
+		// Never follow symlinks in the repository: their target may be outside of it
+		if isSymlink(info) {
+			logger.Warn("🔗 Skipping symlink in repository: %s", relPath)
+			return nil
+		}
+
 		destPath := filepath.Join(userPath, relPath)
 
+		if info.IsDir() && s.scopeExcludesDir("User/"+relPath) {
+			return filepath.SkipDir
+		}
 		if info.IsDir() {
 			// Create directory if it doesn't exist
 			if err := os.MkdirAll(destPath, info.Mode()); err != nil {
@@ -689,16 +1272,41 @@ func (s *Syncer) copyFromRepository() error {
 			return nil
 		}
 
+		// Never write SQLite sidecars next to a local database
+		if isSQLiteSidecar(relPath) {
+			logger.Debug("Skipping SQLite sidecar file: %s", relPath)
+			return nil
+		}
+
+		// Leave files outside a one-off --include/--exclude scope alone
+		if s.outOfScope("User/" + relPath) {
+			return nil
+		}
+
 		// For files, check if we need to copy
-		if s.shouldCopyFile(path, destPath, info) {
+		started := time.Now()
+		// Git LFS pointers are replaced by the content they refer to
+		if pointer, ok := readLFSPointer(path); ok {
+			switch s.restoreLFSFile(target, relPath, pointer, destPath, false, started) {
+			case DecisionSkipped:
+				filesSkipped++
+			case DecisionNew, DecisionHashDiff:
+				filesCopied++
+			}
+			return nil
+		}
+		if copyNeeded, decision := s.copyDecision(path, destPath, info); copyNeeded {
 			if err := s.copyFile(path, destPath); err != nil {
 				logger.Warn("Failed to copy file %s: %v", relPath, err)
+				s.recordDecision(filepath.Join(target.Subdir, relPath), "repo-to-local", DecisionCopyFailed, started, err)
 				return nil // Continue with other files
 			}
 			filesCopied++
+			s.recordDecision(filepath.Join(target.Subdir, relPath), "repo-to-local", decision, started, nil)
 			logger.Debug("📄 Copied changed file: %s", relPath)
 		} else {
 			filesSkipped++
+			s.recordDecision(filepath.Join(target.Subdir, relPath), "repo-to-local", decision, started, nil)
 			logger.Debug("⏭️  Skipped unchanged file: %s", relPath)
 		}
 
@@ -713,21 +1321,22 @@ func (s *Syncer) copyFromRepository() error {
 	return nil
 }
 
-// shouldCopyFile determines if a file should be copied based on content hash comparison
-func (s *Syncer) shouldCopyFile(srcPath, destPath string, srcInfo os.FileInfo) bool {
+// copyDecision determines if a file should be copied based on content hash comparison
+// and returns the reason for the decision
+func (s *Syncer) copyDecision(srcPath, destPath string, srcInfo os.FileInfo) (bool, string) {
 
 	// Check if destination file exists
 	destInfo, err := os.Stat(destPath)
 	if err != nil {
 		// Destination doesn't exist - definitely copy
 		logger.Debug("RSYNC: Destination doesn't exist, copying: %s", filepath.Base(srcPath))
-		return true
+		return true, DecisionNew
 	}
 
 	// Check file size first (fastest check)
 	if srcInfo.Size() != destInfo.Size() {
 		logger.Debug("RSYNC: Size differs, copying: %s (src: %d, dest: %d)", filepath.Base(srcPath), srcInfo.Size(), destInfo.Size())
-		return true
+		return true, DecisionSizeDiff
 	}
 
 	logger.Debug("RSYNC: Sizes match, calculating hashes for: %s", filepath.Base(srcPath))
@@ -736,47 +1345,62 @@ func (s *Syncer) shouldCopyFile(srcPath, destPath string, srcInfo os.FileInfo) b
 	srcHash, err := s.calculateFileHashWithPolling(srcPath, s.config.Sync.HashPollingTimeout)
 	if err != nil {
 		logger.Debug("RSYNC: Could not calculate source hash, copying: %s (error: %v)", filepath.Base(srcPath), err)
-		return true
+		return true, DecisionHashError
 	}
 
 	destHash, err := s.calculateFileHashWithPolling(destPath, s.config.Sync.HashPollingTimeout)
 	if err != nil {
 		logger.Debug("RSYNC: Could not calculate destination hash, copying: %s (error: %v)", filepath.Base(srcPath), err)
-		return true
+		return true, DecisionHashError
 	}
 
 	if srcHash != destHash {
 		logger.Debug("RSYNC: Content hash differs, copying: %s (src: %s, dest: %s)", filepath.Base(srcPath), srcHash[:8], destHash[:8])
-		return true
+		return true, DecisionHashDiff
 	}
 
 	logger.Debug("RSYNC: Skipping identical file (same hash): %s", filepath.Base(srcPath))
-	return false
+	return false, DecisionSkipped
 }
 
 // calculateFileHash calculates SHA256 hash of a file with throttling and caching
 func (s *Syncer) calculateFileHash(filePath string) (string, error) {
 	logger.Debug("🔍 calculateFileHash called for: %s", filepath.Base(filePath))
 
-	// Check cache first
+	// Stat before hashing, so a change made while hashing invalidates the entry
+	info, err := os.Stat(filePath)
+	if err != nil {
+		return "", err
+	}
+
+	// Check cache first; an entry is stale once the file was rewritten
 	s.hashCacheMutex.RLock()
-	if hash, exists := s.hashCache[filePath]; exists {
-		s.hashCacheMutex.RUnlock()
+	entry, exists := s.hashCache[filePath]
+	s.hashCacheMutex.RUnlock()
+	if exists && entry.size == info.Size() && entry.modTime.Equal(info.ModTime()) {
 		logger.Debug("🔍 Hash found in cache for: %s", filepath.Base(filePath))
-		return hash, nil
+		return entry.hash, nil
 	}
-	s.hashCacheMutex.RUnlock()
 
 	logger.Debug("🔍 Hash not in cache, calculating for: %s", filepath.Base(filePath))
 	// Use parallel hash calculation
-	return s.calculateFileHashParallel(filePath)
+	hash, err := s.calculateFileHashParallel(filePath)
+	if err != nil {
+		return "", err
+	}
+
+	s.hashCacheMutex.Lock()
+	s.hashCache[filePath] = hashCacheEntry{hash: hash, size: info.Size(), modTime: info.ModTime()}
+	s.hashCacheMutex.Unlock()
+	return hash, nil
 }
 
 // calculateFileHashParallel calculates hash using parallel workers
 func (s *Syncer) calculateFileHashParallel(filePath string) (string, error) {
 	// Send job to worker
+	job := hashJob{filePath: filePath, result: make(chan HashResult, 1)}
 	select {
-	case s.hashJobChan <- filePath:
+	case s.hashJobChan <- job:
 	default:
 		// If channel is full, fall back to synchronous calculation
 		logger.Debug("Hash job channel full, using synchronous calculation for %s", filepath.Base(filePath))
@@ -785,16 +1409,10 @@ func (s *Syncer) calculateFileHashParallel(filePath string) (string, error) {
 
 	// Wait for result
 	select {
-	case result := <-s.hashResultChan:
+	case result := <-job.result:
 		if result.Error != nil {
 			return "", result.Error
 		}
-
-		// Cache the result
-		s.hashCacheMutex.Lock()
-		s.hashCache[filePath] = result.Hash
-		s.hashCacheMutex.Unlock()
-
 		return result.Hash, nil
 	case <-time.After(30 * time.Second): // Timeout after 30 seconds
 		return "", fmt.Errorf("hash calculation timeout for %s", filePath)
@@ -806,7 +1424,7 @@ func (s *Syncer) clearHashCache(filePath string) {
 	s.hashCacheMutex.Lock()
 	if filePath == "" {
 		// Clear entire cache
-		s.hashCache = make(map[string]string)
+		s.hashCache = make(map[string]hashCacheEntry)
 	} else {
 		// Clear specific file
 		delete(s.hashCache, filePath)
@@ -892,8 +1510,8 @@ func (s *Syncer) copyFile(src, dst string) error {
 		return fmt.Errorf("failed to read source file: %w", err)
 	}
 
-	// Write destination file
-	if err := os.WriteFile(dst, data, 0644); err != nil {
+	// Write destination file atomically; the destination may be the live Cursor config
+	if err := writeFileAtomic(dst, data, 0644, isCriticalFile(dst)); err != nil {
 		return fmt.Errorf("failed to write destination file: %w", err)
 	}
 
@@ -904,45 +1522,51 @@ func (s *Syncer) copyFile(src, dst string) error {
 // CleanupExcludedFiles removes files from the repository that should be excluded
 // This ensures that when users update their exclusion list, previously synced files
 // that should now be excluded are automatically removed from the repository
+// Only the targets' User directories are scanned, so other content in a shared repo is untouched
 func (s *Syncer) CleanupExcludedFiles() error {
 	logger.Debug("Cleaning up excluded files from repository...")
 
-	repoPath := s.config.Repository.LocalPath
 	var filesToRemove []string
 
-	// Walk through the repository and find files that should be excluded
-	err := filepath.Walk(repoPath, func(path string, info os.FileInfo, err error) error {
-		if err != nil {
-			return nil // Skip inaccessible files
-		}
+	for _, target := range s.config.Cursor.SyncTargets() {
+		_, repoUserPath := s.targetUserPaths(target)
 
-		// Skip .git directory
-		if info.IsDir() && info.Name() == ".git" {
-			return filepath.SkipDir
-		}
+		// Walk through the repository and find files that should be excluded
+		err := filepath.Walk(repoUserPath, func(path string, info os.FileInfo, err error) error {
+			if err != nil {
+				return nil // Skip inaccessible files
+			}
 
-		// Get relative path from repository root
-		relPath, err := filepath.Rel(repoPath, path)
-		if err != nil {
-			return nil
-		}
+			// Get relative path from the target's User directory
+			relPath, err := filepath.Rel(repoUserPath, path)
+			if err != nil {
+				return nil
+			}
 
-		// Skip root directory
-		if relPath == "." {
-			return nil
-		}
+			// Skip root directory
+			if relPath == "." {
+				return nil
+			}
 
-		// Check if this path should be excluded
-		if s.shouldExcludePath(relPath) {
-			filesToRemove = append(filesToRemove, path)
-			logger.Debug("Marked for removal (excluded): %s", relPath)
-		}
+			// Check if this path should be excluded
+			if s.shouldExcludePath(target, "User/"+relPath) {
+				// Allow-listed files may live below an excluded globalStorage or workspaceStorage folder
+				if info.IsDir() && s.hasAllowedFiles("User/"+relPath) {
+					return nil
+				}
+				filesToRemove = append(filesToRemove, path)
+				logger.Debug("Marked for removal (excluded): %s", filepath.Join(target.Subdir, "User", relPath))
+				if info.IsDir() {
+					return filepath.SkipDir
+				}
+			}
 
-		return nil
-	})
+			return nil
+		})
 
-	if err != nil {
-		return fmt.Errorf("failed to scan repository for excluded files: %w", err)
+		if err != nil {
+			return fmt.Errorf("failed to scan repository for excluded files: %w", err)
+		}
 	}
 
 	// Remove the excluded files
@@ -963,27 +1587,121 @@ func (s *Syncer) CleanupExcludedFiles() error {
 	return nil
 }
 
-func (s *Syncer) shouldExcludePath(path string) bool {
+// targetUserPaths returns the local and repository User directories of a sync target
+func (s *Syncer) targetUserPaths(target config.Target) (userPath, repoUserPath string) {
+	userPath = filepath.Join(target.ConfigPath, "User")
+	repoUserPath = filepath.Join(s.config.Repository.ContentPath(), target.Subdir, "User")
+	return userPath, repoUserPath
+}
+
+func (s *Syncer) shouldExcludePath(target config.Target, path string) bool {
+	excluded, _ := s.excludeReason(target, path)
+	return excluded
+}
+
+// excludeReason decides whether a path is excluded from syncing and describes the rule
+// that decided it ("" when no rule applies and the path is synced by default)
+func (s *Syncer) excludeReason(target config.Target, path string) (bool, string) {
 	// Always exclude the custom sync marker file (local only)
-	if strings.HasSuffix(path, ".custom.sync") {
-		return true
+	if filepath.Base(path) == syncMarkerFile {
+		return true, "sync marker file (never synced)"
+	}
+
+	// Operating system metadata is ignored by the repository's .gitignore as well
+	if isOSJunk(path) {
+		return true, "operating system metadata file (never synced)"
+	}
+
+	// Always exclude the ignore file itself (local only)
+	if filepath.Base(path) == ignore.FileName {
+		return true, ignore.FileName + " itself (never synced)"
+	}
+
+	// Allow-listed globalStorage files are synced regardless of exclusions
+	if pattern, ok := s.globalStorageAllowPattern(path); ok {
+		return false, fmt.Sprintf("allowed by cursor.global_storage_allow pattern %q", pattern)
+	}
+
+	// So are the folders of allow-listed workspaces below workspaceStorage
+	if entry, ok := s.workspaceAllowEntry(target, path); ok {
+		return false, workspaceRule(entry, s.WorkspaceLocation(target, path))
+	}
+
+	// Patterns from .cursorsyncignore are relative to the User directory
+	if relPath, ok := strings.CutPrefix(filepath.ToSlash(path), "User/"); ok {
+		if ignored, pattern := s.ignoreMatchers[target.Name].MatchPattern(relPath); ignored {
+			return true, fmt.Sprintf("matches %s pattern %q", ignore.FileName, pattern)
+		}
 	}
 
 	for _, excludePattern := range s.config.Cursor.ExcludePaths {
-		// Handle ** glob pattern for recursive matching
-		if strings.Contains(excludePattern, "**") {
-			if s.matchesRecursivePattern(path, excludePattern) {
-				return true
-			}
-		} else {
-			// Handle regular patterns
-			matched, _ := filepath.Match(excludePattern, path)
-			if matched || strings.HasPrefix(path, excludePattern) {
-				return true
-			}
+		if s.matchesPathPattern(path, excludePattern) {
+			return true, fmt.Sprintf("matches cursor.exclude_paths pattern %q", excludePattern)
 		}
 	}
-	return false
+	return false, ""
+}
+
+// matchesPathPattern matches a path against a cursor.exclude_paths style pattern: a glob,
+// a prefix, or a ** pattern
+func (s *Syncer) matchesPathPattern(path, pattern string) bool {
+	// Handle ** glob pattern for recursive matching
+	if strings.Contains(pattern, "**") {
+		return s.matchesRecursivePattern(path, pattern)
+	}
+	// Handle regular patterns
+	matched, _ := filepath.Match(pattern, path)
+	return matched || strings.HasPrefix(path, pattern)
+}
+
+// loadIgnoreFile (re)loads patterns from each target's <ConfigPath>/User/.cursorsyncignore
+// The patterns are merged with Cursor.ExcludePaths by shouldExcludePath
+func (s *Syncer) loadIgnoreFile() {
+	matchers := make(map[string]*ignore.Matcher)
+
+	for _, target := range s.config.Cursor.SyncTargets() {
+		ignorePath := filepath.Join(target.ConfigPath, "User", ignore.FileName)
+
+		matcher, err := ignore.Load(ignorePath)
+		if err != nil {
+			logger.Warn("Failed to load %s: %v", ignorePath, err)
+			matcher = s.ignoreMatchers[target.Name] // Keep the previous patterns
+		} else if patterns := matcher.Patterns(); len(patterns) > 0 {
+			logger.Debug("Loaded %d pattern(s) from %s", len(patterns), ignorePath)
+		}
+		matchers[target.Name] = matcher
+	}
+
+	s.ignoreMatchers = matchers
+}
+
+// globalStoragePrefix is the folder whose files can be selectively synced via cursor.global_storage_allow
+const globalStoragePrefix = "User/globalStorage/"
+
+// inAllowedGlobalStorage reports whether path is globalStorage or a folder below it
+// while an allow-list is configured, so the walk has to descend into it
+func (s *Syncer) inAllowedGlobalStorage(path string) bool {
+	if len(s.config.Cursor.GlobalStorageAllow) == 0 {
+		return false
+	}
+	path = filepath.ToSlash(path)
+	return path+"/" == globalStoragePrefix || strings.HasPrefix(path, globalStoragePrefix)
+}
+
+// globalStorageAllowPattern returns the cursor.global_storage_allow pattern matching path
+// when it is an allow-listed globalStorage file
+// Patterns are relative to globalStorage, e.g. "*/state.json" or "storage.json"
+func (s *Syncer) globalStorageAllowPattern(path string) (string, bool) {
+	relPath, ok := strings.CutPrefix(filepath.ToSlash(path), globalStoragePrefix)
+	if !ok {
+		return "", false
+	}
+	for _, pattern := range s.config.Cursor.GlobalStorageAllow {
+		if matched, _ := pathpkg.Match(pattern, relPath); matched {
+			return pattern, true
+		}
+	}
+	return "", false
 }
 
 // matchesRecursivePattern checks if a path matches a ** glob pattern
@@ -1015,16 +1733,35 @@ func (s *Syncer) ShouldPull() bool {
 	return s.forcePull || time.Since(s.lastSync) >= s.config.Sync.PullInterval
 }
 
-// hasCustomSyncMarker checks if the custom sync marker file exists
+// hasCustomSyncMarker checks if every sync target has the custom sync marker file
 func (s *Syncer) hasCustomSyncMarker() bool {
-	markerPath := filepath.Join(s.config.Cursor.ConfigPath, ".custom.sync")
-	_, err := os.Stat(markerPath)
+	for _, target := range s.config.Cursor.SyncTargets() {
+		if !s.hasTargetSyncMarker(target) {
+			return false
+		}
+	}
+	return true
+}
+
+// hasTargetSyncMarker checks if the custom sync marker file exists for a target
+func (s *Syncer) hasTargetSyncMarker(target config.Target) bool {
+	_, err := os.Stat(syncMarkerPath(target))
 	return err == nil
 }
 
-// createCustomSyncMarker creates the custom sync marker file
+// createCustomSyncMarker creates the custom sync marker file for every sync target
 func (s *Syncer) createCustomSyncMarker() error {
-	markerPath := filepath.Join(s.config.Cursor.ConfigPath, ".custom.sync")
+	for _, target := range s.config.Cursor.SyncTargets() {
+		if err := s.createTargetSyncMarker(target); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// createTargetSyncMarker creates the custom sync marker file for a target
+func (s *Syncer) createTargetSyncMarker(target config.Target) error {
+	markerPath := syncMarkerPath(target)
 
 	// Create the marker file with timestamp and sync information
 	content := fmt.Sprintf(`cursor-sync marker file
@@ -1046,6 +1783,11 @@ If deleted, cursor-sync will treat local settings as "fresh" and overwrite them
 		return fmt.Errorf("failed to create custom sync marker: %w", err)
 	}
 
+	// A 'marker --reset' is done once the target is synced again
+	if err := os.Remove(markerResetPath(target)); err != nil && !os.IsNotExist(err) {
+		logger.Warn("Failed to remove %s: %v", markerResetPath(target), err)
+	}
+
 	logger.Debug("Created/updated custom sync marker at: %s", markerPath)
 	return nil
 }
@@ -1054,8 +1796,20 @@ If deleted, cursor-sync will treat local settings as "fresh" and overwrite them
 func (s *Syncer) checkRepositoryPrivacy() error {
 	logger.Info("Checking repository privacy for security...")
 
-	checker := privacy.NewRepositoryChecker()
-	isPrivate, err := checker.CheckRepositoryPrivacy(s.config.Repository.URL)
+	isPrivate, err := s.privacyChecker.CheckRepositoryPrivacy(s.config.Repository.URL)
+
+	// Rate limiting says nothing about privacy - wait briefly and retry, or postpone the sync
+	if rateErr, ok := ratelimit.As(err); ok {
+		if wait := rateErr.Wait(); wait <= maxRateLimitWait {
+			logger.Warn("⏳ GitHub API rate limit hit - retrying privacy check in %v", wait.Round(time.Second))
+			time.Sleep(wait)
+			isPrivate, err = s.privacyChecker.CheckRepositoryPrivacy(s.config.Repository.URL)
+		}
+		if rateErr, ok := ratelimit.As(err); ok {
+			logger.Warn("⏳ GitHub API rate limit exceeded - sync postponed until %s", rateErr.Reset.Format("15:04:05"))
+			return fmt.Errorf("cannot verify repository privacy: %w", rateErr)
+		}
+	}
 
 	if err != nil {
 		privacy.ShowPrivacyCheckError(s.config.Repository.URL, err)
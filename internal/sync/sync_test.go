@@ -0,0 +1,968 @@
+package sync
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"cursor-sync/internal/config"
+	"cursor-sync/internal/git"
+	"cursor-sync/internal/paths"
+)
+
+func TestCloseStopsHashWorkers(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "settings.json")
+	if err := os.WriteFile(file, []byte(`{}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	s := &Syncer{
+		hashCache:    make(map[string]hashCacheEntry),
+		hashWorkers:  2,
+		hashJobChan:  make(chan hashJob, 4),
+		hashStopChan: make(chan struct{}),
+	}
+	s.startHashWorkers()
+
+	// Nobody reads the results, as when a caller timed out waiting for them
+	for i := 0; i < 4; i++ {
+		s.hashJobChan <- hashJob{filePath: file, result: make(chan HashResult, 1)}
+	}
+	time.Sleep(50 * time.Millisecond)
+
+	closed := make(chan struct{})
+	go func() {
+		s.Close()
+		s.Close() // Closing twice must not panic on the closed stop channel
+		close(closed)
+	}()
+
+	select {
+	case <-closed:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Close() didn't return - hash workers are still running")
+	}
+}
+
+func TestConcurrentHashesMatchTheirFiles(t *testing.T) {
+	dir := t.TempDir()
+	want := make(map[string]string)
+	for i := 0; i < 32; i++ {
+		file := filepath.Join(dir, fmt.Sprintf("file-%d.json", i))
+		content := fmt.Sprintf(`{"n": %d}`, i)
+		if err := os.WriteFile(file, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		want[file] = fmt.Sprintf("%x", sha256.Sum256([]byte(content)))
+	}
+
+	s := &Syncer{
+		hashCache:    make(map[string]hashCacheEntry),
+		hashWorkers:  4,
+		hashJobChan:  make(chan hashJob, 8),
+		hashStopChan: make(chan struct{}),
+	}
+	s.startHashWorkers()
+	defer s.Close()
+
+	var wg sync.WaitGroup
+	for file, hash := range want {
+		wg.Add(1)
+		go func(file, hash string) {
+			defer wg.Done()
+			got, err := s.calculateFileHash(file)
+			if err != nil {
+				t.Errorf("calculateFileHash(%s) error = %v", filepath.Base(file), err)
+				return
+			}
+			if got != hash {
+				t.Errorf("calculateFileHash(%s) returned the hash of another file", filepath.Base(file))
+			}
+		}(file, hash)
+	}
+	wg.Wait()
+
+	// The cache must only hold each file's own hash
+	for file, hash := range want {
+		if entry := s.hashCache[file]; entry.hash != hash {
+			t.Errorf("cached hash for %s belongs to another file", filepath.Base(file))
+		}
+	}
+}
+
+func TestCachedHashFollowsSameSizeEdits(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "settings.json")
+	if err := os.WriteFile(file, []byte(`{"editor.fontSize": 14}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	s := &Syncer{hashCache: make(map[string]hashCacheEntry)}
+	before, err := s.calculateFileHash(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Same size, new content and modification time
+	if err := os.WriteFile(file, []byte(`{"editor.fontSize": 16}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	later := time.Now().Add(time.Minute)
+	if err := os.Chtimes(file, later, later); err != nil {
+		t.Fatal(err)
+	}
+
+	after, err := s.calculateFileHash(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if after == before {
+		t.Error("calculateFileHash() returned the cached hash of the previous content")
+	}
+}
+
+func TestCopyFileReplacesDestinationAtomically(t *testing.T) {
+	dir := t.TempDir()
+	src := filepath.Join(dir, "src", "settings.json")
+	dst := filepath.Join(dir, "dst", "settings.json")
+	if err := os.MkdirAll(filepath.Dir(src), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(src, []byte(`{"new": true}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	s := &Syncer{}
+	if err := s.copyFile(src, dst); err != nil {
+		t.Fatalf("copyFile() error = %v", err)
+	}
+	if err := os.WriteFile(src, []byte(`{"newer": true}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.copyFile(src, dst); err != nil {
+		t.Fatalf("copyFile() over existing file error = %v", err)
+	}
+
+	data, err := os.ReadFile(dst)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != `{"newer": true}` {
+		t.Errorf("destination = %s, want source content", data)
+	}
+
+	// No temporary files may be left next to the destination
+	entries, err := os.ReadDir(filepath.Dir(dst))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 {
+		t.Errorf("destination directory has %d entries, want 1", len(entries))
+	}
+}
+
+func TestCopyToRepositorySkipsLockedDatabases(t *testing.T) {
+	configPath := t.TempDir()
+	repoPath := t.TempDir()
+	userPath := filepath.Join(configPath, "User")
+	files := map[string]string{
+		"settings.json":               `{}`,
+		"state.vscdb":                 "main database",
+		"state.vscdb-wal":             "uncheckpointed writes",
+		"state.vscdb-shm":             "shared memory",
+		"idle.vscdb":                  "checkpointed database",
+		"history/other.vscdb-journal": "rollback journal",
+	}
+	for name, content := range files {
+		path := filepath.Join(userPath, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	s := &Syncer{config: &config.Config{
+		Repository: config.Repository{LocalPath: repoPath},
+		Sync:       config.Sync{SnapshotDatabases: false},
+	}}
+	target := config.Target{Name: "cursor", ConfigPath: configPath}
+	if err := s.copyToRepository(target); err != nil {
+		t.Fatalf("copyToRepository() error = %v", err)
+	}
+
+	repoUserPath := filepath.Join(repoPath, "User")
+	for name, wantCopied := range map[string]bool{
+		"settings.json":               true,
+		"idle.vscdb":                  true,
+		"state.vscdb":                 false, // Locked: its WAL holds writes
+		"state.vscdb-wal":             false,
+		"state.vscdb-shm":             false,
+		"history/other.vscdb-journal": false,
+	} {
+		_, err := os.Stat(filepath.Join(repoUserPath, name))
+		if copied := err == nil; copied != wantCopied {
+			t.Errorf("%s copied = %v, want %v", name, copied, wantCopied)
+		}
+	}
+}
+
+func TestCopyToRepositorySyncsAllowedGlobalStorageFiles(t *testing.T) {
+	configPath := t.TempDir()
+	repoPath := t.TempDir()
+	userPath := filepath.Join(configPath, "User")
+	for _, name := range []string{
+		"globalStorage/storage.json",
+		"globalStorage/state.vscdb",
+		"globalStorage/publisher.ext/state.json",
+		"globalStorage/publisher.ext/cache.bin",
+	} {
+		path := filepath.Join(userPath, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(name), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	s := &Syncer{config: &config.Config{
+		Repository: config.Repository{LocalPath: repoPath},
+		Cursor: config.Cursor{
+			ExcludePaths:       []string{"User/globalStorage/"},
+			GlobalStorageAllow: []string{"*/state.json", "storage.json"},
+		},
+	}}
+	target := config.Target{Name: "cursor", ConfigPath: configPath}
+	if err := s.copyToRepository(target); err != nil {
+		t.Fatalf("copyToRepository() error = %v", err)
+	}
+
+	repoUserPath := filepath.Join(repoPath, "User")
+	for name, wantCopied := range map[string]bool{
+		"globalStorage/storage.json":             true,
+		"globalStorage/publisher.ext/state.json": true,
+		"globalStorage/state.vscdb":              false,
+		"globalStorage/publisher.ext/cache.bin":  false,
+	} {
+		_, err := os.Stat(filepath.Join(repoUserPath, name))
+		if copied := err == nil; copied != wantCopied {
+			t.Errorf("%s copied = %v, want %v", name, copied, wantCopied)
+		}
+	}
+
+	// Cleanup must keep the allow-listed files in the repository
+	if err := s.CleanupExcludedFiles(); err != nil {
+		t.Fatalf("CleanupExcludedFiles() error = %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(repoUserPath, "globalStorage/publisher.ext/state.json")); err != nil {
+		t.Errorf("allow-listed file removed by cleanup: %v", err)
+	}
+}
+
+func TestCopyToRepositorySyncsAllowedWorkspaces(t *testing.T) {
+	configPath := t.TempDir()
+	repoPath := t.TempDir()
+	userPath := filepath.Join(configPath, "User")
+	for name, content := range map[string]string{
+		"workspaceStorage/1a2b/workspace.json": `{"folder": "file:///home/me/src/dotfiles"}`,
+		"workspaceStorage/1a2b/state.vscdb":    "dotfiles state",
+		"workspaceStorage/3c4d/workspace.json": `{"folder": "file:///home/me/src/other"}`,
+		"workspaceStorage/3c4d/state.vscdb":    "other state",
+		"workspaceStorage/5e6f/state.vscdb":    "allowed by hash",
+	} {
+		path := filepath.Join(userPath, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	s := &Syncer{config: &config.Config{
+		Repository: config.Repository{LocalPath: repoPath},
+		Cursor: config.Cursor{
+			ExcludePaths:   []string{"User/workspaceStorage/"},
+			WorkspaceAllow: []string{"dotfiles", "5e6f"},
+		},
+	}}
+	target := config.Target{Name: "cursor", ConfigPath: configPath}
+	if err := s.copyToRepository(target); err != nil {
+		t.Fatalf("copyToRepository() error = %v", err)
+	}
+
+	repoUserPath := filepath.Join(repoPath, "User")
+	for name, wantCopied := range map[string]bool{
+		"workspaceStorage/1a2b/state.vscdb": true,
+		"workspaceStorage/5e6f/state.vscdb": true,
+		"workspaceStorage/3c4d/state.vscdb": false,
+	} {
+		_, err := os.Stat(filepath.Join(repoUserPath, filepath.FromSlash(name)))
+		if copied := err == nil; copied != wantCopied {
+			t.Errorf("%s copied = %v, want %v", name, copied, wantCopied)
+		}
+	}
+
+	if excluded, rule := s.excludeReason(target, "User/workspaceStorage/1a2b/state.vscdb"); excluded || !strings.Contains(rule, "/home/me/src/dotfiles") {
+		t.Errorf("excludeReason() = %v, %q, want allowed with the workspace path", excluded, rule)
+	}
+
+	// Cleanup must keep the allowed workspaces in the repository
+	if err := s.CleanupExcludedFiles(); err != nil {
+		t.Fatalf("CleanupExcludedFiles() error = %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(repoUserPath, "workspaceStorage", "1a2b", "state.vscdb")); err != nil {
+		t.Errorf("allowed workspace removed by cleanup: %v", err)
+	}
+}
+
+func TestCopyToRepositorySkipsInvalidJSON(t *testing.T) {
+	configPath := t.TempDir()
+	repoPath := t.TempDir()
+	userPath := filepath.Join(configPath, "User")
+	write := func(name, content string) {
+		t.Helper()
+		path := filepath.Join(userPath, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	read := func(name string) string {
+		t.Helper()
+		data, _ := os.ReadFile(filepath.Join(repoPath, "User", filepath.FromSlash(name)))
+		return string(data)
+	}
+
+	s := &Syncer{
+		config: &config.Config{
+			Repository: config.Repository{LocalPath: repoPath},
+			Sync:       config.Sync{ValidateJSON: true},
+		},
+		hashCache: make(map[string]hashCacheEntry),
+	}
+	target := config.Target{Name: "cursor", ConfigPath: configPath}
+
+	// The first copy into an empty repository checks files too
+	write("settings.json", `{
+	// Comments and trailing commas are fine
+	"editor.fontSize": 14,
+}`)
+	write("snippets/go.json", `{"Print": {"prefix": "pr"`)
+	if err := s.copyToRepository(target); err != nil {
+		t.Fatalf("copyToRepository() error = %v", err)
+	}
+	if read("settings.json") == "" {
+		t.Error("valid JSONC settings.json wasn't copied")
+	}
+	if got := read("snippets/go.json"); got != "" {
+		t.Errorf("truncated snippet copied to the repository: %q", got)
+	}
+
+	// A later broken edit keeps the last good version in the repository
+	good := read("settings.json")
+	write("settings.json", `{"editor.fontSize": 16`)
+	write("keybindings.json", `[]`)
+	if err := s.copyToRepository(target); err != nil {
+		t.Fatalf("copyToRepository() error = %v", err)
+	}
+	if got := read("settings.json"); got != good {
+		t.Errorf("repository settings.json = %q, want the last good version", got)
+	}
+	if got := read("keybindings.json"); got != `[]` {
+		t.Errorf("repository keybindings.json = %q, want the valid local file", got)
+	}
+}
+
+func TestVerifyReportsDivergence(t *testing.T) {
+	configPath := t.TempDir()
+	repoPath := t.TempDir()
+	write := func(path, content string) {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write(filepath.Join(configPath, "User", "settings.json"), `{"a": 1}`)
+	write(filepath.Join(repoPath, "User", "settings.json"), `{"a": 1}`)
+	write(filepath.Join(configPath, "User", "keybindings.json"), `[1]`)
+	write(filepath.Join(repoPath, "User", "keybindings.json"), `[2]`)
+	write(filepath.Join(configPath, "User", "snippets", "go.json"), `{}`)
+	write(filepath.Join(repoPath, "User", "tasks.json"), `{}`)
+	write(filepath.Join(configPath, "User", "workspaceStorage", "x.json"), `{}`)
+	// Matching pairs with distinct contents, hashed concurrently: any hash paired with the
+	// wrong file would show up as a mismatch
+	for i := 0; i < 16; i++ {
+		name := fmt.Sprintf("snippet-%d.code-snippets", i)
+		content := fmt.Sprintf(`{"n": %d}`, i)
+		write(filepath.Join(configPath, "User", "snippets", name), content)
+		write(filepath.Join(repoPath, "User", "snippets", name), content)
+	}
+
+	s := &Syncer{
+		config: &config.Config{
+			Repository: config.Repository{LocalPath: repoPath},
+			Cursor: config.Cursor{
+				ConfigPath:   configPath,
+				ExcludePaths: []string{"User/workspaceStorage/"},
+			},
+		},
+		hashCache:    make(map[string]hashCacheEntry),
+		hashWorkers:  2,
+		hashJobChan:  make(chan hashJob, 4),
+		hashStopChan: make(chan struct{}),
+	}
+	s.startHashWorkers()
+	defer s.Close()
+
+	result, err := s.Verify()
+	if err != nil {
+		t.Fatalf("Verify() error = %v", err)
+	}
+	if !result.Diverged() {
+		t.Fatal("Verify() reported no divergence")
+	}
+	if result.Checked != 18 {
+		t.Errorf("Checked = %d, want 18", result.Checked)
+	}
+	if want := filepath.Join("User", "keybindings.json"); len(result.Mismatched) != 1 || result.Mismatched[0] != want {
+		t.Errorf("Mismatched = %v, want [%s]", result.Mismatched, want)
+	}
+	if want := filepath.Join("User", "snippets", "go.json"); len(result.OnlyLocal) != 1 || result.OnlyLocal[0] != want {
+		t.Errorf("OnlyLocal = %v, want [%s]", result.OnlyLocal, want)
+	}
+	if want := filepath.Join("User", "tasks.json"); len(result.OnlyRemote) != 1 || result.OnlyRemote[0] != want {
+		t.Errorf("OnlyRemote = %v, want [%s]", result.OnlyRemote, want)
+	}
+}
+
+func TestCopyToRepositorySkipsSymlinks(t *testing.T) {
+	configPath := t.TempDir()
+	repoPath := t.TempDir()
+	userPath := filepath.Join(configPath, "User")
+	if err := os.MkdirAll(userPath, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	secret := filepath.Join(t.TempDir(), "id_rsa")
+	if err := os.WriteFile(secret, []byte("private key"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(secret, filepath.Join(userPath, "linked.json")); err != nil {
+		t.Skipf("symlinks not supported: %v", err)
+	}
+	if err := os.Symlink(t.TempDir(), filepath.Join(userPath, "linkeddir")); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(userPath, "settings.json"), []byte(`{}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	s := &Syncer{config: &config.Config{Repository: config.Repository{LocalPath: repoPath}}}
+	if err := s.copyToRepository(config.Target{Name: "cursor", ConfigPath: configPath}); err != nil {
+		t.Fatalf("copyToRepository() error = %v", err)
+	}
+
+	repoUserPath := filepath.Join(repoPath, "User")
+	if _, err := os.Lstat(filepath.Join(repoUserPath, "settings.json")); err != nil {
+		t.Errorf("settings.json not copied: %v", err)
+	}
+	for _, name := range []string{"linked.json", "linkeddir"} {
+		if _, err := os.Lstat(filepath.Join(repoUserPath, name)); err == nil {
+			t.Errorf("symlink %s was copied into the repository", name)
+		}
+	}
+}
+
+func TestCheckRepoSizeRejectsOversizedTree(t *testing.T) {
+	repoPath := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(repoPath, ".git"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	// Git objects don't count towards the tree size
+	if err := os.WriteFile(filepath.Join(repoPath, ".git", "pack"), make([]byte, 2<<20), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(repoPath, "settings.json"), []byte(`{}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	s := &Syncer{config: &config.Config{
+		Repository: config.Repository{LocalPath: repoPath},
+		Sync:       config.Sync{MaxRepoSize: 1},
+	}}
+	if err := s.checkRepoSize(); err != nil {
+		t.Fatalf("checkRepoSize() error = %v for a small tree", err)
+	}
+
+	if err := os.WriteFile(filepath.Join(repoPath, "huge.bin"), make([]byte, 2<<20), 0644); err != nil {
+		t.Fatal(err)
+	}
+	err := s.checkRepoSize()
+	if err == nil {
+		t.Fatal("checkRepoSize() accepted a tree over the limit")
+	}
+	if !strings.Contains(err.Error(), "huge.bin (2.0 MB)") {
+		t.Errorf("error doesn't name the largest file: %v", err)
+	}
+}
+
+func TestSyncDeletedFilesFromRemoteRefusesMassDelete(t *testing.T) {
+	configPath := t.TempDir()
+	repoPath := t.TempDir()
+	userPath := filepath.Join(configPath, "User")
+	repoUserPath := filepath.Join(repoPath, "User")
+	for _, dir := range []string{userPath, repoUserPath} {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(repoUserPath, "settings.json"), []byte(`{}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 12; i++ {
+		name := filepath.Join(userPath, "snippet"+strings.Repeat("x", i)+".json")
+		if err := os.WriteFile(name, []byte(`{}`), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	s := &Syncer{config: &config.Config{
+		Repository: config.Repository{LocalPath: repoPath},
+		Sync:       config.Sync{MaxDeletes: 5},
+	}}
+	target := config.Target{Name: "cursor", ConfigPath: configPath}
+
+	err := s.syncDeletedFilesFromRemote(target)
+	if !errors.Is(err, ErrTooManyDeletes) {
+		t.Fatalf("syncDeletedFilesFromRemote() error = %v, want ErrTooManyDeletes", err)
+	}
+	if entries, _ := os.ReadDir(userPath); len(entries) != 12 {
+		t.Errorf("%d local files left after refused deletion, want 12", len(entries))
+	}
+
+	s.ConfirmDeletes(true)
+	if err := s.syncDeletedFilesFromRemote(target); err != nil {
+		t.Fatalf("syncDeletedFilesFromRemote() error = %v after confirmation", err)
+	}
+	if entries, _ := os.ReadDir(userPath); len(entries) != 0 {
+		t.Errorf("%d local files left after confirmed deletion, want 0", len(entries))
+	}
+}
+
+func TestCopyToRepositoryRecordsManifest(t *testing.T) {
+	configPath := t.TempDir()
+	repoPath := t.TempDir()
+	userPath := filepath.Join(configPath, "User")
+	if err := os.MkdirAll(userPath, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(userPath, "settings.json"), []byte(`{}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	s := &Syncer{config: &config.Config{Repository: config.Repository{LocalPath: repoPath}}}
+	target := config.Target{Name: "cursor", ConfigPath: configPath}
+	if err := s.copyToRepository(target); err != nil {
+		t.Fatalf("copyToRepository() error = %v", err)
+	}
+
+	hostname, _ := os.Hostname()
+	entry, ok := s.loadManifest()["User/settings.json"]
+	if !ok {
+		t.Fatal("copied file missing from the manifest")
+	}
+	if entry.Host != hostname || entry.Hash != "44136fa355b3678a1146ad16f7e8649e94fb4fc21fe77e8310c060f61caaff8a" {
+		t.Errorf("manifest entry = %+v, want host %s and the SHA-256 of {}", entry, hostname)
+	}
+
+	if err := os.Remove(filepath.Join(userPath, "settings.json")); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.syncDeletedFiles(target); err != nil {
+		t.Fatalf("syncDeletedFiles() error = %v", err)
+	}
+	if _, ok := s.loadManifest()["User/settings.json"]; ok {
+		t.Error("deleted file still in the manifest")
+	}
+}
+
+func TestExplainReportsExclusionRule(t *testing.T) {
+	configPath := t.TempDir()
+	repoPath := t.TempDir()
+	for _, dir := range []string{filepath.Join(configPath, "User", "History"), filepath.Join(repoPath, "User")} {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(configPath, "User", "History", "a.json"), []byte(`{}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(configPath, "User", "settings.json"), []byte(`{}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	s := &Syncer{config: &config.Config{
+		Repository: config.Repository{LocalPath: repoPath},
+		Cursor:     config.Cursor{ConfigPath: configPath, ExcludePaths: []string{"User/History"}},
+	}}
+
+	explanations, err := s.Explain("History/a.json")
+	if err != nil {
+		t.Fatalf("Explain() error = %v", err)
+	}
+	e := explanations[0]
+	if !e.Excluded || !strings.Contains(e.Rule, `"User/History"`) {
+		t.Errorf("Explain(History/a.json) = excluded %v, rule %q; want excluded by User/History", e.Excluded, e.Rule)
+	}
+
+	explanations, err = s.Explain("User/settings.json")
+	if err != nil {
+		t.Fatalf("Explain() error = %v", err)
+	}
+	e = explanations[0]
+	if e.Excluded || !e.Local.Exists || e.Repo.Exists {
+		t.Errorf("Explain(User/settings.json) = %+v; want synced, local only", e)
+	}
+	if !strings.HasPrefix(e.Push, "copy local → repository") {
+		t.Errorf("Push = %q, want a copy to the repository", e.Push)
+	}
+}
+
+func TestCopyToRepositoryCopiesInitialSyncInParallel(t *testing.T) {
+	configPath := t.TempDir()
+	repoPath := t.TempDir()
+	userPath := filepath.Join(configPath, "User")
+	for i := 0; i < 50; i++ {
+		name := filepath.Join(userPath, "snippets", strings.Repeat("s", i%5+1), "file"+strings.Repeat("x", i)+".json")
+		if err := os.MkdirAll(filepath.Dir(name), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(name, []byte(name), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	s := &Syncer{
+		config:      &config.Config{Repository: config.Repository{LocalPath: repoPath}},
+		hashWorkers: 4,
+	}
+	if err := s.copyToRepository(config.Target{Name: "cursor", ConfigPath: configPath}); err != nil {
+		t.Fatalf("copyToRepository() error = %v", err)
+	}
+
+	var copied int
+	err := filepath.Walk(userPath, func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return err
+		}
+		relPath, _ := filepath.Rel(userPath, path)
+		data, err := os.ReadFile(filepath.Join(repoPath, "User", relPath))
+		if err != nil || string(data) != path {
+			t.Errorf("%s not copied intact: %q, %v", relPath, data, err)
+		}
+		copied++
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if copied != 50 {
+		t.Errorf("walked %d files, want 50", copied)
+	}
+	if entries := len(s.loadManifest()); entries != 50 {
+		t.Errorf("manifest has %d entries, want 50", entries)
+	}
+}
+
+func TestEstimateRemaining(t *testing.T) {
+	tests := []struct {
+		name              string
+		done, total       int
+		bytes, totalBytes int64
+		want              time.Duration
+		wantOK            bool
+	}{
+		{"nothing copied yet", 0, 10, 0, 1000, 0, false},
+		{"by bytes", 9, 10, 250, 1000, 30 * time.Second, true},
+		{"by files when sizes are unknown", 5, 10, 0, 0, 10 * time.Second, true},
+		{"done", 10, 10, 1000, 1000, 0, true},
+	}
+	for _, tt := range tests {
+		got, ok := estimateRemaining(10*time.Second, tt.done, tt.total, tt.bytes, tt.totalBytes)
+		if got != tt.want || ok != tt.wantOK {
+			t.Errorf("%s: estimateRemaining() = %v, %v, want %v, %v", tt.name, got, ok, tt.want, tt.wantOK)
+		}
+	}
+}
+
+func TestRecordSyncKeepsRecentSyncs(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	for i := 0; i < maxSyncRecords+5; i++ {
+		recordSync("pull", time.Now().Add(-2*time.Second))
+	}
+	recordSync("push", time.Now().Add(-4*time.Second))
+
+	status, err := ReadStatus()
+	if err != nil {
+		t.Fatalf("ReadStatus() error = %v", err)
+	}
+	if len(status.Recent) != maxSyncRecords {
+		t.Errorf("status file keeps %d syncs, want %d", len(status.Recent), maxSyncRecords)
+	}
+	if last, ok := status.LastSync(); !ok || last.Operation != "push" {
+		t.Errorf("LastSync() = %+v, %v, want the push", last, ok)
+	}
+	if average, count := status.AverageDuration("pull"); count != maxSyncRecords-1 || average < 2*time.Second || average > 3*time.Second {
+		t.Errorf("AverageDuration(pull) = %v, %d", average, count)
+	}
+}
+
+func TestPushStaleFallsBackToOldestSync(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	now := time.Now().Add(3 * time.Hour)
+
+	// No push has succeeded yet: the first recorded sync starts the clock
+	recordSync("push", time.Now())
+	status, err := ReadStatus()
+	if err != nil {
+		t.Fatalf("ReadStatus() error = %v", err)
+	}
+	if age, stale := status.PushStale(time.Hour, now); !stale || age < 3*time.Hour {
+		t.Errorf("PushStale() = %v, %v, want stale for about 3h", age, stale)
+	}
+	if _, stale := status.PushStale(0, now); stale {
+		t.Error("PushStale(0) reported stale, want the warning disabled")
+	}
+
+	recordPushState(0)
+	if status, err = ReadStatus(); err != nil {
+		t.Fatalf("ReadStatus() error = %v", err)
+	}
+	if _, stale := status.PushStale(time.Hour, time.Now()); stale {
+		t.Error("PushStale() reported stale right after a push")
+	}
+}
+
+func TestGitIgnorePatternsMatchExclusions(t *testing.T) {
+	s := &Syncer{config: &config.Config{
+		Repository: config.Repository{Subdir: "cursor"},
+		Cursor: config.Cursor{
+			ConfigPath:         "/unused",
+			ExcludePaths:       []string{"User/History/", "**/node_modules/", "User/globalStorage/"},
+			GlobalStorageAllow: []string{"storage.json"},
+		},
+	}}
+
+	patterns := strings.Join(s.gitIgnorePatterns(), "\n")
+	for _, want := range []string{".custom.sync", ".DS_Store", "Thumbs.db", "/cursor/User/History/", "/cursor/**/node_modules/"} {
+		if !strings.Contains(patterns, want) {
+			t.Errorf("gitIgnorePatterns() lacks %q:\n%s", want, patterns)
+		}
+	}
+	if strings.Contains(patterns, "globalStorage") {
+		t.Errorf("gitIgnorePatterns() ignores globalStorage despite its allow-list:\n%s", patterns)
+	}
+
+	target := config.Target{Name: "cursor", ConfigPath: "/unused"}
+	for _, path := range []string{"User/.DS_Store", "User/snippets/Thumbs.db", ".custom.sync"} {
+		if !s.shouldExcludePath(target, path) {
+			t.Errorf("shouldExcludePath(%s) = false, want excluded like .gitignore", path)
+		}
+	}
+	if s.shouldExcludePath(target, "User/settings.custom.sync") {
+		t.Error("shouldExcludePath() excludes a file merely ending in the marker name")
+	}
+}
+
+func TestCopyToRepositoryNeverCopiesSyncMarker(t *testing.T) {
+	configPath := t.TempDir()
+	repoPath := t.TempDir()
+	for _, name := range []string{"User/.custom.sync", "User/snippets/.custom.sync", "User/settings.json"} {
+		path := filepath.Join(configPath, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(name), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	s := &Syncer{config: &config.Config{Repository: config.Repository{LocalPath: repoPath}}}
+	if err := s.copyToRepository(config.Target{Name: "cursor", ConfigPath: configPath}); err != nil {
+		t.Fatalf("copyToRepository() error = %v", err)
+	}
+
+	filepath.Walk(repoPath, func(path string, info os.FileInfo, err error) error {
+		if err == nil && info.Name() == ".custom.sync" {
+			t.Errorf("sync marker copied into the repository: %s", path)
+		}
+		return nil
+	})
+	if _, err := os.Stat(filepath.Join(repoPath, "User", "settings.json")); err != nil {
+		t.Errorf("settings.json not copied: %v", err)
+	}
+}
+
+func TestInitialOverwriteKeepsNewerLocalFiles(t *testing.T) {
+	tests := []struct {
+		name     string
+		strategy string
+		reset    bool // 'marker --reset' was run
+		want     string
+	}{
+		// A missing marker may have been lost, so newer local files are only replaced on request
+		{"newer", "newer", false, "local"},
+		{"remote", "remote", false, "remote"},
+		{"local", "local", false, "local"},
+		{"local after marker reset", "local", true, "remote"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("HOME", t.TempDir())
+			configPath := t.TempDir()
+			repoPath := t.TempDir()
+			writeFile := func(root, name, content string) {
+				path := filepath.Join(root, "User", name)
+				if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+					t.Fatal(err)
+				}
+				if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			writeFile(repoPath, "settings.json", "remote")
+			writeFile(configPath, "settings.json", "local")
+			writeFile(repoPath, "keybindings.json", "same")
+			writeFile(configPath, "keybindings.json", "same")
+
+			// The repository has no commits, so every differing local file counts as newer
+			s := &Syncer{
+				repo: &git.Repository{},
+				config: &config.Config{
+					Repository: config.Repository{LocalPath: repoPath},
+					Cursor:     config.Cursor{ConfigPath: configPath},
+					Sync:       config.Sync{ConflictResolve: tt.strategy},
+				},
+			}
+			if tt.reset {
+				if _, err := ResetSyncMarkers(s.config); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			keep, err := s.resolveInitialConflicts()
+			if err != nil {
+				t.Fatalf("resolveInitialConflicts() error = %v", err)
+			}
+			if err := s.syncFromRemote(keep); err != nil {
+				t.Fatalf("syncFromRemote() error = %v", err)
+			}
+
+			data, err := os.ReadFile(filepath.Join(configPath, "User", "settings.json"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(data) != tt.want {
+				t.Errorf("settings.json = %q, want %q", data, tt.want)
+			}
+
+			stateDir, err := paths.StateDir()
+			if err != nil {
+				t.Fatal(err)
+			}
+			backups, _ := filepath.Glob(filepath.Join(stateDir, "backups", "*", "User", "settings.json"))
+			if backedUp := len(backups) > 0; backedUp != (tt.want == "remote") {
+				t.Errorf("settings.json backed up = %v, want %v", backedUp, tt.want == "remote")
+			}
+
+			if tt.reset {
+				if err := s.createCustomSyncMarker(); err != nil {
+					t.Fatal(err)
+				}
+				if markerResetRequested(config.Target{ConfigPath: configPath}) {
+					t.Error("marker reset still pending after the target synced again")
+				}
+			}
+		})
+	}
+}
+
+func TestForceSyncDropsCachedHashes(t *testing.T) {
+	s := &Syncer{hashCache: map[string]hashCacheEntry{"/cursor/User/settings.json": {hash: "stale"}}}
+
+	s.ForcePull()
+	if len(s.hashCache) != 0 || !s.forcePull {
+		t.Errorf("ForcePull() left hash cache %v, forcePull = %v", s.hashCache, s.forcePull)
+	}
+
+	s.hashCache["/cursor/User/settings.json"] = hashCacheEntry{hash: "stale"}
+	s.ForcePush()
+	if len(s.hashCache) != 0 || !s.forcePush {
+		t.Errorf("ForcePush() left hash cache %v, forcePush = %v", s.hashCache, s.forcePush)
+	}
+}
+
+func TestMergeFileCombinesKeybindingsSnippetsAndManifest(t *testing.T) {
+	for name, mergeable := range map[string]bool{
+		"User/keybindings.json":               true,
+		"User/profiles/work/keybindings.json": true,
+		"User/snippets/go.json":               true,
+		"User/snippets/shared.code-snippets":  true,
+		"cursor/.cursor-sync/manifest.json":   true,
+		".cursor-sync/manifest.json":          true,
+		"User/settings.json":                  false,
+		"User/globalStorage/state.vscdb":      false,
+		"User/snippets/notes.txt":             false,
+		"User/workspaceStorage/snippets.json": false,
+	} {
+		if got := mergeFile(name) != nil; got != mergeable {
+			t.Errorf("mergeFile(%q) mergeable = %v, want %v", name, got, mergeable)
+		}
+	}
+
+	entry := func(host string, minute int) ManifestEntry {
+		return ManifestEntry{Host: host, Hash: host, Modified: time.Date(2024, 5, 1, 12, minute, 0, 0, time.UTC)}
+	}
+	encode := func(m manifest) []byte {
+		data, err := json.Marshal(m)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return data
+	}
+	base := manifest{"User/settings.json": entry("base", 0), "User/old.json": entry("base", 0)}
+	local := manifest{"User/settings.json": entry("laptop", 10), "User/keybindings.json": entry("laptop", 10)}
+	remote := manifest{"User/settings.json": entry("desktop", 20), "User/old.json": entry("base", 0), "User/snippets/go.json": entry("desktop", 5)}
+
+	data, err := mergeManifests(encode(base), encode(local), encode(remote))
+	if err != nil {
+		t.Fatalf("mergeManifests() error = %v", err)
+	}
+	merged, err := parseManifest(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{"User/settings.json": "desktop", "User/keybindings.json": "laptop", "User/snippets/go.json": "desktop"}
+	if len(merged) != len(want) {
+		t.Errorf("merged manifest has %d entries, want %d: %v", len(merged), len(want), merged)
+	}
+	for key, host := range want {
+		if merged[key].Host != host {
+			t.Errorf("merged[%s] = %+v, want the change by %s", key, merged[key], host)
+		}
+	}
+}
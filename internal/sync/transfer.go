@@ -0,0 +1,135 @@
+package sync
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"cursor-sync/internal/config"
+	"cursor-sync/internal/ignore"
+	"cursor-sync/internal/logger"
+)
+
+// prepareRepository makes the local clone current for a one-shot operation:
+// it opens and pulls an existing clone, or clones the repository
+// Unlike Initialize it never touches the local Cursor settings
+func (s *Syncer) prepareRepository() error {
+	if err := s.checkRepositoryPrivacy(); err != nil {
+		return fmt.Errorf("repository privacy check failed: %w", err)
+	}
+
+	if _, err := os.Stat(filepath.Join(s.config.Repository.LocalPath, ".git")); err != nil {
+		logger.Info("Repository doesn't exist locally - cloning from remote")
+		if err := s.repo.Clone(s.config.Repository.URL); err != nil {
+			return fmt.Errorf("failed to clone repository: %w", err)
+		}
+		return nil
+	}
+
+	if err := s.repo.Open(); err != nil {
+		return err
+	}
+	if err := s.repo.PullWithConflictResolution(s.config.Sync.ConflictResolve); err != nil {
+		return fmt.Errorf("failed to pull remote changes: %w", err)
+	}
+	return nil
+}
+
+// Export copies the synced User directories from the repository into destDir
+// Each target is written to <destDir>/<target subdir>/User
+func (s *Syncer) Export(destDir string) error {
+	if err := s.prepareRepository(); err != nil {
+		return err
+	}
+
+	for _, target := range s.config.Cursor.SyncTargets() {
+		exportTarget := config.Target{
+			Name:       target.Name,
+			ConfigPath: filepath.Join(destDir, target.Subdir),
+			Subdir:     target.Subdir,
+		}
+		if err := s.copyFromRepository(exportTarget); err != nil {
+			return fmt.Errorf("failed to export %s settings: %w", target.Name, err)
+		}
+	}
+
+	logger.Info("📦 Exported settings to %s", destDir)
+	return nil
+}
+
+// Import copies the settings in srcDir (a folder containing User/) into the repository
+// in place of the target's Cursor config directory, then commits and pushes them
+// Exclusions and srcDir's User/.cursorsyncignore apply; files already in the
+// repository but missing from srcDir are kept
+func (s *Syncer) Import(srcDir, targetName string) error {
+	targets := s.config.Cursor.SyncTargets()
+	target := targets[0]
+	if targetName != "" {
+		found := false
+		for _, t := range targets {
+			if t.Name == targetName {
+				target, found = t, true
+				break
+			}
+		}
+		if !found {
+			return fmt.Errorf("unknown cursor target: %s", targetName)
+		}
+	}
+
+	if _, err := os.Stat(filepath.Join(srcDir, "User")); err != nil {
+		return fmt.Errorf("%s does not contain a User directory", srcDir)
+	}
+
+	if err := s.prepareRepository(); err != nil {
+		return err
+	}
+
+	importTarget := config.Target{
+		Name:       target.Name,
+		ConfigPath: srcDir,
+		Subdir:     target.Subdir,
+	}
+
+	// Apply the ignore file of the imported folder, not of the local installation
+	s.loadIgnoreFile()
+	matcher, err := ignore.Load(filepath.Join(srcDir, "User", ignore.FileName))
+	if err != nil {
+		return fmt.Errorf("failed to load ignore file: %w", err)
+	}
+	s.ignoreMatchers[importTarget.Name] = matcher
+
+	if err := s.copyToRepository(importTarget); err != nil {
+		return fmt.Errorf("failed to import settings: %w", err)
+	}
+
+	hasChanges, err := s.repo.HasChanges()
+	if err != nil {
+		return fmt.Errorf("failed to check for changes: %w", err)
+	}
+	if !hasChanges {
+		logger.Info("Repository already contains these settings - nothing to import")
+		return nil
+	}
+
+	if err := s.checkRepoSize(); err != nil {
+		return err
+	}
+
+	if err := s.repo.Add("."); err != nil {
+		return fmt.Errorf("failed to add changes: %w", err)
+	}
+
+	commitMessage := fmt.Sprintf("Import settings from %s on %s", filepath.Base(srcDir), s.config.Sync.MachineName())
+	authorName, authorEmail := s.config.Sync.CommitAuthor()
+	if err := s.repo.Commit(commitMessage, authorName, authorEmail); err != nil {
+		return fmt.Errorf("failed to commit changes: %w", err)
+	}
+
+	if err := s.repo.Push(); err != nil {
+		return fmt.Errorf("imported settings were committed but not pushed (run 'cursor-sync sync' to retry): %w", err)
+	}
+
+	logger.Info("📥 Imported settings from %s", srcDir)
+	return nil
+}
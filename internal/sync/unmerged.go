@@ -0,0 +1,27 @@
+package sync
+
+import (
+	"errors"
+	"fmt"
+
+	"cursor-sync/internal/logger"
+)
+
+// ErrUnmerged is returned while a merge has left conflicted files in the clone; syncing
+// would commit them with their conflict markers
+var ErrUnmerged = errors.New("unresolved merge conflicts in the repository")
+
+// checkUnmerged refuses to sync until 'cursor-sync resolve' has settled conflicted files
+func (s *Syncer) checkUnmerged() error {
+	files, err := s.repo.UnmergedFiles()
+	if err != nil {
+		logger.Debug("Failed to check for unmerged files: %v", err)
+		return nil
+	}
+	if len(files) == 0 {
+		return nil
+	}
+
+	logger.Warn("🛑 %d file(s) in the repository are unmerged - run 'cursor-sync resolve'", len(files))
+	return fmt.Errorf("%w: %d file(s) - run 'cursor-sync resolve'", ErrUnmerged, len(files))
+}
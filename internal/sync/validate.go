@@ -0,0 +1,38 @@
+package sync
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"cursor-sync/internal/jsonc"
+)
+
+// jsonSettingsExtensions are the extensions of the JSON (with comments) files sync.validate_json checks
+var jsonSettingsExtensions = []string{".json", ".jsonc", ".code-snippets"}
+
+// isJSONSettingsFile reports whether path is a settings, keybindings, snippets or other
+// JSON file that sync.validate_json checks before it is committed
+func isJSONSettingsFile(path string) bool {
+	ext := strings.ToLower(filepath.Ext(path))
+	for _, jsonExt := range jsonSettingsExtensions {
+		if ext == jsonExt {
+			return true
+		}
+	}
+	return false
+}
+
+// checkJSONSettings returns why a local JSON settings file must not be committed: it doesn't
+// parse, e.g. because Cursor was halfway through writing it. An empty file counts as broken
+// too. Other files, or any file while sync.validate_json is off, are never rejected
+func (s *Syncer) checkJSONSettings(relPath, srcPath string) error {
+	if !s.config.Sync.ValidateJSON || !isJSONSettingsFile(relPath) {
+		return nil
+	}
+	data, err := os.ReadFile(srcPath)
+	if err != nil {
+		return nil // Unreadable files are reported by the copy
+	}
+	return jsonc.Valid(data)
+}
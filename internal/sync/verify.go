@@ -0,0 +1,138 @@
+package sync
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"cursor-sync/internal/config"
+	"cursor-sync/internal/logger"
+)
+
+// VerifyResult lists the differences between the local settings and the repository clone
+// Paths are relative to the repository content directory (e.g. "User/settings.json")
+type VerifyResult struct {
+	Checked    int      // Files present on both sides and compared by hash
+	Mismatched []string // Content differs
+	OnlyLocal  []string // Present locally but missing from the repository
+	OnlyRemote []string // Present in the repository but missing locally
+	// Machine that last pushed each differing file that exists in the repository, from the file manifest
+	LastChanged map[string]ManifestEntry
+}
+
+// Diverged reports whether local settings and the repository differ
+func (r *VerifyResult) Diverged() bool {
+	return len(r.Mismatched) > 0 || len(r.OnlyLocal) > 0 || len(r.OnlyRemote) > 0
+}
+
+// Verify hashes every non-excluded file under each target's local and repository User
+// directories and reports the differences. It only reads files and doesn't touch the remote
+func (s *Syncer) Verify() (*VerifyResult, error) {
+	s.loadIgnoreFile()
+	s.clearHashCache("") // Hash the current contents, not what earlier syncs saw
+
+	result := &VerifyResult{}
+
+	for _, target := range s.config.Cursor.SyncTargets() {
+		userPath, repoUserPath := s.targetUserPaths(target)
+
+		localFiles, err := s.verifyFiles(target, userPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan %s: %w", userPath, err)
+		}
+		repoFiles, err := s.verifyFiles(target, repoUserPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan %s: %w", repoUserPath, err)
+		}
+
+		var paths []string
+		for relPath := range localFiles {
+			if _, ok := repoFiles[relPath]; !ok {
+				result.OnlyLocal = append(result.OnlyLocal, filepath.Join(target.Subdir, "User", relPath))
+				continue
+			}
+			paths = append(paths, localFiles[relPath], repoFiles[relPath])
+		}
+		for relPath := range repoFiles {
+			if _, ok := localFiles[relPath]; !ok {
+				result.OnlyRemote = append(result.OnlyRemote, filepath.Join(target.Subdir, "User", relPath))
+			}
+		}
+
+		hashes := s.calculateFileHashesParallel(paths)
+		for relPath, localPath := range localFiles {
+			repoPath, ok := repoFiles[relPath]
+			if !ok {
+				continue
+			}
+			result.Checked++
+
+			localHash, localOK := hashes[localPath]
+			repoHash, repoOK := hashes[repoPath]
+			// A Git LFS pointer names the SHA-256 of the content it stands for
+			if pointer, ok := readLFSPointer(repoPath); ok {
+				repoHash, repoOK = pointer.OID, true
+			}
+			if !localOK || !repoOK || localHash != repoHash {
+				// Unreadable files count as mismatches rather than being silently skipped
+				result.Mismatched = append(result.Mismatched, filepath.Join(target.Subdir, "User", relPath))
+			}
+		}
+	}
+
+	fileManifest := s.loadManifest()
+	result.LastChanged = make(map[string]ManifestEntry)
+	for _, path := range append(append([]string{}, result.Mismatched...), result.OnlyRemote...) {
+		if entry, ok := fileManifest[filepath.ToSlash(path)]; ok {
+			result.LastChanged[path] = entry
+		}
+	}
+
+	sort.Strings(result.Mismatched)
+	sort.Strings(result.OnlyLocal)
+	sort.Strings(result.OnlyRemote)
+
+	logger.Debug("Verified %d file(s): %d mismatched, %d only local, %d only remote",
+		result.Checked, len(result.Mismatched), len(result.OnlyLocal), len(result.OnlyRemote))
+	return result, nil
+}
+
+// verifyFiles returns the files under a User directory that take part in syncing
+// (relative path -> absolute path), applying the same exclusions as the sync itself
+func (s *Syncer) verifyFiles(target config.Target, root string) (map[string]string, error) {
+	files := make(map[string]string)
+
+	if _, err := os.Stat(root); os.IsNotExist(err) {
+		return files, nil
+	}
+
+	err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return nil // Skip inaccessible files
+		}
+
+		relPath, err := filepath.Rel(root, path)
+		if err != nil || relPath == "." {
+			return nil
+		}
+
+		excludePath := "User/" + relPath
+		if s.shouldExcludePath(target, excludePath) {
+			if info.IsDir() && !s.hasAllowedFiles(excludePath) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		if info.IsDir() || isSymlink(info) || strings.HasSuffix(relPath, ".sock") || isSQLiteSidecar(relPath) {
+			return nil
+		}
+
+		files[relPath] = path
+		return nil
+	})
+
+	return files, err
+}
@@ -0,0 +1,200 @@
+package sync
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"cursor-sync/internal/config"
+)
+
+// FileState describes one side (local or repository) of a file
+type FileState struct {
+	Path    string
+	Exists  bool
+	Symlink bool
+	Size    int64
+	ModTime time.Time
+	Hash    string // SHA-256, empty when the file couldn't be read
+
+	info os.FileInfo
+}
+
+// FileExplanation describes how a file under a target's User directory is synced
+type FileExplanation struct {
+	Target      string
+	Path        string // Relative to the target's config directory, e.g. "User/settings.json"
+	Excluded    bool
+	Rule        string // Rule that decided the exclusion ("" = synced by default)
+	Workspace   string // Folder a file below User/workspaceStorage belongs to ("" = unknown)
+	Watched     bool   // Changes are picked up by the file watcher
+	WatchRule   string
+	Local       FileState
+	Repo        FileState
+	LastChanged *ManifestEntry // Machine that last pushed the file, from the file manifest
+	Pull        string         // What the next pull would do with the file
+	Push        string         // What the next push would do with the file
+}
+
+// Explain reports, for every sync target, how the file at path (relative to the User
+// directory, with or without the "User/" prefix) is matched by the exclusion rules and
+// what the next pull and push would do with it. Nothing is changed
+func (s *Syncer) Explain(path string) ([]*FileExplanation, error) {
+	s.loadIgnoreFile()
+	s.clearHashCache("") // Hash the current contents, not what earlier syncs saw
+
+	relPath := strings.TrimPrefix(filepath.ToSlash(filepath.Clean(path)), "User/")
+	if relPath == "." || relPath == "" || strings.HasPrefix(relPath, "../") || filepath.IsAbs(path) {
+		return nil, fmt.Errorf("%s is not a path below the User directory", path)
+	}
+	relPath = filepath.FromSlash(relPath)
+
+	fileManifest := s.loadManifest()
+
+	var explanations []*FileExplanation
+	for _, target := range s.config.Cursor.SyncTargets() {
+		userPath, repoUserPath := s.targetUserPaths(target)
+
+		e := &FileExplanation{
+			Target: target.Name,
+			Path:   filepath.ToSlash(filepath.Join("User", relPath)),
+			Local:  fileState(filepath.Join(userPath, relPath)),
+			Repo:   fileState(filepath.Join(repoUserPath, relPath)),
+		}
+		e.Excluded, e.Rule = s.explainExclusion(target, e.Path)
+		e.Workspace = s.WorkspaceLocation(target, e.Path)
+		e.Watched, e.WatchRule = s.explainWatch(e.Path)
+		if entry, ok := fileManifest[manifestKey(target, relPath)]; ok {
+			e.LastChanged = &entry
+		}
+		e.Pull = s.explainPull(e)
+		e.Push = s.explainPush(e)
+
+		explanations = append(explanations, e)
+	}
+	return explanations, nil
+}
+
+// explainExclusion applies the exclusion rules the way the sync walk does: a file below an
+// excluded directory is skipped unless the directory leads to allow-listed globalStorage
+// files or workspaces
+func (s *Syncer) explainExclusion(target config.Target, path string) (bool, string) {
+	parts := strings.Split(path, "/")
+	for i := 2; i < len(parts); i++ {
+		dir := strings.Join(parts[:i], "/")
+		if excluded, rule := s.excludeReason(target, dir); excluded && !s.hasAllowedFiles(dir) {
+			return true, fmt.Sprintf("directory %s %s", dir, rule)
+		}
+	}
+	return s.excludeReason(target, path)
+}
+
+// explainWatch applies cursor.include_paths the way the file watcher does
+func (s *Syncer) explainWatch(path string) (bool, string) {
+	if len(s.config.Cursor.IncludePaths) == 0 {
+		return true, "no cursor.include_paths configured, every synced file is watched"
+	}
+	for _, pattern := range s.config.Cursor.IncludePaths {
+		matched, _ := filepath.Match(pattern, path)
+		if matched || strings.Contains(path, pattern) {
+			return true, fmt.Sprintf("matches cursor.include_paths pattern %q", pattern)
+		}
+	}
+	return false, "matches no cursor.include_paths pattern, changes are only picked up by periodic syncs"
+}
+
+// explainPull describes what copyFromRepository and the deletion sync would do with the file
+func (s *Syncer) explainPull(e *FileExplanation) string {
+	switch {
+	case e.Repo.Symlink:
+		return "skip: symlinks in the repository are never followed"
+	case e.Repo.Exists && isSQLiteSidecar(e.Path):
+		return "skip: SQLite sidecar files are never synced"
+	}
+
+	if pointer, ok := readLFSPointer(e.Repo.Path); ok {
+		if e.Local.Hash == pointer.OID {
+			return "nothing: local copy is identical"
+		}
+		if _, err := s.repo.ReadLFSObject(pointer); err != nil {
+			return fmt.Sprintf("skip: %v", err)
+		}
+		return "copy the Git LFS object → local"
+	}
+
+	switch {
+	case e.Repo.Exists:
+		if copyNeeded, decision := s.copyDecision(e.Repo.Path, e.Local.Path, e.Repo.info); copyNeeded {
+			return fmt.Sprintf("copy repository → local (%s)", decision)
+		}
+		return "nothing: local copy is identical"
+	case e.Local.Exists && !e.Excluded:
+		return "delete the local file: it is missing from the repository (after a successful pull)"
+	default:
+		return "nothing: not in the repository"
+	}
+}
+
+// explainPush describes what CleanupExcludedFiles, the deletion sync and copyToRepository
+// would do with the file
+func (s *Syncer) explainPush(e *FileExplanation) string {
+	switch {
+	case e.Excluded && e.Repo.Exists:
+		return "remove it from the repository: the file is excluded"
+	case e.Excluded:
+		return "nothing: the file is excluded"
+	case !e.Local.Exists && e.Repo.Exists:
+		return "delete it from the repository: the local file was deleted"
+	case !e.Local.Exists:
+		return "nothing: the file exists on neither side"
+	case e.Local.Symlink:
+		return "skip: symlinks are never followed"
+	case strings.HasSuffix(e.Path, ".sock"):
+		return "skip: socket files can't be read"
+	case isSQLiteSidecar(e.Path):
+		return "skip: SQLite sidecar files are never synced"
+	case isSQLiteDatabase(e.Path):
+		return "copy a snapshot of the database if its content changed (skipped while Cursor has it locked)"
+	}
+
+	if err := s.checkJSONSettings(e.Path, e.Local.Path); err != nil {
+		return fmt.Sprintf("skip: invalid JSON (%v), the repository keeps its last good version", err)
+	}
+
+	if s.lfsTracked(e.Repo.Path) {
+		if pointer, ok := readLFSPointer(e.Repo.Path); ok && pointer.OID == e.Local.Hash {
+			return "nothing: repository copy is identical (Git LFS)"
+		}
+		return "store as a Git LFS object and copy its pointer → repository"
+	}
+
+	if copyNeeded, decision := s.copyDecision(e.Local.Path, e.Repo.Path, e.Local.info); copyNeeded {
+		return fmt.Sprintf("copy local → repository (%s)", decision)
+	}
+	return "nothing: repository copy is identical"
+}
+
+// fileState describes the file at path without following symlinks
+func fileState(path string) FileState {
+	state := FileState{Path: path}
+
+	info, err := os.Lstat(path)
+	if err != nil || info.IsDir() {
+		return state
+	}
+	state.Exists = true
+	state.info = info
+	state.Symlink = isSymlink(info)
+	state.Size = info.Size()
+	state.ModTime = info.ModTime()
+
+	if !state.Symlink {
+		if data, err := os.ReadFile(path); err == nil {
+			state.Hash = fmt.Sprintf("%x", sha256.Sum256(data))
+		}
+	}
+	return state
+}
@@ -0,0 +1,113 @@
+package sync
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	pathpkg "path"
+	"path/filepath"
+	"strings"
+
+	"cursor-sync/internal/config"
+)
+
+// workspaceStoragePrefix is the folder whose workspaces can be selectively synced via cursor.workspace_allow
+const workspaceStoragePrefix = "User/workspaceStorage/"
+
+// workspaceFile describes the folder (or .code-workspace file) a workspace storage folder belongs to
+const workspaceFile = "workspace.json"
+
+// inWorkspaceStorage reports whether path is the workspaceStorage folder itself while an
+// allow-list is configured, so the walk has to descend into it to reach allowed workspaces
+func (s *Syncer) inWorkspaceStorage(path string) bool {
+	return len(s.config.Cursor.WorkspaceAllow) > 0 && filepath.ToSlash(path)+"/" == workspaceStoragePrefix
+}
+
+// hasAllowedFiles reports whether an excluded folder may still contain allow-listed
+// files (cursor.global_storage_allow or cursor.workspace_allow), so walks descend into it
+func (s *Syncer) hasAllowedFiles(path string) bool {
+	return s.inAllowedGlobalStorage(path) || s.inWorkspaceStorage(path)
+}
+
+// workspaceAllowEntry returns the cursor.workspace_allow entry matching the workspace
+// storage folder path is in or below. Entries name the folder's hash, or the workspace's
+// path or folder name as recorded in its workspace.json
+func (s *Syncer) workspaceAllowEntry(target config.Target, path string) (string, bool) {
+	hash, ok := workspaceHash(path)
+	if !ok || len(s.config.Cursor.WorkspaceAllow) == 0 {
+		return "", false
+	}
+	var location string
+	for _, entry := range s.config.Cursor.WorkspaceAllow {
+		if entry == hash {
+			return entry, true
+		}
+		if location == "" {
+			location = s.workspaceLocation(target, hash)
+		}
+		if location != "" && (entry == location || entry == pathpkg.Base(location)) {
+			return entry, true
+		}
+	}
+	return "", false
+}
+
+// workspaceHash returns the workspace storage folder name of a path in or below it
+func workspaceHash(path string) (string, bool) {
+	rest, ok := strings.CutPrefix(filepath.ToSlash(path), workspaceStoragePrefix)
+	if !ok || rest == "" {
+		return "", false
+	}
+	hash, _, _ := strings.Cut(rest, "/")
+	return hash, true
+}
+
+// WorkspaceLocation returns the folder or .code-workspace file the workspace storage folder
+// of a file below User/workspaceStorage belongs to, or "" when it isn't known
+func (s *Syncer) WorkspaceLocation(target config.Target, path string) string {
+	hash, ok := workspaceHash(path)
+	if !ok {
+		return ""
+	}
+	return s.workspaceLocation(target, hash)
+}
+
+// workspaceLocation reads the workspace.json of a workspace storage folder, locally or else
+// from the repository copy
+func (s *Syncer) workspaceLocation(target config.Target, hash string) string {
+	userPath, repoUserPath := s.targetUserPaths(target)
+	for _, root := range []string{userPath, repoUserPath} {
+		data, err := os.ReadFile(filepath.Join(root, "workspaceStorage", hash, workspaceFile))
+		if err != nil {
+			continue
+		}
+		var workspace struct {
+			Folder    string `json:"folder"`
+			Workspace string `json:"workspace"`
+		}
+		if err := json.Unmarshal(data, &workspace); err != nil {
+			continue
+		}
+		location := workspace.Folder
+		if location == "" {
+			location = workspace.Workspace
+		}
+		// Local folders are file:// URIs; remote ones (vscode-remote://...) are kept as they are
+		if u, err := url.Parse(location); err == nil && u.Scheme == "file" {
+			location = u.Path
+		}
+		if location != "" {
+			return location
+		}
+	}
+	return ""
+}
+
+// workspaceRule describes the cursor.workspace_allow entry that allowed a workspace
+func workspaceRule(entry, location string) string {
+	if location == "" || entry == location {
+		return fmt.Sprintf("allowed by cursor.workspace_allow entry %q", entry)
+	}
+	return fmt.Sprintf("allowed by cursor.workspace_allow entry %q (workspace %s)", entry, location)
+}
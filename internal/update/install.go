@@ -0,0 +1,236 @@
+package update
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"cursor-sync/internal/logger"
+)
+
+// maxDownloadSize bounds release downloads
+const maxDownloadSize = 200 << 20
+
+// checksumsAsset is the checksum file published alongside release binaries
+const checksumsAsset = "checksums.txt"
+
+// platformAliases maps GOOS/GOARCH to other names used in release asset names
+var platformAliases = map[string][]string{
+	"amd64":  {"x86_64"},
+	"arm64":  {"aarch64"},
+	"darwin": {"macos"},
+}
+
+// SelectAsset returns the release asset built for goos/goarch
+// Assets are matched by name, e.g. cursor-sync_linux_amd64.tar.gz or
+// cursor-sync-Darwin-arm64.zip
+func SelectAsset(assets []Asset, goos, goarch string) (*Asset, error) {
+	for i, asset := range assets {
+		name := strings.ToLower(asset.Name)
+		if name == checksumsAsset || strings.HasSuffix(name, ".sha256") {
+			continue
+		}
+		if matchesPlatform(name, goos) && matchesPlatform(name, goarch) {
+			return &assets[i], nil
+		}
+	}
+	return nil, fmt.Errorf("release has no binary for %s/%s", goos, goarch)
+}
+
+// matchesPlatform reports whether an asset name contains the OS or architecture
+// as a separate word
+func matchesPlatform(name, platform string) bool {
+	words := strings.FieldsFunc(name, func(r rune) bool {
+		return r == '_' || r == '-' || r == '.'
+	})
+	candidates := append([]string{platform}, platformAliases[platform]...)
+	for _, word := range words {
+		for _, candidate := range candidates {
+			if word == candidate {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// ErrUnverified is returned for a release without checksums.txt unless unverified
+// installs were allowed
+var ErrUnverified = errors.New("release publishes no " + checksumsAsset)
+
+// Install downloads the release binary for this platform and replaces the running
+// executable with it
+// The download is verified against the release's checksums.txt; a release without one is
+// only installed when allowUnverified is set
+func Install(ctx context.Context, release *Release, allowUnverified bool) (string, error) {
+	asset, err := SelectAsset(release.Assets, runtime.GOOS, runtime.GOARCH)
+	if err != nil {
+		return "", err
+	}
+
+	executable, err := os.Executable()
+	if err != nil {
+		return "", fmt.Errorf("failed to locate cursor-sync binary: %w", err)
+	}
+	if resolved, err := filepath.EvalSymlinks(executable); err == nil {
+		executable = resolved
+	}
+
+	logger.Debug("Downloading %s", asset.DownloadURL)
+	data, err := download(ctx, asset.DownloadURL, maxDownloadSize)
+	if err != nil {
+		return "", err
+	}
+	if err := verifyChecksum(ctx, release, asset.Name, data, allowUnverified); err != nil {
+		return "", err
+	}
+
+	binary, err := extractBinary(asset.Name, data)
+	if err != nil {
+		return "", err
+	}
+	if err := replaceExecutable(executable, binary); err != nil {
+		return "", err
+	}
+	return executable, nil
+}
+
+// verifyChecksum compares data with its entry in the release's checksums.txt
+// Releases without a checksum file are refused unless allowUnverified is set
+func verifyChecksum(ctx context.Context, release *Release, name string, data []byte, allowUnverified bool) error {
+	var checksums *Asset
+	for i := range release.Assets {
+		if strings.EqualFold(release.Assets[i].Name, checksumsAsset) {
+			checksums = &release.Assets[i]
+			break
+		}
+	}
+	if checksums == nil {
+		if !allowUnverified {
+			return fmt.Errorf("%w: %s can't be verified (pass --skip-verify to install it anyway)", ErrUnverified, release.TagName)
+		}
+		logger.Info("⚠️  Release %s has no %s - installing %s without checksum verification (--skip-verify)", release.TagName, checksumsAsset, name)
+		return nil
+	}
+
+	list, err := download(ctx, checksums.DownloadURL, 1<<20)
+	if err != nil {
+		return err
+	}
+
+	sum := sha256.Sum256(data)
+	actual := hex.EncodeToString(sum[:])
+	scanner := bufio.NewScanner(bytes.NewReader(list))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 2 || strings.TrimPrefix(fields[1], "*") != name {
+			continue
+		}
+		if !strings.EqualFold(fields[0], actual) {
+			return fmt.Errorf("checksum mismatch for %s", name)
+		}
+		return nil
+	}
+	return fmt.Errorf("%s has no entry for %s", checksumsAsset, name)
+}
+
+// extractBinary returns the cursor-sync binary from a downloaded asset, which is
+// either the binary itself or a .tar.gz/.zip archive containing it
+func extractBinary(name string, data []byte) ([]byte, error) {
+	lower := strings.ToLower(name)
+	switch {
+	case strings.HasSuffix(lower, ".tar.gz"), strings.HasSuffix(lower, ".tgz"):
+		gz, err := gzip.NewReader(bytes.NewReader(data))
+		if err != nil {
+			return nil, fmt.Errorf("failed to open %s: %w", name, err)
+		}
+		defer gz.Close()
+
+		tr := tar.NewReader(gz)
+		for {
+			header, err := tr.Next()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				return nil, fmt.Errorf("failed to read %s: %w", name, err)
+			}
+			if header.Typeflag == tar.TypeReg && isBinaryName(header.Name) {
+				return io.ReadAll(io.LimitReader(tr, maxDownloadSize))
+			}
+		}
+	case strings.HasSuffix(lower, ".zip"):
+		zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+		if err != nil {
+			return nil, fmt.Errorf("failed to open %s: %w", name, err)
+		}
+		for _, file := range zr.File {
+			if file.FileInfo().IsDir() || !isBinaryName(file.Name) {
+				continue
+			}
+			rc, err := file.Open()
+			if err != nil {
+				return nil, fmt.Errorf("failed to read %s: %w", name, err)
+			}
+			defer rc.Close()
+			return io.ReadAll(io.LimitReader(rc, maxDownloadSize))
+		}
+	default:
+		return data, nil
+	}
+	return nil, fmt.Errorf("%s does not contain a cursor-sync binary", name)
+}
+
+// isBinaryName reports whether an archive entry is the cursor-sync binary
+func isBinaryName(name string) bool {
+	base := path.Base(filepath.ToSlash(name))
+	return base == "cursor-sync" || base == "cursor-sync.exe"
+}
+
+// replaceExecutable writes binary next to executable and renames it into place
+// Windows can't overwrite a running binary, so the old one is moved aside first
+func replaceExecutable(executable string, binary []byte) error {
+	dir := filepath.Dir(executable)
+	tmp, err := os.CreateTemp(dir, ".cursor-sync-update-*")
+	if err != nil {
+		return fmt.Errorf("failed to write to %s: %w", dir, err)
+	}
+	tmpPath := tmp.Name()
+	defer os.Remove(tmpPath)
+
+	if _, err := tmp.Write(binary); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write new binary: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write new binary: %w", err)
+	}
+	if err := os.Chmod(tmpPath, 0755); err != nil {
+		return fmt.Errorf("failed to make new binary executable: %w", err)
+	}
+
+	if runtime.GOOS == "windows" {
+		old := executable + ".old"
+		os.Remove(old)
+		if err := os.Rename(executable, old); err != nil {
+			return fmt.Errorf("failed to move old binary aside: %w", err)
+		}
+	}
+	if err := os.Rename(tmpPath, executable); err != nil {
+		return fmt.Errorf("failed to replace %s: %w", executable, err)
+	}
+	return nil
+}
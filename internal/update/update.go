@@ -0,0 +1,235 @@
+// Package update checks GitHub releases for newer cursor-sync builds and installs them
+// Nothing is installed unless Install is called explicitly ('cursor-sync update')
+package update
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"cursor-sync/internal/logger"
+	"cursor-sync/internal/paths"
+	"cursor-sync/internal/ratelimit"
+	"cursor-sync/internal/version"
+)
+
+const (
+	// latestReleaseURL is the GitHub API endpoint of cursor-sync's latest release
+	latestReleaseURL = "https://api.github.com/repos/meshin-dev/cursor-sync/releases/latest"
+	// checkInterval is how long a check result is reused before GitHub is asked again
+	checkInterval = 24 * time.Hour
+	// stateFileName caches the last check in the state directory
+	stateFileName = "update-check.json"
+)
+
+// Release is a published cursor-sync release
+type Release struct {
+	TagName string  `json:"tag_name"`
+	HTMLURL string  `json:"html_url"`
+	Assets  []Asset `json:"assets"`
+}
+
+// Asset is a file attached to a release
+type Asset struct {
+	Name        string `json:"name"`
+	DownloadURL string `json:"browser_download_url"`
+	Size        int64  `json:"size"`
+}
+
+// Version returns the release's version without the leading "v"
+func (r *Release) Version() string {
+	return strings.TrimPrefix(r.TagName, "v")
+}
+
+// checkState is the content of the state file
+type checkState struct {
+	CheckedAt    time.Time `json:"checked_at"`
+	ETag         string    `json:"etag,omitempty"`
+	BackoffUntil time.Time `json:"backoff_until,omitempty"`
+	Release      *Release  `json:"release,omitempty"`
+}
+
+// httpClient bounds API requests; downloads use their own timeout
+var httpClient = &http.Client{Timeout: 30 * time.Second}
+
+// Check returns the latest release and whether it is newer than the running build
+// A result younger than a day is reused unless force is set, and GitHub is not asked
+// again before a rate limit resets. Conditional requests keep repeated checks from
+// counting against the rate limit
+func Check(ctx context.Context, force bool) (*Release, bool, error) {
+	state := readState()
+
+	if state.Release != nil && time.Now().Before(state.BackoffUntil) {
+		logger.Debug("GitHub API rate limited - using cached release until %s", state.BackoffUntil.Format("15:04:05"))
+		return state.Release, isNewer(state.Release.Version(), version.Version), nil
+	}
+	if !force && state.Release != nil && time.Since(state.CheckedAt) < checkInterval {
+		return state.Release, isNewer(state.Release.Version(), version.Version), nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, latestReleaseURL, nil)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("User-Agent", "cursor-sync/"+version.Version)
+	if state.Release != nil && state.ETag != "" {
+		req.Header.Set("If-None-Match", state.ETag)
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to query GitHub releases: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if rateErr := ratelimit.FromResponse(resp); rateErr != nil {
+		state.BackoffUntil = rateErr.Reset
+		writeState(state)
+		return nil, false, rateErr
+	}
+
+	switch resp.StatusCode {
+	case http.StatusNotModified:
+	case http.StatusOK:
+		var release Release
+		if err := json.NewDecoder(resp.Body).Decode(&release); err != nil {
+			return nil, false, fmt.Errorf("failed to parse release: %w", err)
+		}
+		state.Release = &release
+		state.ETag = resp.Header.Get("ETag")
+	case http.StatusNotFound:
+		return nil, false, fmt.Errorf("no cursor-sync releases published yet")
+	default:
+		return nil, false, fmt.Errorf("GitHub releases API returned %s", resp.Status)
+	}
+
+	state.CheckedAt = time.Now()
+	state.BackoffUntil = time.Time{}
+	writeState(state)
+	return state.Release, isNewer(state.Release.Version(), version.Version), nil
+}
+
+// isNewer reports whether version latest is newer than current. Development builds
+// (anything that isn't a version number) are never considered outdated
+func isNewer(latest, current string) bool {
+	latestParts, latestPre, ok := parseVersion(latest)
+	if !ok {
+		return false
+	}
+	currentParts, currentPre, ok := parseVersion(current)
+	if !ok {
+		return false
+	}
+
+	for i := range latestParts {
+		if latestParts[i] != currentParts[i] {
+			return latestParts[i] > currentParts[i]
+		}
+	}
+	// 1.2.0 is newer than 1.2.0-rc1
+	return latestPre == "" && currentPre != ""
+}
+
+// parseVersion splits "v1.2.3-rc1" into [1 2 3] and "rc1". Missing minor and patch
+// numbers are 0
+func parseVersion(v string) ([3]int, string, bool) {
+	var parts [3]int
+	v = strings.TrimPrefix(strings.TrimSpace(v), "v")
+	v, pre, _ := strings.Cut(v, "-")
+	v, _, _ = strings.Cut(v, "+") // Build metadata doesn't order versions
+
+	fields := strings.Split(v, ".")
+	if len(fields) > 3 {
+		return parts, "", false
+	}
+	for i, field := range fields {
+		n, err := strconv.Atoi(field)
+		if err != nil || n < 0 {
+			return parts, "", false
+		}
+		parts[i] = n
+	}
+	return parts, pre, true
+}
+
+// statePath returns the path of the state file
+func statePath() (string, error) {
+	stateDir, err := paths.StateDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(stateDir, stateFileName), nil
+}
+
+// readState reads the state file; a missing or unreadable file yields an empty state
+func readState() *checkState {
+	state := &checkState{}
+	path, err := statePath()
+	if err != nil {
+		return state
+	}
+	if data, err := os.ReadFile(path); err == nil {
+		if err := json.Unmarshal(data, state); err != nil {
+			logger.Debug("Ignoring unreadable update check state: %v", err)
+			return &checkState{}
+		}
+	}
+	return state
+}
+
+// writeState saves the state file. It is only a cache, so failures are logged and ignored
+func writeState(state *checkState) {
+	path, err := statePath()
+	if err != nil {
+		logger.Debug("Failed to save update check state: %v", err)
+		return
+	}
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		logger.Debug("Failed to encode update check state: %v", err)
+		return
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		logger.Debug("Failed to create state directory: %v", err)
+		return
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		logger.Debug("Failed to save update check state: %v", err)
+	}
+}
+
+// download fetches url, refusing bodies over limit bytes
+func download(ctx context.Context, url string, limit int64) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("User-Agent", "cursor-sync/"+version.Version)
+
+	client := &http.Client{Timeout: 10 * time.Minute}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download %s: %w", url, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to download %s: %s", url, resp.Status)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, limit+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to download %s: %w", url, err)
+	}
+	if int64(len(data)) > limit {
+		return nil, fmt.Errorf("download of %s exceeds %d MB", url, limit>>20)
+	}
+	return data, nil
+}
@@ -0,0 +1,73 @@
+package update
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestIsNewer(t *testing.T) {
+	cases := []struct {
+		latest, current string
+		want            bool
+	}{
+		{"v1.2.0", "1.1.9", true},
+		{"1.10.0", "1.9.0", true},
+		{"v1.2.0", "v1.2.0", false},
+		{"1.2.0", "1.3.0", false},
+		{"1.2", "1.2.0", false},
+		{"1.2.0", "1.2.0-rc1", true},
+		{"1.2.0-rc1", "1.2.0", false},
+		{"1.2.1", "dev", false},
+		{"nightly", "1.0.0", false},
+	}
+
+	for _, c := range cases {
+		if got := isNewer(c.latest, c.current); got != c.want {
+			t.Errorf("isNewer(%q, %q) = %v, want %v", c.latest, c.current, got, c.want)
+		}
+	}
+}
+
+func TestSelectAsset(t *testing.T) {
+	assets := []Asset{
+		{Name: "checksums.txt"},
+		{Name: "cursor-sync_linux_amd64.tar.gz"},
+		{Name: "cursor-sync_Linux_aarch64.tar.gz"},
+		{Name: "cursor-sync_darwin_arm64.zip"},
+		{Name: "cursor-sync_windows_amd64.zip"},
+	}
+
+	cases := map[[2]string]string{
+		{"linux", "amd64"}:   "cursor-sync_linux_amd64.tar.gz",
+		{"linux", "arm64"}:   "cursor-sync_Linux_aarch64.tar.gz",
+		{"darwin", "arm64"}:  "cursor-sync_darwin_arm64.zip",
+		{"windows", "amd64"}: "cursor-sync_windows_amd64.zip",
+	}
+	for platform, want := range cases {
+		asset, err := SelectAsset(assets, platform[0], platform[1])
+		if err != nil {
+			t.Errorf("SelectAsset(%s/%s) failed: %v", platform[0], platform[1], err)
+			continue
+		}
+		if asset.Name != want {
+			t.Errorf("SelectAsset(%s/%s) = %s, want %s", platform[0], platform[1], asset.Name, want)
+		}
+	}
+
+	if _, err := SelectAsset(assets, "darwin", "amd64"); err == nil {
+		t.Error("expected an error for a platform without a binary")
+	}
+}
+
+func TestVerifyChecksumRefusesUnverifiedReleases(t *testing.T) {
+	release := &Release{TagName: "v1.2.0", Assets: []Asset{{Name: "cursor-sync_linux_amd64.tar.gz"}}}
+
+	err := verifyChecksum(context.Background(), release, "cursor-sync_linux_amd64.tar.gz", []byte("binary"), false)
+	if !errors.Is(err, ErrUnverified) {
+		t.Errorf("verifyChecksum() without checksums.txt = %v, want %v", err, ErrUnverified)
+	}
+	if err := verifyChecksum(context.Background(), release, "cursor-sync_linux_amd64.tar.gz", []byte("binary"), true); err != nil {
+		t.Errorf("verifyChecksum() with unverified installs allowed = %v, want nil", err)
+	}
+}
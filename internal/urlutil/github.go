@@ -0,0 +1,159 @@
+// Package urlutil parses the repository URLs cursor-sync is configured with
+package urlutil
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+)
+
+const (
+	// DefaultHost is the host of repositories given as a bare owner/repo
+	DefaultHost = "github.com"
+	// GitHubAPIBaseURL is the REST API endpoint of github.com
+	GitHubAPIBaseURL = "https://api.github.com"
+)
+
+// GitHubURL is a parsed GitHub or GitHub Enterprise Server repository URL
+type GitHubURL struct {
+	Scheme     string // "https", "http", "ssh" or "git"; scp-like addresses are "ssh", host/owner/repo "https"
+	Host       string // Lowercase, without port or credentials
+	Port       string // Explicit port, "" for the scheme's default
+	PathPrefix string // Path segments before owner/repo on a server mounted below a path, usually ""
+	Owner      string
+	Repo       string // Without .git
+}
+
+// WebHost returns the host and port of the web UI and API. SSH ports are left out since
+// that isn't where the web server listens
+func (u *GitHubURL) WebHost() string {
+	if u.Port == "" || (u.Scheme != "https" && u.Scheme != "http") {
+		return u.Host
+	}
+	return net.JoinHostPort(u.Host, u.Port)
+}
+
+// APIBaseURL returns the REST API endpoint serving the repository: api.github.com for
+// github.com and <server>/api/v3 for GitHub Enterprise Server, below the path prefix
+func (u *GitHubURL) APIBaseURL() string {
+	if u.Host == DefaultHost {
+		return GitHubAPIBaseURL
+	}
+	scheme := "https"
+	if u.Scheme == "http" {
+		scheme = "http"
+	}
+	base := scheme + "://" + u.WebHost()
+	if u.PathPrefix != "" {
+		base += "/" + u.PathPrefix
+	}
+	return base + "/api/v3"
+}
+
+// ParseGitHubURL parses a GitHub or GitHub Enterprise Server repository URL:
+// https://host[:port]/[prefix/]owner/repo(.git), ssh://git@host[:port]/owner/repo,
+// git@host:owner/repo(.git), host[:port]/owner/repo or owner/repo
+//
+// The host is lowercased, without credentials and with www.github.com folded into
+// github.com. Trailing slashes, query strings and fragments are ignored. On github.com
+// extra path segments are too (https://github.com/owner/repo/tree/main); on other hosts
+// the segments before owner/repo, or before the segment ending in .git, are a path prefix
+func ParseGitHubURL(repoURL string) (*GitHubURL, error) {
+	raw := strings.TrimSpace(repoURL)
+	invalid := fmt.Errorf("invalid GitHub URL format: %s", repoURL)
+
+	parsed := &GitHubURL{Scheme: "https"}
+	var hostPort, path string
+	if strings.Contains(raw, "://") {
+		u, err := url.Parse(raw)
+		if err != nil || u.Host == "" {
+			return nil, invalid
+		}
+		parsed.Scheme = strings.ToLower(u.Scheme)
+		hostPort, path = u.Host, u.Path
+	} else {
+		if end := strings.IndexAny(raw, "?#"); end >= 0 {
+			raw = raw[:end]
+		}
+		var ok bool
+		if hostPort, path, ok = splitSCP(raw); ok {
+			parsed.Scheme = "ssh"
+		} else {
+			// host/owner/repo, or a bare owner/repo on github.com
+			path = strings.Trim(raw, "/")
+			if strings.Count(path, "/") >= 2 {
+				hostPort, path, _ = strings.Cut(path, "/")
+			}
+		}
+	}
+
+	// Credentials embedded in the address (https://token@host/...) are not part of the host
+	if at := strings.LastIndex(hostPort, "@"); at >= 0 {
+		hostPort = hostPort[at+1:]
+	}
+	parsed.Host = hostPort
+	if host, port, err := net.SplitHostPort(hostPort); err == nil {
+		if !isDigits(port) {
+			return nil, invalid
+		}
+		parsed.Host, parsed.Port = host, port
+	}
+	parsed.Host = strings.ToLower(parsed.Host)
+	switch parsed.Host {
+	case "":
+		parsed.Host = DefaultHost
+	case "www." + DefaultHost:
+		parsed.Host = DefaultHost
+	}
+
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	repoIndex := -1
+	for i := 1; i < len(segments); i++ {
+		if strings.HasSuffix(segments[i], ".git") {
+			repoIndex = i
+			break
+		}
+	}
+	switch {
+	case len(segments) < 2:
+		return nil, invalid
+	case repoIndex > 0:
+	case parsed.Host == DefaultHost:
+		repoIndex = 1
+	default:
+		repoIndex = len(segments) - 1
+	}
+
+	parsed.PathPrefix = strings.Join(segments[:repoIndex-1], "/")
+	parsed.Owner = segments[repoIndex-1]
+	parsed.Repo = strings.TrimSuffix(segments[repoIndex], ".git")
+	if parsed.Owner == "" || parsed.Repo == "" {
+		return nil, invalid
+	}
+	return parsed, nil
+}
+
+// splitSCP splits an scp-like SSH address ([user@]host:owner/repo) into host and path
+// A colon followed by digits and a slash is a port instead (host:8443/owner/repo)
+func splitSCP(raw string) (host, path string, ok bool) {
+	colon := strings.Index(raw, ":")
+	if colon <= 0 || strings.Contains(raw[:colon], "/") {
+		return "", "", false
+	}
+	rest := raw[colon+1:]
+	if slash := strings.Index(rest, "/"); slash > 0 && isDigits(rest[:slash]) {
+		return "", "", false
+	}
+	return raw[:colon], rest, true
+}
+
+// isDigits reports whether s consists of ASCII digits only
+func isDigits(s string) bool {
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return s != ""
+}
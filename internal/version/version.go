@@ -0,0 +1,80 @@
+// Package version identifies the running build. Release builds inject the values with
+// -ldflags, e.g.:
+//
+//	go build -ldflags "-X cursor-sync/internal/version.Version=1.4.0 \
+//	  -X cursor-sync/internal/version.Commit=$(git rev-parse --short HEAD) \
+//	  -X cursor-sync/internal/version.Date=$(date -u +%Y-%m-%dT%H:%M:%SZ)" -o bin/cursor-sync .
+//
+// Without them the commit and date come from the VCS information Go embeds when building
+// inside a git checkout
+package version
+
+import (
+	"fmt"
+	"runtime"
+	"runtime/debug"
+)
+
+// Set with -ldflags -X at build time
+var (
+	Version = "dev"
+	Commit  = ""
+	Date    = ""
+)
+
+// Info describes a build
+type Info struct {
+	Version   string
+	Commit    string // Short git commit, "unknown" if not recorded
+	Date      string // Build (or, from VCS information, commit) time, "unknown" if not recorded
+	Modified  bool   // Built from a checkout with uncommitted changes (VCS information only)
+	GoVersion string
+	Platform  string // GOOS/GOARCH
+}
+
+// Get returns the running build's information
+func Get() Info {
+	info := Info{
+		Version:   Version,
+		Commit:    Commit,
+		Date:      Date,
+		GoVersion: runtime.Version(),
+		Platform:  runtime.GOOS + "/" + runtime.GOARCH,
+	}
+
+	// Injected values describe the release; VCS information only fills the gaps
+	if buildInfo, ok := debug.ReadBuildInfo(); ok && Commit == "" {
+		for _, setting := range buildInfo.Settings {
+			switch setting.Key {
+			case "vcs.revision":
+				info.Commit = setting.Value
+			case "vcs.time":
+				if info.Date == "" {
+					info.Date = setting.Value
+				}
+			case "vcs.modified":
+				info.Modified = setting.Value == "true"
+			}
+		}
+	}
+
+	if len(info.Commit) > 12 {
+		info.Commit = info.Commit[:12]
+	}
+	if info.Commit == "" {
+		info.Commit = "unknown"
+	}
+	if info.Date == "" {
+		info.Date = "unknown"
+	}
+	return info
+}
+
+// String formats the build on one line, e.g. for logs and bug reports
+func (i Info) String() string {
+	commit := i.Commit
+	if i.Modified {
+		commit += "-dirty"
+	}
+	return fmt.Sprintf("%s (commit %s, built %s, %s %s)", i.Version, commit, i.Date, i.GoVersion, i.Platform)
+}
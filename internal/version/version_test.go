@@ -0,0 +1,16 @@
+package version
+
+import "testing"
+
+func TestGetPrefersInjectedValues(t *testing.T) {
+	defer func(version, commit, date string) { Version, Commit, Date = version, commit, date }(Version, Commit, Date)
+	Version, Commit, Date = "1.4.0", "0123456789abcdef", "2026-01-02T03:04:05Z"
+
+	info := Get()
+	if info.Version != "1.4.0" || info.Commit != "0123456789ab" || info.Date != "2026-01-02T03:04:05Z" || info.Modified {
+		t.Errorf("Get() = %+v, want the injected version, commit (shortened) and date", info)
+	}
+	if got, want := info.String(), "1.4.0 (commit 0123456789ab, built 2026-01-02T03:04:05Z, "+info.GoVersion+" "+info.Platform+")"; got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+}
@@ -15,6 +15,18 @@ import (
 	"cursor-sync/internal/logger"
 )
 
+// postSyncQuietPeriod is how long events for files the sync left behind are ignored after
+// the watcher is re-enabled. fsnotify delivers events asynchronously, so events caused by
+// the sync's own writes can arrive after the sync finished; ignoring them prevents sync
+// feedback loops
+const postSyncQuietPeriod = 3 * time.Second
+
+// fileStamp identifies the content of a file without reading it
+type fileStamp struct {
+	size    int64
+	modTime time.Time
+}
+
 // FileChange represents a file system change
 type FileChange struct {
 	Path   string
@@ -24,11 +36,15 @@ type FileChange struct {
 // Watcher watches for file system changes
 type Watcher struct {
 	fsWatcher     *fsnotify.Watcher
-	config        *config.Config
+	config        *config.Config // Replaced as a whole by SetConfig; read it with currentConfig
+	configMutex   sync.RWMutex
 	changeChan    chan FileChange
 	debounceTime  time.Duration
-	lastChangeMap map[string]time.Time
+	debounceDir   bool                 // Debounce per directory instead of per file
+	lastChangeMap map[string]time.Time // Debounce key (file or directory) -> last change
 	disabled      bool
+	quietUntil    time.Time            // Until then, events for files still as synced are ignored
+	syncedFiles   map[string]fileStamp // Watched files as they were when the watcher was re-enabled
 	disabledMutex sync.RWMutex
 	watchMutex    sync.Mutex
 }
@@ -45,6 +61,7 @@ func New(cfg *config.Config) (*Watcher, error) {
 		config:        cfg,
 		changeChan:    make(chan FileChange, 100),
 		debounceTime:  cfg.Sync.DebounceTime,
+		debounceDir:   cfg.Sync.DebounceMode == config.DebounceDir,
 		lastChangeMap: make(map[string]time.Time),
 	}, nil
 }
@@ -82,11 +99,67 @@ func (w *Watcher) Disable() {
 }
 
 // Enable re-enables the file watcher
+// Events that arrive during the quiet period for files that still look as the sync left
+// them come from the sync's own writes and are ignored; edits made since are reported
 func (w *Watcher) Enable() {
+	synced := w.snapshotFiles()
+
 	w.disabledMutex.Lock()
 	defer w.disabledMutex.Unlock()
 	w.disabled = false
-	logger.Debug("File watcher enabled")
+	w.quietUntil = time.Now().Add(postSyncQuietPeriod)
+	w.syncedFiles = synced
+	logger.Debug("File watcher enabled (ignoring events for %d unchanged file(s) for %v)", len(synced), postSyncQuietPeriod)
+}
+
+// snapshotFiles records the size and modification time of every watched file
+func (w *Watcher) snapshotFiles() map[string]fileStamp {
+	files := make(map[string]fileStamp)
+	for _, target := range w.currentConfig().Cursor.SyncTargets() {
+		userPath := filepath.Join(target.ConfigPath, "User")
+		filepath.Walk(userPath, func(path string, info os.FileInfo, err error) error {
+			if err != nil {
+				return nil // Skip inaccessible paths
+			}
+			if info.IsDir() && path != userPath && w.shouldExcludePath(path) {
+				return filepath.SkipDir
+			}
+			files[path] = fileStamp{size: info.Size(), modTime: info.ModTime()}
+			return nil
+		})
+	}
+	return files
+}
+
+// unchangedSinceSync reports whether path is still as it was when the watcher was
+// re-enabled: present with the recorded size and modification time, or absent in both
+func unchangedSinceSync(path string, synced map[string]fileStamp) bool {
+	recorded, known := synced[path]
+	info, err := os.Stat(path)
+	if err != nil {
+		return !known && os.IsNotExist(err)
+	}
+	return known && info.Size() == recorded.size && info.ModTime().Equal(recorded.modTime)
+}
+
+// SetConfig switches to a reloaded configuration, including how rapid changes are collapsed
+// Watched directories only change with RestartWatching
+func (w *Watcher) SetConfig(cfg *config.Config) {
+	w.configMutex.Lock()
+	w.config = cfg
+	w.configMutex.Unlock()
+
+	w.disabledMutex.Lock()
+	defer w.disabledMutex.Unlock()
+	w.debounceTime = cfg.Sync.DebounceTime
+	w.debounceDir = cfg.Sync.DebounceMode == config.DebounceDir
+}
+
+// currentConfig returns the configuration in effect
+func (w *Watcher) currentConfig() *config.Config {
+	w.configMutex.RLock()
+	defer w.configMutex.RUnlock()
+	return w.config
 }
 
 // RestartWatching restarts the watching process for the entire User directory
@@ -111,21 +184,26 @@ func (w *Watcher) RestartWatching() error {
 }
 
 func (w *Watcher) addWatchPaths() error {
-	basePath := w.config.Cursor.ConfigPath
-	userPath := filepath.Join(basePath, "User")
+	for _, target := range w.currentConfig().Cursor.SyncTargets() {
+		userPath := filepath.Join(target.ConfigPath, "User")
 
-	// Check if User directory exists
-	if _, err := os.Stat(userPath); os.IsNotExist(err) {
-		return fmt.Errorf("User directory does not exist: %s", userPath)
-	}
+		// Check if User directory exists
+		if _, err := os.Stat(userPath); os.IsNotExist(err) {
+			return fmt.Errorf("User directory does not exist: %s", userPath)
+		}
+
+		logger.Debug("Adding %s User directory watch path: %s", target.Name, userPath)
+		if err := w.fsWatcher.Add(userPath); err != nil {
+			return fmt.Errorf("failed to add User path: %w", err)
+		}
 
-	logger.Debug("Adding User directory watch path: %s", userPath)
-	if err := w.fsWatcher.Add(userPath); err != nil {
-		return fmt.Errorf("failed to add User path: %w", err)
+		// Add all subdirectories recursively within User (watch everything except excluded paths)
+		if err := w.addDirectoryWatch(userPath); err != nil {
+			return err
+		}
 	}
 
-	// Add all subdirectories recursively within User (watch everything except excluded paths)
-	return w.addDirectoryWatch(userPath)
+	return nil
 }
 
 func (w *Watcher) addDirectoryWatch(dir string) error {
@@ -157,6 +235,16 @@ func (w *Watcher) addNewDirectoryToWatch(dirPath string) {
 	}
 }
 
+// watchIfNewDirectory adds a directory created by event to the watch list
+func (w *Watcher) watchIfNewDirectory(event fsnotify.Event) {
+	if event.Op&fsnotify.Create == 0 {
+		return
+	}
+	if info, err := os.Stat(event.Name); err == nil && info.IsDir() {
+		w.addNewDirectoryToWatch(event.Name)
+	}
+}
+
 func (w *Watcher) processEvents(ctx context.Context) {
 	for {
 		select {
@@ -187,8 +275,16 @@ func (w *Watcher) shouldProcessEvent(event fsnotify.Event) bool {
 		w.disabledMutex.RUnlock()
 		return false
 	}
+	quiet := time.Now().Before(w.quietUntil)
+	synced := w.syncedFiles
+	debounceTime, debounceDir := w.debounceTime, w.debounceDir
 	w.disabledMutex.RUnlock()
 
+	// Late events for the sync's own writes
+	if quiet && unchangedSinceSync(event.Name, synced) {
+		return false
+	}
+
 	// Process create, write, and remove events
 	if event.Op&fsnotify.Create == 0 && event.Op&fsnotify.Write == 0 && event.Op&fsnotify.Remove == 0 {
 		return false
@@ -205,26 +301,63 @@ func (w *Watcher) shouldProcessEvent(event fsnotify.Event) bool {
 	}
 
 	// Debounce rapid changes
+	key := w.debounceKey(event.Name)
 	now := time.Now()
-	if lastChange, exists := w.lastChangeMap[event.Name]; exists {
-		if now.Sub(lastChange) < w.debounceTime {
+	if lastChange, exists := w.lastChangeMap[key]; exists {
+		if now.Sub(lastChange) < debounceTime {
+			// A collapsed event may still be a new directory that needs watching
+			if debounceDir {
+				w.watchIfNewDirectory(event)
+			}
 			return false
 		}
 	}
 
-	w.lastChangeMap[event.Name] = now
+	w.lastChangeMap[key] = now
 
 	return true
 }
 
+// debounceKey returns the path rapid changes are collapsed by: the file itself,
+// or its parent directory in directory debounce mode
+func (w *Watcher) debounceKey(path string) string {
+	if w.isDebouncingDirs() {
+		return filepath.Dir(path)
+	}
+	return path
+}
+
+// isDebouncingDirs reports whether directory debounce mode is active
+func (w *Watcher) isDebouncingDirs() bool {
+	w.disabledMutex.RLock()
+	defer w.disabledMutex.RUnlock()
+	return w.debounceDir
+}
+
+// targetFor returns the sync target whose directory contains path
+func (w *Watcher) targetFor(path string) (config.Target, bool) {
+	for _, target := range w.currentConfig().Cursor.SyncTargets() {
+		relativePath, err := filepath.Rel(target.ConfigPath, path)
+		if err == nil && relativePath != ".." && !strings.HasPrefix(relativePath, ".."+string(filepath.Separator)) {
+			return target, true
+		}
+	}
+	return config.Target{}, false
+}
+
 func (w *Watcher) shouldExcludePath(path string) bool {
-	userPath := filepath.Join(w.config.Cursor.ConfigPath, "User")
+	target, ok := w.targetFor(path)
+	if !ok {
+		return false
+	}
+
+	userPath := filepath.Join(target.ConfigPath, "User")
 	relativePath, err := filepath.Rel(userPath, path)
 	if err != nil {
 		return false
 	}
 
-	for _, excludePattern := range w.config.Cursor.ExcludePaths {
+	for _, excludePattern := range w.currentConfig().Cursor.ExcludePaths {
 		// Remove "User/" prefix from exclude patterns for comparison
 		pattern := strings.TrimPrefix(excludePattern, "User/")
 		matched, _ := filepath.Match(pattern, relativePath)
@@ -238,17 +371,23 @@ func (w *Watcher) shouldExcludePath(path string) bool {
 
 func (w *Watcher) matchesWatchPattern(path string) bool {
 	// If no include patterns specified, include all non-excluded files
-	if len(w.config.Cursor.IncludePaths) == 0 {
+	includePaths := w.currentConfig().Cursor.IncludePaths
+	if len(includePaths) == 0 {
 		return true
 	}
 
-	relativePath, err := filepath.Rel(w.config.Cursor.ConfigPath, path)
+	target, ok := w.targetFor(path)
+	if !ok {
+		return false
+	}
+
+	relativePath, err := filepath.Rel(target.ConfigPath, path)
 	if err != nil {
 		return false
 	}
 
 	// Check against include patterns
-	for _, pattern := range w.config.Cursor.IncludePaths {
+	for _, pattern := range includePaths {
 		matched, _ := filepath.Match(pattern, relativePath)
 		if matched || strings.Contains(relativePath, pattern) {
 			return true
@@ -261,16 +400,13 @@ func (w *Watcher) matchesWatchPattern(path string) bool {
 func (w *Watcher) handleEvent(event fsnotify.Event) {
 	logger.Debug("File changed: %s (%s)", event.Name, event.Op.String())
 
-	// Handle directory creation by adding it to watch list
-	if event.Op&fsnotify.Create != 0 {
-		if info, err := os.Stat(event.Name); err == nil && info.IsDir() {
-			w.addNewDirectoryToWatch(event.Name)
-		}
-	}
+	w.watchIfNewDirectory(event)
 
 	// Determine the action based on the event type
 	var action string
 	switch {
+	case w.isDebouncingDirs():
+		action = "modify" // The change stands for everything under the directory
 	case event.Op&fsnotify.Create != 0:
 		action = "create"
 	case event.Op&fsnotify.Write != 0:
@@ -282,7 +418,7 @@ func (w *Watcher) handleEvent(event fsnotify.Event) {
 	}
 
 	change := FileChange{
-		Path:   event.Name,
+		Path:   w.debounceKey(event.Name),
 		Action: action,
 	}
 
@@ -0,0 +1,96 @@
+package watcher
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+
+	"cursor-sync/internal/config"
+)
+
+func TestDirectoryDebounceCollapsesEvents(t *testing.T) {
+	configPath := t.TempDir()
+	snippets := filepath.Join(configPath, "User", "snippets")
+
+	for _, tc := range []struct {
+		mode string
+		want int
+	}{
+		{config.DebounceFile, 3},
+		{config.DebounceDir, 1},
+	} {
+		cfg := &config.Config{
+			Sync:   config.Sync{DebounceTime: time.Minute, DebounceMode: tc.mode},
+			Cursor: config.Cursor{ConfigPath: configPath},
+		}
+		w := &Watcher{
+			config:        cfg,
+			changeChan:    make(chan FileChange, 10),
+			debounceTime:  cfg.Sync.DebounceTime,
+			debounceDir:   cfg.Sync.DebounceMode == config.DebounceDir,
+			lastChangeMap: make(map[string]time.Time),
+		}
+
+		for _, name := range []string{"go.json", "python.json", "rust.json"} {
+			event := fsnotify.Event{Name: filepath.Join(snippets, name), Op: fsnotify.Write}
+			if w.shouldProcessEvent(event) {
+				w.handleEvent(event)
+			}
+		}
+
+		if got := len(w.changeChan); got != tc.want {
+			t.Errorf("debounce_mode %s: %d change events, want %d", tc.mode, got, tc.want)
+		}
+		if tc.mode == config.DebounceDir {
+			if change := <-w.changeChan; change.Path != snippets {
+				t.Errorf("debounce_mode dir: change path = %s, want %s", change.Path, snippets)
+			}
+		}
+	}
+}
+
+func TestEditAfterSyncIsReported(t *testing.T) {
+	configPath := t.TempDir()
+	userPath := filepath.Join(configPath, "User")
+	if err := os.MkdirAll(userPath, 0755); err != nil {
+		t.Fatal(err)
+	}
+	settings := filepath.Join(userPath, "settings.json")
+	if err := os.WriteFile(settings, []byte(`{"a": 1}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := &config.Config{Cursor: config.Cursor{ConfigPath: configPath}}
+	w := &Watcher{
+		config:        cfg,
+		changeChan:    make(chan FileChange, 10),
+		lastChangeMap: make(map[string]time.Time),
+	}
+	w.Disable()
+	w.Enable()
+
+	// The event for the sync's own write arrives after the watcher was re-enabled
+	event := fsnotify.Event{Name: settings, Op: fsnotify.Write}
+	if w.shouldProcessEvent(event) {
+		t.Error("event for the file as the sync wrote it was reported")
+	}
+
+	// The user edits the file within the quiet period
+	if err := os.WriteFile(settings, []byte(`{"a": 2, "b": 3}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if !w.shouldProcessEvent(event) {
+		t.Error("edit made after the sync was ignored")
+	}
+
+	created := fsnotify.Event{Name: filepath.Join(userPath, "keybindings.json"), Op: fsnotify.Create}
+	if err := os.WriteFile(created.Name, []byte(`[]`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if !w.shouldProcessEvent(created) {
+		t.Error("file created after the sync was ignored")
+	}
+}